@@ -62,6 +62,28 @@ type BreakingChange struct {
 	DocumentationReference string
 }
 
+// diffProcessorReport mirrors the versioned JSON report written by
+// `diff-processor --format=json`.
+type diffProcessorReport struct {
+	Version  int `json:"version"`
+	Findings []struct {
+		Message                string       `json:"message"`
+		DocumentationReference string       `json:"documentation_reference"`
+		Suppression            *Suppression `json:"suppression"`
+	} `json:"findings"`
+	UnusedSuppressions []Suppression `json:"unused_suppressions"`
+}
+
+// Suppression is an entry in the diff processor allowlist.
+type Suppression struct {
+	Resource      string `json:"resource"`
+	Field         string `json:"field"`
+	Rule          string `json:"rule"`
+	Justification string `json:"justification"`
+}
+
+const diffProcessorReportVersion = 1
+
 type MissingTestInfo struct {
 	SuggestedTest string
 	Tests         []string
@@ -92,6 +114,7 @@ type diffCommentData struct {
 	CommitSHA            string
 	Diffs                []Diff
 	BreakingChanges      []BreakingChange
+	UnusedSuppressions   []Suppression
 	MissingServiceLabels []string
 	MissingTests         map[string]*MissingTestInfo
 	MissingDocs          *MissingDocsSummary
@@ -309,6 +332,8 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 	uniqueAddedResources := map[string]struct{}{}
 	uniqueAffectedResources := map[string]struct{}{}
 	uniqueBreakingChanges := map[string]BreakingChange{}
+	// Allowlist entries are only unused if neither provider version used them.
+	var unusedSuppressions map[Suppression]bool
 	diffProcessorPath := filepath.Join(mmLocalPath, "tools", "diff-processor")
 	diffProcessorEnv := map[string]string{
 		"OLD_REF": oldBranch,
@@ -334,10 +359,18 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 			continue
 		}
 
-		breakingChanges, err := computeBreakingChanges(diffProcessorPath, rnr)
+		breakingChanges, unused, err := computeBreakingChanges(diffProcessorPath, rnr)
 		if err != nil {
 			fmt.Println("computing breaking changes: ", err)
 			errors[repo.Title] = append(errors[repo.Title], "The diff processor crashed while computing breaking changes. This is usually due to the downstream provider failing to compile.")
+		} else {
+			repoUnused := map[Suppression]bool{}
+			for _, suppression := range unused {
+				if unusedSuppressions == nil || unusedSuppressions[suppression] {
+					repoUnused[suppression] = true
+				}
+			}
+			unusedSuppressions = repoUnused
 		}
 		for _, breakingChange := range breakingChanges {
 			uniqueBreakingChanges[breakingChange.Message] = breakingChange
@@ -390,6 +423,11 @@ func execGenerateComment(prNumber int, ghTokenMagicModules, buildId, buildStep,
 		return breakingChangesSlice[i].Message < breakingChangesSlice[j].Message
 	})
 	data.BreakingChanges = breakingChangesSlice
+	data.UnusedSuppressions = maps.Keys(unusedSuppressions)
+	sort.Slice(data.UnusedSuppressions, func(i, j int) bool {
+		a, b := data.UnusedSuppressions[i], data.UnusedSuppressions[j]
+		return a.Resource+"."+a.Field+" "+a.Rule < b.Resource+"."+b.Field+" "+b.Rule
+	})
 
 	// Check if multiple resources were added.
 	multipleResourcesState := "success"
@@ -568,24 +606,39 @@ func buildDiffProcessor(diffProcessorPath, providerLocalPath string, env map[str
 	return rnr.PopDir()
 }
 
-func computeBreakingChanges(diffProcessorPath string, rnr ExecRunner) ([]BreakingChange, error) {
+// computeBreakingChanges returns the breaking changes not suppressed by the
+// diff processor allowlist, and the allowlist entries that matched nothing.
+func computeBreakingChanges(diffProcessorPath string, rnr ExecRunner) ([]BreakingChange, []Suppression, error) {
 	if err := rnr.PushDir(diffProcessorPath); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	output, err := rnr.Run("bin/diff-processor", []string{"breaking-changes"}, nil)
+	output, err := rnr.Run("bin/diff-processor", []string{"breaking-changes", "--format=json", "--allowlist=allowlist.yaml"}, nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if output == "" {
-		return nil, nil
+		return nil, nil, rnr.PopDir()
 	}
 
+	var report diffProcessorReport
+	if err = json.Unmarshal([]byte(output), &report); err != nil {
+		return nil, nil, err
+	}
+	if report.Version != diffProcessorReportVersion {
+		return nil, nil, fmt.Errorf("unsupported diff processor report version %d", report.Version)
+	}
 	var changes []BreakingChange
-	if err = json.Unmarshal([]byte(output), &changes); err != nil {
-		return nil, err
+	for _, finding := range report.Findings {
+		if finding.Suppression != nil {
+			continue
+		}
+		changes = append(changes, BreakingChange{
+			Message:                finding.Message,
+			DocumentationReference: finding.DocumentationReference,
+		})
 	}
-	return changes, rnr.PopDir()
+	return changes, report.UnusedSuppressions, rnr.PopDir()
 }
 
 func computeAffectedResources(diffProcessorPath string, rnr ExecRunner, repo source.Repo) (simpleSchemaDiff, error) {
@@ -106,10 +106,10 @@ func TestExecGenerateComment(t *testing.T) {
 			{"/mock/dir/tgc", "git", []string{"diff", "origin/auto-pr-123456-old", "origin/auto-pr-123456", "--name-only"}, map[string]string(nil)},
 			{"/mock/dir/tfoics", "git", []string{"diff", "origin/auto-pr-123456-old", "origin/auto-pr-123456", "--shortstat"}, map[string]string(nil)},
 			{"/mock/dir/magic-modules/tools/diff-processor", "make", []string{"build"}, diffProcessorEnv},
-			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"breaking-changes"}, map[string]string(nil)},
+			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"breaking-changes", "--format=json", "--allowlist=allowlist.yaml"}, map[string]string(nil)},
 			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"schema-diff"}, map[string]string(nil)},
 			{"/mock/dir/magic-modules/tools/diff-processor", "make", []string{"build"}, diffProcessorEnv},
-			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"breaking-changes"}, map[string]string(nil)},
+			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"breaking-changes", "--format=json", "--allowlist=allowlist.yaml"}, map[string]string(nil)},
 			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"detect-missing-tests", "/mock/dir/tpgb/google-beta/services"}, map[string]string(nil)},
 			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"detect-missing-docs", "/mock/dir/tpgb"}, map[string]string(nil)},
 			{"/mock/dir/magic-modules/tools/diff-processor", "bin/diff-processor", []string{"detect-missing-identity", "/mock/dir/tpgb/google-beta/services"}, map[string]string(nil)},
@@ -247,6 +247,22 @@ func TestFormatDiffComment(t *testing.T) {
 				"## Missing test report",
 			},
 		},
+		"unused suppressions are displayed": {
+			data: diffCommentData{
+				UnusedSuppressions: []Suppression{
+					{Resource: "google_x", Rule: "resource-map-resource-removal-or-rename"},
+					{Resource: "google_y", Field: "field_a", Rule: "field-becoming-sensitive"},
+				},
+			},
+			expectedStrings: []string{
+				"## Unused breaking change suppressions",
+				"- `resource-map-resource-removal-or-rename` on `google_x`\n- `field-becoming-sensitive` on `google_y.field_a`\n",
+			},
+			notExpectedStrings: []string{
+				"## Breaking Change(s) Detected",
+				"## Errors",
+			},
+		},
 		"multiple resources are displayed": {
 			data: diffCommentData{
 				MultipleResources: []string{"google_redis_instance", "google_alloydb_cluster"},
@@ -0,0 +1,172 @@
+// Copyright 2025 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"golang.org/x/exp/slices"
+)
+
+// Action describes a custom method of a resource, such as `:restart` or
+// `:failover`, that is generated as a Plugin Framework action. Actions are
+// invoked imperatively by Terraform and never change the resource's state.
+type Action struct {
+	// The name of the action, in camelCase. It is appended to the Terraform
+	// name of its resource, i.e. `restart` on `google_sql_database_instance`
+	// is generated as `google_sql_database_instance_restart`.
+	Name string `yaml:"name"`
+
+	// A description of what invoking the action does.
+	Description string `yaml:"description"`
+
+	// The URL of the custom method, relative to the product base URL.
+	// Terraform field names of the resource enclosed in double curly braces
+	// are arguments of the action.
+	// i.e. `projects/{{project}}/instances/{{name}}:restart`
+	Url string `yaml:"url"`
+
+	// The HTTP verb used to invoke the action. Defaults to POST.
+	Verb string `yaml:"verb,omitempty"`
+
+	// The fields sent in the request body, which are optional arguments of
+	// the action unless marked as required.
+	Properties []*Type `yaml:"properties,omitempty"`
+
+	// If set, the method returns a long-running operation that is polled
+	// until it completes. Only `OpAsync` is supported, and the product's
+	// operation waiter is used.
+	Async *Async `yaml:"async,omitempty"`
+
+	// How long to wait for the operation to complete. Defaults to 20.
+	TimeoutMinutes int `yaml:"timeout_minutes,omitempty"`
+
+	// boolean to determine whether unit tests should be generated for the
+	// action
+	ExcludeTest bool `yaml:"exclude_test,omitempty"`
+
+	ResourceMetadata *Resource `yaml:"-"`
+}
+
+func (a *Action) SetDefault(r *Resource) {
+	a.ResourceMetadata = r
+	if a.Verb == "" {
+		a.Verb = "POST"
+	}
+	if a.TimeoutMinutes == 0 {
+		a.TimeoutMinutes = 20
+	}
+	for _, p := range a.Properties {
+		p.SetDefault(r)
+	}
+}
+
+func (a *Action) Validate() (es []error) {
+	r := a.ResourceMetadata
+	if a.Name == "" {
+		return append(es, fmt.Errorf("missing `name` for action in resource %s", r.Name))
+	}
+	if a.Description == "" {
+		es = append(es, fmt.Errorf("missing `description` for action %s in resource %s", a.Name, r.Name))
+	}
+	if a.Url == "" {
+		es = append(es, fmt.Errorf("missing `url` for action %s in resource %s", a.Name, r.Name))
+	}
+
+	allowed := []string{"POST", "PUT", "PATCH", "DELETE"}
+	if !slices.Contains(allowed, a.Verb) {
+		es = append(es, fmt.Errorf("value on `verb` for action %s in resource %s should be one of %#v", a.Name, r.Name, allowed))
+	}
+	if a.Async != nil && !a.Async.IsA("OpAsync") {
+		es = append(es, fmt.Errorf("`async` for action %s in resource %s must be `OpAsync`", a.Name, r.Name))
+	}
+	if a.Async != nil && (r.GetAsync() == nil || !r.GetAsync().IsA("OpAsync")) {
+		es = append(es, fmt.Errorf("`async` for action %s in resource %s requires the resource or product to use `OpAsync`, whose operation waiter it uses", a.Name, r.Name))
+	}
+
+	arguments := map[string]bool{}
+	for _, p := range a.Arguments() {
+		arguments[google.Underscore(p.Name)] = true
+		if p.GetFWType() != "String" {
+			es = append(es, fmt.Errorf("URL parameter %s of type %s is not supported by action %s in resource %s", google.Underscore(p.Name), p.Type, a.Name, r.Name))
+		}
+	}
+	for _, id := range r.ExtractIdentifiers(a.Url) {
+		if id != "project" && !arguments[id] {
+			es = append(es, fmt.Errorf("`url` for action %s in resource %s references unknown field %s", a.Name, r.Name, id))
+		}
+	}
+
+	for _, p := range a.Properties {
+		es = append(es, p.Validate(r.Name)...)
+		name := google.Underscore(p.Name)
+		if arguments[name] || (name == "project" && a.HasProject()) {
+			es = append(es, fmt.Errorf("property %s of action %s in resource %s is also a URL parameter", name, a.Name, r.Name))
+		}
+		if !isActionScalar(p) && !(p.IsA("Array") && isActionScalar(p.ItemType)) && !p.IsA("KeyValuePairs") {
+			es = append(es, fmt.Errorf("property %s of type %s is not supported by action %s in resource %s", name, p.Type, a.Name, r.Name))
+		}
+	}
+
+	return es
+}
+
+// TerraformName returns the name of the action in Terraform configuration.
+func (a Action) TerraformName() string {
+	return fmt.Sprintf("%s_%s", a.ResourceMetadata.TerraformName(), google.Underscore(a.Name))
+}
+
+// ActionName returns the prefix of the generated Go types of the action.
+func (a Action) ActionName() string {
+	return a.ResourceMetadata.ResourceName() + google.Camelize(a.Name, "upper")
+}
+
+// HasProject returns whether the action URL contains the project, which
+// defaults to the provider project.
+func (a Action) HasProject() bool {
+	return strings.Contains(a.Url, "{{project}}")
+}
+
+// HasRegion returns whether the action URL contains the region, which
+// defaults to the provider region.
+func (a Action) HasRegion() bool {
+	return strings.Contains(a.Url, "{{region}}")
+}
+
+// HasZone returns whether the action URL contains the zone, which defaults
+// to the provider zone.
+func (a Action) HasZone() bool {
+	return strings.Contains(a.Url, "{{zone}}")
+}
+
+// Arguments returns the properties of the resource that appear in the action
+// URL, which are required arguments of the action.
+func (a Action) Arguments() []*Type {
+	identifiers := a.ResourceMetadata.ExtractIdentifiers(a.Url)
+	return google.Select(a.ResourceMetadata.AllUserProperties(), func(p *Type) bool {
+		name := google.Underscore(p.Name)
+		return slices.Contains(identifiers, name) && name != "project"
+	})
+}
+
+func (a Action) ShouldGenerateTests() bool {
+	return !a.ExcludeTest
+}
+
+// isActionScalar returns whether a property is sent as a single JSON value.
+func isActionScalar(p *Type) bool {
+	return slices.Contains([]string{"String", "Int64", "Bool", "Float64"}, p.GetFWType())
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateActions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		action      api.Action
+		async       *api.Async
+		wantErrs    []string
+	}{
+		{
+			description: "url parameters and body properties",
+			action: api.Action{
+				Name:        "restart",
+				Description: "Restarts the instance.",
+				Url:         "projects/{{project}}/instances/{{name}}:restart",
+				Properties: []*api.Type{
+					{Name: "reason", Type: "String"},
+					{Name: "tags", Type: "Array", ItemType: &api.Type{Name: "tags", Type: "String"}},
+					{Name: "metadata", Type: "KeyValuePairs"},
+				},
+			},
+		},
+		{
+			description: "operations use the resource's async",
+			action: api.Action{
+				Name:        "restart",
+				Description: "Restarts the instance.",
+				Url:         "projects/{{project}}/instances/{{name}}:restart",
+				Async:       &api.Async{Type: "OpAsync"},
+			},
+			async: &api.Async{Type: "OpAsync"},
+		},
+		{
+			description: "required fields",
+			action: api.Action{
+				Name: "restart",
+			},
+			wantErrs: []string{
+				"missing `description` for action restart in resource Instance",
+				"missing `url` for action restart in resource Instance",
+			},
+		},
+		{
+			description: "async requires an operation waiter",
+			action: api.Action{
+				Name:        "restart",
+				Description: "Restarts the instance.",
+				Url:         "projects/{{project}}/instances/{{name}}:restart",
+				Async:       &api.Async{Type: "PollAsync"},
+			},
+			wantErrs: []string{
+				"`async` for action restart in resource Instance must be `OpAsync`",
+				"`async` for action restart in resource Instance requires the resource or product to use `OpAsync`, whose operation waiter it uses",
+			},
+		},
+		{
+			description: "unknown url parameter",
+			action: api.Action{
+				Name:        "failover",
+				Description: "Fails over the instance.",
+				Url:         "projects/{{project}}/instances/{{name}}/replicas/{{replica}}:failover",
+				Verb:        "GET",
+			},
+			wantErrs: []string{
+				"value on `verb` for action failover in resource Instance should be one of []string{\"POST\", \"PUT\", \"PATCH\", \"DELETE\"}",
+				"`url` for action failover in resource Instance references unknown field replica",
+			},
+		},
+		{
+			description: "unsupported properties",
+			action: api.Action{
+				Name:        "export",
+				Description: "Exports the instance.",
+				Url:         "projects/{{project}}/instances/{{name}}:export",
+				Properties: []*api.Type{
+					{Name: "name", Type: "String"},
+					{Name: "destination", Type: "NestedObject", Properties: []*api.Type{{Name: "uri", Type: "String"}}},
+				},
+			},
+			wantErrs: []string{
+				"property name of action export in resource Instance is also a URL parameter",
+				"property destination of type NestedObject is not supported by action export in resource Instance",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &api.Resource{
+				Name:            "Instance",
+				Description:     "An instance.",
+				BaseUrl:         "projects/{{project}}/instances",
+				Async:           tc.async,
+				ProductMetadata: &api.Product{Name: "Test"},
+				Parameters: []*api.Type{
+					{Name: "name", Type: "String", UrlParamOnly: true, Required: true, Description: "A field."},
+				},
+				Properties: []*api.Type{
+					{Name: "state", Type: "String", Output: true, Description: "A field."},
+				},
+				Actions: []*api.Action{&tc.action},
+			}
+			r.SetDefault(r.ProductMetadata)
+			for _, p := range tc.action.Properties {
+				p.Description = "A field."
+				for _, np := range p.Properties {
+					np.Description = "A field."
+				}
+			}
+
+			var got []string
+			for _, err := range r.Validate() {
+				if strings.Contains(err.Error(), "action") {
+					got = append(got, err.Error())
+				}
+			}
+			if diff := cmp.Diff(tc.wantErrs, got); diff != "" {
+				t.Errorf("Validate() action errors unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestActionNames(t *testing.T) {
+	t.Parallel()
+
+	r := &api.Resource{
+		Name:            "DatabaseInstance",
+		ProductMetadata: &api.Product{Name: "Sql"},
+		Parameters: []*api.Type{
+			{Name: "name", Type: "String", UrlParamOnly: true},
+		},
+		Actions: []*api.Action{
+			{Name: "promoteReplica", Url: "projects/{{project}}/instances/{{name}}/promoteReplica"},
+		},
+	}
+	r.SetDefault(r.ProductMetadata)
+	a := r.Actions[0]
+
+	if got, want := a.TerraformName(), "google_sql_database_instance_promote_replica"; got != want {
+		t.Errorf("TerraformName() = %q, want %q", got, want)
+	}
+	if got, want := a.ActionName(), "SqlDatabaseInstancePromoteReplica"; got != want {
+		t.Errorf("ActionName() = %q, want %q", got, want)
+	}
+	if got, want := a.Verb, "POST"; got != want {
+		t.Errorf("Verb = %q, want %q", got, want)
+	}
+	if !a.HasProject() || a.HasRegion() || a.HasZone() {
+		t.Errorf("HasProject(), HasRegion(), HasZone() = %t, %t, %t, want true, false, false", a.HasProject(), a.HasRegion(), a.HasZone())
+	}
+	var args []string
+	for _, p := range a.Arguments() {
+		args = append(args, p.Name)
+	}
+	if diff := cmp.Diff([]string{"name"}, args); diff != "" {
+		t.Errorf("Arguments() unexpected diff (-want +got):\n%s", diff)
+	}
+}
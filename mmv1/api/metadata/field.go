@@ -24,8 +24,14 @@ func FromProperties(props []*api.Type) []Field {
 			continue
 		}
 		f := Field{
-			Json:         p.IsJsonField(),
-			ProviderOnly: p.ProviderOnly(),
+			Json:            p.IsJsonField(),
+			ProviderOnly:    p.ProviderOnly(),
+			ValidationRegex: p.Validation.Regex,
+		}
+		if p.IsA("Enum") {
+			f.EnumValues = p.EnumValues
+		} else if p.IsA("Array") && p.ItemType != nil && p.ItemType.IsA("Enum") {
+			f.EnumValues = p.ItemType.EnumValues
 		}
 		lineage := p.Lineage()
 		apiLineage := p.ApiLineage()
@@ -60,6 +66,10 @@ type Field struct {
 	// If true, this is a JSON field which "covers" all child API fields. As a special case, JSON fields which cover an entire resource can
 	// have `api_field` set to `*`.
 	Json bool `yaml:"json,omitempty"`
+	// The values accepted by an enum field, or by the items of an array of enums. Used to detect removed values as breaking changes.
+	EnumValues []string `yaml:"enum_values,omitempty"`
+	// The regex a field's value must match. Used to detect changed validation as a breaking change.
+	ValidationRegex string `yaml:"validation_regex,omitempty"`
 }
 
 // Returns true if the lineage is the default we'd expect for a field, and false otherwise.
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
 	"github.com/google/go-cmp/cmp"
 )
@@ -140,6 +141,43 @@ func TestFromProperties(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "enum and validation",
+			properties: []*api.Type{
+				{
+					Name:       "size",
+					Type:       "Enum",
+					EnumValues: []string{"SMALL", "LARGE"},
+				},
+				{
+					Name: "sizes",
+					Type: "Array",
+					ItemType: &api.Type{
+						Type:       "Enum",
+						EnumValues: []string{"SMALL", "LARGE"},
+					},
+				},
+				{
+					Name:       "name",
+					Type:       "String",
+					Validation: resource.Validation{Regex: "^[a-z]+$"},
+				},
+			},
+			wantFields: []Field{
+				{
+					ApiField:        "name",
+					ValidationRegex: "^[a-z]+$",
+				},
+				{
+					ApiField:   "size",
+					EnumValues: []string{"SMALL", "LARGE"},
+				},
+				{
+					ApiField:   "sizes",
+					EnumValues: []string{"SMALL", "LARGE"},
+				},
+			},
+		},
 		{
 			name: "map",
 			properties: []*api.Type{
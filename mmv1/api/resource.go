@@ -251,6 +251,19 @@ type Resource struct {
 	// EXPERIMENTAL: If true, resource should be autogenerated as a data source
 	Datasource *resource.Datasource `yaml:"datasource_experimental,omitempty"`
 
+	// EXPERIMENTAL: If set, a Plugin Framework ephemeral resource that reads
+	// this resource without persisting it to state is generated alongside it.
+	Ephemeral *resource.Ephemeral `yaml:"ephemeral,omitempty"`
+
+	// Custom methods of the resource, such as `:restart`, generated as
+	// Plugin Framework actions.
+	Actions []*Action `yaml:"actions,omitempty"`
+
+	// If true, provider-defined functions that parse an id of the resource
+	// into its segments and build an id from them are generated, named
+	// `<product>_<resource>_id_parse` and `<product>_<resource>_id_build`.
+	GenerateIdFunctions bool `yaml:"generate_id_functions,omitempty"`
+
 	GenerateListResource bool `yaml:"generate_list_resource,omitempty"`
 
 	// [Optional] A static filter string appended as a ?filter= query parameter when
@@ -261,6 +274,11 @@ type Resource struct {
 	// If true, skip sweeper generation for this resource
 	ExcludeSweeper bool `yaml:"exclude_sweeper,omitempty"`
 
+	// If true, generate offline contract tests that run each eligible sample
+	// against an in-memory fake of the resource's REST collection. Samples
+	// are eligible if they only declare resources of this resource's type.
+	GenerateContractTests bool `yaml:"generate_contract_tests,omitempty"`
+
 	// If true, skip identity generation for this resource
 	ExcludeIdentityGeneration bool `yaml:"exclude_identity_generation,omitempty"`
 
@@ -352,6 +370,13 @@ type Resource struct {
 	// Add a deprecation message for a resource that's been deprecated in the API.
 	DeprecationMessage string `yaml:"deprecation_message,omitempty"`
 
+	// [Optional] (Api::Resource::Successor) The resource that replaces this
+	// one. The successor, which must set `plugin_framework_experimental`,
+	// accepts this resource's state through a Terraform `moved` block, and
+	// the docs of this resource include an upgrade guide. The field mapping
+	// is validated against both schemas at generation time.
+	Successor *resource.Successor `yaml:"successor,omitempty"`
+
 	// Tag autogen resources so that we can track them. In the future this will
 	// control if a resource is continuously generated from public OpenAPI docs
 	AutogenStatus string `yaml:"autogen_status,omitempty"`
@@ -359,8 +384,9 @@ type Resource struct {
 	// If true, generates product operation handling logic.
 	AutogenAsync bool `yaml:"autogen_async,omitempty"`
 
-	// EXPERIMENTAL: this is an incomplete feature and may have several build errors.
-	// If true, this resource generates with the new plugin framework resource template
+	// EXPERIMENTAL: If true, this resource generates with the plugin framework
+	// resource template instead of the SDKv2 one. Features that the framework
+	// template doesn't support are rejected when the resource is validated.
 	FrameworkResource bool `yaml:"plugin_framework_experimental,omitempty"`
 
 	ProductMetadata *Product `yaml:"-"`
@@ -371,6 +397,11 @@ type Resource struct {
 	ImportPath     string `yaml:"-"`
 	SourceYamlFile string `yaml:"-"`
 
+	// The resource named by `successor` and the resources that name this
+	// resource as theirs, set by the loader.
+	SuccessorResource *Resource   `yaml:"-"`
+	Predecessors      []*Resource `yaml:"-"`
+
 	constraintGroupRegistry     map[string]*[]string `yaml:"-"`
 	constraintGroupsInitialized bool                 `yaml:"-"`
 
@@ -526,6 +557,9 @@ func (r *Resource) setShallowDefaults() {
 	if r.IamPolicy != nil && r.IamPolicy.MinVersion == "" {
 		r.IamPolicy.MinVersion = r.MinVersion
 	}
+	if r.Ephemeral != nil {
+		r.Ephemeral.SetDefault()
+	}
 	if r.Timeouts == nil {
 		r.Timeouts = NewTimeouts() // This only sets defaults if Timeouts is nil
 	}
@@ -547,6 +581,9 @@ func (r *Resource) SetDefault(product *Product) {
 	for _, vf := range r.VirtualFields {
 		vf.SetDefault(r)
 	}
+	for _, a := range r.Actions {
+		a.SetDefault(r)
+	}
 
 	if r.IamPolicy != nil && r.DeprecationMessage != "" && r.IamPolicy.DeprecationMessage == "" {
 		r.IamPolicy.DeprecationMessage = fmt.Sprintf("The parent resource has been deprecated: %v", r.DeprecationMessage)
@@ -622,9 +659,438 @@ func (r *Resource) Validate() (es []error) {
 		es = append(es, sample.Validate(r.Name)...)
 	}
 
+	if r.FrameworkResource {
+		es = append(es, r.validateFramework()...)
+	}
+
+	if r.IncludeInTGCNext {
+		es = append(es, r.validateTgcNext()...)
+	}
+
+	if r.GenerateContractTests {
+		es = append(es, r.validateContractTests()...)
+	}
+
+	if r.ShouldGenerateEphemeral() {
+		es = append(es, r.Ephemeral.Validate(r.Name)...)
+		es = append(es, r.validateEphemeral()...)
+	}
+
+	if r.Successor != nil {
+		es = append(es, r.Successor.Validate(r.Name)...)
+		es = append(es, r.validateSuccessor()...)
+	}
+
+	for _, a := range r.Actions {
+		es = append(es, a.Validate()...)
+	}
+
+	if r.GenerateIdFunctions && len(r.IdFunctionSegments()) == 0 {
+		es = append(es, fmt.Errorf("generate_id_functions requires the id format of resource %s to contain at least one field", r.Name))
+	}
+
 	return es
 }
 
+// validateContractTests rejects resources whose API interactions can't be
+// served by the contract test fake.
+func (r *Resource) validateContractTests() (es []error) {
+	unsupported := func(feature string) {
+		es = append(es, fmt.Errorf("%s is not supported by generate_contract_tests on resource %s", feature, r.Name))
+	}
+
+	if r.NestedQuery != nil {
+		unsupported("`nested_query`")
+	}
+	if r.Async != nil && r.Async.IsA("PollAsync") {
+		unsupported("`PollAsync` async")
+	}
+	if r.ReadVerb != "" && r.ReadVerb != "GET" {
+		unsupported(fmt.Sprintf("`read_verb: %s`", r.ReadVerb))
+	}
+	return es
+}
+
+// validateFramework rejects features that the plugin framework resource
+// template doesn't implement, so that flipping a resource to the framework
+// fails at generation time rather than silently changing its behavior.
+func (r *Resource) validateFramework() (es []error) {
+	unsupported := func(feature string) {
+		es = append(es, fmt.Errorf("%s is not supported by plugin_framework_experimental on resource %s", feature, r.Name))
+	}
+
+	if r.NestedQuery != nil {
+		unsupported("`nested_query`")
+	}
+	if r.Async != nil && r.Async.IsA("PollAsync") {
+		unsupported("`PollAsync` async")
+	}
+	if r.ExcludeRead {
+		unsupported("`exclude_read`")
+	}
+	if r.TaintResourceOnFailedCreate {
+		unsupported("`taint_resource_on_failed_create`")
+	}
+	// The labels and annotations diffs added for top-level fields are planned
+	// by the framework template's ModifyPlan instead.
+	plannedDiffs := []string{"tpgresource.SetLabelsDiff", "tpgresource.SetLabelsDiffWithoutAttributionLabel", "tpgresource.SetAnnotationsDiff"}
+	if slices.ContainsFunc(r.CustomDiff, func(d string) bool { return !slices.Contains(plannedDiffs, d) }) {
+		unsupported("`custom_diff`")
+	}
+	if r.StateUpgraders || r.SchemaVersion > 0 {
+		unsupported("`state_upgraders`")
+	}
+	if r.IdentityUpgraders {
+		unsupported("`identity_upgraders`")
+	}
+	if r.GenerateListResource {
+		unsupported("`generate_list_resource`")
+	}
+	if r.Datasource != nil {
+		unsupported("`datasource_experimental`")
+	}
+	if len(r.CustomCode.CustomIdentity) > 0 {
+		unsupported("`custom_code.custom_identity`")
+	}
+	if r.CustomCode.ValidateRawResourceConfigFuncs != "" {
+		unsupported("`custom_code.raw_resource_config_validation`")
+	}
+
+	if slices.ContainsFunc(r.AllUserProperties(), func(p *Type) bool { return google.Underscore(p.Name) == "id" }) {
+		unsupported("a property named `id`")
+	}
+
+	for _, p := range r.AllNestedProperties(r.AllUserProperties()) {
+		es = append(es, p.validateFramework(r.Name)...)
+	}
+
+	return es
+}
+
+// validateTgcNext rejects properties that tfplan2cai converts but cai2hcl
+// can't convert back, so that the round trip of the generated converters fails
+// at generation time rather than in the bidirectional conversion tests.
+func (r *Resource) validateTgcNext() (es []error) {
+	for _, p := range r.AllNestedProperties(r.AllUserProperties()) {
+		es = append(es, p.validateTgcNext(r.Name)...)
+	}
+	return es
+}
+
+// validateEphemeral rejects features that the ephemeral resource template
+// doesn't implement.
+func (r *Resource) validateEphemeral() (es []error) {
+	unsupported := func(feature string) {
+		es = append(es, fmt.Errorf("%s is not supported by `ephemeral` on resource %s", feature, r.Name))
+	}
+
+	if r.NestedQuery != nil {
+		unsupported("`nested_query`")
+	}
+	for _, p := range r.EphemeralArguments() {
+		if p.GetFWType() != "String" {
+			unsupported(fmt.Sprintf("URL parameter %s of type %s", google.Underscore(p.Name), p.Type))
+		}
+	}
+	for _, p := range r.AllNestedProperties(r.EphemeralAttributes()) {
+		fullFieldPath := strings.Join(p.Lineage(), ".")
+		if p.IsA("Map") {
+			unsupported(fmt.Sprintf("property %s of type `Map`", fullFieldPath))
+		}
+		if p.IsA("Array") && p.ItemType.IsA("Array") {
+			unsupported(fmt.Sprintf("property %s, an array of arrays,", fullFieldPath))
+		}
+		if p.FlattenObject {
+			unsupported(fmt.Sprintf("`flatten_object` on property %s", fullFieldPath))
+		}
+		if p.CustomFlatten != "" {
+			unsupported(fmt.Sprintf("`custom_flatten` on property %s", fullFieldPath))
+		}
+	}
+	return es
+}
+
+// validateSuccessor checks the field mapping of `successor` against the
+// schemas of both resources, so that moving state between them can't fail on
+// a field that was renamed or retyped without a mapping.
+func (r *Resource) validateSuccessor() (es []error) {
+	successor := r.SuccessorResource
+	if successor == nil {
+		if r.Successor.Resource != "" {
+			es = append(es, fmt.Errorf("successor %s of resource %s not found", r.Successor.Resource, r.Name))
+		}
+		return es
+	}
+	if !successor.FrameworkResource {
+		es = append(es, fmt.Errorf("successor %s of resource %s must set `plugin_framework_experimental`", successor.Name, r.Name))
+	}
+	if successor.Exclude {
+		es = append(es, fmt.Errorf("successor %s of resource %s is excluded", successor.Name, r.Name))
+	}
+
+	sources := r.topLevelFields()
+	targets := successor.topLevelFields()
+	for _, from := range slices.Sorted(maps.Keys(r.Successor.FieldMapping)) {
+		to := r.Successor.FieldMapping[from]
+		if _, ok := sources[from]; !ok {
+			es = append(es, fmt.Errorf("`successor.field_mapping` on resource %s maps unknown field %s", r.Name, from))
+		}
+		if _, ok := targets[to]; to != "" && !ok {
+			es = append(es, fmt.Errorf("`successor.field_mapping` on resource %s maps %s to unknown field %s of %s", r.Name, from, to, successor.Name))
+		}
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(r.Successor.FieldFormats)) {
+		if target, ok := targets[field]; !ok || !target.IsA("String") {
+			es = append(es, fmt.Errorf("`successor.field_formats` on resource %s formats %s, which is not a String field of %s", r.Name, field, successor.Name))
+		}
+		for _, m := range regexp.MustCompile(`\{\{(\w+)\}\}`).FindAllStringSubmatch(r.Successor.FieldFormats[field], -1) {
+			if (m[1] == "project" && successor.HasProject()) || (m[1] == "region" && successor.HasRegion()) || (m[1] == "zone" && successor.HasZone()) {
+				continue
+			}
+			if target, ok := targets[m[1]]; !ok || !target.IsA("String") {
+				es = append(es, fmt.Errorf("`successor.field_formats` on resource %s builds %s from %s, which is not a String field of %s", r.Name, field, m[1], successor.Name))
+			}
+		}
+	}
+
+	for _, from := range slices.Sorted(maps.Keys(sources)) {
+		to, ok := r.Successor.FieldMapping[from]
+		if !ok {
+			to = from
+		}
+		if to == "" {
+			continue
+		}
+		target, ok := targets[to]
+		if !ok {
+			es = append(es, fmt.Errorf("field %s of resource %s has no counterpart on its successor %s; add it to `successor.field_mapping`, mapping it to \"\" to drop it", from, r.Name, successor.Name))
+			continue
+		}
+		if !compatibleFieldTypes(sources[from], target) {
+			es = append(es, fmt.Errorf("field %s of resource %s has type %s, but %s of its successor %s has type %s", from, r.Name, sources[from].Type, to, successor.Name, target.Type))
+		}
+	}
+	return es
+}
+
+// topLevelFields returns the user-facing top-level fields of the resource by
+// their Terraform name.
+func (r Resource) topLevelFields() map[string]*Type {
+	fields := map[string]*Type{}
+	for _, p := range google.Concat(r.AllUserProperties(), r.VirtualFields) {
+		fields[google.Underscore(p.Name)] = p
+	}
+	return fields
+}
+
+// MoveStateDefaultFields returns the top-level fields whose default value the
+// generated MoveState sets when a moved state leaves them null.
+func (r Resource) MoveStateDefaultFields() []*Type {
+	var fields []*Type
+	for _, p := range r.AllUserProperties() {
+		if p.Output || p.DefaultValue == nil {
+			continue
+		}
+		if slices.Contains([]string{"String", "Bool", "Int64", "Float64"}, p.GetFWType()) {
+			fields = append(fields, p)
+		}
+	}
+	return fields
+}
+
+// FrameworkImports returns the names of the packages that the plugin framework
+// resource generated for r refers to, for the packages that
+// resource_fw.go.tmpl only imports when they are used.
+func (r Resource) FrameworkImports() map[string]bool {
+	imports := map[string]bool{}
+	use := func(pkgs ...string) {
+		for _, pkg := range pkgs {
+			imports[pkg] = true
+		}
+	}
+	// Validation functions are Go expressions such as
+	// "validation.StringLenBetween(0, 1024)".
+	useFunction := func(f string) {
+		if pkg, _, ok := strings.Cut(f, "."); ok {
+			use(pkg)
+		}
+	}
+
+	// Schema
+	for _, p := range r.AllNestedProperties(google.Concat(r.AllUserProperties(), r.VirtualFields)) {
+		typePkg := strings.ToLower(p.GetFWResourceType())
+		for _, l := range [][]string{p.Conflicting(), p.AtLeastOneOfList(), p.ExactlyOneOfList(), p.RequiredWithList()} {
+			if len(p.GetFWPathExpressionList(l)) > 0 {
+				use("validator", typePkg+"validator", "path")
+			}
+		}
+		if p.IsFWBlock() {
+			if p.Required {
+				use("validator", typePkg+"validator")
+			}
+			if p.IsA("NestedObject") {
+				use("validator", "listvalidator")
+			}
+			if p.IsForceNew() {
+				use(typePkg + "planmodifier")
+			}
+			continue
+		}
+		if !p.IsFWOutput() {
+			if !p.DefaultFromApi && !p.Required && p.DefaultValue != nil && slices.Contains([]string{"String", "Bool", "Int64", "Float64"}, p.GetFWType()) {
+				use(strings.ToLower(p.GetFWType()) + "default")
+			}
+			if p.IsA("Enum") {
+				use("validator", "stringvalidator")
+			}
+			if p.Validation.Regex != "" {
+				use("validator", "fwvalidators", "verify")
+			} else if p.Validation.Function != "" {
+				use("validator", "fwvalidators")
+				useFunction(p.Validation.Function)
+			}
+			if p.ItemValidation.Regex != "" {
+				use("validator", typePkg+"validator", "fwvalidators", "verify")
+			} else if p.ItemValidation.Function != "" {
+				use("validator", typePkg+"validator", "fwvalidators")
+				useFunction(p.ItemValidation.Function)
+			}
+		}
+		stableOutput := p.Output && p.ParentMetadata == nil && r.InPostCreateComputed(*p)
+		if p.IsForceNew() || p.DefaultFromApi || stableOutput {
+			use(typePkg + "planmodifier")
+		}
+	}
+
+	// Request bodies
+	emptyValueChecks := func(props []*Type) {
+		if slices.ContainsFunc(props, func(p *Type) bool { return !p.SendEmptyValue }) {
+			use("reflect", "tpgresource")
+		}
+	}
+	if r.CustomCode.CustomCreate == "" {
+		emptyValueChecks(r.SettableProperties())
+	}
+	if r.Updatable() && r.CustomCode.CustomUpdate == "" {
+		emptyValueChecks(r.UpdateBodyProperties())
+	}
+
+	// Expanders and flatteners. Custom ones are expected to use tpgresource,
+	// as name_from_self_link_fw.go.tmpl does.
+	var expand func(p *Type)
+	expand = func(p *Type) {
+		use("attr")
+		if p.CustomExpand != "" {
+			use("tpgresource")
+			return
+		}
+		switch {
+		case p.IsA("NestedObject") || (p.IsA("Array") && p.ItemType.IsA("NestedObject")):
+			emptyValueChecks(google.Reject(p.NestedProperties(), func(np *Type) bool {
+				return np.Output || np.UrlParamOnly || np.ClientSide
+			}))
+		case !strings.HasPrefix(p.Type, "KeyValue"):
+			use("fwresource")
+		}
+		for _, np := range p.NestedProperties() {
+			expand(np)
+		}
+	}
+	for _, p := range r.SettableProperties() {
+		expand(p)
+	}
+	var flatten func(p *Type)
+	flatten = func(p *Type) {
+		if p.CustomFlatten != "" {
+			use("tpgresource")
+			return
+		}
+		if p.IsA("NestedObject") || p.IsA("Array") || strings.HasPrefix(p.Type, "KeyValue") {
+			use("attr")
+		}
+		if !p.IsA("NestedObject") && !(p.IsA("Array") && p.ItemType.IsA("NestedObject")) {
+			use("fwresource")
+		}
+		for _, np := range p.NestedProperties() {
+			flatten(np)
+		}
+	}
+	for _, p := range r.GettableProperties() {
+		if p.ClientSide {
+			continue
+		}
+		if p.IsA("KeyValueLabels") || p.IsA("KeyValueTerraformLabels") || p.IsA("KeyValueAnnotations") {
+			use("fwresource")
+			continue
+		}
+		flatten(p)
+	}
+
+	// Resource methods
+	deletes := !r.ExcludeDelete && r.CustomCode.CustomDelete == ""
+	updates := r.Updatable() && r.CustomCode.CustomUpdate == ""
+	if r.CustomCode.CustomCreate == "" || updates || deletes {
+		use("time")
+	}
+	if (r.HasProject() && r.LegacyLongFormProject) || (updates && r.UpdateMask) {
+		use("strings")
+	}
+	if r.SupportsIndirectUserProjectOverride {
+		use("regexp")
+	}
+	if async := r.GetAsync(); async != nil && async.IsA("OpAsync") {
+		waits := (async.Allow("Create") && r.CustomCode.CustomCreate == "") || (async.Allow("Update") && updates) || (async.Allow("Delete") && deletes)
+		if waits && (r.ProductMetadata.Version.RepEnabled() || (r.LegacyLongFormProject && (r.HasProject() || async.IncludeProject))) {
+			use("tpgresource")
+		}
+	}
+	if r.HasSelfLink {
+		use("tpgresource")
+	}
+	if !r.ExcludeIdentityGeneration {
+		use("identityschema")
+		if len(r.IdentityProperties()) > 0 {
+			use("path")
+		}
+	}
+	if !r.ExcludeImport && r.CustomCode.CustomImport == "" {
+		use("path", "fwresource")
+	}
+	if len(r.Predecessors) > 0 {
+		use("fwresource")
+		if len(r.MoveStateDefaultFields()) > 0 {
+			use("path")
+		}
+	}
+	if r.HasProject() || r.HasRegion() || r.HasZone() || len(r.CustomDiff) > 0 || (!r.ExcludeDelete && !r.DeletionPolicyExclude) {
+		use("fwresource")
+	}
+
+	return imports
+}
+
+// compatibleFieldTypes reports whether a value of field a can be stored in
+// field b. Nested objects are compatible when every field of a has a
+// compatible field of the same name in b.
+func compatibleFieldTypes(a, b *Type) bool {
+	if a.GetFWType() != b.GetFWType() {
+		return false
+	}
+	if a.IsA("Array") && a.ItemType != nil && b.ItemType != nil {
+		return compatibleFieldTypes(a.ItemType, b.ItemType)
+	}
+	if a.IsA("NestedObject") {
+		for _, ap := range a.NestedProperties() {
+			i := slices.IndexFunc(b.NestedProperties(), func(bp *Type) bool { return bp.Name == ap.Name })
+			if i < 0 || !compatibleFieldTypes(ap, b.NestedProperties()[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // ====================
 // Custom Getters and Setters
 // ====================
@@ -1583,6 +2049,80 @@ func (r Resource) GetIdFormat() string {
 	return idFormat
 }
 
+// IdFunctionName returns the prefix of the provider-defined functions that
+// parse and build ids of the resource, i.e. `compute_instance_id`.
+func (r Resource) IdFunctionName() string {
+	return strings.TrimPrefix(r.TerraformName(), "google_") + "_id"
+}
+
+// IdFunctionSegments returns the fields of the id format, which are the
+// attributes returned by the id parse function and the arguments of the id
+// build function.
+func (r Resource) IdFunctionSegments() []string {
+	return r.ExtractIdentifiers(r.GetIdFormat())
+}
+
+// IdFunctionMarker returns the marker of a field in the id format, which
+// is `{{%field}}` if the field may contain `/` characters.
+func (r Resource) IdFunctionMarker(segment string) string {
+	if strings.Contains(r.GetIdFormat(), fmt.Sprintf("{{%%%s}}", segment)) {
+		return fmt.Sprintf("{{%%%s}}", segment)
+	}
+	return fmt.Sprintf("{{%s}}", segment)
+}
+
+// IdFunctionFormats returns the formats accepted by the id parse function:
+// the id format followed by the import formats that contain exactly the
+// same fields.
+func (r Resource) IdFunctionFormats() []string {
+	segments := r.IdFunctionSegments()
+	slices.Sort(segments)
+	formats := []string{r.GetIdFormat()}
+	for _, f := range r.ImportIdFormatsFromResource() {
+		identifiers := r.ExtractIdentifiers(f)
+		slices.Sort(identifiers)
+		if slices.Equal(identifiers, segments) && !slices.Contains(formats, f) {
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
+
+// IdFunctionPattern returns a regular expression matching ids in the given
+// format, with a named group per field. Formats that begin with a literal
+// also match resource URIs, self links and full resource names ending in
+// them.
+//
+// For instance, `projects/{{project}}/topics/{{name}}` returns
+// `^(?:.*/)?projects/(?P<project>[^/]+)/topics/(?P<name>[^/]+)$`
+func (r Resource) IdFunctionPattern(format string) string {
+	re := regexp.MustCompile(`\{\{(%?)(\w+)\}\}`)
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	if !strings.HasPrefix(format, "{{") {
+		pattern.WriteString("(?:.*/)?")
+	}
+	last := 0
+	for _, m := range re.FindAllStringSubmatchIndex(format, -1) {
+		pattern.WriteString(regexp.QuoteMeta(format[last:m[0]]))
+		segment := "[^/]+"
+		if m[3] > m[2] {
+			segment = ".+"
+		}
+		pattern.WriteString(fmt.Sprintf("(?P<%s>%s)", format[m[4]:m[5]], segment))
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(format[last:]))
+	pattern.WriteString("$")
+	return pattern.String()
+}
+
+// IdFunctionExample returns an id in the given format whose fields are set
+// to `test-<field>`, used by the generated id function tests.
+func (r Resource) IdFunctionExample(format string) string {
+	return regexp.MustCompile(`\{\{%?(\w+)\}\}`).ReplaceAllString(format, "test-$1")
+}
+
 // Returns true if the Type is in the ID format and false otherwise.
 func (r Resource) InPostCreateComputed(prop Type) bool {
 	fields := map[string]struct{}{}
@@ -2120,6 +2660,48 @@ func (r Resource) TestSamples() []*resource.Sample {
 	})
 }
 
+var hclBlockRegex = regexp.MustCompile(`(?m)^\s*(resource|data|ephemeral|module)\s+"([^"]+)"`)
+
+// ContractTestSamples returns the test samples that can run against the
+// contract test fake, i.e. those whose steps only declare resources of the
+// sample's primary resource type and don't need real test infrastructure.
+func (r Resource) ContractTestSamples() []*resource.Sample {
+	return google.Reject(r.TestSamples(), func(s *resource.Sample) bool {
+		if len(s.ExternalProviders) > 0 || len(s.BootstrapIam) > 0 {
+			return true
+		}
+		primaryType := s.ResourceType(r.TerraformName())
+		for _, step := range s.TestSteps() {
+			// Overrides are Go expressions that may call out to real APIs.
+			if len(step.TestVarsOverrides) > 0 {
+				return true
+			}
+			for _, m := range hclBlockRegex.FindAllStringSubmatch(step.TestHCLText, -1) {
+				if m[1] != "resource" || m[2] != primaryType {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+// ContractIdQueryParam returns the create_url query parameter that carries
+// the id of a new resource, e.g. "urlListId", or "" if there is none.
+func (r Resource) ContractIdQueryParam() string {
+	_, query, found := strings.Cut(r.CreateUri(), "?")
+	if !found {
+		return ""
+	}
+	for _, param := range strings.Split(query, "&") {
+		key, value, _ := strings.Cut(param, "=")
+		if strings.HasPrefix(value, "{{") {
+			return key
+		}
+	}
+	return ""
+}
+
 func (r Resource) TestSampleSetUp(sysfs fs.FS) {
 	res := make(map[string]string)
 	for _, sample := range r.Samples {
@@ -2479,6 +3061,33 @@ func (r *Resource) ShouldGenerateSingularDataSourceTests() bool {
 	return !r.Datasource.ExcludeTest
 }
 
+func (r *Resource) ShouldGenerateEphemeral() bool {
+	return r.Ephemeral != nil && r.Ephemeral.Generate
+}
+
+func (r *Resource) ShouldGenerateEphemeralTests() bool {
+	return r.ShouldGenerateEphemeral() && !r.Ephemeral.ExcludeTest
+}
+
+// EphemeralArguments returns the properties that appear in the read URL,
+// which configure the ephemeral resource.
+func (r Resource) EphemeralArguments() []*Type {
+	identifiers := r.ExtractIdentifiers(r.SelfLinkUri())
+	return google.Select(r.AllUserProperties(), func(p *Type) bool {
+		name := google.Underscore(p.Name)
+		return slices.Contains(identifiers, name) && !(name == "project" && r.HasProject())
+	})
+}
+
+// EphemeralAttributes returns the properties read from the API that make up
+// the result of the ephemeral resource.
+func (r Resource) EphemeralAttributes() []*Type {
+	arguments := r.EphemeralArguments()
+	return google.Reject(r.GettableProperties(), func(p *Type) bool {
+		return slices.Contains(arguments, p) || p.ClientSide
+	})
+}
+
 func (r Resource) ShouldDatasourceSetLabels() bool {
 	for _, p := range r.Properties {
 		if p.Name == "labels" && p.Type == "KeyValueLabels" {
@@ -0,0 +1,90 @@
+// Copyright 2025 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Ephemeral configures a Plugin Framework ephemeral resource generated
+// alongside the resource. The ephemeral resource reads the resource from the
+// API when Terraform opens it, and its result is never persisted to the plan
+// or state. Use it for secret-like values such as secret versions or
+// generated keys.
+type Ephemeral struct {
+	// boolean to determine whether the ephemeral resource file should be generated
+	Generate bool `yaml:"generate"`
+
+	// boolean to determine whether unit tests should be generated for the
+	// ephemeral resource
+	ExcludeTest bool `yaml:"exclude_test"`
+
+	// How long Terraform can use the result before the ephemeral resource must
+	// be renewed, as a Go duration. Requires `renew_url`.
+	// i.e. `55m`
+	RenewInterval string `yaml:"renew_interval"`
+
+	// The URL called to renew the ephemeral resource, relative to the product
+	// base URL. Terraform field names enclosed in double curly braces are
+	// replaced with the values the ephemeral resource was opened with.
+	RenewUrl string `yaml:"renew_url"`
+
+	// The HTTP verb used to renew the ephemeral resource. Defaults to POST.
+	RenewVerb string `yaml:"renew_verb"`
+
+	// The URL called when Terraform closes the ephemeral resource, relative to
+	// the product base URL, e.g. to revoke a credential.
+	CloseUrl string `yaml:"close_url"`
+
+	// The HTTP verb used to close the ephemeral resource. Defaults to DELETE.
+	CloseVerb string `yaml:"close_verb"`
+}
+
+func (e *Ephemeral) SetDefault() {
+	if e.RenewUrl != "" && e.RenewVerb == "" {
+		e.RenewVerb = "POST"
+	}
+	if e.CloseUrl != "" && e.CloseVerb == "" {
+		e.CloseVerb = "DELETE"
+	}
+}
+
+// RenewIntervalSeconds returns the renew interval in whole seconds.
+func (e Ephemeral) RenewIntervalSeconds() int64 {
+	d, _ := time.ParseDuration(e.RenewInterval)
+	return int64(d / time.Second)
+}
+
+func (e *Ephemeral) Validate(rName string) (es []error) {
+	if (e.RenewInterval == "") != (e.RenewUrl == "") {
+		es = append(es, fmt.Errorf("`renew_interval` and `renew_url` must be set together for `ephemeral` in resource %s", rName))
+	}
+	if e.RenewInterval != "" {
+		if d, err := time.ParseDuration(e.RenewInterval); err != nil || d < time.Second {
+			es = append(es, fmt.Errorf("`renew_interval` for `ephemeral` in resource %s must be a duration of at least a second, got %q", rName, e.RenewInterval))
+		}
+	}
+
+	allowed := []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	if e.RenewVerb != "" && !slices.Contains(allowed, e.RenewVerb) {
+		es = append(es, fmt.Errorf("value on `renew_verb` for `ephemeral` in resource %s should be one of %#v", rName, allowed))
+	}
+	if e.CloseVerb != "" && !slices.Contains(allowed, e.CloseVerb) {
+		es = append(es, fmt.Errorf("value on `close_verb` for `ephemeral` in resource %s should be one of %#v", rName, allowed))
+	}
+
+	return es
+}
@@ -92,7 +92,7 @@ type IamPolicy struct {
 	CustomDiffSuppress *string `yaml:"custom_diff_suppress,omitempty"`
 
 	// ImportStateIDFuncs may use a custom template if default funcs don't work.
-	CustomImportStateIDFuncs string `yaml:"custom_import_state_id_funcs"`
+	CustomImportStateIDFuncs string `yaml:"custom_import_state_id_funcs,omitempty"`
 
 	// Some resources (IAP) use fields named differently from the parent resource.
 	// We need to use the parent's attributes to create an IAM policy, but they may not be
@@ -153,6 +153,12 @@ func newIamPolicyWithDefaults() IamPolicy {
 	}
 }
 
+// NewIamPolicy returns an IamPolicy object with default values set.
+func NewIamPolicy() *IamPolicy {
+	p := newIamPolicyWithDefaults()
+	return &p
+}
+
 // UnmarshalYAML implements a custom unmarshaler for the IamPolicy struct.
 // It sets default values and then decodes the YAML over them.
 func (p *IamPolicy) UnmarshalYAML(value *yaml.Node) error {
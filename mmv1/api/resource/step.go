@@ -191,33 +191,50 @@ func validateRegexForContents(r *regexp.Regexp, contents string, configPath stri
 	}
 }
 
+// docsEnvVarDefaults are placeholder values for test_env_vars, used in
+// documentation and in contract tests.
+var docsEnvVarDefaults = map[string]string{
+	"PROJECT_NAME":         "my-project-name",
+	"PROJECT_NUMBER":       "1111111111111",
+	"CREDENTIALS":          "my/credentials/filename.json",
+	"REGION":               "us-west1",
+	"ORG_ID":               "123456789",
+	"ORG_DOMAIN":           "example.com",
+	"ORG_TARGET":           "123456789",
+	"BILLING_ACCT":         "000000-0000000-0000000-000000",
+	"MASTER_BILLING_ACCT":  "000000-0000000-0000000-000000",
+	"SERVICE_ACCT":         "my@service-account.com",
+	"CUST_ID":              "A01b123xz",
+	"IDENTITY_USER":        "cloud_identity_user",
+	"PAP_DESCRIPTION":      "description",
+	"CHRONICLE_ID":         "00000000-0000-0000-0000-000000000000",
+	"VMWAREENGINE_PROJECT": "my-vmwareengine-project",
+}
+
+// ContractTestEnvVars returns placeholder values for the step's
+// test_env_vars, for use in contract tests that don't read the environment.
+func (s *Step) ContractTestEnvVars() map[string]string {
+	vars := make(map[string]string, len(s.TestEnvVars))
+	for key, envVar := range s.TestEnvVars {
+		if v, ok := docsEnvVarDefaults[envVar]; ok {
+			vars[key] = v
+		} else {
+			vars[key] = strings.ToLower(strings.ReplaceAll(envVar, "_", "-"))
+		}
+	}
+	return vars
+}
+
 // Executes step configuration templates for documentation and tests
 func (s *Step) SetHCLText(sysfs fs.FS) {
 	originalResourceIdVars := s.ResourceIdVars
 	originalVars := s.Vars
 	originalTestEnvVars := s.TestEnvVars
 	docTestEnvVars := make(map[string]string)
-	docs_defaults := map[string]string{
-		"PROJECT_NAME":         "my-project-name",
-		"PROJECT_NUMBER":       "1111111111111",
-		"CREDENTIALS":          "my/credentials/filename.json",
-		"REGION":               "us-west1",
-		"ORG_ID":               "123456789",
-		"ORG_DOMAIN":           "example.com",
-		"ORG_TARGET":           "123456789",
-		"BILLING_ACCT":         "000000-0000000-0000000-000000",
-		"MASTER_BILLING_ACCT":  "000000-0000000-0000000-000000",
-		"SERVICE_ACCT":         "my@service-account.com",
-		"CUST_ID":              "A01b123xz",
-		"IDENTITY_USER":        "cloud_identity_user",
-		"PAP_DESCRIPTION":      "description",
-		"CHRONICLE_ID":         "00000000-0000-0000-0000-000000000000",
-		"VMWAREENGINE_PROJECT": "my-vmwareengine-project",
-	}
 
 	// Apply doc defaults to test_env_vars from YAML
 	for key := range s.TestEnvVars {
-		docTestEnvVars[key] = docs_defaults[s.TestEnvVars[key]]
+		docTestEnvVars[key] = docsEnvVarDefaults[s.TestEnvVars[key]]
 	}
 	s.TestEnvVars = docTestEnvVars
 	s.DocumentationHCLText = s.ExecuteTemplate(sysfs)
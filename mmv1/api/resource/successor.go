@@ -0,0 +1,53 @@
+// Copyright 2025 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+)
+
+// Successor names the resource that replaces a deprecated resource. The
+// successor accepts the deprecated resource's state through a Terraform
+// `moved` block, so users can switch resources without rewriting their state.
+type Successor struct {
+	// The name of the replacing resource, as in its `name` field.
+	// i.e. `Instance`
+	Resource string
+
+	// The product directory of the replacing resource, if it is in a
+	// different product.
+	// i.e. `compute`
+	Product string
+
+	// Maps the top-level fields of this resource to fields of the successor,
+	// both by their Terraform name. Fields that aren't listed keep their name.
+	// Map a field to an empty string to drop it from the moved state.
+	// i.e. `{ "instance_name": "name", "legacy_flag": "" }`
+	FieldMapping map[string]string `yaml:"field_mapping"`
+
+	// Maps top-level String fields of the successor to the format of their
+	// value in the moved state, for fields like `name` that embed the
+	// resource's path. Formats refer to other top-level fields of the
+	// successor. The moved `id` always follows the successor's id format.
+	// i.e. `{ "name": "projects/{{project}}/locations/{{location}}/instances/{{instance_id}}" }`
+	FieldFormats map[string]string `yaml:"field_formats"`
+}
+
+func (s *Successor) Validate(rName string) (es []error) {
+	if s.Resource == "" {
+		es = append(es, fmt.Errorf("missing `resource` for `successor` in resource %s", rName))
+	}
+
+	return es
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"runtime"
 	"slices"
 	"strings"
@@ -1195,3 +1196,696 @@ func TestSamplePrimaryResourceId(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateFrameworkResource(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		modify      func(r *api.Resource)
+		wantErrs    []string
+	}{
+		{
+			description: "supported resource has no framework errors",
+			modify:      func(r *api.Resource) {},
+		},
+		{
+			description: "resource-level features are rejected",
+			modify: func(r *api.Resource) {
+				r.ExcludeRead = true
+				r.CustomDiff = []string{"tpgresource.DefaultProviderProject"}
+			},
+			wantErrs: []string{
+				"`exclude_read` is not supported by plugin_framework_experimental on resource Widget",
+				"`custom_diff` is not supported by plugin_framework_experimental on resource Widget",
+			},
+		},
+		{
+			description: "property-level features are rejected",
+			modify: func(r *api.Resource) {
+				r.Properties[0].DiffSuppressFunc = "tpgresource.CaseDiffSuppress"
+			},
+			wantErrs: []string{
+				"property display_name: `diff_suppress_func` is not supported by plugin_framework_experimental on resource Widget",
+			},
+		},
+		{
+			description: "validation, conflicts and custom code are supported",
+			modify: func(r *api.Resource) {
+				r.Properties[0].Validation.Regex = "^[a-z]+$"
+				r.Properties[0].Conflicts = []string{"description"}
+				r.Properties[0].CustomExpand = "templates/terraform/custom_expand/widget_fw.go.tmpl"
+				r.Properties[0].CustomFlatten = "templates/terraform/custom_flatten/widget_fw.go.tmpl"
+			},
+		},
+		{
+			description: "arrays of maps are rejected",
+			modify: func(r *api.Resource) {
+				r.Properties[0].Type = "Array"
+				r.Properties[0].ItemType = &api.Type{Type: "KeyValuePairs", ResourceMetadata: r}
+			},
+			wantErrs: []string{
+				"property display_name: an array of maps is not supported by plugin_framework_experimental on resource Widget",
+			},
+		},
+		{
+			description: "labels diffs are planned by the template",
+			modify: func(r *api.Resource) {
+				r.CustomDiff = []string{"tpgresource.SetLabelsDiff", "tpgresource.SetAnnotationsDiff"}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &api.Resource{
+				Name:              "Widget",
+				Description:       "A widget.",
+				BaseUrl:           "projects/{{project}}/widgets",
+				CreateVerb:        "POST",
+				ReadVerb:          "GET",
+				UpdateVerb:        "PATCH",
+				DeleteVerb:        "DELETE",
+				FrameworkResource: true,
+				ProductMetadata:   &api.Product{Name: "Test"},
+			}
+			r.Properties = []*api.Type{
+				{
+					Name:             "displayName",
+					Type:             "String",
+					Description:      "The display name.",
+					ResourceMetadata: r,
+				},
+			}
+			tc.modify(r)
+
+			var got []string
+			for _, err := range r.Validate() {
+				if strings.Contains(err.Error(), "plugin_framework_experimental") {
+					got = append(got, err.Error())
+				}
+			}
+			if diff := cmp.Diff(tc.wantErrs, got); diff != "" {
+				t.Errorf("Validate() framework errors unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateTgcNextResource(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		modify      func(r *api.Resource)
+		wantErrs    []string
+	}{
+		{
+			description: "custom_tgc_expand with custom_tgc_flatten",
+			modify: func(r *api.Resource) {
+				r.Properties[0].CustomTgcExpand = "templates/tgc_next/custom_expand/widget.go.tmpl"
+				r.Properties[0].CustomTgcFlatten = "templates/tgc_next/custom_flatten/widget.go.tmpl"
+			},
+		},
+		{
+			description: "custom_tgc_expand with custom_flatten",
+			modify: func(r *api.Resource) {
+				r.Properties[0].CustomTgcExpand = "templates/tgc_next/custom_expand/widget.go.tmpl"
+				r.Properties[0].CustomFlatten = "templates/terraform/custom_flatten/widget.go.tmpl"
+			},
+		},
+		{
+			description: "custom_tgc_expand on a url_param_only property",
+			modify: func(r *api.Resource) {
+				r.Properties[0].CustomTgcExpand = "templates/tgc_next/custom_expand/widget.go.tmpl"
+				r.Properties[0].UrlParamOnly = true
+			},
+		},
+		{
+			description: "custom_tgc_expand on a ResourceRef",
+			modify: func(r *api.Resource) {
+				r.Properties[0].Type = "ResourceRef"
+				r.Properties[0].Resource = "Gadget"
+				r.Properties[0].CustomTgcExpand = "templates/terraform/custom_expand/resourceref_with_validation.go.tmpl"
+			},
+		},
+		{
+			description: "custom_tgc_expand without an inverse",
+			modify: func(r *api.Resource) {
+				r.Properties[0].CustomTgcExpand = "templates/tgc_next/custom_expand/widget.go.tmpl"
+			},
+			wantErrs: []string{
+				"property display_name sets `custom_tgc_expand` without `custom_tgc_flatten` to convert it back to HCL in resource Widget",
+			},
+		},
+		{
+			description: "custom_tgc_expand with an ignored custom_flatten",
+			modify: func(r *api.Resource) {
+				r.Properties[0].CustomTgcExpand = "templates/tgc_next/custom_expand/widget.go.tmpl"
+				r.Properties[0].CustomFlatten = "templates/terraform/custom_flatten/widget.go.tmpl"
+				r.Properties[0].TGCIgnoreTerraformCustomFlatten = true
+			},
+			wantErrs: []string{
+				"property display_name sets `custom_tgc_expand` without `custom_tgc_flatten` to convert it back to HCL in resource Widget",
+			},
+		},
+		{
+			description: "resource not included in tgc next",
+			modify: func(r *api.Resource) {
+				r.IncludeInTGCNext = false
+				r.Properties[0].CustomTgcExpand = "templates/tgc_next/custom_expand/widget.go.tmpl"
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &api.Resource{
+				Name:            "Widget",
+				Description:     "A widget.",
+				BaseUrl:         "projects/{{project}}/widgets",
+				CreateVerb:      "POST",
+				ReadVerb:        "GET",
+				UpdateVerb:      "PATCH",
+				DeleteVerb:      "DELETE",
+				ProductMetadata: &api.Product{Name: "Test"},
+			}
+			r.IncludeInTGCNext = true
+			r.Properties = []*api.Type{
+				{
+					Name:             "displayName",
+					Type:             "String",
+					Description:      "The display name.",
+					ResourceMetadata: r,
+				},
+			}
+			tc.modify(r)
+
+			var got []string
+			for _, err := range r.Validate() {
+				if strings.Contains(err.Error(), "custom_tgc_expand") {
+					got = append(got, err.Error())
+				}
+			}
+			if diff := cmp.Diff(tc.wantErrs, got); diff != "" {
+				t.Errorf("Validate() tgc next errors unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateSuccessor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		modify      func(predecessor, successor *api.Resource)
+		wantErrs    []string
+	}{
+		{
+			description: "fields map by name or through the mapping",
+			modify:      func(predecessor, successor *api.Resource) {},
+		},
+		{
+			description: "successor must be found",
+			modify: func(predecessor, successor *api.Resource) {
+				predecessor.SuccessorResource = nil
+			},
+			wantErrs: []string{"successor Widget of resource LegacyWidget not found"},
+		},
+		{
+			description: "successor must use the plugin framework",
+			modify: func(predecessor, successor *api.Resource) {
+				successor.FrameworkResource = false
+			},
+			wantErrs: []string{"successor Widget of resource LegacyWidget must set `plugin_framework_experimental`"},
+		},
+		{
+			description: "mapping must name fields of both resources",
+			modify: func(predecessor, successor *api.Resource) {
+				predecessor.Successor.FieldMapping["missing"] = "name"
+				predecessor.Successor.FieldMapping["size"] = "missing"
+			},
+			wantErrs: []string{
+				"`successor.field_mapping` on resource LegacyWidget maps unknown field missing",
+				"`successor.field_mapping` on resource LegacyWidget maps size to unknown field missing of Widget",
+				"field size of resource LegacyWidget has no counterpart on its successor Widget; add it to `successor.field_mapping`, mapping it to \"\" to drop it",
+			},
+		},
+		{
+			description: "unmapped fields must exist on the successor",
+			modify: func(predecessor, successor *api.Resource) {
+				delete(predecessor.Successor.FieldMapping, "legacy_flag")
+			},
+			wantErrs: []string{"field legacy_flag of resource LegacyWidget has no counterpart on its successor Widget; add it to `successor.field_mapping`, mapping it to \"\" to drop it"},
+		},
+		{
+			description: "types must be compatible",
+			modify: func(predecessor, successor *api.Resource) {
+				successor.Properties[1].Type = "String"
+			},
+			wantErrs: []string{"field size of resource LegacyWidget has type Integer, but size of its successor Widget has type String"},
+		},
+		{
+			description: "formats build String fields from String fields",
+			modify: func(predecessor, successor *api.Resource) {
+				predecessor.Successor.FieldFormats = map[string]string{
+					"name": "projects/{{project}}/widgets/{{widget_id}}",
+					"size": "projects/{{project}}/widgets/{{size}}",
+				}
+			},
+			wantErrs: []string{
+				"`successor.field_formats` on resource LegacyWidget builds name from widget_id, which is not a String field of Widget",
+				"`successor.field_formats` on resource LegacyWidget formats size, which is not a String field of Widget",
+				"`successor.field_formats` on resource LegacyWidget builds size from size, which is not a String field of Widget",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			newResource := func(name string, props ...*api.Type) *api.Resource {
+				r := &api.Resource{
+					Name:            name,
+					Description:     "A widget.",
+					BaseUrl:         "projects/{{project}}/widgets",
+					CreateVerb:      "POST",
+					ReadVerb:        "GET",
+					UpdateVerb:      "PATCH",
+					DeleteVerb:      "DELETE",
+					ProductMetadata: &api.Product{Name: "Test"},
+					Properties:      props,
+				}
+				for _, p := range props {
+					p.Description = "A field."
+					p.ResourceMetadata = r
+				}
+				return r
+			}
+			predecessor := newResource("LegacyWidget",
+				&api.Type{Name: "widgetName", Type: "String"},
+				&api.Type{Name: "size", Type: "Integer"},
+				&api.Type{Name: "legacyFlag", Type: "Boolean"},
+			)
+			successor := newResource("Widget",
+				&api.Type{Name: "name", Type: "String"},
+				&api.Type{Name: "size", Type: "Integer"},
+			)
+			successor.FrameworkResource = true
+			predecessor.Successor = &resource.Successor{
+				Resource:     "Widget",
+				FieldMapping: map[string]string{"widget_name": "name", "legacy_flag": ""},
+			}
+			predecessor.SuccessorResource = successor
+			tc.modify(predecessor, successor)
+
+			var got []string
+			for _, err := range predecessor.Validate() {
+				if strings.Contains(err.Error(), "successor") {
+					got = append(got, err.Error())
+				}
+			}
+			if diff := cmp.Diff(tc.wantErrs, got); diff != "" {
+				t.Errorf("Validate() successor errors unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMoveStateDefaultFields(t *testing.T) {
+	t.Parallel()
+
+	r := &api.Resource{
+		Name: "Widget",
+		Parameters: []*api.Type{
+			{Name: "location", Type: "String", DefaultValue: "global"},
+		},
+		Properties: []*api.Type{
+			{Name: "name", Type: "String"},
+			{Name: "size", Type: "Integer", DefaultValue: 3},
+			{Name: "state", Type: "String", DefaultValue: "ACTIVE", Output: true},
+			{Name: "tags", Type: "Array", ItemType: &api.Type{Type: "String"}, DefaultValue: []string{"a"}},
+		},
+	}
+
+	var got []string
+	for _, p := range r.MoveStateDefaultFields() {
+		got = append(got, p.Name)
+	}
+	if diff := cmp.Diff([]string{"size", "location"}, got); diff != "" {
+		t.Errorf("MoveStateDefaultFields() unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestFrameworkImports(t *testing.T) {
+	t.Parallel()
+
+	r := &api.Resource{
+		Name:                      "Widget",
+		BaseUrl:                   "projects/{{project}}/widgets",
+		CreateUrl:                 "projects/{{project}}/widgets",
+		Immutable:                 true,
+		ExcludeImport:             true,
+		ExcludeIdentityGeneration: true,
+	}
+	r.Properties = []*api.Type{
+		{Name: "tier", Type: "Enum", EnumValues: []string{"BASIC", "PREMIUM"}, SendEmptyValue: true},
+		{Name: "schedule", Type: "NestedObject", SendEmptyValue: true, Properties: []*api.Type{
+			{Name: "cronSpec", Type: "String", SendEmptyValue: true},
+		}},
+	}
+	r.SetDefault(&api.Product{Name: "Test"})
+
+	var got []string
+	for pkg := range r.FrameworkImports() {
+		got = append(got, pkg)
+	}
+	slices.Sort(got)
+	want := []string{"attr", "fwresource", "listplanmodifier", "listvalidator", "stringplanmodifier", "stringvalidator", "time", "validator"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("FrameworkImports() unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateEphemeral(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		modify      func(r *api.Resource)
+		wantErrs    []string
+	}{
+		{
+			description: "renew and close",
+			modify:      func(r *api.Resource) {},
+		},
+		{
+			description: "renew_interval requires renew_url",
+			modify: func(r *api.Resource) {
+				r.Ephemeral.RenewUrl = ""
+				r.Ephemeral.RenewVerb = ""
+			},
+			wantErrs: []string{"`renew_interval` and `renew_url` must be set together for `ephemeral` in resource Token"},
+		},
+		{
+			description: "renew_interval must be a duration of at least a second",
+			modify: func(r *api.Resource) {
+				r.Ephemeral.RenewInterval = "5ms"
+			},
+			wantErrs: []string{"`renew_interval` for `ephemeral` in resource Token must be a duration of at least a second, got \"5ms\""},
+		},
+		{
+			description: "verbs must be HTTP verbs",
+			modify: func(r *api.Resource) {
+				r.Ephemeral.CloseVerb = "REVOKE"
+			},
+			wantErrs: []string{"value on `close_verb` for `ephemeral` in resource Token should be one of []string{\"GET\", \"POST\", \"PUT\", \"PATCH\", \"DELETE\"}"},
+		},
+		{
+			description: "custom decoder",
+			modify: func(r *api.Resource) {
+				r.CustomCode.Decoder = "templates/terraform/decoders/treat_deleted_state_as_gone.go.tmpl"
+			},
+		},
+		{
+			description: "URL parameters must be strings",
+			modify: func(r *api.Resource) {
+				r.Parameters[0].Type = "Boolean"
+			},
+			wantErrs: []string{"URL parameter name of type Boolean is not supported by `ephemeral` on resource Token"},
+		},
+		{
+			description: "unsupported features",
+			modify: func(r *api.Resource) {
+				r.NestedQuery = &resource.NestedQuery{Keys: []string{"tokens"}}
+				r.Parameters[0].Type = "Integer"
+				r.Properties[0].CustomFlatten = "templates/terraform/custom_flatten/token.go.tmpl"
+			},
+			wantErrs: []string{
+				"`nested_query` is not supported by `ephemeral` on resource Token",
+				"URL parameter name of type Integer is not supported by `ephemeral` on resource Token",
+				"`custom_flatten` on property secret is not supported by `ephemeral` on resource Token",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := &api.Resource{
+				Name:            "Token",
+				Description:     "A token.",
+				BaseUrl:         "projects/{{project}}/tokens",
+				SelfLink:        "projects/{{project}}/tokens/{{name}}",
+				CreateVerb:      "POST",
+				ReadVerb:        "GET",
+				UpdateVerb:      "PATCH",
+				DeleteVerb:      "DELETE",
+				ProductMetadata: &api.Product{Name: "Test"},
+				Parameters: []*api.Type{
+					{Name: "name", Type: "String", UrlParamOnly: true, Required: true},
+				},
+				Properties: []*api.Type{
+					{Name: "secret", Type: "String", Output: true, Sensitive: true},
+				},
+				Ephemeral: &resource.Ephemeral{
+					Generate:      true,
+					RenewInterval: "55m",
+					RenewUrl:      "projects/{{project}}/tokens/{{name}}:refresh",
+					RenewVerb:     "POST",
+					CloseUrl:      "projects/{{project}}/tokens/{{name}}",
+					CloseVerb:     "DELETE",
+				},
+			}
+			for _, p := range append(r.Parameters, r.Properties...) {
+				p.Description = "A field."
+				p.ResourceMetadata = r
+			}
+			tc.modify(r)
+
+			var got []string
+			for _, err := range r.Validate() {
+				if strings.Contains(err.Error(), "ephemeral") {
+					got = append(got, err.Error())
+				}
+			}
+			if diff := cmp.Diff(tc.wantErrs, got); diff != "" {
+				t.Errorf("Validate() ephemeral errors unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEphemeralFields(t *testing.T) {
+	t.Parallel()
+
+	r := &api.Resource{
+		Name:     "Token",
+		SelfLink: "projects/{{project}}/locations/{{location}}/tokens/{{name}}",
+		Parameters: []*api.Type{
+			{Name: "location", Type: "String", UrlParamOnly: true},
+			{Name: "name", Type: "String", UrlParamOnly: true},
+		},
+		Properties: []*api.Type{
+			{Name: "secret", Type: "String", Output: true},
+			{Name: "scratch", Type: "String", ClientSide: true},
+		},
+		ProductMetadata: &api.Product{Name: "Test"},
+	}
+	for _, p := range append(r.Parameters, r.Properties...) {
+		p.ResourceMetadata = r
+	}
+
+	var args, attrs []string
+	for _, p := range r.EphemeralArguments() {
+		args = append(args, p.Name)
+	}
+	for _, p := range r.EphemeralAttributes() {
+		attrs = append(attrs, p.Name)
+	}
+	if diff := cmp.Diff([]string{"location", "name"}, args); diff != "" {
+		t.Errorf("EphemeralArguments() unexpected diff (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"secret"}, attrs); diff != "" {
+		t.Errorf("EphemeralAttributes() unexpected diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestContractIdQueryParam(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		createUrl   string
+		want        string
+	}{
+		{
+			description: "no create_url",
+			want:        "",
+		},
+		{
+			description: "id in query",
+			createUrl:   "projects/{{project}}/locations/{{location}}/urlLists?urlListId={{name}}",
+			want:        "urlListId",
+		},
+		{
+			description: "id after a literal parameter",
+			createUrl:   "projects/{{project}}/widgets?validateOnly=false&widgetId={{widget_id}}",
+			want:        "widgetId",
+		},
+		{
+			description: "only literal parameters",
+			createUrl:   "projects/{{project}}/widgets?validateOnly=false",
+			want:        "",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := api.Resource{
+				BaseUrl:   "projects/{{project}}/widgets",
+				CreateUrl: tc.createUrl,
+			}
+			if got := r.ContractIdQueryParam(); got != tc.want {
+				t.Errorf("ContractIdQueryParam() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIdFunctionFormats(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description  string
+		idFormat     string
+		importFormat []string
+		want         []string
+	}{
+		{
+			description:  "import formats with the same fields",
+			idFormat:     "projects/{{project}}/zones/{{zone}}/instances/{{name}}",
+			importFormat: []string{"projects/{{project}}/zones/{{zone}}/instances/{{name}}"},
+			want: []string{
+				"projects/{{project}}/zones/{{zone}}/instances/{{name}}",
+				"{{project}}/{{zone}}/{{name}}",
+			},
+		},
+		{
+			description:  "short id format",
+			idFormat:     "{{project}}/{{name}}",
+			importFormat: []string{"projects/{{project}}/topics/{{name}}"},
+			want: []string{
+				"{{project}}/{{name}}",
+				"projects/{{project}}/topics/{{name}}",
+			},
+		},
+		{
+			description:  "multi-segment field",
+			idFormat:     "projects/{{project}}/docs/{{%path}}",
+			importFormat: []string{"projects/{{project}}/docs/{{%path}}"},
+			want:         []string{"projects/{{project}}/docs/{{%path}}"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			r := api.Resource{
+				IdFormat:     tc.idFormat,
+				ImportFormat: tc.importFormat,
+			}
+			if diff := cmp.Diff(tc.want, r.IdFunctionFormats()); diff != "" {
+				t.Errorf("IdFunctionFormats() unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIdFunctionPattern(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description string
+		format      string
+		input       string
+		want        map[string]string
+	}{
+		{
+			description: "id",
+			format:      "projects/{{project}}/topics/{{name}}",
+			input:       "projects/my-project/topics/my-topic",
+			want:        map[string]string{"project": "my-project", "name": "my-topic"},
+		},
+		{
+			description: "self link",
+			format:      "projects/{{project}}/topics/{{name}}",
+			input:       "https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic",
+			want:        map[string]string{"project": "my-project", "name": "my-topic"},
+		},
+		{
+			description: "trailing segments",
+			format:      "projects/{{project}}/topics/{{name}}",
+			input:       "projects/my-project/topics/my-topic/subscriptions/my-subscription",
+		},
+		{
+			description: "short id",
+			format:      "{{project}}/{{name}}",
+			input:       "my-project/my-topic",
+			want:        map[string]string{"project": "my-project", "name": "my-topic"},
+		},
+		{
+			description: "short id with a prefix",
+			format:      "{{project}}/{{name}}",
+			input:       "projects/my-project/my-topic",
+		},
+		{
+			description: "multi-segment field",
+			format:      "projects/{{project}}/docs/{{%path}}",
+			input:       "projects/my-project/docs/a/b/c",
+			want:        map[string]string{"project": "my-project", "path": "a/b/c"},
+		},
+		{
+			description: "literal characters",
+			format:      "projects/{{project}}/models/{{name}}.v1",
+			input:       "projects/my-project/models/my-modelxv1",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			re := regexp.MustCompile(api.Resource{}.IdFunctionPattern(tc.format))
+			var got map[string]string
+			if submatches := re.FindStringSubmatch(tc.input); submatches != nil {
+				got = map[string]string{}
+				for i, name := range re.SubexpNames() {
+					if name != "" {
+						got[name] = submatches[i]
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("IdFunctionPattern(%q) matched %q with unexpected diff (-want +got):\n%s", tc.format, tc.input, diff)
+			}
+		})
+	}
+}
@@ -26,6 +26,9 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// The values a property's `type` may take.
+var PropertyTypes = []string{"Boolean", "Double", "Integer", "String", "Time", "Enum", "ResourceRef", "NestedObject", "Array", "KeyValuePairs", "KeyValueLabels", "KeyValueTerraformLabels", "KeyValueEffectiveLabels", "KeyValueAnnotations", "Map", "Fingerprint"}
+
 // Represents a property type
 type Type struct {
 	Name string `yaml:"name,omitempty"`
@@ -504,7 +507,7 @@ func (t *Type) Validate(rName string) (es []error) {
 	}
 
 	// Check type is valid. Also allow empty as it's currently used in unit tests.
-	if !slices.Contains([]string{"Boolean", "Double", "Integer", "String", "Time", "Enum", "ResourceRef", "NestedObject", "Array", "KeyValuePairs", "KeyValueLabels", "KeyValueTerraformLabels", "KeyValueEffectiveLabels", "KeyValueAnnotations", "Map", "Fingerprint"}, t.Type) {
+	if !slices.Contains(PropertyTypes, t.Type) {
 		es = append(es, fmt.Errorf("property %s unknown type %q in resource %s", fullFieldPath, t.Type, rName))
 	}
 
@@ -554,6 +557,73 @@ func (t *Type) Validate(rName string) (es []error) {
 	return es
 }
 
+// validateFramework rejects property features that the plugin framework
+// resource template doesn't implement.
+func (t Type) validateFramework(rName string) (es []error) {
+	fullFieldPath := strings.Join(t.Lineage(), ".")
+	unsupported := func(feature string) {
+		es = append(es, fmt.Errorf("property %s: %s is not supported by plugin_framework_experimental on resource %s", fullFieldPath, feature, rName))
+	}
+
+	if t.IsA("Map") {
+		unsupported("type `Map`")
+	}
+	if t.ParentMetadata != nil && (t.IsA("KeyValueLabels") || t.IsA("KeyValueTerraformLabels") || t.IsA("KeyValueEffectiveLabels") || t.IsA("KeyValueAnnotations")) {
+		unsupported(fmt.Sprintf("type `%s` on a nested field", t.Type))
+	}
+	if t.IsA("Array") && t.ItemType.IsA("Array") {
+		unsupported("an array of arrays")
+	}
+	if t.IsA("Array") && strings.HasPrefix(t.ItemType.Type, "KeyValue") {
+		unsupported("an array of maps")
+	}
+	if t.FlattenObject {
+		unsupported("`flatten_object`")
+	}
+	if t.DiffSuppressFunc != "" {
+		unsupported("`diff_suppress_func`")
+	}
+	if t.StateFunc != "" {
+		unsupported("`state_func`")
+	}
+	if t.WriteOnly || t.WriteOnlyLegacy {
+		unsupported("`write_only`")
+	}
+	if t.UpdateUrl != "" {
+		unsupported("`update_url`")
+	}
+	if t.IgnoreRead && t.ParentMetadata != nil {
+		unsupported("`ignore_read` on a nested field")
+	}
+	if t.DefaultFromApi && t.IsFWBlock() {
+		unsupported("`default_from_api` on a nested block")
+	}
+
+	return es
+}
+
+// validateTgcNext rejects a `custom_tgc_expand` without an inverse flattener.
+// The CAI value it builds is converted back to HCL by `custom_tgc_flatten`, by
+// `custom_flatten` unless it's ignored, or by the default ResourceRef flattener
+// for self links. Values that aren't read back from CAI need no inverse.
+func (t Type) validateTgcNext(rName string) (es []error) {
+	if t.CustomTgcExpand == "" || t.CustomTgcFlatten != "" {
+		return es
+	}
+	if t.UrlParamOnly || t.IgnoreRead || t.TGCIgnoreRead || t.IsMissingInCai {
+		return es
+	}
+	if t.CustomFlatten != "" && !t.TGCIgnoreTerraformCustomFlatten {
+		return es
+	}
+	if t.IsA("ResourceRef") {
+		return es
+	}
+
+	fullFieldPath := strings.Join(t.Lineage(), ".")
+	return append(es, fmt.Errorf("property %s sets `custom_tgc_expand` without `custom_tgc_flatten` to convert it back to HCL in resource %s", fullFieldPath, rName))
+}
+
 // TODO rewrite: add validations
 // check :description, required: true
 // check :update_verb, allowed: %i[POST PUT PATCH NONE],
@@ -985,6 +1055,9 @@ func (t Type) GetFWType() string {
 	case "NestedObject":
 		return "Object"
 	case "Array":
+		if t.IsSet {
+			return "Set"
+		}
 		return "List"
 	case "KeyValuePairs":
 		return "Map"
@@ -1005,6 +1078,36 @@ func (t Type) GetFWType() string {
 	return "String"
 }
 
+// GetFWResourceType returns the type of the field in a plugin framework
+// resource model. Nested objects are single-element lists there, as they are
+// in the state of SDK resources.
+func (t Type) GetFWResourceType() string {
+	if t.IsA("NestedObject") {
+		return "List"
+	}
+	return t.GetFWType()
+}
+
+// IsFWBlock reports whether a plugin framework resource declares the field as
+// a nested block, matching the block syntax of SDK resources. Output-only
+// nested fields are computed attributes instead, as blocks can't be computed.
+func (t Type) IsFWBlock() bool {
+	if !t.IsA("NestedObject") && !(t.IsA("Array") && t.ItemType.IsA("NestedObject")) {
+		return false
+	}
+	return !t.IsFWOutput()
+}
+
+// IsFWOutput reports whether the field or one of its parents is output-only.
+func (t Type) IsFWOutput() bool {
+	for p := &t; p != nil; p = p.ParentMetadata {
+		if p.Output {
+			return true
+		}
+	}
+	return false
+}
+
 // TODO rewrite: validation
 // // Represents an enum, and store is valid values
 // class Enum < Primitive
@@ -1545,6 +1648,33 @@ func (t Type) GetPropertySchemaPathList(propertyList []string) []string {
 	return list
 }
 
+// GetFWPathExpressionList returns the plugin framework path expressions, as Go
+// code, for the given Terraform field paths. Nested objects are single-element
+// lists in the framework, like arrays, so their ".0." segments become an
+// explicit list index.
+func (t Type) GetFWPathExpressionList(propertyList []string) []string {
+	var list []string
+	for _, schemaPath := range t.GetPropertySchemaPathList(propertyList) {
+		nestedProps := t.ResourceMetadata.UserProperites()
+		var expr strings.Builder
+		for i, pname := range strings.Split(schemaPath, ".0.") {
+			if i == 0 {
+				fmt.Fprintf(&expr, "path.MatchRoot(%q)", pname)
+			} else {
+				fmt.Fprintf(&expr, ".AtName(%q)", pname)
+			}
+			if prop := findPropByNameInFlattenedList(nestedProps, google.Camelize(pname, "lower")); prop != nil {
+				if prop.IsA("Array") || prop.IsA("NestedObject") {
+					expr.WriteString(".AtListIndex(0)")
+				}
+				nestedProps = prop.NestedProperties()
+			}
+		}
+		list = append(list, strings.TrimSuffix(expr.String(), ".AtListIndex(0)"))
+	}
+	return list
+}
+
 func (t Type) IsJsonField() bool {
 	if t.CustomFlatten == "templates/terraform/custom_flatten/json_schema.tmpl" {
 		return true
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestTypeMinVersionObj(t *testing.T) {
@@ -631,3 +632,56 @@ func TestProviderOnly(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFWPathExpressionList(t *testing.T) {
+	t.Parallel()
+
+	maxOne := 1
+	r := &Resource{
+		ProductMetadata: &Product{Name: "Test"},
+		Properties: []*Type{
+			{
+				Name: "displayName",
+				Type: "String",
+			},
+			{
+				Name: "schedule",
+				Type: "NestedObject",
+				Properties: []*Type{
+					{
+						Name: "cronSpec",
+						Type: "String",
+					},
+				},
+			},
+			{
+				Name:    "rules",
+				Type:    "Array",
+				MaxSize: &maxOne,
+				ItemType: &Type{
+					Type: "NestedObject",
+					Properties: []*Type{
+						{
+							Name: "action",
+							Type: "String",
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, p := range r.Properties {
+		p.SetDefault(r)
+	}
+
+	got := r.Properties[0].GetFWPathExpressionList([]string{"display_name", "schedule.0.cron_spec", "rules.0.action", "rules", "missing"})
+	want := []string{
+		`path.MatchRoot("display_name")`,
+		`path.MatchRoot("schedule").AtListIndex(0).AtName("cron_spec")`,
+		`path.MatchRoot("rules").AtListIndex(0).AtName("action")`,
+		`path.MatchRoot("rules")`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetFWPathExpressionList() unexpected diff (-want +got):\n%s", diff)
+	}
+}
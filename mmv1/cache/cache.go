@@ -0,0 +1,588 @@
+// Package cache lets the generator skip resources whose inputs have not
+// changed since the last run against the same output directory.
+//
+// Each resource is keyed by a hash of its inputs: its YAML (and any override),
+// the YAML of sibling resources it references, the YAML of the resources it
+// moves state to or from, and the templates it consumes (custom_code,
+// custom_expand, sample configs, ...). Inputs shared by every resource are
+// hashed into a single shared key: the generator binary, the flags, the
+// product.yaml files, the names of all resources and every other template. A
+// resource is fresh when both keys match the ones recorded in the manifest and
+// the files it generated last time are still on disk, unmodified.
+//
+// The manifest also records the inputs of each resource and the few fields
+// that provider-wide files read from it, so that a product whose resources are
+// all fresh is skipped before its resource YAML is even parsed.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+)
+
+// manifestVersion is bumped whenever the manifest format or the key
+// derivation changes, invalidating every existing manifest.
+const manifestVersion = 2
+
+type Config struct {
+	Dir               string // required; directory holding manifests
+	OutputPath        string // required
+	Version           string // required
+	Provider          string // optional
+	GenerateCode      bool
+	GenerateDocs      bool
+	BaseDirectory     string // required
+	OverrideDirectory string // optional
+	TemplateFS        fs.FS  // required; the filesystem templates are rendered from
+	// Salt identifies the generator. It defaults to a hash of the running
+	// binary, so that rebuilding the generator with changes invalidates the
+	// cache.
+	Salt string
+}
+
+// Cache tracks which resources need to be regenerated.
+type Cache struct {
+	config       Config
+	manifestPath string
+
+	// shared is the key of the inputs shared by every resource, computed by
+	// Open with the templates that the manifest's resources consume.
+	// sharedFresh reports whether it matches the manifest's.
+	shared      string
+	sharedFresh bool
+
+	// keys, inputs and fresh are computed once by Hash and only read
+	// afterwards.
+	keys   map[string]string
+	inputs map[string][]string
+	fresh  map[string]bool
+
+	mu           sync.Mutex
+	placeholders map[*api.Resource]bool
+	manifest     manifest
+	recorded     []*entry
+}
+
+type manifest struct {
+	Version   int               `json:"version"`
+	Shared    string            `json:"shared"`
+	Resources map[string]*entry `json:"resources"`
+}
+
+type entry struct {
+	Key string `json:"key"`
+	// Inputs lists the YAML files and templates hashed into Key.
+	Inputs []string `json:"inputs"`
+	// Files maps each generated file, relative to the output path, to the
+	// sha256 of its contents.
+	Files map[string]string `json:"files"`
+	// Resource is what a placeholder for the resource is made of.
+	Resource *summary `json:"resource"`
+}
+
+// summary holds the fields of a resource that are read beyond its own
+// generated files: by the provider-wide files listing every resource, by the
+// map of packages tests import resources from, and by its successor.
+type summary struct {
+	Name            string              `json:"name"`
+	LegacyName      string              `json:"legacyName,omitempty"`
+	MinVersion      string              `json:"minVersion,omitempty"`
+	Exclude         bool                `json:"exclude,omitempty"`
+	ExcludeResource bool                `json:"excludeResource,omitempty"`
+	IamPolicy       *iamPolicySummary   `json:"iamPolicy,omitempty"`
+	Successor       *resource.Successor `json:"successor,omitempty"`
+}
+
+type iamPolicySummary struct {
+	Exclude    bool   `json:"exclude,omitempty"`
+	MinVersion string `json:"minVersion,omitempty"`
+}
+
+func summarize(r *api.Resource) *summary {
+	s := &summary{
+		Name:            r.Name,
+		LegacyName:      r.LegacyName,
+		MinVersion:      r.MinVersion,
+		Exclude:         r.Exclude,
+		ExcludeResource: r.ExcludeResource,
+		Successor:       r.Successor,
+	}
+	if r.IamPolicy != nil {
+		s.IamPolicy = &iamPolicySummary{Exclude: r.IamPolicy.Exclude, MinVersion: r.IamPolicy.MinVersion}
+	}
+	return s
+}
+
+// placeholder returns a resource with only the summarized fields set.
+func (s *summary) placeholder(id string) *api.Resource {
+	r := &api.Resource{
+		Name:            s.Name,
+		LegacyName:      s.LegacyName,
+		MinVersion:      s.MinVersion,
+		Exclude:         s.Exclude,
+		ExcludeResource: s.ExcludeResource,
+		Successor:       s.Successor,
+		SourceYamlFile:  id,
+	}
+	if s.IamPolicy != nil {
+		r.IamPolicy = &resource.IamPolicy{Exclude: s.IamPolicy.Exclude, MinVersion: s.IamPolicy.MinVersion}
+	}
+	return r
+}
+
+// Open reads the manifest for the configured output, if there is one.
+func Open(config Config) (*Cache, error) {
+	if config.Dir == "" || config.OutputPath == "" || config.Version == "" || config.BaseDirectory == "" || config.TemplateFS == nil {
+		return nil, errors.New("cache: dir, output path, version, base directory and template filesystem are required")
+	}
+	outputPath, err := filepath.Abs(config.OutputPath)
+	if err != nil {
+		return nil, err
+	}
+	config.OutputPath = outputPath
+	if config.Salt == "" {
+		if config.Salt, err = executableHash(); err != nil {
+			return nil, fmt.Errorf("cache: hashing generator binary: %w", err)
+		}
+	}
+	if err := os.MkdirAll(config.Dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	// Keep a manifest per output and set of flags, so that the ga and beta
+	// providers or code-only and docs-only passes don't evict each other.
+	name := hashStrings(outputPath, config.Provider, config.Version, fmt.Sprint(config.GenerateCode), fmt.Sprint(config.GenerateDocs))
+	c := &Cache{
+		config:       config,
+		manifestPath: filepath.Join(config.Dir, name[:16]+".json"),
+		keys:         map[string]string{},
+		inputs:       map[string][]string{},
+		fresh:        map[string]bool{},
+		placeholders: map[*api.Resource]bool{},
+		manifest:     manifest{Version: manifestVersion, Resources: map[string]*entry{}},
+	}
+
+	data, err := os.ReadFile(c.manifestPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	var m manifest
+	// A missing, unreadable or outdated manifest just means a full generation.
+	if err == nil && json.Unmarshal(data, &m) == nil && m.Version == manifestVersion && m.Resources != nil {
+		c.manifest = m
+	}
+
+	owned := map[string]bool{}
+	for _, e := range c.manifest.Resources {
+		for _, f := range e.Inputs {
+			if !isYaml(f) {
+				owned[f] = true
+			}
+		}
+	}
+	if c.shared, err = c.sharedKey(owned); err != nil {
+		return nil, err
+	}
+	c.sharedFresh = c.shared == c.manifest.Shared
+	return c, nil
+}
+
+// ID identifies a resource in the manifest.
+func ID(r *api.Resource) string {
+	return r.SourceYamlFile
+}
+
+// Unchanged returns placeholders for the resources of the product at
+// packagePath if all of them are fresh, so that the product's resource YAML
+// doesn't need to be parsed. Placeholders only have the fields that other
+// resources and the provider-wide files read, and are never generated.
+func (c *Cache) Unchanged(packagePath string) ([]*api.Resource, bool) {
+	if !c.sharedFresh {
+		return nil, false
+	}
+	var resources []*api.Resource
+	for _, f := range c.resourceFiles(packagePath) {
+		e := c.manifest.Resources[f]
+		if e == nil || e.Resource == nil || !c.upToDate(e, c.inputKey(e.Inputs)) {
+			return nil, false
+		}
+		resources = append(resources, e.Resource.placeholder(f))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range resources {
+		c.placeholders[r] = true
+	}
+	return resources, true
+}
+
+// Placeholder reports whether r was returned by Unchanged rather than loaded.
+func (c *Cache) Placeholder(r *api.Resource) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.placeholders[r]
+}
+
+// Hash computes the key of every loaded resource and decides which of them
+// are fresh. It must be called once, after products are loaded and before
+// Fresh or Record.
+func (c *Cache) Hash(products map[string]*api.Product) error {
+	owned := map[string]bool{}
+	for _, p := range products {
+		for _, r := range p.Objects {
+			var inputs []string
+			if c.Placeholder(r) {
+				inputs = c.manifest.Resources[ID(r)].Inputs
+			} else {
+				inputs = c.resourceInputs(p, r)
+			}
+			c.inputs[ID(r)] = inputs
+			for _, f := range inputs {
+				if !isYaml(f) {
+					owned[f] = true
+				}
+			}
+		}
+	}
+
+	for _, p := range products {
+		for _, r := range p.Objects {
+			e := c.manifest.Resources[ID(r)]
+			if c.Placeholder(r) {
+				c.keys[ID(r)] = e.Key
+				c.fresh[ID(r)] = true
+				continue
+			}
+			key := c.inputKey(c.inputs[ID(r)])
+			c.keys[ID(r)] = key
+			c.fresh[ID(r)] = c.sharedFresh && c.upToDate(e, key)
+		}
+	}
+
+	// The templates that resources consume may have changed, and with them
+	// the templates hashed into the shared key.
+	shared, err := c.sharedKey(owned)
+	if err != nil {
+		return err
+	}
+	c.manifest.Shared = shared
+	return nil
+}
+
+// resourceInputs returns the YAML files and templates that r's key covers.
+func (c *Cache) resourceInputs(p *api.Product, r *api.Resource) []string {
+	inputs := []string{r.SourceYamlFile}
+	for _, sibling := range referencedResources(c.readYaml(r.SourceYamlFile)) {
+		inputs = append(inputs, path.Join(p.PackagePath, sibling+".yaml"))
+	}
+	// Moving state between a resource and its successor depends on the
+	// schemas of both.
+	if r.SuccessorResource != nil {
+		inputs = append(inputs, r.SuccessorResource.SourceYamlFile)
+	}
+	for _, predecessor := range r.Predecessors {
+		inputs = append(inputs, predecessor.SourceYamlFile)
+	}
+	return append(inputs, c.templateRefs(r)...)
+}
+
+// inputKey hashes the contents of inputs.
+func (c *Cache) inputKey(inputs []string) string {
+	h := sha256.New()
+	for _, f := range inputs {
+		if isYaml(f) {
+			writeField(h, f, c.readYaml(f))
+			continue
+		}
+		data, _ := fs.ReadFile(c.config.TemplateFS, f)
+		writeField(h, f, data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Fresh reports whether r's outputs are up to date and it can be skipped.
+func (c *Cache) Fresh(r *api.Resource) bool {
+	return c.fresh[ID(r)]
+}
+
+// Record stores the files generated for r, given as absolute paths or
+// relative to the output path. Files are hashed by Save, since other steps of
+// the generation may still overwrite them.
+func (c *Cache) Record(r *api.Resource, files []string) error {
+	key, ok := c.keys[ID(r)]
+	if !ok {
+		return fmt.Errorf("cache: %s was not hashed", ID(r))
+	}
+	e := &entry{Key: key, Inputs: c.inputs[ID(r)], Files: map[string]string{}, Resource: summarize(r)}
+	for _, f := range files {
+		if filepath.IsAbs(f) {
+			rel, err := filepath.Rel(c.config.OutputPath, f)
+			if err != nil {
+				return err
+			}
+			f = rel
+		}
+		e.Files[filepath.ToSlash(f)] = ""
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.manifest.Resources[ID(r)] = e
+	c.recorded = append(c.recorded, e)
+	return nil
+}
+
+// Save hashes the files recorded during this run and writes the manifest,
+// dropping resources that no longer exist.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.recorded {
+		for f := range e.Files {
+			sum, err := fileHash(filepath.Join(c.config.OutputPath, filepath.FromSlash(f)))
+			if err != nil {
+				return err
+			}
+			e.Files[f] = sum
+		}
+	}
+	c.recorded = nil
+	for id := range c.manifest.Resources {
+		if _, ok := c.keys[id]; !ok {
+			delete(c.manifest.Resources, id)
+		}
+	}
+	data, err := json.MarshalIndent(c.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write atomically so an interrupted run never leaves a truncated manifest.
+	tmp := c.manifestPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.manifestPath)
+}
+
+func (c *Cache) upToDate(e *entry, key string) bool {
+	if e == nil || e.Key != key {
+		return false
+	}
+	for f, want := range e.Files {
+		got, err := fileHash(filepath.Join(c.config.OutputPath, filepath.FromSlash(f)))
+		if err != nil || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// sharedKey covers inputs shared by every resource: the generator, the flags,
+// all product.yaml files, the names of all resources, which make up the
+// provider's list of resources and the packages tests import them from, and
+// every template that isn't owned by a resource.
+func (c *Cache) sharedKey(owned map[string]bool) (string, error) {
+	h := sha256.New()
+	writeField(h, "salt", []byte(c.config.Salt))
+	writeField(h, "flags", []byte(fmt.Sprint(c.config.Provider, c.config.Version, c.config.GenerateCode, c.config.GenerateDocs)))
+
+	for _, p := range c.productDirs() {
+		writeField(h, p, c.readYaml(path.Join(p, "product.yaml")))
+		for _, f := range c.resourceFiles(p) {
+			writeField(h, f, bytes.Join(resourceNameRegexp.FindAll(c.readYaml(f), -1), []byte("\n")))
+		}
+	}
+
+	err := fs.WalkDir(c.config.TemplateFS, "templates", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || owned[p] || ownedByConvention(p) {
+			return err
+		}
+		data, err := fs.ReadFile(c.config.TemplateFS, p)
+		if err != nil {
+			return err
+		}
+		writeField(h, p, data)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("cache: hashing templates: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+var resourceNameRegexp = regexp.MustCompile(`(?m)^(?:name|legacy_name):.*$`)
+
+// ownedByConvention reports whether a template is only ever consumed through
+// a sample's config path, so it's hashed by the resources using it rather than
+// by every resource.
+func ownedByConvention(p string) bool {
+	return strings.HasPrefix(p, "templates/terraform/samples/services/") ||
+		path.Dir(p) == "templates/terraform/examples"
+}
+
+var templatePathRegexp = regexp.MustCompile(`(?:templates|third_party)/[\w./-]+\.\w+`)
+
+// templateRefs returns the templates a resource consumes: every path
+// mentioned in its YAML, the configs of its samples, and anything those
+// files mention in turn.
+func (c *Cache) templateRefs(r *api.Resource) []string {
+	seen := map[string]bool{}
+	var queue []string
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			queue = append(queue, p)
+		}
+	}
+	for _, p := range templatePathRegexp.FindAllString(string(c.readYaml(r.SourceYamlFile)), -1) {
+		add(p)
+	}
+	for _, s := range r.Samples {
+		for _, step := range s.Steps {
+			if step.ConfigPath != "" {
+				add(step.ConfigPath)
+			}
+		}
+	}
+	for i := 0; i < len(queue); i++ {
+		data, err := fs.ReadFile(c.config.TemplateFS, queue[i])
+		if err != nil {
+			// Missing files are caught by validation; they hash as empty.
+			continue
+		}
+		for _, p := range templatePathRegexp.FindAllString(string(data), -1) {
+			add(p)
+		}
+	}
+	sort.Strings(queue)
+	return queue
+}
+
+var resourceRefRegexp = regexp.MustCompile(`(?m)^\s*resource:\s*['"]?(\w+)`)
+
+// referencedResources returns the names of the sibling resources a resource
+// YAML references through ResourceRef fields.
+func referencedResources(yaml []byte) []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, m := range resourceRefRegexp.FindAllSubmatch(yaml, -1) {
+		name := string(m[1])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// productDirs returns the product directories under products/, such as
+// products/compute, in the base and override directories.
+func (c *Cache) productDirs() []string {
+	return c.glob("products/*/product.yaml", path.Dir)
+}
+
+// resourceFiles returns the resource YAML files of the product directory p,
+// such as products/compute/Address.yaml, in the base and override
+// directories. They are the IDs of the resources.
+func (c *Cache) resourceFiles(p string) []string {
+	return c.glob(path.Join(p, "*.yaml"), func(f string) string {
+		if path.Base(f) == "product.yaml" {
+			return ""
+		}
+		return f
+	})
+}
+
+// glob returns the sorted, distinct results of mapping the files matching
+// pattern under the base and override directories, dropping empty ones.
+func (c *Cache) glob(pattern string, f func(string) string) []string {
+	seen := map[string]bool{}
+	var results []string
+	for _, dir := range []string{c.config.BaseDirectory, c.config.OverrideDirectory} {
+		if dir == "" {
+			continue
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		for _, m := range matches {
+			rel, err := filepath.Rel(dir, m)
+			if err != nil {
+				continue
+			}
+			if result := f(filepath.ToSlash(rel)); result != "" && !seen[result] {
+				seen[result] = true
+				results = append(results, result)
+			}
+		}
+	}
+	sort.Strings(results)
+	return results
+}
+
+// isYaml reports whether an input is a YAML file under products/, rather
+// than a template.
+func isYaml(f string) bool {
+	return strings.HasPrefix(f, "products/")
+}
+
+// readYaml returns the base and override contents of a file under products/.
+// Missing files read as empty.
+func (c *Cache) readYaml(rel string) []byte {
+	base, _ := os.ReadFile(filepath.Join(c.config.BaseDirectory, rel))
+	if c.config.OverrideDirectory == "" {
+		return base
+	}
+	override, _ := os.ReadFile(filepath.Join(c.config.OverrideDirectory, rel))
+	return append(append(base, 0), override...)
+}
+
+func writeField(w io.Writer, name string, data []byte) {
+	fmt.Fprintf(w, "%s\x00%d\x00", name, len(data))
+	w.Write(data)
+}
+
+func hashStrings(values ...string) string {
+	h := sha256.New()
+	for _, v := range values {
+		writeField(h, "", []byte(v))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func fileHash(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func executableHash() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return fileHash(exe)
+}
@@ -0,0 +1,324 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+)
+
+// testTree is a minimal mmv1 checkout: a product with two resources, one of
+// which uses custom code that includes a further template and references the
+// other resource.
+var testTree = map[string]string{
+	"products/widgets/product.yaml": "name: Widgets\n",
+	"products/widgets/Widget.yaml": `name: Widget
+custom_code:
+  constants: templates/terraform/constants/widget.go.tmpl
+properties:
+  - name: gadget
+    type: ResourceRef
+    resource: 'Gadget'
+`,
+	"products/widgets/Gadget.yaml":                                      "name: Gadget\n",
+	"templates/terraform/resource.go.tmpl":                              "resource\n",
+	"templates/terraform/constants/widget.go.tmpl":                      `{{ $.CustomTemplate "templates/terraform/constants/widget_helpers.go.tmpl" }}`,
+	"templates/terraform/constants/widget_helpers.go.tmpl":              "helpers\n",
+	"templates/terraform/samples/services/widgets/gadget_basic.tf.tmpl": "gadget\n",
+}
+
+func testProducts() map[string]*api.Product {
+	p := &api.Product{Name: "Widgets", PackagePath: "products/widgets"}
+	widget := &api.Resource{Name: "Widget", LegacyName: "google_widget", SourceYamlFile: "products/widgets/Widget.yaml"}
+	gadget := &api.Resource{Name: "Gadget", SourceYamlFile: "products/widgets/Gadget.yaml"}
+	gadget.Samples = []*resource.Sample{{Name: "gadget_basic", Steps: []*resource.Step{{ConfigPath: "templates/terraform/samples/services/widgets/gadget_basic.tf.tmpl"}}}}
+	p.Objects = []*api.Resource{gadget, widget}
+	return map[string]*api.Product{p.PackagePath: p}
+}
+
+type testEnv struct {
+	config Config
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	base := t.TempDir()
+	for name, content := range testTree {
+		writeTestFile(t, filepath.Join(base, name), content)
+	}
+	return &testEnv{config: Config{
+		Dir:           t.TempDir(),
+		OutputPath:    t.TempDir(),
+		Version:       "ga",
+		GenerateCode:  true,
+		GenerateDocs:  true,
+		BaseDirectory: base,
+		TemplateFS:    os.DirFS(base),
+		Salt:          "generator-1",
+	}}
+}
+
+// generate simulates a cached generation: it returns the resources that were
+// not fresh, writing an output file for each of them. Unchanged products are
+// not loaded.
+func (e *testEnv) generate(t *testing.T) []string {
+	t.Helper()
+	c, err := Open(e.config)
+	if err != nil {
+		t.Fatalf("Open() = %v", err)
+	}
+	products := testProducts()
+	for packagePath, p := range products {
+		if placeholders, ok := c.Unchanged(packagePath); ok {
+			p.Objects = placeholders
+		}
+	}
+	if err := c.Hash(products); err != nil {
+		t.Fatalf("Hash() = %v", err)
+	}
+	var generated []string
+	for _, p := range products {
+		for _, r := range p.Objects {
+			if c.Fresh(r) {
+				continue
+			}
+			generated = append(generated, r.Name)
+			out := filepath.Join(e.config.OutputPath, "resource_"+r.Name+".go")
+			writeTestFile(t, out, r.Name)
+			if err := c.Record(r, []string{out}); err != nil {
+				t.Fatalf("Record(%s) = %v", r.Name, err)
+			}
+		}
+	}
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	sort.Strings(generated)
+	return generated
+}
+
+func writeTestFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(t *testing.T, e *testEnv)
+		want   []string
+	}{
+		{
+			name:   "nothing changed",
+			change: func(t *testing.T, e *testEnv) {},
+			want:   nil,
+		},
+		{
+			name: "resource yaml changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "products/widgets/Widget.yaml"), testTree["products/widgets/Widget.yaml"]+"description: changed\n")
+			},
+			want: []string{"Widget"},
+		},
+		{
+			name: "referenced resource yaml changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "products/widgets/Gadget.yaml"), "name: Gadget\ndescription: changed\n")
+			},
+			want: []string{"Gadget", "Widget"},
+		},
+		{
+			name: "custom code changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "templates/terraform/constants/widget.go.tmpl"), testTree["templates/terraform/constants/widget.go.tmpl"]+"changed\n")
+			},
+			want: []string{"Widget"},
+		},
+		{
+			name: "template included by custom code changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "templates/terraform/constants/widget_helpers.go.tmpl"), "changed\n")
+			},
+			want: []string{"Widget"},
+		},
+		{
+			name: "sample config changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "templates/terraform/samples/services/widgets/gadget_basic.tf.tmpl"), "changed\n")
+			},
+			want: []string{"Gadget"},
+		},
+		{
+			name: "shared template changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "templates/terraform/resource.go.tmpl"), "changed\n")
+			},
+			want: []string{"Gadget", "Widget"},
+		},
+		{
+			name: "product yaml changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "products/widgets/product.yaml"), "name: Widgets\nversions: []\n")
+			},
+			want: []string{"Gadget", "Widget"},
+		},
+		{
+			name: "override added",
+			change: func(t *testing.T, e *testEnv) {
+				e.config.OverrideDirectory = t.TempDir()
+				writeTestFile(t, filepath.Join(e.config.OverrideDirectory, "products/widgets/Gadget.yaml"), "name: Gadget\nmin_version: beta\n")
+			},
+			want: []string{"Gadget", "Widget"},
+		},
+		{
+			name: "generator changed",
+			change: func(t *testing.T, e *testEnv) {
+				e.config.Salt = "generator-2"
+			},
+			want: []string{"Gadget", "Widget"},
+		},
+		{
+			name: "output modified",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.OutputPath, "resource_Gadget.go"), "edited by hand")
+			},
+			want: []string{"Gadget"},
+		},
+		{
+			name: "output deleted",
+			change: func(t *testing.T, e *testEnv) {
+				if err := os.Remove(filepath.Join(e.config.OutputPath, "resource_Widget.go")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"Widget"},
+		},
+		{
+			name: "different flags",
+			change: func(t *testing.T, e *testEnv) {
+				e.config.GenerateDocs = false
+			},
+			want: []string{"Gadget", "Widget"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			if got, want := e.generate(t), []string{"Gadget", "Widget"}; !cmp.Equal(got, want) {
+				t.Fatalf("first generation regenerated %v, want %v", got, want)
+			}
+			tc.change(t, e)
+			if diff := cmp.Diff(tc.want, e.generate(t)); diff != "" {
+				t.Errorf("regenerated resources differ (-want +got):\n%s", diff)
+			}
+			if got := e.generate(t); len(got) != 0 {
+				t.Errorf("generation after %q regenerated %v, want nothing", tc.name, got)
+			}
+		})
+	}
+}
+
+func TestCacheCorruptManifest(t *testing.T) {
+	e := newTestEnv(t)
+	e.generate(t)
+	entries, err := os.ReadDir(e.config.Dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache dir has %v (%v), want one manifest", entries, err)
+	}
+	writeTestFile(t, filepath.Join(e.config.Dir, entries[0].Name()), "{not json")
+	if diff := cmp.Diff([]string{"Gadget", "Widget"}, e.generate(t)); diff != "" {
+		t.Errorf("regenerated resources differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestCacheUnchanged(t *testing.T) {
+	e := newTestEnv(t)
+	e.generate(t)
+
+	c, err := Open(e.config)
+	if err != nil {
+		t.Fatalf("Open() = %v", err)
+	}
+	placeholders, ok := c.Unchanged("products/widgets")
+	if !ok {
+		t.Fatalf("Unchanged() = false for an unchanged product, want true")
+	}
+	var got [][]string
+	for _, r := range placeholders {
+		if !c.Placeholder(r) {
+			t.Errorf("Placeholder(%s) = false, want true", r.Name)
+		}
+		got = append(got, []string{r.Name, r.LegacyName, r.SourceYamlFile})
+	}
+	want := [][]string{
+		{"Gadget", "", "products/widgets/Gadget.yaml"},
+		{"Widget", "google_widget", "products/widgets/Widget.yaml"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unchanged() placeholders differ (-want +got):\n%s", diff)
+	}
+
+	cases := []struct {
+		name   string
+		change func(t *testing.T, e *testEnv)
+	}{
+		{
+			name: "resource yaml changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "products/widgets/Gadget.yaml"), "name: Gadget\ndescription: changed\n")
+			},
+		},
+		{
+			name: "custom code changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "templates/terraform/constants/widget_helpers.go.tmpl"), "changed\n")
+			},
+		},
+		{
+			name: "shared template changed",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "templates/terraform/resource.go.tmpl"), "changed\n")
+			},
+		},
+		{
+			name: "resource added",
+			change: func(t *testing.T, e *testEnv) {
+				writeTestFile(t, filepath.Join(e.config.BaseDirectory, "products/widgets/Doohickey.yaml"), "name: Doohickey\n")
+			},
+		},
+		{
+			name: "output deleted",
+			change: func(t *testing.T, e *testEnv) {
+				if err := os.Remove(filepath.Join(e.config.OutputPath, "resource_Widget.go")); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			e.generate(t)
+			tc.change(t, e)
+			c, err := Open(e.config)
+			if err != nil {
+				t.Fatalf("Open() = %v", err)
+			}
+			if placeholders, ok := c.Unchanged("products/widgets"); ok {
+				t.Errorf("Unchanged() = %v, true after %q, want false", placeholders, tc.name)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ require (
 	github.com/getkin/kin-openapi v0.146.0
 	github.com/google/go-cmp v0.7.0
 	github.com/otiai10/copy v1.9.0
+	github.com/pmezard/go-difflib v1.0.0
 )
 
 require (
@@ -0,0 +1,215 @@
+// Package jsonschema generates JSON Schemas for product and resource YAML
+// files from the Go structs they are decoded into, so that editors can
+// validate and complete the files without running the generator.
+//
+// The schemas are checked in next to this file; regenerate them with
+//
+//	go run . --json-schema jsonschema
+//
+// from the mmv1 directory after changing a struct's fields or their comments.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/validator"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/yamlkeys"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema, limited to the keywords the generator writes.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 []string           `json:"type,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Files maps each schema file to the struct its documents are decoded into.
+var Files = map[string]reflect.Type{
+	"product.schema.json":  reflect.TypeOf(api.Product{}),
+	"resource.schema.json": reflect.TypeOf(api.Resource{}),
+}
+
+var (
+	typeType  = reflect.TypeOf(api.Type{})
+	asyncType = reflect.TypeOf(api.Async{})
+)
+
+// enums lists the values of string fields that only accept a fixed set.
+func enums(owner reflect.Type, key string) []string {
+	switch {
+	case owner == typeType && key == "type":
+		return api.PropertyTypes
+	case owner == asyncType && key == "type":
+		return api.AsyncTypes
+	case key == "min_version" || key == "exact_version":
+		return product.ORDER
+	}
+	return nil
+}
+
+type generator struct {
+	docs  validator.Docs
+	names map[reflect.Type]string
+	defs  map[string]*Schema
+}
+
+// Generate returns the schema for documents decoded into root, with the
+// structs it reaches under $defs and descriptions taken from docs.
+func Generate(root reflect.Type, docs validator.Docs) *Schema {
+	g := &generator{docs: docs, names: defNames(root), defs: map[string]*Schema{}}
+	s := g.schema(root)
+	return &Schema{
+		Schema:      draft,
+		Title:       fmt.Sprintf("mmv1 %s", root.Name()),
+		Description: docs.Type(root),
+		Ref:         s.Ref,
+		Defs:        g.defs,
+	}
+}
+
+// defNames names the $defs entry of each struct reachable from root after
+// the struct, qualified by its package if another struct shares the name.
+func defNames(root reflect.Type) map[reflect.Type]string {
+	var structs []reflect.Type
+	seen := map[reflect.Type]bool{}
+	var visit func(t reflect.Type)
+	visit = func(t reflect.Type) {
+		t = deref(t)
+		switch t.Kind() {
+		case reflect.Slice, reflect.Map:
+			visit(t.Elem())
+		case reflect.Struct:
+			if seen[t] {
+				return
+			}
+			seen[t] = true
+			structs = append(structs, t)
+			fields := yamlkeys.Fields(t)
+			for _, key := range sortedKeys(fields) {
+				visit(fields[key].Type)
+			}
+		}
+	}
+	visit(root)
+
+	count := map[string]int{}
+	for _, t := range structs {
+		count[t.Name()]++
+	}
+	names := map[reflect.Type]string{}
+	for _, t := range structs {
+		names[t] = t.Name()
+		if count[t.Name()] > 1 {
+			names[t] = filepath.Base(t.PkgPath()) + "." + t.Name()
+		}
+	}
+	return names
+}
+
+// schema returns the schema of values decoded into t. Like yaml.v3, it
+// accepts null for any value and any scalar for a string.
+func (g *generator) schema(t reflect.Type) *Schema {
+	t = deref(t)
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: []string{"boolean", "null"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: []string{"integer", "null"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: []string{"number", "null"}}
+	case reflect.String:
+		return &Schema{Type: []string{"string", "number", "boolean", "null"}}
+	case reflect.Slice:
+		return &Schema{Type: []string{"array", "null"}, Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: []string{"object", "null"}, AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		name := g.names[t]
+		if _, ok := g.defs[name]; !ok {
+			def := &Schema{Type: []string{"object", "null"}, Description: g.docs.Type(t), Properties: map[string]*Schema{}, AdditionalProperties: false}
+			g.defs[name] = def
+			for key, field := range yamlkeys.Fields(t) {
+				s := g.schema(field.Type)
+				s.Description = g.docs.Field(field)
+				if values := enums(t, key); values != nil && deref(field.Type).Kind() == reflect.String {
+					s.Type = []string{"string", "null"}
+					for _, v := range values {
+						s.Enum = append(s.Enum, v)
+					}
+					s.Enum = append(s.Enum, nil)
+				}
+				def.Properties[key] = s
+			}
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	// Interfaces, such as default_value, accept any value.
+	return &Schema{}
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Marshal returns the schema of a file in Files as indented JSON.
+func Marshal(file, baseDirectory string) ([]byte, error) {
+	t, ok := Files[file]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema file %q", file)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(Generate(t, validator.LoadDocs(baseDirectory))); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write writes every schema in Files to dir, reading doc comments from the
+// Go sources under baseDirectory.
+func Write(dir, baseDirectory string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for _, file := range sortedKeys(Files) {
+		content, err := Marshal(file, baseDirectory)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package jsonschema
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/validator"
+)
+
+func TestSchemasUpToDate(t *testing.T) {
+	for file := range Files {
+		t.Run(file, func(t *testing.T) {
+			want, err := Marshal(file, "..")
+			if err != nil {
+				t.Fatalf("Marshal(%q) = %v", file, err)
+			}
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("%s is out of date; run `go run . --json-schema jsonschema` in mmv1 to regenerate it (-want +got):\n%s", file, diff)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	docs := validator.Docs{
+		"api.Async":      "Base class from which other Async classes can inherit.",
+		"api.Async.Type": "Describes an operation.",
+	}
+	s := Generate(reflect.TypeOf(api.Resource{}), docs)
+
+	if s.Ref != "#/$defs/Resource" {
+		t.Errorf("root $ref = %q, want #/$defs/Resource", s.Ref)
+	}
+	resource := s.Defs["Resource"]
+	if resource == nil {
+		t.Fatalf("$defs has no Resource: %v", s.Defs)
+	}
+	for _, key := range []string{"name", "properties", "async", "exclude_tgc", "cai_resource_kind"} {
+		if _, ok := resource.Properties[key]; !ok {
+			t.Errorf("Resource has no property %q", key)
+		}
+	}
+	if diff := cmp.Diff(&Schema{Type: []string{"array", "null"}, Items: &Schema{Ref: "#/$defs/Type"}}, resource.Properties["properties"]); diff != "" {
+		t.Errorf("Resource.properties differs (-want +got):\n%s", diff)
+	}
+
+	async := s.Defs["Async"]
+	if async == nil {
+		t.Fatalf("$defs has no Async: %v", s.Defs)
+	}
+	if async.Description != docs["api.Async"] || async.AdditionalProperties != false {
+		t.Errorf("Async = %+v, want a closed object described by its doc comment", async)
+	}
+	// Fields of the inlined OpAsync and PollAsync.
+	for _, key := range []string{"result", "check_response_func_existence"} {
+		if _, ok := async.Properties[key]; !ok {
+			t.Errorf("Async has no property %q", key)
+		}
+	}
+	want := &Schema{Description: "Describes an operation.", Type: []string{"string", "null"}, Enum: []interface{}{"OpAsync", "PollAsync", nil}}
+	if diff := cmp.Diff(want, async.Properties["type"]); diff != "" {
+		t.Errorf("Async.type differs (-want +got):\n%s", diff)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
 	apiresource "github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/utils"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/cache"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
 	"github.com/golang/glog"
 	"golang.org/x/exp/slices"
@@ -25,6 +26,7 @@ type Loader struct {
 	Products          map[string]*api.Product
 	version           string
 	sysfs             google.ReadDirReadFileFS
+	cache             *cache.Cache
 }
 
 type Config struct {
@@ -33,6 +35,7 @@ type Config struct {
 	Version           string                   // required
 	Sysfs             google.ReadDirReadFileFS // required
 	CompilerTarget    string                   // optional
+	Cache             *cache.Cache             // optional; unchanged products are not parsed and fresh resources are not validated
 }
 
 // NewLoader creates a new Loader instance, applying any
@@ -59,17 +62,27 @@ func NewLoader(config Config) *Loader {
 		version:           config.Version,
 		sysfs:             config.Sysfs,
 		compilerTarget:    config.CompilerTarget,
+		cache:             config.Cache,
 	}
 
 	return l
 }
 
 func (l *Loader) LoadProducts() {
-	if l.version == "" {
-		log.Printf("No version specified, assuming ga")
-		l.version = "ga"
-	}
+	l.loadProducts(l.allProductFiles())
+}
 
+// LoadProductsNamed loads the given products (products/<name>) and the
+// products of their resources' successors, rather than every product.
+// Resources in other products that name one of them as a successor are not
+// loaded.
+func (l *Loader) LoadProductsNamed(productNames ...string) {
+	l.loadProducts(productNames)
+}
+
+// allProductFiles returns the path (products/<name>) of every product in the
+// base and override directories.
+func (l *Loader) allProductFiles() []string {
 	var allProductFiles []string = make([]string, 0)
 
 	files, err := filepath.Glob(filepath.Join(l.baseDirectory, "products/**/product.yaml"))
@@ -101,7 +114,48 @@ func (l *Loader) LoadProducts() {
 		}
 	}
 
-	l.Products = l.batchLoadProducts(allProductFiles)
+	return allProductFiles
+}
+
+func (l *Loader) loadProducts(productNames []string) {
+	if l.version == "" {
+		log.Printf("No version specified, assuming ga")
+		l.version = "ga"
+	}
+
+	l.Products = l.batchLoadProducts(productNames)
+	// Products not available at this version are skipped by
+	// batchLoadProducts, so remember every one already requested.
+	requested := map[string]bool{}
+	for _, name := range productNames {
+		requested[name] = true
+	}
+	for {
+		var successors []string
+		for _, p := range l.Products {
+			for _, r := range p.Objects {
+				if r.Successor == nil {
+					continue
+				}
+				sp := successorProductPath(p, r)
+				if requested[sp] {
+					continue
+				}
+				requested[sp] = true
+				// A missing successor product is reported when r is validated.
+				if Exists(l.baseDirectory, sp, "product.yaml") || (l.overrideDirectory != "" && Exists(l.overrideDirectory, sp, "product.yaml")) {
+					successors = append(successors, sp)
+				}
+			}
+		}
+		if len(successors) == 0 {
+			break
+		}
+		for name, p := range l.batchLoadProducts(successors) {
+			l.Products[name] = p
+		}
+	}
+	l.loadPlaceholderSuccessors()
 
 	// Set up ResourcePrefixPkgMap now that all products are loaded.
 	runtime := api.Runtime{
@@ -144,6 +198,91 @@ func (l *Loader) LoadProducts() {
 			r.Runtime = runtime
 		}
 	}
+
+	l.linkSuccessors()
+}
+
+// linkSuccessors resolves the `successor` of every resource that declares
+// one, and records the resource as a predecessor of its successor. Successors
+// that can't be found are reported when the resource is validated.
+func (l *Loader) linkSuccessors() {
+	for _, p := range l.Products {
+		for _, r := range p.Objects {
+			if r.Successor == nil {
+				continue
+			}
+			sp, ok := l.Products[successorProductPath(p, r)]
+			if !ok {
+				continue
+			}
+			for _, successor := range sp.Objects {
+				if successor.Name == r.Successor.Resource {
+					r.SuccessorResource = successor
+					successor.Predecessors = append(successor.Predecessors, r)
+				}
+			}
+		}
+	}
+	for _, p := range l.Products {
+		for _, r := range p.Objects {
+			slices.SortFunc(r.Predecessors, func(a, b *api.Resource) int {
+				return strings.Compare(a.SourceYamlFile, b.SourceYamlFile)
+			})
+		}
+	}
+}
+
+// successorProductPath returns the path of the product of r's successor.
+func successorProductPath(p *api.Product, r *api.Resource) string {
+	if r.Successor.Product != "" {
+		return filepath.Join("products", r.Successor.Product)
+	}
+	return p.PackagePath
+}
+
+// loadPlaceholderSuccessors parses the resources of the products that were
+// left as cache placeholders when one of their resources moves state to or
+// from a parsed resource, since generating either one needs the schema of the
+// other.
+func (l *Loader) loadPlaceholderSuccessors() {
+	if l.cache == nil {
+		return
+	}
+	isPlaceholder := func(p *api.Product) bool {
+		return len(p.Objects) > 0 && l.cache.Placeholder(p.Objects[0])
+	}
+	for {
+		var placeholders []*api.Product
+		for _, p := range l.Products {
+			for _, r := range p.Objects {
+				if r.Successor == nil {
+					continue
+				}
+				sp, ok := l.Products[successorProductPath(p, r)]
+				if !ok || isPlaceholder(p) == isPlaceholder(sp) {
+					continue
+				}
+				if isPlaceholder(p) {
+					placeholders = append(placeholders, p)
+				} else {
+					placeholders = append(placeholders, sp)
+				}
+			}
+		}
+		if len(placeholders) == 0 {
+			return
+		}
+		for _, p := range placeholders {
+			if !isPlaceholder(p) {
+				continue
+			}
+			resources, err := l.parseResources(p)
+			if err != nil {
+				log.Fatalf("Error loading %s: %v", p.PackagePath, err)
+			}
+			p.Objects = resources
+		}
+	}
 }
 
 func (l *Loader) batchLoadProducts(productNames []string) map[string]*api.Product {
@@ -279,8 +418,28 @@ func NewVarsReplacingFS(inner google.ReadDirReadFileFS) google.ReadDirReadFileFS
 	return varsReplacingFS{inner}
 }
 
-// loadResources loads all resources for a product
+// loadResources loads all resources for a product. If the product is unchanged
+// since the last cached generation, its resources are placeholders from the
+// cache instead, and their YAML isn't parsed.
 func (l *Loader) loadResources(product *api.Product) ([]*api.Resource, error) {
+	if l.cache != nil {
+		if resources, ok := l.cache.Unchanged(product.PackagePath); ok {
+			google.LogVerbose("%s: Skipping loading unchanged resources", product.PackagePath)
+			for _, resource := range resources {
+				resource.ProductMetadata = product
+				resource.TargetVersionName = l.version
+			}
+			slices.SortFunc(resources, func(a, b *api.Resource) int {
+				return strings.Compare(a.Name, b.Name)
+			})
+			return resources, nil
+		}
+	}
+	return l.parseResources(product)
+}
+
+// parseResources parses all resources for a product
+func (l *Loader) parseResources(product *api.Product) ([]*api.Resource, error) {
 	var resources []*api.Resource = make([]*api.Resource, 0)
 
 	// Get base resource files
@@ -451,6 +610,9 @@ func (l *Loader) AddExtraFields() error {
 
 	for _, product := range l.Products {
 		for _, resource := range product.Objects {
+			if l.cache != nil && l.cache.Placeholder(resource) {
+				continue
+			}
 			resource.Properties = resource.AddExtraFields(resource.PropertiesWithExcluded(), nil)
 			// SetDefault after AddExtraFields to ensure relevant metadata is available for the newly generated fields
 			resource.SetDefault(product)
@@ -460,22 +622,47 @@ func (l *Loader) AddExtraFields() error {
 	return nil
 }
 
+// Validate checks every loaded resource and exits, listing all of their
+// errors, if any are invalid.
 func (l *Loader) Validate() {
 	if l.Products == nil {
 		log.Fatalln("products have not been loaded into memory")
 	}
 
+	resourceErrors := l.ResourceErrors()
+	files := make([]string, 0, len(resourceErrors))
+	for file := range resourceErrors {
+		files = append(files, file)
+	}
+	slices.Sort(files)
+
+	var es []error
+	for _, file := range files {
+		es = append(es, utils.TransformErrs(func(e error) error {
+			return fmt.Errorf("%s%s%s: %w", utils.ColorRed, file, utils.ColorReset, e)
+		}, resourceErrors[file])...)
+	}
+	if len(es) > 0 {
+		log.Fatalf("%v", errors.Join(es...))
+	}
+}
+
+// ResourceErrors returns the validation errors of every loaded resource that
+// has any, keyed by the resource's source YAML file.
+func (l *Loader) ResourceErrors() map[string][]error {
+	resourceErrors := map[string][]error{}
 	for _, product := range l.Products {
 		for _, resource := range product.Objects {
-			es := resource.Validate()
-			if len(es) > 0 {
-				es = utils.TransformErrs(func(e error) error {
-					return fmt.Errorf("%s%s%s: %w", utils.ColorRed, resource.SourceYamlFile, utils.ColorReset, e)
-				}, es)
-				log.Fatalf("%v", errors.Join(es...))
+			// A fresh resource passed validation when it was last generated.
+			if l.cache != nil && l.cache.Fresh(resource) {
+				continue
+			}
+			if es := resource.Validate(); len(es) > 0 {
+				resourceErrors[resource.SourceYamlFile] = append(resourceErrors[resource.SourceYamlFile], es...)
 			}
 		}
 	}
+	return resourceErrors
 }
 
 // CountProductsAndResources returns the number of products and resources that will be generated
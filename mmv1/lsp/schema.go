@@ -0,0 +1,259 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/yamlkeys"
+)
+
+var (
+	productType    = reflect.TypeOf(api.Product{})
+	resourceType   = reflect.TypeOf(api.Resource{})
+	typeType       = reflect.TypeOf(api.Type{})
+	asyncType      = reflect.TypeOf(api.Async{})
+	customCodeType = reflect.TypeOf(resource.CustomCode{})
+)
+
+// yamlLine is what a single line of a (possibly incomplete) YAML document
+// says about its place in the tree.
+type yamlLine struct {
+	blank      bool
+	dash       bool // the line starts a list item
+	dashIndent int
+	keyIndent  int
+	key        string // empty if the line isn't a `key:` entry
+	value      string
+}
+
+var (
+	keyRegexp     = regexp.MustCompile(`^([\w.-]+):(?:\s+(.*))?$`)
+	partialRegexp = regexp.MustCompile(`^-?\s*[\w.-]*$`)
+)
+
+func parseLine(s string) yamlLine {
+	rest := strings.TrimLeft(s, " ")
+	indent := len(s) - len(rest)
+	if rest == "" || strings.HasPrefix(rest, "#") {
+		return yamlLine{blank: true}
+	}
+	l := yamlLine{keyIndent: indent}
+	if rest == "-" || strings.HasPrefix(rest, "- ") {
+		l.dash, l.dashIndent = true, indent
+		item := strings.TrimLeft(rest[1:], " ")
+		l.keyIndent = indent + len(rest) - len(item)
+		rest = item
+	}
+	if m := keyRegexp.FindStringSubmatch(strings.TrimRight(rest, " ")); m != nil {
+		l.key = m[1]
+		if value := strings.TrimSpace(m[2]); !strings.HasPrefix(value, "#") {
+			l.value = value
+		}
+	}
+	return l
+}
+
+// cursor describes where in the document a position is.
+type cursor struct {
+	// path holds the keys enclosing the position, outermost first, with "-"
+	// standing for a list item.
+	path []string
+	// key is the key whose value the position is in, or empty if the
+	// position is where a key goes.
+	key   string
+	value string
+}
+
+// locate works out the cursor at the given position from indentation alone,
+// so that it works on documents that are being edited and don't parse. It
+// returns false if the position is in neither a key nor a value.
+func locate(lines []string, pos position) (cursor, bool) {
+	if pos.Line >= len(lines) {
+		return cursor{}, false
+	}
+	before := lines[pos.Line]
+	if pos.Character < len(before) {
+		before = before[:pos.Character]
+	}
+	current := parseLine(before)
+	var c cursor
+	switch {
+	case current.key != "":
+		c.key, c.value = current.key, current.value
+	case current.blank:
+		current.keyIndent = len(before)
+	default:
+		// A partially typed key.
+		if !partialRegexp.MatchString(strings.TrimSpace(before)) {
+			return cursor{}, false
+		}
+	}
+
+	var path []string
+	indent := current.keyIndent
+	// Once the enclosing list item is found, the key holding the list is
+	// searched for; it may be at the same indentation as the dashes.
+	inList := false
+	if current.dash {
+		path = append(path, "-")
+		indent, inList = current.dashIndent, true
+	}
+	for i := pos.Line - 1; i >= 0; i-- {
+		l := parseLine(lines[i])
+		if l.blank {
+			continue
+		}
+		if !inList && l.dash && l.keyIndent == indent {
+			path = append(path, "-")
+			indent, inList = l.dashIndent, true
+			continue
+		}
+		outer := l.keyIndent < indent || (inList && l.keyIndent == indent && !l.dash)
+		if !outer {
+			continue
+		}
+		if l.key == "" || l.value != "" {
+			return cursor{}, false
+		}
+		path = append(path, l.key)
+		indent, inList = l.keyIndent, false
+		if l.dash {
+			path = append(path, "-")
+			indent, inList = l.dashIndent, true
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	c.path = path
+	return c, true
+}
+
+// resolve returns the Go type the value at path is decoded into, or nil if the
+// path doesn't exist.
+func resolve(t reflect.Type, path []string) reflect.Type {
+	for _, key := range path {
+		t = deref(t)
+		switch {
+		case key == "-" && t.Kind() == reflect.Slice:
+			t = t.Elem()
+		case t.Kind() == reflect.Struct:
+			field, ok := yamlkeys.Fields(t)[key]
+			if !ok {
+				return nil
+			}
+			t = field.Type
+		case t.Kind() == reflect.Map:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+	return deref(t)
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func rootType(file string) reflect.Type {
+	if filepath.Base(file) == "product.yaml" {
+		return productType
+	}
+	return resourceType
+}
+
+// keyCompletions lists the keys of the struct at the cursor.
+func (s *Server) keyCompletions(t reflect.Type) []completionItem {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := yamlkeys.Fields(t)
+	items := []completionItem{}
+	for key, field := range fields {
+		item := completionItem{Label: key, Kind: kindProperty, Detail: typeString(field.Type), InsertText: key + ": "}
+		if k := deref(field.Type).Kind(); k == reflect.Struct || k == reflect.Slice || k == reflect.Map {
+			item.InsertText = key + ":"
+		}
+		if doc := s.comments.Field(field); doc != "" {
+			item.Documentation = &markupContent{Kind: "markdown", Value: doc}
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+var httpVerbs = []string{"POST", "PUT", "PATCH", "DELETE", "GET"}
+
+// valueCompletions lists the values key may take in the struct at the cursor.
+func (s *Server) valueCompletions(t reflect.Type, key, productDir string) []completionItem {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	field, ok := yamlkeys.Fields(t)[key]
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	kind := kindValue
+	switch {
+	case deref(field.Type).Kind() == reflect.Bool:
+		values = []string{"true", "false"}
+	case t == typeType && key == "type":
+		values = api.PropertyTypes
+	case t == asyncType && key == "type":
+		values = api.AsyncTypes
+	case t == typeType && key == "resource":
+		for name := range s.index[productDir] {
+			values = append(values, name)
+		}
+	case key == "min_version" || key == "exact_version":
+		values = product.ORDER
+	case strings.HasSuffix(key, "_verb"):
+		values = httpVerbs
+	case field.Owner == customCodeType || (t == typeType && (key == "custom_expand" || key == "custom_flatten")):
+		values, kind = s.templates(key), kindFile
+	}
+
+	items := []completionItem{}
+	for _, v := range values {
+		items = append(items, completionItem{Label: v, Kind: kind})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+	return items
+}
+
+// templates lists the template files for a custom code key, which by
+// convention live in a directory named after the key.
+func (s *Server) templates(key string) []string {
+	var files []string
+	for _, dir := range []string{key, key + "s"} {
+		rel := filepath.Join("templates", "terraform", dir)
+		filepath.WalkDir(filepath.Join(s.baseDirectory, rel), func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if r, err := filepath.Rel(s.baseDirectory, path); err == nil {
+				files = append(files, filepath.ToSlash(r))
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+func typeString(t reflect.Type) string {
+	return strings.ReplaceAll(t.String(), "*", "")
+}
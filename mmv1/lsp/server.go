@@ -0,0 +1,520 @@
+// Package lsp implements a language server for product and resource YAML
+// files, offering completion, hover docs, go-to-definition and diagnostics
+// based on the api.Product, api.Resource and api.Type structs the files are
+// decoded into.
+//
+// Diagnostics come from two places. The validator's schema pass runs on
+// every change, since it is fast and only needs the buffer. The generator's
+// full validation, which also checks resource references and runs the
+// loader, runs over the file's product when a file is opened or saved,
+// through Config.Check. The loader only runs once the product passes the
+// schema pass, which catches the problems it would exit on.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/validator"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/yamlkeys"
+)
+
+type Config struct {
+	BaseDirectory     string // optional; the mmv1 directory, defaults to the workspace the editor opens
+	OverrideDirectory string // optional
+
+	// Check runs the generator's validation over a product directory
+	// (products/<name>) in the given base directory. If Check is nil only the
+	// schema pass runs.
+	Check func(baseDirectory, product string) ([]validator.Diagnostic, error)
+}
+
+type Server struct {
+	config        Config
+	baseDirectory string
+	conn          *conn
+	comments      validator.Docs
+
+	mu        sync.Mutex
+	documents map[string]string // open documents by URI
+	// index maps each product directory (products/<name>) to the files of
+	// its resources by resource name.
+	index map[string]map[string]string
+	// semantic holds the diagnostics of the last Check of each document.
+	semantic map[string][]validator.Diagnostic
+	// checkRuns counts the Checks started for each product directory, so that
+	// results of a Check that finished after a later one are dropped.
+	checkRuns map[string]int
+	checks    sync.WaitGroup
+}
+
+func NewServer(config Config) *Server {
+	return &Server{
+		config:    config,
+		documents: map[string]string{},
+		index:     map[string]map[string]string{},
+		semantic:  map[string][]validator.Diagnostic{},
+		checkRuns: map[string]int{},
+	}
+}
+
+// Validate returns a Check that runs the validator in process, over the
+// saved file's product and with the given override directory and version.
+func Validate(overrideDirectory, version string) func(string, string) ([]validator.Diagnostic, error) {
+	return func(baseDirectory, product string) ([]validator.Diagnostic, error) {
+		return validator.Validate(validator.Config{BaseDirectory: baseDirectory, OverrideDirectory: overrideDirectory, Version: version, Product: product})
+	}
+}
+
+// Serve answers requests read from r until the client exits or r is closed.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+	defer s.checks.Wait()
+	for {
+		m, err := s.conn.read()
+		if err != nil {
+			var rerr *responseError
+			if errors.As(err, &rerr) {
+				s.conn.replyError(nil, rerr.Code, rerr.Message)
+				continue
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if m.Method == "exit" {
+			return nil
+		}
+
+		result, err := s.handle(m)
+		if m.ID == nil {
+			if err != nil {
+				log.Printf("error handling %s: %v", m.Method, err)
+			}
+			continue
+		}
+		if err != nil {
+			rerr := &responseError{Code: codeInvalidParams, Message: err.Error()}
+			errors.As(err, &rerr)
+			err = s.conn.replyError(m.ID, rerr.Code, rerr.Message)
+		} else {
+			err = s.conn.reply(m.ID, result)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(m *message) (interface{}, error) {
+	switch m.Method {
+	case "initialize":
+		var params initializeParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(params), nil
+	case "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+		s.check(params.TextDocument.URI)
+		return nil, nil
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		// The server asks for full document sync, so the last change holds
+		// the whole document.
+		if n := len(params.ContentChanges); n > 0 {
+			s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didSave":
+		var params documentParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		s.check(params.TextDocument.URI)
+		return nil, nil
+	case "textDocument/didClose":
+		var params documentParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		delete(s.documents, params.TextDocument.URI)
+		delete(s.semantic, params.TextDocument.URI)
+		s.mu.Unlock()
+		return nil, s.conn.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+	case "textDocument/completion":
+		var params positionParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(params), nil
+	case "textDocument/hover":
+		var params positionParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(params), nil
+	case "textDocument/definition":
+		var params positionParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(params), nil
+	}
+	if m.ID == nil {
+		// Other notifications, such as initialized and $/cancelRequest,
+		// need no action.
+		return nil, nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q is not supported", m.Method)}
+}
+
+func (s *Server) initialize(params initializeParams) interface{} {
+	s.baseDirectory = s.config.BaseDirectory
+	if s.baseDirectory == "" {
+		root := uriPath(params.RootURI)
+		// The workspace may be the whole repository or just mmv1.
+		s.baseDirectory = root
+		if _, err := os.Stat(filepath.Join(root, "mmv1", "products")); err == nil {
+			s.baseDirectory = filepath.Join(root, "mmv1")
+		}
+	}
+	s.comments = validator.LoadDocs(s.baseDirectory)
+	for _, dir := range []string{s.baseDirectory, s.config.OverrideDirectory} {
+		if dir == "" {
+			continue
+		}
+		files, _ := filepath.Glob(filepath.Join(dir, "products", "*", "*.yaml"))
+		for _, file := range files {
+			if content, err := os.ReadFile(file); err == nil {
+				s.indexResource(file, string(content))
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    1, // full
+				"save":      true,
+			},
+			"completionProvider": map[string]interface{}{"triggerCharacters": []string{":", " "}},
+			"hoverProvider":      true,
+			"definitionProvider": true,
+		},
+		"serverInfo": map[string]string{"name": "mmv1-lsp"},
+	}
+}
+
+var nameRegexp = regexp.MustCompile(`(?m)^name:\s*['"]?([\w-]+)`)
+
+// indexResource records the name of the resource defined in file. It must be
+// called with s.mu held or before Serve handles documents.
+func (s *Server) indexResource(file, content string) {
+	if filepath.Base(file) == "product.yaml" {
+		return
+	}
+	product := "products/" + filepath.Base(filepath.Dir(file))
+	names := s.index[product]
+	if names == nil {
+		names = map[string]string{}
+		s.index[product] = names
+	}
+	for name, f := range names {
+		if f == file {
+			delete(names, name)
+		}
+	}
+	if m := nameRegexp.FindStringSubmatch(content); m != nil {
+		names[m[1]] = file
+	}
+}
+
+// update records a document's new content and publishes its diagnostics.
+func (s *Server) update(uri, text string) {
+	s.mu.Lock()
+	s.documents[uri] = text
+	if _, ok := s.documentFile(uri); ok {
+		s.indexResource(uriPath(uri), text)
+	}
+	s.mu.Unlock()
+	s.publish(uri)
+}
+
+// documentFile returns the path of a document relative to the base or
+// override directory, if it is a product or resource file.
+func (s *Server) documentFile(uri string) (string, bool) {
+	p := uriPath(uri)
+	for _, dir := range []string{s.config.OverrideDirectory, s.baseDirectory} {
+		if dir == "" {
+			continue
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if matched, _ := path.Match("products/*/*.yaml", rel); matched {
+			return rel, true
+		}
+	}
+	return "", false
+}
+
+func (s *Server) publish(uri string) {
+	s.mu.Lock()
+	text := s.documents[uri]
+	file, ok := s.documentFile(uri)
+	var diagnostics []validator.Diagnostic
+	if ok {
+		diagnostics = validator.ValidateFile(file, []byte(text), reflect.New(rootType(file)).Interface())
+	}
+	diagnostics = append(diagnostics, s.semantic[uri]...)
+	s.mu.Unlock()
+
+	lines := strings.Split(text, "\n")
+	params := publishDiagnosticsParams{URI: uri, Diagnostics: []diagnostic{}}
+	for _, d := range diagnostics {
+		params.Diagnostics = append(params.Diagnostics, diagnostic{
+			Range:    diagnosticRange(lines, d),
+			Severity: 1, // error
+			Code:     d.Rule,
+			Source:   "mmv1",
+			Message:  d.Message,
+		})
+	}
+	if err := s.conn.notify("textDocument/publishDiagnostics", params); err != nil {
+		log.Printf("error publishing diagnostics: %v", err)
+	}
+}
+
+// diagnosticRange covers the token a diagnostic points at, or the first line
+// of the document if it has no position.
+func diagnosticRange(lines []string, d validator.Diagnostic) lspRange {
+	if d.Line == 0 || d.Line > len(lines) {
+		return lspRange{End: position{Character: len(lines[0])}}
+	}
+	line, start := lines[d.Line-1], d.Column-1
+	end := start
+	for end < len(line) && line[end] != ' ' && line[end] != ':' {
+		end++
+	}
+	return lspRange{Start: position{Line: d.Line - 1, Character: start}, End: position{Line: d.Line - 1, Character: end}}
+}
+
+// check runs Config.Check over the document's product in the background and
+// publishes the problems it finds that the schema pass can't see, resource
+// errors and references to resources that don't exist, for each open
+// document of that product. Other problems are left to the schema pass, which
+// sees unsaved changes.
+func (s *Server) check(uri string) {
+	file, ok := s.documentFile(uri)
+	if !ok || s.config.Check == nil {
+		return
+	}
+	product := path.Dir(file)
+	s.mu.Lock()
+	s.checkRuns[product]++
+	run := s.checkRuns[product]
+	s.mu.Unlock()
+
+	s.checks.Add(1)
+	go func() {
+		defer s.checks.Done()
+		diagnostics, err := s.config.Check(s.baseDirectory, product)
+
+		s.mu.Lock()
+		if s.checkRuns[product] != run {
+			s.mu.Unlock()
+			return
+		}
+		var uris []string
+		for u := range s.documents {
+			if f, ok := s.documentFile(u); !ok || path.Dir(f) != product {
+				continue
+			}
+			uris = append(uris, u)
+			s.semantic[u] = nil
+			for _, d := range diagnostics {
+				if (d.Rule == validator.RuleResourceSemantic || d.Rule == validator.RuleDanglingRef) && s.sameFile(d.File, uriPath(u)) {
+					s.semantic[u] = append(s.semantic[u], d)
+				}
+			}
+		}
+		if err != nil {
+			s.semantic[uri] = append(s.semantic[uri], validator.Diagnostic{File: file, Rule: validator.RuleResourceSemantic, Message: err.Error()})
+		}
+		s.mu.Unlock()
+
+		sort.Strings(uris)
+		for _, u := range uris {
+			s.publish(u)
+		}
+	}()
+}
+
+// sameFile reports whether a file named by the validator, relative to the
+// base directory or absolute, is p.
+func (s *Server) sameFile(file, p string) bool {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(s.baseDirectory, file)
+	}
+	return filepath.Clean(file) == filepath.Clean(p)
+}
+
+func (s *Server) completion(params positionParams) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, ok := s.documentFile(params.TextDocument.URI)
+	if !ok {
+		return []completionItem{}
+	}
+	lines := strings.Split(s.documents[params.TextDocument.URI], "\n")
+	c, ok := locate(lines, params.Position)
+	if !ok {
+		return []completionItem{}
+	}
+	t := resolve(rootType(file), c.path)
+	if c.key == "" {
+		return s.keyCompletions(t)
+	}
+	return s.valueCompletions(t, c.key, path.Dir(file))
+}
+
+func (s *Server) hover(params positionParams) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, ok := s.documentFile(params.TextDocument.URI)
+	lines := strings.Split(s.documents[params.TextDocument.URI], "\n")
+	if !ok || params.Position.Line >= len(lines) {
+		return nil
+	}
+	l := parseLine(lines[params.Position.Line])
+	start, end := l.keyIndent, l.keyIndent+len(l.key)
+	if l.key == "" || params.Position.Character < start || params.Position.Character > end {
+		return nil
+	}
+	c, ok := locate(lines, position{Line: params.Position.Line, Character: start})
+	if !ok {
+		return nil
+	}
+	t := resolve(rootType(file), c.path)
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	field, ok := yamlkeys.Fields(t)[l.key]
+	if !ok {
+		return nil
+	}
+	text := fmt.Sprintf("`%s`: `%s`", l.key, typeString(field.Type))
+	if doc := s.comments.Field(field); doc != "" {
+		text += "\n\n" + doc
+	}
+	return hover{
+		Contents: markupContent{Kind: "markdown", Value: text},
+		Range:    &lspRange{Start: position{Line: params.Position.Line, Character: start}, End: position{Line: params.Position.Line, Character: end}},
+	}
+}
+
+var templatePathRegexp = regexp.MustCompile(`(?:templates|third_party)/[\w./-]+\.\w+`)
+
+func (s *Server) definition(params positionParams) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, ok := s.documentFile(params.TextDocument.URI)
+	lines := strings.Split(s.documents[params.TextDocument.URI], "\n")
+	if !ok || params.Position.Line >= len(lines) {
+		return nil
+	}
+	line := lines[params.Position.Line]
+
+	if l := parseLine(line); l.key == "resource" && l.value != "" {
+		name := strings.Trim(l.value, `'"`)
+		target, ok := s.index[path.Dir(file)][name]
+		if !ok {
+			// ResourceRefs may point to another product.
+			var products []string
+			for product := range s.index {
+				products = append(products, product)
+			}
+			sort.Strings(products)
+			for _, product := range products {
+				if target, ok = s.index[product][name]; ok {
+					break
+				}
+			}
+		}
+		if !ok {
+			return nil
+		}
+		return location{URI: fileURI(target), Range: s.nameRange(target)}
+	}
+
+	for _, span := range templatePathRegexp.FindAllStringIndex(line, -1) {
+		if params.Position.Character < span[0] || params.Position.Character > span[1] {
+			continue
+		}
+		for _, dir := range []string{s.config.OverrideDirectory, s.baseDirectory} {
+			if dir == "" {
+				continue
+			}
+			target := filepath.Join(dir, line[span[0]:span[1]])
+			if _, err := os.Stat(target); err == nil {
+				return location{URI: fileURI(target), Range: lspRange{}}
+			}
+		}
+	}
+	return nil
+}
+
+// nameRange returns the range of the top-level name key in a resource file,
+// preferring an open document's content to what is on disk.
+func (s *Server) nameRange(file string) lspRange {
+	content, ok := s.documents[fileURI(file)]
+	if !ok {
+		b, _ := os.ReadFile(file)
+		content = string(b)
+	}
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "name:") {
+			return lspRange{Start: position{Line: i}, End: position{Line: i, Character: len(line)}}
+		}
+	}
+	return lspRange{}
+}
+
+func uriPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func fileURI(p string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(p)}).String()
+}
@@ -0,0 +1,362 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/validator"
+)
+
+func TestLocate(t *testing.T) {
+	doc := `name: Widget
+async:
+  type: OpAsync
+  operation:
+    ` + `
+properties:
+  - name: size
+    type: Integer
+    ` + `
+  - name: shape
+    item_type:
+      type: NestedObject
+      properties:
+        - name: sides
+          type: Str
+examples:
+- name: basic
+  ` + `
+`
+	cases := []struct {
+		name string
+		pos  position
+		want cursor
+	}{
+		{name: "top level", pos: position{Line: 0, Character: 0}, want: cursor{}},
+		{name: "top level value", pos: position{Line: 0, Character: 8}, want: cursor{key: "name", value: "Wi"}},
+		{name: "nested value", pos: position{Line: 2, Character: 8}, want: cursor{path: []string{"async"}, key: "type"}},
+		{name: "nested key", pos: position{Line: 4, Character: 4}, want: cursor{path: []string{"async", "operation"}}},
+		{name: "list item", pos: position{Line: 8, Character: 4}, want: cursor{path: []string{"properties", "-"}}},
+		{name: "new list item", pos: position{Line: 9, Character: 4}, want: cursor{path: []string{"properties", "-"}}},
+		{name: "deeply nested", pos: position{Line: 14, Character: 19}, want: cursor{path: []string{"properties", "-", "item_type", "properties", "-"}, key: "type", value: "Str"}},
+		{name: "unindented list", pos: position{Line: 17, Character: 2}, want: cursor{path: []string{"examples", "-"}}},
+	}
+	lines := strings.Split(doc, "\n")
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := locate(lines, tc.pos)
+			if !ok {
+				t.Fatalf("locate(%v) found no cursor", tc.pos)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(cursor{})); diff != "" {
+				t.Errorf("locate(%v) differs (-want +got):\n%s", tc.pos, diff)
+			}
+		})
+	}
+}
+
+const testWidget = `name: Widget
+description: A widget.
+custom_code:
+  encoder: templates/terraform/encoders/widget.go.tmpl
+properties:
+  - name: gadget
+    type: ResourceRef
+    resource: Gadget
+    imports: name
+    requried: true
+`
+
+var testTree = map[string]string{
+	"products/widgets/product.yaml":               "name: Widgets\n",
+	"products/widgets/Widget.yaml":                testWidget,
+	"products/widgets/Gadget.yaml":                "# A gadget.\nname: Gadget\n",
+	"templates/terraform/encoders/widget.go.tmpl": "return obj, nil\n",
+	"api/type.go": `package api
+
+type Type struct {
+	// Whether the field must be set.
+	Required bool ` + "`yaml:\"required,omitempty\"`" + `
+}
+`,
+}
+
+type testClient struct {
+	t      *testing.T
+	w      io.Writer
+	r      *bufio.Reader
+	nextID int
+	// notifications holds the notifications read while waiting for a
+	// response.
+	notifications []message
+}
+
+func (c *testClient) send(method string, id *int, params interface{}) {
+	c.t.Helper()
+	m := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id != nil {
+		m["id"] = *id
+	}
+	body, err := json.Marshal(m)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testClient) read() message {
+	c.t.Helper()
+	header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		c.t.Fatal(err)
+	}
+	var m message
+	if err := json.Unmarshal(body, &m); err != nil {
+		c.t.Fatal(err)
+	}
+	return m
+}
+
+// call sends a request and decodes the result of its response into result.
+func (c *testClient) call(method string, params, result interface{}) {
+	c.t.Helper()
+	c.nextID++
+	id := c.nextID
+	c.send(method, &id, params)
+	for {
+		m := c.read()
+		if m.ID == nil {
+			c.notifications = append(c.notifications, m)
+			continue
+		}
+		if m.Error != nil {
+			c.t.Fatalf("%s returned error %v", method, m.Error.Message)
+		}
+		if err := json.Unmarshal(m.Result, result); err != nil {
+			c.t.Fatal(err)
+		}
+		return
+	}
+}
+
+// diagnostics returns the next diagnostics published for uri.
+func (c *testClient) diagnostics(uri string) []diagnostic {
+	c.t.Helper()
+	for {
+		var m message
+		if len(c.notifications) > 0 {
+			m, c.notifications = c.notifications[0], c.notifications[1:]
+		} else {
+			m = c.read()
+		}
+		if m.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var params publishDiagnosticsParams
+		if err := json.Unmarshal(m.Params, &params); err != nil {
+			c.t.Fatal(err)
+		}
+		if params.URI == uri {
+			return params.Diagnostics
+		}
+	}
+}
+
+func startServer(t *testing.T, config Config) *testClient {
+	t.Helper()
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- NewServer(config).Serve(serverIn, serverOut)
+		serverOut.Close()
+	}()
+	c := &testClient{t: t, w: clientOut, r: bufio.NewReader(clientIn)}
+	t.Cleanup(func() {
+		c.send("exit", nil, nil)
+		if err := <-done; err != nil {
+			t.Errorf("Serve() = %v", err)
+		}
+	})
+	var result map[string]interface{}
+	c.call("initialize", initializeParams{RootURI: fileURI(config.BaseDirectory)}, &result)
+	c.send("initialized", nil, map[string]interface{}{})
+	return c
+}
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func labels(items []completionItem) []string {
+	var ls []string
+	for _, item := range items {
+		ls = append(ls, item.Label)
+	}
+	return ls
+}
+
+func TestServer(t *testing.T) {
+	base := writeTree(t, testTree)
+	checked := make(chan [2]string, 1)
+	c := startServer(t, Config{
+		BaseDirectory: base,
+		Check: func(baseDirectory, product string) ([]validator.Diagnostic, error) {
+			checked <- [2]string{baseDirectory, product}
+			return []validator.Diagnostic{
+				{File: "products/widgets/Widget.yaml", Rule: validator.RuleResourceSemantic, Message: "missing `base_url` for resource Widget"},
+				{File: "products/widgets/Widget.yaml", Line: 8, Column: 15, Rule: validator.RuleDanglingRef, Message: "resource `Gadget` is not defined in products/widgets"},
+				{File: "products/widgets/Gadget.yaml", Rule: validator.RuleResourceSemantic, Message: "reported for another file"},
+				{File: "products/widgets/Widget.yaml", Line: 10, Column: 5, Rule: validator.RuleUnknownKey, Message: "reported by the schema pass"},
+			}, nil
+		},
+	})
+	uri := fileURI(filepath.Join(base, "products/widgets/Widget.yaml"))
+	document := textDocumentIdentifier{URI: uri}
+
+	c.send("textDocument/didOpen", nil, didOpenParams{TextDocument: textDocumentItem{URI: uri, Text: testWidget}})
+	want := []diagnostic{
+		{Range: lspRange{Start: position{Line: 9, Character: 4}, End: position{Line: 9, Character: 12}}, Severity: 1, Code: validator.RuleUnknownKey, Source: "mmv1", Message: "unknown key `requried` for field (did you mean `required`?)"},
+	}
+	if diff := cmp.Diff(want, c.diagnostics(uri)); diff != "" {
+		t.Errorf("diagnostics on open differ (-want +got):\n%s", diff)
+	}
+	if got, want := <-checked, [2]string{base, "products/widgets"}; got != want {
+		t.Errorf("Check(%q, %q), want Check(%q, %q)", got[0], got[1], want[0], want[1])
+	}
+	want = append(want,
+		diagnostic{Range: lspRange{End: position{Character: 12}}, Severity: 1, Code: validator.RuleResourceSemantic, Source: "mmv1", Message: "missing `base_url` for resource Widget"},
+		diagnostic{Range: lspRange{Start: position{Line: 7, Character: 14}, End: position{Line: 7, Character: 20}}, Severity: 1, Code: validator.RuleDanglingRef, Source: "mmv1", Message: "resource `Gadget` is not defined in products/widgets"},
+	)
+	if diff := cmp.Diff(want, c.diagnostics(uri)); diff != "" {
+		t.Errorf("diagnostics after check differ (-want +got):\n%s", diff)
+	}
+
+	fixed := strings.Replace(testWidget, "requried", "required", 1) + "  - name: size\n    type: \n"
+	c.send("textDocument/didChange", nil, map[string]interface{}{
+		"textDocument":   document,
+		"contentChanges": []map[string]string{{"text": fixed}},
+	})
+	if diff := cmp.Diff(want[1:], c.diagnostics(uri)); diff != "" {
+		t.Errorf("diagnostics after change differ (-want +got):\n%s", diff)
+	}
+
+	t.Run("completion", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			pos      position
+			contains []string
+			excludes []string
+		}{
+			{name: "property keys", pos: position{Line: 11, Character: 4}, contains: []string{"custom_expand", "enum_values", "required"}, excludes: []string{"base_url"}},
+			{name: "resource keys", pos: position{Line: 2, Character: 0}, contains: []string{"base_url", "properties"}},
+			{name: "property types", pos: position{Line: 11, Character: 10}, contains: []string{"Enum", "ResourceRef", "String"}},
+			{name: "resource refs", pos: position{Line: 7, Character: 14}, contains: []string{"Gadget", "Widget"}},
+			{name: "booleans", pos: position{Line: 9, Character: 14}, contains: []string{"false", "true"}},
+			{name: "custom code templates", pos: position{Line: 3, Character: 11}, contains: []string{"templates/terraform/encoders/widget.go.tmpl"}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				var items []completionItem
+				c.call("textDocument/completion", positionParams{TextDocument: document, Position: tc.pos}, &items)
+				got := labels(items)
+				for _, label := range tc.contains {
+					if !strings.Contains(strings.Join(got, "\n")+"\n", label+"\n") {
+						t.Errorf("completion at %v = %v, want it to contain %q", tc.pos, got, label)
+					}
+				}
+				for _, label := range tc.excludes {
+					if strings.Contains(strings.Join(got, "\n")+"\n", label+"\n") {
+						t.Errorf("completion at %v = %v, want it to not contain %q", tc.pos, got, label)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("hover", func(t *testing.T) {
+		var got hover
+		c.call("textDocument/hover", positionParams{TextDocument: document, Position: position{Line: 9, Character: 6}}, &got)
+		want := hover{
+			Contents: markupContent{Kind: "markdown", Value: "`required`: `bool`\n\nWhether the field must be set."},
+			Range:    &lspRange{Start: position{Line: 9, Character: 4}, End: position{Line: 9, Character: 12}},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("hover differs (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("definition", func(t *testing.T) {
+		cases := []struct {
+			name string
+			pos  position
+			want location
+		}{
+			{
+				name: "resource ref",
+				pos:  position{Line: 7, Character: 15},
+				want: location{URI: fileURI(filepath.Join(base, "products/widgets/Gadget.yaml")), Range: lspRange{Start: position{Line: 1}, End: position{Line: 1, Character: 12}}},
+			},
+			{
+				name: "template",
+				pos:  position{Line: 3, Character: 30},
+				want: location{URI: fileURI(filepath.Join(base, "templates/terraform/encoders/widget.go.tmpl"))},
+			},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				var got location
+				c.call("textDocument/definition", positionParams{TextDocument: document, Position: tc.pos}, &got)
+				if diff := cmp.Diff(tc.want, got); diff != "" {
+					t.Errorf("definition differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	base := writeTree(t, map[string]string{
+		"products/widgets/product.yaml": "name: Widgets\nversions:\n  - name: ga\n    base_url: https://widgets.googleapis.com/v1/\nscopes:\n  - https://www.googleapis.com/auth/cloud-platform\n",
+		"products/widgets/Widget.yaml":  "name: Widget\nbase_url: widgets\nproperties:\n  - name: name\n    type: String\n",
+		"products/gizmos/Gizmo.yaml":    "name: Gizmo\nexclde: true\n",
+	})
+	got, err := Validate("", "ga")(base, "products/widgets")
+	if err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	want := []validator.Diagnostic{
+		{File: "products/widgets/Widget.yaml", Rule: validator.RuleResourceSemantic, Message: "missing `description` for resource Widget"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Validate() differs (-want +got):\n%s", diff)
+	}
+}
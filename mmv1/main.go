@@ -3,9 +3,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -13,10 +15,15 @@ import (
 	"golang.org/x/exp/slices"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/cache"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/jsonschema"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/loader"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/lsp"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/openapi_generate"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/plan"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/provider"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/validator"
 )
 
 var wg sync.WaitGroup
@@ -35,8 +42,14 @@ var resourceFlag = flag.String("resource", "", "optional resource name. Limits g
 var doNotGenerateCode = flag.Bool("no-code", false, "do not generate code")
 var doNotGenerateDocs = flag.Bool("no-docs", false, "do not generate docs")
 var providerFlag = flag.String("provider", "", "optional provider name. If specified, a non-default provider will be used.")
-var openapiGenerate = flag.Bool("openapi-generate", false, "Generate MMv1 YAML from openapi directory (Experimental)")
+var openapiGenerate = flag.Bool("openapi-generate", false, "Generate MMv1 YAML from openapi directory (Experimental). What was last generated is kept in .openapi/, which is not checked in, so reruns preserve hand edits")
 var verboseFlag = flag.Bool("verbose", false, "enable verbose logging")
+var cacheDirFlag = flag.String("cache-dir", "", "optional directory for the incremental generation cache. If specified, resources whose inputs and generated files are unchanged since the last run are skipped. Only the default provider records generated files; other providers always regenerate everything.")
+var validateOnlyFlag = flag.Bool("validate-only", false, "check all product and resource YAML files, report every problem found and exit without generating. Exits with status 1 if there are problems.")
+var validateFormatFlag = flag.String("validate-format", "text", "output format for --validate-only: text or sarif")
+var jsonSchemaFlag = flag.String("json-schema", "", "write JSON Schemas for product and resource YAML files to this directory and exit")
+var lspFlag = flag.Bool("lsp", false, "run a language server for product and resource YAML files over stdin and stdout. --base defaults to the workspace the editor opens.")
+var planFlag = flag.Bool("plan", false, "report how files under --output would change, with diffs, without writing to it. Writes a full temporary copy of the generated output to $TMPDIR to compare against, removed afterwards. Exits with status 2 if there are changes.")
 
 func main() {
 
@@ -51,15 +64,123 @@ func main() {
 		return
 	}
 
+	if *jsonSchemaFlag != "" {
+		baseDirectory := *baseDirectoryFlag
+		if baseDirectory == "" {
+			baseDirectory = "."
+		}
+		if err := jsonschema.Write(*jsonSchemaFlag, baseDirectory); err != nil {
+			log.Fatalf("error writing JSON Schemas: %v", err)
+		}
+		return
+	}
+
+	if *lspFlag {
+		ServeLanguageServer(*baseDirectoryFlag, *overrideDirectoryFlag, *versionFlag)
+		return
+	}
+
+	if *validateOnlyFlag {
+		if !ValidateProducts(*baseDirectoryFlag, *overrideDirectoryFlag, *versionFlag, *validateFormatFlag) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	if *outputPathFlag == "" {
 		log.Printf("No output path specified, exiting")
 		return
 	}
 
-	GenerateProducts(*productFlag, *resourceFlag, *providerFlag, *versionFlag, *outputPathFlag, *baseDirectoryFlag, *overrideDirectoryFlag, !*doNotGenerateCode, !*doNotGenerateDocs)
+	if *planFlag {
+		if PlanProducts(*productFlag, *resourceFlag, *providerFlag, *versionFlag, *outputPathFlag, *baseDirectoryFlag, *overrideDirectoryFlag, !*doNotGenerateCode, !*doNotGenerateDocs) {
+			os.Exit(2)
+		}
+		return
+	}
+
+	GenerateProducts(*productFlag, *resourceFlag, *providerFlag, *versionFlag, *outputPathFlag, *baseDirectoryFlag, *overrideDirectoryFlag, *cacheDirFlag, !*doNotGenerateCode, !*doNotGenerateDocs)
 }
 
-func GenerateProducts(product, resource, providerName, version, outputPath, baseDirectory, overrideDirectory string, generateCode, generateDocs bool) {
+// ValidateProducts checks every product and resource YAML file and writes the
+// problems found to stdout in the given format. It returns true if there are
+// none.
+func ValidateProducts(baseDirectory, overrideDirectory, version, format string) bool {
+	if baseDirectory == "" {
+		var err error
+		if baseDirectory, err = os.Getwd(); err != nil {
+			panic(err)
+		}
+	}
+
+	var write func(io.Writer, []validator.Diagnostic) error
+	switch format {
+	case "text":
+		write = validator.WriteText
+	case "sarif":
+		write = validator.WriteSARIF
+	default:
+		log.Fatalf("unknown --validate-format %q, expected text or sarif", format)
+	}
+
+	diagnostics, err := validator.Validate(validator.Config{BaseDirectory: baseDirectory, OverrideDirectory: overrideDirectory, Version: version})
+	if err != nil {
+		log.Fatalf("error validating products: %v", err)
+	}
+	if err := write(os.Stdout, diagnostics); err != nil {
+		log.Fatalf("error writing diagnostics: %v", err)
+	}
+	log.Printf("Found %d problems.", len(diagnostics))
+	return len(diagnostics) == 0
+}
+
+// ServeLanguageServer runs the language server over stdin and stdout. Saved
+// files are validated in process, along with the rest of their product.
+func ServeLanguageServer(baseDirectory, overrideDirectory, version string) {
+	server := lsp.NewServer(lsp.Config{BaseDirectory: baseDirectory, OverrideDirectory: overrideDirectory, Check: lsp.Validate(overrideDirectory, version)})
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("error serving: %v", err)
+	}
+}
+
+// PlanProducts generates into a scratch directory and prints the files that
+// would be added, removed or changed under outputPath, grouped by product and
+// resource. It returns true if there are any changes.
+//
+// Generators read back and rewrite the files they write, so the provider is
+// rendered to disk rather than in memory. The scratch directory has the same
+// name as outputPath, which decides whether copyright headers are added.
+func PlanProducts(product, resource, providerName, version, outputPath, baseDirectory, overrideDirectory string, generateCode, generateDocs bool) bool {
+	tempPath, err := os.MkdirTemp("", "mmv1-plan-")
+	if err != nil {
+		log.Fatalf("error creating plan directory: %v", err)
+	}
+	defer os.RemoveAll(tempPath)
+	scratchPath := filepath.Join(tempPath, filepath.Base(filepath.Clean(outputPath)))
+
+	loadedProducts := GenerateProducts(product, resource, providerName, version, scratchPath, baseDirectory, overrideDirectory, "", generateCode, generateDocs)
+
+	var productFilter []string
+	if product != "" {
+		for _, prod := range strings.Split(product, ",") {
+			productFilter = append(productFilter, strings.TrimSpace(prod))
+		}
+	}
+	scope := plan.NewScope(loadedProducts, productFilter, resource)
+	scope.NoCode = !generateCode
+	scope.NoDocs = !generateDocs
+	changes, err := plan.Compare(scratchPath, outputPath, scope)
+	if err != nil {
+		log.Fatalf("error comparing against %q: %v", outputPath, err)
+	}
+	plan.Report(os.Stdout, changes)
+	return len(changes) > 0
+}
+
+// GenerateProducts generates the requested products into outputPath and
+// returns every product loaded for the version. If cacheDir is set, resources
+// that are unchanged since the last generation into outputPath are skipped.
+func GenerateProducts(product, resource, providerName, version, outputPath, baseDirectory, overrideDirectory, cacheDir string, generateCode, generateDocs bool) []*api.Product {
 	if version == "" {
 		log.Printf("No version specified, assuming ga")
 		version = "ga"
@@ -86,9 +207,22 @@ func GenerateProducts(product, resource, providerName, version, outputPath, base
 
 	wrappedFS := loader.NewVarsReplacingFS(ofs)
 
-	loader := loader.NewLoader(loader.Config{Version: version, BaseDirectory: baseDirectory, OverrideDirectory: overrideDirectory, Sysfs: wrappedFS, CompilerTarget: providerName})
+	var generationCache *cache.Cache
+	if cacheDir != "" {
+		generationCache, err = cache.Open(cache.Config{Dir: cacheDir, OutputPath: outputPath, Version: version, Provider: providerName, GenerateCode: generateCode, GenerateDocs: generateDocs, BaseDirectory: baseDirectory, OverrideDirectory: overrideDirectory, TemplateFS: wrappedFS})
+		if err != nil {
+			log.Fatalf("error opening generation cache: %v", err)
+		}
+	}
+
+	loader := loader.NewLoader(loader.Config{Version: version, BaseDirectory: baseDirectory, OverrideDirectory: overrideDirectory, Sysfs: wrappedFS, CompilerTarget: providerName, Cache: generationCache})
 	loader.LoadProducts()
 	loader.AddExtraFields()
+	if generationCache != nil {
+		if err := generationCache.Hash(loader.Products); err != nil {
+			log.Fatalf("error hashing generation inputs: %v", err)
+		}
+	}
 	loader.Validate()
 	loadedProducts := loader.Products
 
@@ -108,7 +242,7 @@ func GenerateProducts(product, resource, providerName, version, outputPath, base
 
 	for _, productApi := range loadedProducts {
 		wg.Add(1)
-		go GenerateProduct(version, providerName, productApi, outputPath, startTime, wrappedFS, productsToGenerate, resource, generateCode, generateDocs)
+		go GenerateProduct(version, providerName, productApi, outputPath, startTime, wrappedFS, generationCache, productsToGenerate, resource, generateCode, generateDocs)
 	}
 	wg.Wait()
 
@@ -122,21 +256,28 @@ func GenerateProducts(product, resource, providerName, version, outputPath, base
 
 	// In order to only copy/compile files once per provider this must be called outside
 	// of the products loop. Create an MMv1 provider with a nil product to trigger shared file behavior.
-	providerToGenerate := newProvider(providerName, version, nil, startTime, wrappedFS)
+	providerToGenerate := newProvider(providerName, version, nil, startTime, wrappedFS, nil)
 	providerToGenerate.CopyCommonFiles(outputPath, generateCode, generateDocs)
 
 	if generateCode {
 		providerToGenerate.CompileCommonFiles(outputPath, productsForVersion, "")
 	}
 
+	if generationCache != nil {
+		if err := generationCache.Save(); err != nil {
+			log.Printf("error saving generation cache: %v", err)
+		}
+	}
+
 	log.Printf("Generated %d products, %d resources for %s version %s.", productCount, resourceCount, providerName, version)
 	log.Println("Done MM generation.")
+	return productsForVersion
 }
 
 // GenerateProduct generates code and documentation for a product
 // This now uses the CompileProduct method to separate compilation from generation
 func GenerateProduct(version, providerName string, productApi *api.Product, outputPath string,
-	startTime time.Time, fsys fs.FS, productsToGenerate []string, resourceToGenerate string,
+	startTime time.Time, fsys fs.FS, generationCache *cache.Cache, productsToGenerate []string, resourceToGenerate string,
 	generateCode, generateDocs bool) {
 	defer wg.Done()
 
@@ -146,7 +287,7 @@ func GenerateProduct(version, providerName string, productApi *api.Product, outp
 	}
 
 	google.LogVerbose("%s: Generating files", productApi.PackagePath)
-	providerToGenerate := newProvider(providerName, version, productApi, startTime, fsys)
+	providerToGenerate := newProvider(providerName, version, productApi, startTime, fsys, generationCache)
 	providerToGenerate.Generate(outputPath, resourceToGenerate, generateCode, generateDocs)
 
 	providerToGenerate.CopyCommonFiles(outputPath, generateCode, generateDocs)
@@ -155,7 +296,7 @@ func GenerateProduct(version, providerName string, productApi *api.Product, outp
 	}
 }
 
-func newProvider(providerName, version string, productApi *api.Product, startTime time.Time, fsys fs.FS) provider.Provider {
+func newProvider(providerName, version string, productApi *api.Product, startTime time.Time, fsys fs.FS, generationCache *cache.Cache) provider.Provider {
 	switch providerName {
 	case "tgc":
 		return provider.NewTerraformGoogleConversion(productApi, version, startTime, fsys)
@@ -166,6 +307,8 @@ func newProvider(providerName, version string, productApi *api.Product, startTim
 	case "oics":
 		return provider.NewTerraformOiCS(productApi, version, startTime, fsys)
 	default:
-		return provider.NewTerraform(productApi, version, startTime, fsys)
+		t := provider.NewTerraform(productApi, version, startTime, fsys)
+		t.Cache = generationCache
+		return t
 	}
 }
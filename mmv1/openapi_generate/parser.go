@@ -114,38 +114,190 @@ func (parser Parser) WriteYaml(filePath string) {
 }
 
 func (parser Parser) writeResource(resource api.Resource, productPath string) {
-	// marshal method
-	var yamlContent bytes.Buffer
 	resourceOutPathMarshal := filepath.Join(productPath, fmt.Sprintf("%s.yaml", resource.Name))
-	encoder := yaml.NewEncoder(&yamlContent)
-	encoder.SetIndent(2)
+	if err := writeYaml(resourceOutPathMarshal, generatedYamlPath(parser.Output, resourceOutPathMarshal), &resource); err != nil {
+		log.Fatalf("error writing resource file %v", err)
+	}
+	log.Printf("Generated resource %s", resourceOutPathMarshal)
+}
 
-	err := encoder.Encode(&resource)
+// writeYaml writes value to filePath. If the file already exists, values
+// inferred from the OpenAPI document are merged into it rather than
+// replacing it, so hand-written changes are preserved. What was generated is
+// kept at generatedPath, so that keys deleted by hand since the last run
+// aren't added back.
+func writeYaml(filePath, generatedPath string, value any) error {
+	var generated yaml.Node
+	if err := generated.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filePath, err)
+	}
+	generatedContent, err := encodeYaml(&generated)
 	if err != nil {
-		log.Fatalf("Failed to encode: %v", err)
+		return fmt.Errorf("failed to encode %s: %w", filePath, err)
+	}
+
+	prefix := header
+	doc := &generated
+	existing, err := os.ReadFile(filePath)
+	switch {
+	case err == nil:
+		// Keep the license header and document marker verbatim, as they
+		// don't survive a round trip through yaml.Node.
+		prefix, existing = splitYamlHeader(existing)
+		var existingDoc yaml.Node
+		if err := yaml.Unmarshal(existing, &existingDoc); err != nil {
+			return fmt.Errorf("failed to parse existing %s: %w", filePath, err)
+		}
+		base, err := readGeneratedYaml(generatedPath)
+		if err != nil {
+			return err
+		}
+		if len(existingDoc.Content) > 0 {
+			mergeYamlNodes(existingDoc.Content[0], &generated, base)
+			doc = &existingDoc
+		}
+	case !os.IsNotExist(err):
+		return err
 	}
 
-	f, err := os.Create(resourceOutPathMarshal)
+	yamlContent, err := encodeYaml(doc)
 	if err != nil {
-		log.Fatalf("error creating resource file %v", err)
+		return fmt.Errorf("failed to encode %s: %w", filePath, err)
 	}
-	_, err = f.Write(header)
-	if err != nil {
-		log.Fatalf("error writing resource file header %v", err)
+	if err := os.WriteFile(filePath, append(slices.Clone(prefix), yamlContent...), 0644); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(generatedPath), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(generatedPath, generatedContent, 0644)
+}
+
+func encodeYaml(node *yaml.Node) ([]byte, error) {
+	var yamlContent bytes.Buffer
+	encoder := yaml.NewEncoder(&yamlContent)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
 	}
-	_, err = f.Write(yamlContent.Bytes())
+	return yamlContent.Bytes(), nil
+}
+
+// generatedYamlPath returns where the YAML last generated for filePath, a
+// file in the output directory, is kept: under a .openapi directory next to
+// output, outside of the product directories. It isn't checked in.
+func generatedYamlPath(output, filePath string) string {
+	rel, err := filepath.Rel(output, filePath)
 	if err != nil {
-		log.Fatalf("error writing resource file %v", err)
+		rel = filepath.Base(filePath)
+	}
+	return filepath.Join(filepath.Dir(output), ".openapi", rel)
+}
+
+// readGeneratedYaml returns the YAML last generated at generatedPath, or nil
+// if it wasn't generated by a previous run.
+func readGeneratedYaml(generatedPath string) (*yaml.Node, error) {
+	content, err := os.ReadFile(generatedPath)
+	if os.IsNotExist(err) {
+		return nil, nil
 	}
-	err = f.Close()
 	if err != nil {
-		log.Fatalf("error closing resource file %v", err)
+		return nil, err
 	}
-	log.Printf("Generated resource %s", resourceOutPathMarshal)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", generatedPath, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// splitYamlHeader splits a YAML file after its leading "---" document
+// marker, if there is one.
+func splitYamlHeader(content []byte) ([]byte, []byte) {
+	if bytes.HasPrefix(content, []byte("---\n")) {
+		return content[:4], content[4:]
+	}
+	if i := bytes.Index(content, []byte("\n---\n")); i >= 0 {
+		return content[:i+5], content[i+5:]
+	}
+	return nil, content
+}
+
+// mergeYamlNodes merges src into dst. Values already present in dst win, so
+// only keys that dst doesn't set are taken from src. Lists of named objects,
+// such as properties, are merged by name and new entries are appended. base
+// is what was previously generated, if known: keys and entries it has that
+// dst doesn't were deleted by hand, and aren't taken from src.
+func mergeYamlNodes(dst, src, base *yaml.Node) {
+	if dst.Kind != src.Kind {
+		return
+	}
+	if base != nil && base.Kind != dst.Kind {
+		base = nil
+	}
+	switch dst.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			var baseValue *yaml.Node
+			if base != nil {
+				baseValue = yamlMappingValue(base, key.Value)
+			}
+			if existing := yamlMappingValue(dst, key.Value); existing != nil {
+				mergeYamlNodes(existing, value, baseValue)
+			} else if baseValue == nil {
+				dst.Content = append(dst.Content, key, value)
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range src.Content {
+			name := yamlMappingValue(item, "name")
+			if name == nil {
+				// Unnamed lists, e.g. import_format, are left as written.
+				return
+			}
+			var baseItem *yaml.Node
+			if base != nil {
+				baseItem = yamlNamedItem(base, name.Value)
+			}
+			if existing := yamlNamedItem(dst, name.Value); existing != nil {
+				mergeYamlNodes(existing, item, baseItem)
+			} else if baseItem == nil {
+				dst.Content = append(dst.Content, item)
+			}
+		}
+	}
+}
+
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func yamlNamedItem(node *yaml.Node, name string) *yaml.Node {
+	for _, item := range node.Content {
+		if n := yamlMappingValue(item, "name"); n != nil && n.Value == name {
+			return item
+		}
+	}
+	return nil
 }
 
 type resourceOp struct {
 	path  string
+	verb  string
+	op    *openapi3.Operation
 	async bool
 }
 
@@ -168,21 +320,73 @@ func anyToBool(a any) bool {
 	}
 }
 
-func buildOperation(resourcePath string, op *openapi3.Operation, prefix string) (string, *resourceOp) {
+func buildOperation(resourcePath string, op *openapi3.Operation, prefix, verb string) (string, *resourceOp) {
 	if op == nil {
 		return "", nil
 	}
 	if strings.HasPrefix(op.OperationID, prefix) {
 		resourceName := strings.Replace(op.OperationID, prefix, "", 1)
-		async := false
+		async := returnsOperation(op)
 		if a, ok := op.Extensions["x-google-lro"]; ok {
 			async = anyToBool(a)
 		}
-		return resourceName, &resourceOp{path: resourcePath, async: async}
+		return resourceName, &resourceOp{path: resourcePath, verb: verb, op: op, async: async}
 	}
 	return "", nil
 }
 
+// returnsOperation reports whether a method responds with a long-running
+// operation (google.longrunning.Operation) rather than the resource.
+func returnsOperation(op *openapi3.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+	for _, response := range op.Responses.Map() {
+		if response.Value == nil {
+			continue
+		}
+		mediaType := response.Value.Content.Get("application/json")
+		if mediaType == nil || mediaType.Schema == nil {
+			continue
+		}
+		if strings.HasSuffix(mediaType.Schema.Ref, "/Operation") {
+			return true
+		}
+		if schema := mediaType.Schema.Value; schema != nil {
+			_, hasName := schema.Properties["name"]
+			_, hasDone := schema.Properties["done"]
+			if hasName && hasDone {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requestSchema returns the JSON request body schema of a method, or nil.
+func requestSchema(op *openapi3.Operation) *openapi3.Schema {
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil || op.RequestBody.Value.Content == nil {
+		return nil
+	}
+	mediaType, ok := op.RequestBody.Value.Content["application/json"]
+	if !ok || mediaType == nil || mediaType.Schema == nil {
+		return nil
+	}
+	return mediaType.Schema.Value
+}
+
+func hasParameter(op *openapi3.Operation, name string) bool {
+	if op == nil {
+		return false
+	}
+	for _, param := range op.Parameters {
+		if param.Value != nil && param.Value.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func findResources(doc *openapi3.T) map[string]*resource {
 	resources := make(map[string]*resource)
 	getDefault := func(n string) *resource {
@@ -195,15 +399,15 @@ func findResources(doc *openapi3.T) map[string]*resource {
 	}
 
 	for key, pathValue := range doc.Paths.Map() {
-		if name, op := buildOperation(key, pathValue.Post, "Create"); op != nil {
+		if name, op := buildOperation(key, pathValue.Post, "Create", "POST"); op != nil {
 			getDefault(name).create = op
 		}
-		if name, op := buildOperation(key, pathValue.Delete, "Delete"); op != nil {
+		if name, op := buildOperation(key, pathValue.Delete, "Delete", "DELETE"); op != nil {
 			getDefault(name).delete = op
 		}
-		if name, op := buildOperation(key, pathValue.Patch, "Update"); op != nil {
+		if name, op := buildOperation(key, pathValue.Patch, "Update", "PATCH"); op != nil {
 			getDefault(name).update = op
-		} else if name, op := buildOperation(key, pathValue.Put, "Update"); op != nil {
+		} else if name, op := buildOperation(key, pathValue.Put, "Update", "PUT"); op != nil {
 			getDefault(name).update = op
 		}
 	}
@@ -240,29 +444,9 @@ func buildProduct(filePath, output string, root *openapi3.T, header []byte) stri
 	apiProduct.Scopes = []string{"https://www.googleapis.com/auth/cloud-platform"}
 
 	productOutPathMarshal := filepath.Join(output, fmt.Sprintf("/%s/product.yaml", productName))
-
-	// Default yaml marshaller
-	bytes, err := yaml.Marshal(apiProduct)
-	if err != nil {
-		log.Fatalf("error marshalling yaml %v: %v", productOutPathMarshal, err)
-	}
-
-	f, err := os.Create(productOutPathMarshal)
-	if err != nil {
-		log.Fatalf("error creating product file %v", err)
-	}
-	_, err = f.Write(header)
-	if err != nil {
-		log.Fatalf("error writing product file header %v", err)
-	}
-	_, err = f.Write(bytes)
-	if err != nil {
+	if err := writeYaml(productOutPathMarshal, generatedYamlPath(output, productOutPathMarshal), apiProduct); err != nil {
 		log.Fatalf("error writing product file %v", err)
 	}
-	err = f.Close()
-	if err != nil {
-		log.Fatalf("error closing product file %v", err)
-	}
 	return productPath
 }
 
@@ -294,12 +478,8 @@ func buildSingleton(resourceName string, in *resource, root *openapi3.T) api.Res
 	resource := api.Resource{}
 	resourcePath := in.update.path
 
-	op := root.Paths.Find(resourcePath).Patch
-	verb := "PATCH"
-	if op == nil {
-		op = root.Paths.Find(resourcePath).Put
-		verb = "PUT"
-	}
+	op := in.update.op
+	verb := in.update.verb
 	parsedObjects := parseOpenApi(resourcePath, resourceName, op)
 
 	parameters := parsedObjects[0].([]*api.Type)
@@ -318,16 +498,10 @@ func buildSingleton(resourceName string, in *resource, root *openapi3.T) api.Res
 	resource.CreateVerb = verb
 
 	resource.UpdateVerb = verb
-	resource.UpdateMask = true
+	resource.UpdateMask = hasParameter(op, "updateMask")
 	if in.update.async {
 		resource.AutogenAsync = true
-		async := api.NewAsync()
-		async.Operation.BaseUrl = "{{op_id}}"
-		async.Result.ResourceInsideResponse = true
-		// Clear the default, we will attach the right values below
-		async.Actions = nil
-		resource.Async = async
-		resource.Async.Actions = append(resource.Async.Actions, "update")
+		resource.Async = newOpAsync([]string{"update"})
 	}
 
 	resource.ExcludeDelete = true
@@ -358,28 +532,31 @@ func buildResource(resourceName string, in *resource, root *openapi3.T) api.Reso
 	resource.SelfLink = selfLink
 	resource.CreateUrl = fmt.Sprintf("%s?%s={{%s}}", baseUrl, queryParam, google.Underscore(queryParam))
 
-	resource.AutogenAsync = true
-	async := api.NewAsync()
-	async.Operation.BaseUrl = "{{op_id}}"
-	async.Result.ResourceInsideResponse = true
-	// Clear the default, we will attach the right values below
-	async.Actions = nil
-	resource.Async = async
-	if in.create.async {
-		resource.Async.Actions = append(resource.Async.Actions, "create")
-	}
-
 	if in.update != nil {
-		resource.UpdateVerb = "PATCH"
-		resource.UpdateMask = true
-		if in.update.async {
-			resource.Async.Actions = append(resource.Async.Actions, "update")
-		}
+		resource.UpdateVerb = in.update.verb
+		resource.UpdateMask = hasParameter(in.update.op, "updateMask")
+		markImmutable(properties, requestSchema(in.update.op))
 	} else {
 		resource.Immutable = true
 	}
+
+	var asyncActions []string
+	if in.create.async {
+		asyncActions = append(asyncActions, "create")
+	}
 	if in.delete != nil && in.delete.async {
-		resource.Async.Actions = append(resource.Async.Actions, "delete")
+		asyncActions = append(asyncActions, "delete")
+	}
+	if in.update != nil && in.update.async {
+		asyncActions = append(asyncActions, "update")
+	}
+	if len(asyncActions) > 0 {
+		resource.AutogenAsync = true
+		resource.Async = newOpAsync(asyncActions)
+	}
+
+	if queryParam != "" {
+		resource.IamPolicy = buildIamPolicy(root, resourcePath, queryParam)
 	}
 
 	resource = attachStandardFunctionality(resource)
@@ -387,6 +564,78 @@ func buildResource(resourceName string, in *resource, root *openapi3.T) api.Reso
 	return resource
 }
 
+// newOpAsync returns an OpAsync configuration for long-running operations
+// that return the resource in their response.
+func newOpAsync(actions []string) *api.Async {
+	async := api.NewAsync()
+	async.Operation.BaseUrl = "{{op_id}}"
+	async.Result.ResourceInsideResponse = true
+	async.Actions = actions
+	return async
+}
+
+// markImmutable marks properties that can be set on create but are missing
+// from the update method's request body as immutable.
+func markImmutable(properties []*api.Type, updateSchema *openapi3.Schema) {
+	// Without named properties there's nothing to compare against.
+	if updateSchema == nil || len(updateSchema.Properties) == 0 {
+		return
+	}
+	for _, p := range properties {
+		if p.Output {
+			continue
+		}
+		updateProp, ok := updateSchema.Properties[p.Name]
+		if !ok {
+			p.Immutable = true
+			continue
+		}
+		if p.Type == "NestedObject" && updateProp.Value != nil {
+			updateValue := updateProp.Value
+			if len(updateValue.AllOf) > 0 && updateValue.AllOf[0].Value != nil {
+				updateValue = updateValue.AllOf[0].Value
+			}
+			markImmutable(p.Properties, updateValue)
+		}
+	}
+}
+
+// buildIamPolicy returns the IAM configuration for the resources under
+// resourcePath, or nil if the API has no getIamPolicy method for them.
+func buildIamPolicy(root *openapi3.T, resourcePath, idParam string) *r.IamPolicy {
+	for _, p := range slices.Sorted(maps.Keys(root.Paths.Map())) {
+		resourceSelfLink, found := strings.CutSuffix(p, ":getIamPolicy")
+		if !found || path.Dir(resourceSelfLink) != resourcePath || !strings.HasPrefix(path.Base(resourceSelfLink), "{") {
+			continue
+		}
+
+		policy := r.NewIamPolicy()
+		policy.MethodNameSeparator = ":"
+		policy.ParentResourceAttribute = google.Underscore(idParam)
+
+		getItem := root.Paths.Value(p)
+		if getItem.Get != nil {
+			policy.FetchIamPolicyVerb = "GET"
+			if hasParameter(getItem.Get, "options.requestedPolicyVersion") {
+				policy.IamConditionsRequestType = "QUERY_PARAM_NESTED"
+			}
+		} else if getItem.Post != nil {
+			policy.FetchIamPolicyVerb = "POST"
+			if body := requestSchema(getItem.Post); body != nil && body.Properties["options"] != nil {
+				policy.IamConditionsRequestType = "REQUEST_BODY"
+			}
+		} else {
+			continue
+		}
+
+		if setItem := root.Paths.Value(resourceSelfLink + ":setIamPolicy"); setItem != nil && setItem.Post == nil && setItem.Put != nil {
+			policy.SetIamPolicyVerb = "PUT"
+		}
+		return policy
+	}
+	return nil
+}
+
 // Standard functionality between regular and singleton resources
 func attachStandardFunctionality(resource api.Resource) api.Resource {
 	resource.Description = "Description"
@@ -450,10 +699,8 @@ func parseOpenApi(resourcePath, resourceName string, op *openapi3.Operation) []a
 	}
 
 	var properties []*api.Type
-	if op.RequestBody != nil && op.RequestBody.Value != nil && op.RequestBody.Value.Content != nil {
-		if mediaType, ok := op.RequestBody.Value.Content["application/json"]; ok && mediaType != nil && mediaType.Schema != nil && mediaType.Schema.Value != nil {
-			properties = buildProperties(mediaType.Schema.Value.Properties, mediaType.Schema.Value.Required, seenRefs, seenPointers)
-		}
+	if schema := requestSchema(op); schema != nil {
+		properties = buildProperties(schema.Properties, schema.Required, seenRefs, seenPointers)
 	}
 
 	returnArray = append(returnArray, parameters)
@@ -493,8 +740,6 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 	case "locationsId":
 		name = "location"
 	}
-	additionalDescription := ""
-
 	if obj.Ref != "" {
 		if seenRefs[obj.Ref] {
 			var field api.Type
@@ -542,15 +787,9 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 	switch objType[0] {
 	case "string":
 		field.Type = "String"
-		if len(obj.Value.Enum) > 0 {
-			var enums []string
-			for _, enum := range obj.Value.Enum {
-				if strings.HasSuffix(fmt.Sprintf("%v", enum), "_UNSPECIFIED") {
-					continue
-				}
-				enums = append(enums, fmt.Sprintf("%v", enum))
-			}
-			additionalDescription = fmt.Sprintf("\n Possible values:\n %s", strings.Join(enums, "\n"))
+		if enums := enumValues(obj.Value.Enum); len(enums) > 0 {
+			field.Type = "Enum"
+			field.EnumValues = enums
 		}
 	case "integer":
 		field.Type = "Integer"
@@ -595,6 +834,12 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 		switch typ[0] {
 		case "string":
 			subField.Type = "String"
+			if obj.Value.Items.Value != nil {
+				if enums := enumValues(obj.Value.Items.Value.Enum); len(enums) > 0 {
+					subField.Type = "Enum"
+					subField.EnumValues = enums
+				}
+			}
 		case "integer":
 			subField.Type = "Integer"
 		case "number":
@@ -610,7 +855,7 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 		panic(fmt.Sprintf("Failed to identify field type for %s %s", field.Name, objType[0]))
 	}
 
-	description := fmt.Sprintf("%s %s", obj.Value.Description, additionalDescription)
+	description := obj.Value.Description
 	if strings.TrimSpace(description) == "" {
 		description = "No description"
 	}
@@ -639,6 +884,12 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 		field.Immutable = true
 	}
 
+	// Not every API sets x-google-immutable, but AIP 203 field behaviors are
+	// also included at the start of descriptions.
+	if immutableDescriptionRegexp.MatchString(obj.Value.Description) {
+		field.Immutable = true
+	}
+
 	if field.Output {
 		makeOutputOnly(&field)
 	}
@@ -646,6 +897,22 @@ func WriteObject(name string, obj *openapi3.SchemaRef, objType openapi3.Types, u
 	return field
 }
 
+var immutableDescriptionRegexp = regexp.MustCompile(`^((Optional|Required)\. )?Immutable\.`)
+
+// enumValues returns the values of an enum, omitting the *_UNSPECIFIED value
+// that is equivalent to not setting the field.
+func enumValues(enum []any) []string {
+	var values []string
+	for _, v := range enum {
+		value := fmt.Sprintf("%v", v)
+		if strings.HasSuffix(value, "_UNSPECIFIED") {
+			continue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
 func makeOutputOnly(t *api.Type) {
 	if t == nil {
 		return
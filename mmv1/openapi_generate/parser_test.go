@@ -2,15 +2,23 @@ package openapi_generate
 
 import (
 	_ "embed"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
 	"github.com/getkin/kin-openapi/openapi3"
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed test_data/test_api.yaml
 var testData []byte
 
+//go:embed test_data/widgets_api.yaml
+var widgetsTestData []byte
+
 func TestMapType(t *testing.T) {
 	_ = NewOpenapiParser("/fake", "/fake")
 	ctx := t.Context()
@@ -276,3 +284,206 @@ paths:
 		t.Error("Expected optionalDeprecatedParam to be skipped, but it was found")
 	}
 }
+
+func loadWidgets(t *testing.T) (*openapi3.T, api.Resource) {
+	t.Helper()
+	ctx := t.Context()
+	loader := &openapi3.Loader{Context: ctx, IsExternalRefsAllowed: true}
+	doc, err := loader.LoadFromData(widgetsTestData)
+	if err != nil {
+		t.Fatalf("Could not load data %s", err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		t.Fatalf("Could not validate data %s", err)
+	}
+	res := findResources(doc)
+	if res["Widget"] == nil || res["Widget"].create == nil {
+		t.Fatalf("Widget resource not found: %v", res)
+	}
+	return doc, buildResource("Widget", res["Widget"], doc)
+}
+
+func findProperty(props []*api.Type, name string) *api.Type {
+	for _, p := range props {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestBuildResourceInference(t *testing.T) {
+	_, resource := loadWidgets(t)
+
+	if got, want := resource.SelfLink, "projects/{{project}}/locations/{{location}}/widgets/{{widget_id}}"; got != want {
+		t.Errorf("SelfLink = %q, want %q", got, want)
+	}
+	if resource.UpdateVerb != "PATCH" || !resource.UpdateMask {
+		t.Errorf("Expected a PATCH update with an update mask, got verb %q and update_mask %t", resource.UpdateVerb, resource.UpdateMask)
+	}
+	if resource.Async == nil || !resource.Async.IsA("OpAsync") {
+		t.Fatalf("Expected OpAsync to be inferred from Operation responses, got %+v", resource.Async)
+	}
+	if want := []string{"create", "delete", "update"}; !slices.Equal(resource.Async.Actions, want) {
+		t.Errorf("Async.Actions = %v, want %v", resource.Async.Actions, want)
+	}
+
+	immutable := map[string]bool{
+		"displayName": false,
+		"size":        false,
+		"zone":        true,
+		"kind":        true,
+	}
+	for name, want := range immutable {
+		p := findProperty(resource.Properties, name)
+		if p == nil {
+			t.Errorf("Property %q not found", name)
+		} else if p.Immutable != want {
+			t.Errorf("Property %q immutable = %t, want %t", name, p.Immutable, want)
+		}
+	}
+	settings := findProperty(resource.Properties, "settings")
+	if settings == nil {
+		t.Fatal("Property settings not found")
+	}
+	if color := findProperty(settings.Properties, "color"); color == nil || color.Immutable {
+		t.Errorf("Expected settings.color to be updatable, got %+v", color)
+	}
+	if shape := findProperty(settings.Properties, "shape"); shape == nil || !shape.Immutable {
+		t.Errorf("Expected settings.shape to be immutable, got %+v", shape)
+	}
+
+	size := findProperty(resource.Properties, "size")
+	if size.Type != "Enum" || !slices.Equal(size.EnumValues, []string{"SMALL", "LARGE"}) {
+		t.Errorf("Expected size to be an Enum of [SMALL LARGE], got %s %v", size.Type, size.EnumValues)
+	}
+
+	iam := resource.IamPolicy
+	if iam == nil {
+		t.Fatal("Expected IAM to be inferred from getIamPolicy")
+	}
+	if iam.MethodNameSeparator != ":" || iam.FetchIamPolicyVerb != "GET" || iam.ParentResourceAttribute != "widget_id" || iam.IamConditionsRequestType != "QUERY_PARAM_NESTED" {
+		t.Errorf("Unexpected IAM policy %+v", iam)
+	}
+}
+
+func TestGeneratedYamlPath(t *testing.T) {
+	got := generatedYamlPath(filepath.Join("mmv1", "products"), filepath.Join("mmv1", "products", "widgets", "Widget.yaml"))
+	if want := filepath.Join("mmv1", ".openapi", "widgets", "Widget.yaml"); got != want {
+		t.Errorf("generatedYamlPath() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteYamlMerge(t *testing.T) {
+	_, resource := loadWidgets(t)
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "Widget.yaml")
+	generatedPath := filepath.Join(dir, ".openapi", "Widget.yaml")
+
+	existing := `# Hand-written header
+---
+name: Widget
+description: A hand-written description.
+immutable: false
+properties:
+  - name: displayName
+    type: String
+    description: A hand-written field description.
+  - name: handWritten
+    type: String
+    description: A virtual field.
+`
+	if err := os.WriteFile(filePath, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeYaml(filePath, generatedPath, &resource); err != nil {
+		t.Fatalf("writeYaml() = %s", err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "# Hand-written header\n---\n") {
+		t.Errorf("Expected the file header to be preserved, got:\n%s", content)
+	}
+
+	var merged api.Resource
+	if err := yaml.Unmarshal(content, &merged); err != nil {
+		t.Fatalf("Failed to parse merged file: %s", err)
+	}
+	if merged.Description != "A hand-written description." {
+		t.Errorf("Description = %q, want the hand-written value", merged.Description)
+	}
+	if merged.UpdateVerb != "PATCH" || merged.SelfLink != resource.SelfLink {
+		t.Errorf("Expected inferred values to be added, got update_verb %q and self_link %q", merged.UpdateVerb, merged.SelfLink)
+	}
+	if p := findProperty(merged.Properties, "displayName"); p == nil || p.Description != "A hand-written field description." {
+		t.Errorf("Expected displayName to keep its hand-written description, got %+v", p)
+	}
+	if findProperty(merged.Properties, "handWritten") == nil {
+		t.Error("Expected the hand-written property to be kept")
+	}
+	if p := findProperty(merged.Properties, "zone"); p == nil || !p.Immutable {
+		t.Errorf("Expected the zone property to be added, got %+v", p)
+	}
+	if names := []string{merged.Properties[0].Name, merged.Properties[1].Name}; !slices.Equal(names, []string{"displayName", "handWritten"}) {
+		t.Errorf("Expected existing properties to keep their order, got %v", names)
+	}
+}
+
+func TestWriteYamlMergeKeepsDeletions(t *testing.T) {
+	_, resource := loadWidgets(t)
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "Widget.yaml")
+	generatedPath := filepath.Join(dir, ".openapi", "Widget.yaml")
+	if err := writeYaml(filePath, generatedPath, &resource); err != nil {
+		t.Fatalf("writeYaml() = %s", err)
+	}
+
+	// Delete a key and a property by hand.
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edited api.Resource
+	if err := yaml.Unmarshal(content, &edited); err != nil {
+		t.Fatal(err)
+	}
+	edited.UpdateVerb = ""
+	edited.Properties = slices.DeleteFunc(edited.Properties, func(p *api.Type) bool { return p.Name == "zone" })
+	content, err = yaml.Marshal(&edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The API gains a field.
+	resource.Properties = append(slices.Clone(resource.Properties), &api.Type{Name: "color", Type: "String"})
+	if err := writeYaml(filePath, generatedPath, &resource); err != nil {
+		t.Fatalf("writeYaml() = %s", err)
+	}
+
+	content, err = os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var merged api.Resource
+	if err := yaml.Unmarshal(content, &merged); err != nil {
+		t.Fatalf("Failed to parse merged file: %s", err)
+	}
+	if merged.UpdateVerb != "" {
+		t.Errorf("Expected the deleted update_verb to stay deleted, got %q", merged.UpdateVerb)
+	}
+	if p := findProperty(merged.Properties, "zone"); p != nil {
+		t.Errorf("Expected the deleted zone property to stay deleted, got %+v", p)
+	}
+	if findProperty(merged.Properties, "color") == nil {
+		t.Error("Expected the new color property to be added")
+	}
+	if findProperty(merged.Properties, "displayName") == nil {
+		t.Error("Expected the displayName property to be kept")
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2026 Google Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plan compares a generated provider against an existing output
+// directory, to preview the effect of a change before writing it.
+package plan
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a file that generation would add, remove or change.
+type Change struct {
+	// Path is relative to the output directory.
+	Path string
+	Kind ChangeKind
+	Owner
+	// Diff is a unified diff of the file contents.
+	Diff string
+}
+
+// Owner identifies the product and resource a generated file belongs to.
+// Files shared across the provider have no owner.
+type Owner struct {
+	// Product is the product's directory name, as passed to --product.
+	Product string
+	// Resource is the resource name, as passed to --resource.
+	Resource string
+}
+
+// Scope attributes generated files to products and resources, and limits
+// which files in the output directory a plan may report as removed.
+type Scope struct {
+	// Products and Resource mirror the --product and --resource flags. If
+	// empty, every product or resource is in scope.
+	Products []string
+	Resource string
+	// NoCode and NoDocs mirror the --no-code and --no-docs flags. Files of a
+	// kind that wasn't generated are never reported as removed.
+	NoCode bool
+	NoDocs bool
+
+	// stems maps a resource's Terraform name without the "google_" prefix to
+	// its owner. Generated file names contain this stem.
+	stems map[string]Owner
+	// packages maps a services/ package name to its product.
+	packages map[string]string
+}
+
+// NewScope builds a Scope for the loaded products.
+func NewScope(products []*api.Product, productFilter []string, resourceFilter string) Scope {
+	s := Scope{
+		Products: productFilter,
+		Resource: resourceFilter,
+		stems:    make(map[string]Owner),
+		packages: make(map[string]string),
+	}
+	for _, p := range products {
+		productName := filepath.Base(p.PackagePath)
+		s.packages[p.ApiName] = productName
+		for _, r := range p.Objects {
+			stem := strings.TrimPrefix(r.TerraformName(), "google_")
+			s.stems[stem] = Owner{Product: productName, Resource: r.Name}
+		}
+	}
+	return s
+}
+
+// Owner returns the owner of a file, given its path relative to the output
+// directory. Files are matched to the resource with the longest Terraform
+// name contained in their file name, then to the product of their services/
+// package.
+func (s Scope) Owner(path string) Owner {
+	base := filepath.Base(path)
+	var owner Owner
+	longest := ""
+	for stem, o := range s.stems {
+		if len(stem) > len(longest) && strings.Contains(base, stem) {
+			longest, owner = stem, o
+		}
+	}
+
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for i, segment := range segments[:len(segments)-1] {
+		if segment != "services" || i+1 >= len(segments)-1 {
+			continue
+		}
+		if product, ok := s.packages[segments[i+1]]; ok {
+			if owner.Product != product {
+				// The file name matched a resource in another product.
+				owner = Owner{Product: product}
+			}
+		}
+		break
+	}
+	return owner
+}
+
+// inScope reports whether a file that was not generated may be reported as
+// removed. Only files attributable to a generated product or resource are
+// considered, and files without an owner only if every product was
+// generated.
+func (s Scope) inScope(path string, owner Owner, generated bool) bool {
+	isDoc := strings.HasPrefix(filepath.ToSlash(path), "website/")
+	if (isDoc && s.NoDocs) || (!isDoc && s.NoCode) {
+		return false
+	}
+	if len(s.Products) > 0 && !slices.Contains(s.Products, owner.Product) {
+		return false
+	}
+	if s.Resource != "" && owner.Resource != s.Resource {
+		return false
+	}
+	return owner.Resource != "" || generated
+}
+
+// generatedMarker is included in the header of every file that Magic Modules
+// writes from a template or copies from third_party/.
+var generatedMarker = []byte("***     AUTO GENERATED CODE    ***")
+
+// Compare returns the differences between the provider rendered into
+// plannedDir and outputDir, sorted by owner and path.
+func Compare(plannedDir, outputDir string, scope Scope) ([]Change, error) {
+	var changes []Change
+	planned := make(map[string]bool)
+
+	err := filepath.WalkDir(plannedDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(plannedDir, path)
+		if err != nil {
+			return err
+		}
+		planned[rel] = true
+
+		after, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		before, err := os.ReadFile(filepath.Join(outputDir, rel))
+		switch {
+		case os.IsNotExist(err):
+			changes = append(changes, newChange(rel, Added, nil, after, scope))
+		case err != nil:
+			return err
+		case !bytes.Equal(before, after):
+			changes = append(changes, newChange(rel, Changed, before, after, scope))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if os.IsNotExist(err) && path == outputDir {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != outputDir && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil || planned[rel] {
+			return err
+		}
+		before, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if scope.inScope(rel, scope.Owner(rel), bytes.Contains(before, generatedMarker)) {
+			changes = append(changes, newChange(rel, Removed, before, nil, scope))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		if c := strings.Compare(a.Product, b.Product); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Resource, b.Resource); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Path, b.Path)
+	})
+	return changes, nil
+}
+
+func newChange(path string, kind ChangeKind, before, after []byte, scope Scope) Change {
+	fromFile, toFile := "a/"+path, "b/"+path
+	switch kind {
+	case Added:
+		fromFile = "/dev/null"
+	case Removed:
+		toFile = "/dev/null"
+	}
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(before),
+		B:        splitLines(after),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		diff = fmt.Sprintf("error computing diff: %s\n", err)
+	}
+	return Change{
+		Path:  filepath.ToSlash(path),
+		Kind:  kind,
+		Owner: scope.Owner(path),
+		Diff:  diff,
+	}
+}
+
+// splitLines splits content into lines that each end in a newline.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+// Report writes the changes to w, grouped by product and resource, followed
+// by a summary.
+func Report(w io.Writer, changes []Change) {
+	counts := make(map[ChangeKind]int)
+	var group *Owner
+	for _, c := range changes {
+		if group == nil || *group != c.Owner {
+			group = &c.Owner
+			switch {
+			case c.Resource != "":
+				fmt.Fprintf(w, "\n## %s/%s\n\n", c.Product, c.Resource)
+			case c.Product != "":
+				fmt.Fprintf(w, "\n## %s\n\n", c.Product)
+			default:
+				fmt.Fprintf(w, "\n## Shared files\n\n")
+			}
+		}
+		counts[c.Kind]++
+		fmt.Fprintf(w, "%s %s\n", c.Kind, c.Path)
+		io.WriteString(w, c.Diff)
+	}
+	fmt.Fprintf(w, "\nPlan: %d to add, %d to change, %d to remove.\n", counts[Added], counts[Changed], counts[Removed])
+}
@@ -0,0 +1,188 @@
+package plan
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testScope(productFilter []string, resourceFilter string) Scope {
+	widgets := &api.Product{Name: "Widgets", ApiName: "widgets", PackagePath: "products/widgets"}
+	widgets.Objects = []*api.Resource{
+		{Name: "Widget", ProductMetadata: widgets},
+		{Name: "WidgetPart", ProductMetadata: widgets},
+	}
+	gadgets := &api.Product{Name: "Gadgets", ApiName: "gadgets", PackagePath: "products/gadgets"}
+	gadgets.Objects = []*api.Resource{
+		{Name: "Gadget", ProductMetadata: gadgets},
+	}
+	return NewScope([]*api.Product{widgets, gadgets}, productFilter, resourceFilter)
+}
+
+func TestScopeOwner(t *testing.T) {
+	t.Parallel()
+
+	scope := testScope(nil, "")
+	cases := map[string]Owner{
+		"google/services/widgets/resource_widgets_widget.go":                {Product: "widgets", Resource: "Widget"},
+		"google/services/widgets/resource_widgets_widget_part_test.go":      {Product: "widgets", Resource: "WidgetPart"},
+		"google/services/widgets/widgets_operation.go":                      {Product: "widgets"},
+		"website/docs/r/widgets_widget_part.html.markdown":                  {Product: "widgets", Resource: "WidgetPart"},
+		"google/services/gadgets/resource_gadgets_gadget_generated_test.go": {Product: "gadgets", Resource: "Gadget"},
+		"google/provider/provider_mmv1_resources.go":                        {},
+	}
+	for path, want := range cases {
+		if got := scope.Owner(path); got != want {
+			t.Errorf("Owner(%q) = %+v, want %+v", path, got, want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		description    string
+		productFilter  []string
+		resourceFilter string
+		noDocs         bool
+		want           map[string]ChangeKind
+	}{
+		{
+			description: "all products",
+			want: map[string]ChangeKind{
+				"google/provider/provider.go":                             Changed,
+				"google/provider/stale.go":                                Removed,
+				"google/services/gadgets/resource_gadgets_gadget.go":      Removed,
+				"google/services/widgets/resource_widgets_widget.go":      Changed,
+				"google/services/widgets/resource_widgets_widget_part.go": Added,
+				"website/docs/r/gadgets_gadget.html.markdown":             Removed,
+			},
+		},
+		{
+			description:   "filtered by product",
+			productFilter: []string{"widgets"},
+			want: map[string]ChangeKind{
+				"google/provider/provider.go":                             Changed,
+				"google/services/widgets/resource_widgets_widget.go":      Changed,
+				"google/services/widgets/resource_widgets_widget_part.go": Added,
+			},
+		},
+		{
+			description:    "filtered by resource",
+			productFilter:  []string{"gadgets"},
+			resourceFilter: "Gadget",
+			want: map[string]ChangeKind{
+				"google/provider/provider.go":                             Changed,
+				"google/services/gadgets/resource_gadgets_gadget.go":      Removed,
+				"google/services/widgets/resource_widgets_widget.go":      Changed,
+				"google/services/widgets/resource_widgets_widget_part.go": Added,
+				"website/docs/r/gadgets_gadget.html.markdown":             Removed,
+			},
+		},
+		{
+			description:    "without docs",
+			productFilter:  []string{"gadgets"},
+			resourceFilter: "Gadget",
+			noDocs:         true,
+			want: map[string]ChangeKind{
+				"google/provider/provider.go":                             Changed,
+				"google/services/gadgets/resource_gadgets_gadget.go":      Removed,
+				"google/services/widgets/resource_widgets_widget.go":      Changed,
+				"google/services/widgets/resource_widgets_widget_part.go": Added,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.description, func(t *testing.T) {
+			t.Parallel()
+
+			planned, output := t.TempDir(), t.TempDir()
+			writeFiles(t, planned, map[string]string{
+				"google/provider/provider.go":                             "package provider\n\nvar a = 2\n",
+				"google/services/widgets/resource_widgets_widget.go":      "package widgets\n\nvar b = 2\n",
+				"google/services/widgets/resource_widgets_widget_part.go": "package widgets\n",
+				"google/services/widgets/widgets_operation.go":            "package widgets\n",
+			})
+			writeFiles(t, output, map[string]string{
+				"google/provider/provider.go":                        "package provider\n\nvar a = 1\n",
+				"google/provider/stale.go":                           "// ***     AUTO GENERATED CODE    ***\npackage provider\n",
+				"google/services/widgets/resource_widgets_widget.go": "package widgets\n\nvar b = 1\n",
+				"google/services/widgets/widgets_operation.go":       "package widgets\n",
+				"google/services/gadgets/resource_gadgets_gadget.go": "package gadgets\n",
+				"website/docs/r/gadgets_gadget.html.markdown":        "# Gadget\n",
+				"GNUmakefile": "default: build\n",
+				".git/HEAD":   "ref: refs/heads/main\n",
+			})
+
+			scope := testScope(tc.productFilter, tc.resourceFilter)
+			scope.NoDocs = tc.noDocs
+			changes, err := Compare(planned, output, scope)
+			if err != nil {
+				t.Fatalf("Compare() = %s", err)
+			}
+			got := make(map[string]ChangeKind)
+			for _, c := range changes {
+				got[c.Path] = c.Kind
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Compare() unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	planned, output := t.TempDir(), t.TempDir()
+	writeFiles(t, planned, map[string]string{
+		"google/services/widgets/resource_widgets_widget.go": "package widgets\n\nvar b = 2\n",
+	})
+	writeFiles(t, output, map[string]string{
+		"google/services/widgets/resource_widgets_widget.go": "package widgets\n\nvar b = 1\n",
+	})
+	changes, err := Compare(planned, output, testScope(nil, ""))
+	if err != nil {
+		t.Fatalf("Compare() = %s", err)
+	}
+
+	var out bytes.Buffer
+	Report(&out, changes)
+	want := `
+## widgets/Widget
+
+changed google/services/widgets/resource_widgets_widget.go
+--- a/google/services/widgets/resource_widgets_widget.go
++++ b/google/services/widgets/resource_widgets_widget.go
+@@ -1,3 +1,3 @@
+ package widgets
+ 
+-var b = 1
++var b = 2
+
+Plan: 0 to add, 1 to change, 0 to remove.
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("Report() unexpected diff (-want +got):\n%s", diff)
+	}
+}
@@ -34,6 +34,9 @@ type TemplateData struct {
 	VersionName  string
 	templateFS   fs.FS
 
+	// written, if set, collects the path of every file this writes.
+	written *[]string
+
 	// TODO rewrite: is this needed?
 	//     # Information about the local environment
 	//     # (which formatters are enabled, start-time)
@@ -72,6 +75,9 @@ func (td *TemplateData) GenerateFWResourceFile(filePath string, resource api.Res
 	templates := []string{
 		templatePath,
 		"templates/terraform/schema_property_fw.go.tmpl",
+		"templates/terraform/flatten_property_method_fw.go.tmpl",
+		"templates/terraform/expand_property_method_fw.go.tmpl",
+		"templates/terraform/update_mask_fw.go.tmpl",
 	}
 	td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
@@ -86,6 +92,7 @@ func (td *TemplateData) GenerateMetadataFile(filePath string, resource api.Resou
 	if err != nil {
 		glog.Exit(err)
 	}
+	td.recordWrite(filePath)
 }
 
 func (td *TemplateData) GenerateDataSourceFile(filePath string, resource api.Resource) {
@@ -96,6 +103,33 @@ func (td *TemplateData) GenerateDataSourceFile(filePath string, resource api.Res
 	td.GenerateFile(filePath, templatePath, resource, true, templates...)
 }
 
+func (td *TemplateData) GenerateEphemeralResourceFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/ephemeral_resource.go.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/schema_property_ephemeral.go.tmpl",
+		"templates/terraform/schema_property_fw.go.tmpl",
+		"templates/terraform/flatten_property_method_fw.go.tmpl",
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateActionFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/action.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateIdFunctionFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/function_id.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
 func (td *TemplateData) GenerateProductFile(filePath string, product api.Product) {
 	templatePath := "templates/terraform/product.go.tmpl"
 	templates := []string{
@@ -140,6 +174,41 @@ func (td *TemplateData) GenerateDataSourceDocumentationFile(filePath string, res
 	td.GenerateFile(filePath, templatePath, resource, false, templates...)
 }
 
+func (td *TemplateData) GenerateEphemeralResourceDocumentationFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/ephemeral_resource.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/property_documentation.html.markdown.tmpl",
+		"templates/terraform/nested_property_documentation.html.markdown.tmpl",
+	}
+	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+}
+
+func (td *TemplateData) GenerateActionDocumentationFile(filePath string, action api.Action) {
+	templatePath := "templates/terraform/action.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+		"templates/terraform/property_documentation.html.markdown.tmpl",
+	}
+	td.GenerateFile(filePath, templatePath, action, false, templates...)
+}
+
+func (td *TemplateData) GenerateIdParseFunctionDocumentationFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/function_id_parse.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+}
+
+func (td *TemplateData) GenerateIdBuildFunctionDocumentationFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/function_id_build.html.markdown.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, false, templates...)
+}
+
 func (td *TemplateData) GenerateTestFile(filePath string, resource api.Resource) {
 	templatePath := "templates/terraform/samples/base_configs/test_file.go.tmpl"
 	templates := []string{
@@ -169,6 +238,16 @@ func (td *TemplateData) GenerateTestFile(filePath string, resource api.Resource)
 	td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
 }
 
+func (td *TemplateData) GenerateContractTestFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/samples/base_configs/contract_test_file.go.tmpl"
+	tmplInput := TestInput{
+		Res:        resource,
+		ImportPath: resource.ImportPath,
+	}
+
+	td.GenerateFile(filePath, templatePath, tmplInput, true, templatePath)
+}
+
 func (td *TemplateData) GenerateDataSourceTestFile(filePath string, resource api.Resource) {
 	templatePath := "templates/terraform/samples/base_configs/datasource_test_file.go.tmpl"
 	templates := []string{
@@ -198,6 +277,30 @@ func (td *TemplateData) GenerateDataSourceTestFile(filePath string, resource api
 	td.GenerateFile(filePath, templatePath, tmplInput, true, templates...)
 }
 
+func (td *TemplateData) GenerateEphemeralResourceTestFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/ephemeral_resource_test.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateActionTestFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/action_test.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
+func (td *TemplateData) GenerateIdFunctionTestFile(filePath string, resource api.Resource) {
+	templatePath := "templates/terraform/function_id_test.go.tmpl"
+	templates := []string{
+		templatePath,
+	}
+	td.GenerateFile(filePath, templatePath, resource, true, templates...)
+}
+
 func (td *TemplateData) GenerateIamPolicyFile(filePath string, resource api.Resource) {
 	templatePath := "templates/terraform/iam_policy.go.tmpl"
 	templates := []string{
@@ -322,6 +425,13 @@ func (td *TemplateData) GenerateFile(filePath, templatePath string, input any, g
 	if err != nil {
 		glog.Exit(err)
 	}
+	td.recordWrite(filePath)
+}
+
+func (td *TemplateData) recordWrite(filePath string) {
+	if td.written != nil {
+		*td.written = append(*td.written, filePath)
+	}
 }
 
 type TestInput struct {
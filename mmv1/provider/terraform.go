@@ -14,7 +14,6 @@
 package provider
 
 import (
-	"bufio"
 	"bytes"
 	"errors"
 	"fmt"
@@ -32,6 +31,7 @@ import (
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/product"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api/resource"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/cache"
 	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
 )
 
@@ -50,6 +50,10 @@ type Terraform struct {
 
 	StartTime time.Time
 
+	// Cache, if set, is used to skip resources whose inputs and outputs are
+	// unchanged since the last generation.
+	Cache *cache.Cache
+
 	templateFS fs.FS
 }
 
@@ -96,24 +100,55 @@ func (t *Terraform) GenerateObjects(outputFolder, resourceToGenerate string, gen
 			continue
 		}
 
-		t.GenerateObject(*object, outputFolder, t.TargetVersionName, generateCode, generateDocs)
+		if t.Cache == nil {
+			t.GenerateObject(*object, outputFolder, t.TargetVersionName, generateCode, generateDocs)
+			continue
+		}
+
+		if t.Cache.Fresh(object) {
+			google.LogVerbose("Skipping unchanged %s resource", object.Name)
+			if !object.IsExcluded() {
+				google.IncrementResourceGenerated()
+			}
+			continue
+		}
+
+		var written []string
+		t.generateObject(*object, outputFolder, generateCode, generateDocs, &written)
+		if err := t.Cache.Record(object, written); err != nil {
+			log.Printf("error caching %s: %v", object.Name, err)
+		}
 	}
 }
 
 func (t *Terraform) GenerateObject(object api.Resource, outputFolder, productPath string, generateCode, generateDocs bool) {
+	t.generateObject(object, outputFolder, generateCode, generateDocs, nil)
+}
+
+// generateObject generates a resource's files, appending their paths to
+// written if it is set.
+func (t *Terraform) generateObject(object api.Resource, outputFolder string, generateCode, generateDocs bool, written *[]string) {
 	templateData := NewTemplateData(outputFolder, t.TargetVersionName, t.templateFS)
+	templateData.written = written
 
 	if !object.IsExcluded() {
 		google.LogVerbose("Generating %s resource", object.Name)
 		google.IncrementResourceGenerated()
 		t.GenerateResource(object, *templateData, outputFolder, generateCode, generateDocs)
 		t.GenerateSingularDataSource(object, *templateData, outputFolder, generateCode, generateDocs)
+		t.GenerateEphemeralResource(object, *templateData, outputFolder, generateCode, generateDocs)
+		t.GenerateActions(object, *templateData, outputFolder, generateCode, generateDocs)
+		t.GenerateIdFunctions(object, *templateData, outputFolder, generateCode, generateDocs)
 
 		if generateCode {
 			// log.Printf("Generating %s tests", object.Name)
 			t.GenerateResourceTests(object, *templateData, outputFolder)
+			t.GenerateResourceContractTests(object, *templateData, outputFolder)
 			t.GenerateResourceSweeper(object, *templateData, outputFolder)
 			t.GenerateSingularDataSourceTests(object, *templateData, outputFolder)
+			t.GenerateEphemeralResourceTests(object, *templateData, outputFolder)
+			t.GenerateActionTests(object, *templateData, outputFolder)
+			t.GenerateIdFunctionTests(object, *templateData, outputFolder)
 			// log.Printf("Generating %s metadata", object.Name)
 			t.GenerateResourceMetadata(object, *templateData, outputFolder)
 		}
@@ -138,24 +173,10 @@ func (t *Terraform) makeFolder(filePath ...string) string {
 func (t *Terraform) GenerateResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
 	if generateCode {
 		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s.go", t.ResourceGoFilename(object)))
 		if object.FrameworkResource {
-			fmt.Printf("\n\x1b[1;33mWARNING:\x1b[0m\n")
-			fmt.Printf("The plugin framework generation code is considered a WIP and experimental.\nAre you sure you want to use it for %s? (y/n) ", t.ResourceGoFilename(object))
-
-			reader := bufio.NewReader(os.Stdin)
-			input, err := reader.ReadString('\n')
-			if err != nil {
-				log.Fatalf("Error reading input: %v", err)
-			}
-
-			if strings.ToLower(strings.TrimSpace(input)) == "y" {
-				targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_fw_%s.go", t.ResourceGoFilename(object)))
-				templateData.GenerateFWResourceFile(targetFilePath, object)
-			} else {
-				log.Fatalf("please remove \"plugin_framework_experimental: true\" from the YAML configuration.")
-			}
+			templateData.GenerateFWResourceFile(targetFilePath, object)
 		} else {
-			targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s.go", t.ResourceGoFilename(object)))
 			templateData.GenerateResourceFile(targetFilePath, object)
 		}
 
@@ -196,14 +217,15 @@ func (t *Terraform) GenerateListResource(object api.Resource, templateData Templ
 // GenerateResourceFile is the Bazel counterpart to GenerateResource(), generating *only() the .go file and
 // taking the full path to the output file to generate rather than implicitly generating the path.
 func (t *Terraform) GenerateResourceFile(object api.Resource, targetFilePath string) {
-	if object.FrameworkResource {
-		log.Fatalf("Framework resources are currently unsupported")
-	}
 	targetFolder := path.Dir(targetFilePath)
 	if err := os.MkdirAll(targetFolder, os.ModePerm); err != nil {
 		log.Println(fmt.Errorf("error creating parent directory %v: %v", targetFolder, err))
 	}
 	templateData := NewTemplateData("", t.TargetVersionName, t.templateFS)
+	if object.FrameworkResource {
+		templateData.GenerateFWResourceFile(targetFilePath, object)
+		return
+	}
 	templateData.GenerateResourceFile(targetFilePath, object)
 }
 
@@ -252,6 +274,19 @@ func (t *Terraform) GenerateResourceTests(object api.Resource, templateData Temp
 	templateData.GenerateTestFile(targetFilePath, object)
 }
 
+// GenerateResourceContractTests generates tests that run a resource's samples
+// against an in-memory fake of its API, for resources that opt in with
+// generate_contract_tests.
+func (t *Terraform) GenerateResourceContractTests(object api.Resource, templateData TemplateData, outputFolder string) {
+	if !object.GenerateContractTests || !t.hasEligibleSample(object) || len(object.ContractTestSamples()) == 0 {
+		return
+	}
+
+	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("resource_%s_generated_contract_test.go", t.ResourceGoFilename(object)))
+	templateData.GenerateContractTestFile(targetFilePath, object)
+}
+
 func (t *Terraform) GenerateListResourceQueryTest(object api.Resource, templateData TemplateData, targetFolder string) {
 	if object.Examples != nil {
 		log.Fatalf("Examples block exists in %v", object.Name)
@@ -320,6 +355,94 @@ func (t *Terraform) GenerateSingularDataSourceTests(object api.Resource, templat
 
 }
 
+func (t *Terraform) GenerateEphemeralResource(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+	if !object.ShouldGenerateEphemeral() {
+		return
+	}
+
+	if generateCode {
+		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("ephemeral_%s.go", t.ResourceGoFilename(object)))
+		templateData.GenerateEphemeralResourceFile(targetFilePath, object)
+	}
+
+	if generateDocs {
+		targetFolder := t.makeFolder(outputFolder, "website", "docs", "ephemeral-resources")
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s.html.markdown", t.FullResourceName(object)))
+		templateData.GenerateEphemeralResourceDocumentationFile(targetFilePath, object)
+	}
+}
+
+func (t *Terraform) GenerateEphemeralResourceTests(object api.Resource, templateData TemplateData, outputFolder string) {
+	if !object.ShouldGenerateEphemeralTests() {
+		return
+	}
+
+	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("ephemeral_%s_generated_test.go", t.ResourceGoFilename(object)))
+	templateData.GenerateEphemeralResourceTestFile(targetFilePath, object)
+}
+
+func (t *Terraform) GenerateActions(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+	if len(object.Actions) == 0 {
+		return
+	}
+
+	if generateCode {
+		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("action_%s.go", t.ResourceGoFilename(object)))
+		templateData.GenerateActionFile(targetFilePath, object)
+	}
+
+	if generateDocs {
+		targetFolder := t.makeFolder(outputFolder, "website", "docs", "actions")
+		for _, a := range object.Actions {
+			targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s_%s.html.markdown", t.FullResourceName(object), google.Underscore(a.Name)))
+			templateData.GenerateActionDocumentationFile(targetFilePath, *a)
+		}
+	}
+}
+
+func (t *Terraform) GenerateActionTests(object api.Resource, templateData TemplateData, outputFolder string) {
+	if !slices.ContainsFunc(object.Actions, func(a *api.Action) bool { return a.ShouldGenerateTests() }) {
+		return
+	}
+
+	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("action_%s_generated_test.go", t.ResourceGoFilename(object)))
+	templateData.GenerateActionTestFile(targetFilePath, object)
+}
+
+func (t *Terraform) GenerateIdFunctions(object api.Resource, templateData TemplateData, outputFolder string, generateCode, generateDocs bool) {
+	if !object.GenerateIdFunctions {
+		return
+	}
+
+	if generateCode {
+		targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("function_%s_id.go", t.ResourceGoFilename(object)))
+		templateData.GenerateIdFunctionFile(targetFilePath, object)
+	}
+
+	if generateDocs {
+		targetFolder := t.makeFolder(outputFolder, "website", "docs", "functions")
+		targetFilePath := path.Join(targetFolder, fmt.Sprintf("%s_parse.html.markdown", object.IdFunctionName()))
+		templateData.GenerateIdParseFunctionDocumentationFile(targetFilePath, object)
+		targetFilePath = path.Join(targetFolder, fmt.Sprintf("%s_build.html.markdown", object.IdFunctionName()))
+		templateData.GenerateIdBuildFunctionDocumentationFile(targetFilePath, object)
+	}
+}
+
+func (t *Terraform) GenerateIdFunctionTests(object api.Resource, templateData TemplateData, outputFolder string) {
+	if !object.GenerateIdFunctions {
+		return
+	}
+
+	targetFolder := t.makeFolder(outputFolder, t.FolderName(), "services", t.Product.ApiName)
+	targetFilePath := path.Join(targetFolder, fmt.Sprintf("function_%s_id_generated_test.go", t.ResourceGoFilename(object)))
+	templateData.GenerateIdFunctionTestFile(targetFilePath, object)
+}
+
 // GenerateProduct creates the product.go file for a given service directory.
 // This will be used to seed the directory and add a package-level comment
 // specific to the product.
@@ -0,0 +1,410 @@
+package acctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// ContractCollection describes a REST collection served by a ContractServer.
+// Paths use the same {{var}} placeholders as MMv1 URLs and are relative to
+// the product's base URL.
+type ContractCollection struct {
+	// BaseUrl is the path of the collection, e.g.
+	// "projects/{{project}}/locations/{{location}}/urlLists".
+	BaseUrl string
+	// SelfLink is the path of a single resource in the collection.
+	SelfLink string
+	// CreateVerb is the HTTP method used to create a resource. Defaults to POST.
+	CreateVerb string
+	// IdQueryParam is the create_url query parameter that carries the id of
+	// a new resource, e.g. "urlListId". If empty, the id is read from the
+	// "name" field of the request body.
+	IdQueryParam string
+	// UpdateMask is true if PATCH requests must send an updateMask.
+	UpdateMask bool
+	// AsyncActions lists the actions ("create", "update", "delete") that
+	// return long-running operations rather than the resource itself.
+	AsyncActions []string
+}
+
+// ContractRequest is a request received by a ContractServer.
+type ContractRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   map[string]interface{}
+	// Update is true if the request modified an existing resource.
+	Update bool
+	// UpdateMask is true if the collection requires an updateMask.
+	UpdateMask bool
+}
+
+// ContractServer is an in-memory fake of one or more REST collections. It
+// lets generated contract tests exercise a resource's create, read, update,
+// import and delete code paths without network access.
+type ContractServer struct {
+	URL string
+
+	t           *testing.T
+	collections []contractCollection
+
+	mu         sync.Mutex
+	objects    map[string]map[string]interface{}
+	operations map[string]map[string]interface{}
+	opCount    int
+	requests   []ContractRequest
+	checked    int
+}
+
+type contractCollection struct {
+	ContractCollection
+	collectionRe *regexp.Regexp
+	selfLinkRe   *regexp.Regexp
+	listField    string
+}
+
+var contractPathVarRe = regexp.MustCompile(`{{(%?)[^}]+}}`)
+
+// NewContractServer starts a fake API server for the given collections. The
+// server is closed when the test finishes.
+func NewContractServer(t *testing.T, collections ...ContractCollection) *ContractServer {
+	s := &ContractServer{
+		t:          t,
+		objects:    make(map[string]map[string]interface{}),
+		operations: make(map[string]map[string]interface{}),
+	}
+	for _, c := range collections {
+		if c.CreateVerb == "" {
+			c.CreateVerb = http.MethodPost
+		}
+		base := strings.Trim(strings.SplitN(c.BaseUrl, "?", 2)[0], "/")
+		s.collections = append(s.collections, contractCollection{
+			ContractCollection: c,
+			collectionRe:       contractPathRegexp(base),
+			selfLinkRe:         contractPathRegexp(strings.Trim(c.SelfLink, "/")),
+			listField:          base[strings.LastIndex(base, "/")+1:],
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(server.Close)
+	s.URL = server.URL
+	return s
+}
+
+// SkipIfNoTerraformCLI skips contract tests when no Terraform binary is
+// available locally, as the test framework would otherwise try to download one.
+func SkipIfNoTerraformCLI(t *testing.T) {
+	t.Helper()
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("Terraform CLI not found; set TF_ACC_TERRAFORM_PATH to run contract tests")
+	}
+}
+
+// contractPathRegexp converts an MMv1 URL template to a regular expression.
+// {{var}} matches a single path segment, {{%var}} matches one or more.
+func contractPathRegexp(template string) *regexp.Regexp {
+	var b strings.Builder
+	last := 0
+	for _, m := range contractPathVarRe.FindAllStringSubmatchIndex(template, -1) {
+		b.WriteString(regexp.QuoteMeta(template[last:m[0]]))
+		if m[3] > m[2] {
+			b.WriteString(`.+`)
+		} else {
+			b.WriteString(`[^/]+`)
+		}
+		last = m[1]
+	}
+	b.WriteString(regexp.QuoteMeta(template[last:]))
+	return regexp.MustCompile("^" + b.String() + "$")
+}
+
+// ProviderConfig returns a provider block that sends all requests for the
+// product with the given custom endpoint field to the server.
+func (s *ContractServer) ProviderConfig(providerName, customEndpointField string) string {
+	return fmt.Sprintf(`
+provider "%s" {
+  access_token  = "contract-test-token"
+  project       = "my-project-name"
+  region        = "us-central1"
+  zone          = "us-central1-a"
+  poll_interval = "10ms"
+
+  %s = "%s/"
+}
+`, providerName, customEndpointField, s.URL)
+}
+
+// Requests returns all requests received by the server.
+func (s *ContractServer) Requests() []ContractRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ContractRequest(nil), s.requests...)
+}
+
+// CheckUpdated returns a check that fails unless the server received at
+// least one update since the previous CheckUpdated, and that every PATCH sent
+// to a collection requiring an update mask included a non-empty updateMask.
+func (s *ContractServer) CheckUpdated() resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		requests := s.requests[s.checked:]
+		s.checked = len(s.requests)
+
+		updated := false
+		for _, r := range requests {
+			if !r.Update {
+				continue
+			}
+			updated = true
+			if r.UpdateMask && r.Method == http.MethodPatch && r.Query.Get("updateMask") == "" {
+				return fmt.Errorf("PATCH %s was sent without an updateMask", r.Path)
+			}
+		}
+		if !updated {
+			return fmt.Errorf("expected an update request, got none")
+		}
+		return nil
+	}
+}
+
+// CheckDestroy fails if any resource still exists on the server.
+func (s *ContractServer) CheckDestroy(*terraform.State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path := range s.objects {
+		return fmt.Errorf("%s still exists", path)
+	}
+	return nil
+}
+
+func (s *ContractServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Trim(r.URL.Path, "/")
+	req := ContractRequest{
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.Query(),
+	}
+	if b, err := io.ReadAll(r.Body); err == nil && len(b) > 0 {
+		if err := json.Unmarshal(b, &req.Body); err != nil {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+			return
+		}
+	}
+
+	if r.Method == http.MethodGet {
+		if op, ok := s.operations[path[strings.LastIndex(path, "/")+1:]]; ok {
+			s.requests = append(s.requests, req)
+			s.writeJSON(w, op)
+			return
+		}
+	}
+
+	objectPath, action := path, ""
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") {
+		objectPath, action = path[:i], path[i+1:]
+	}
+
+	for _, c := range s.collections {
+		switch {
+		case action == "" && c.collectionRe.MatchString(path):
+			s.requests = append(s.requests, req)
+			s.handleCollection(w, c, req)
+			return
+		case c.selfLinkRe.MatchString(objectPath):
+			req.Update = r.Method != http.MethodGet && r.Method != http.MethodDelete && s.objects[objectPath] != nil
+			req.UpdateMask = c.UpdateMask
+			s.requests = append(s.requests, req)
+			s.handleObject(w, c, objectPath, action, req)
+			return
+		}
+	}
+
+	s.requests = append(s.requests, req)
+	s.t.Logf("[DEBUG] contract server received unexpected request %s %s", r.Method, r.URL)
+	s.writeError(w, http.StatusNotFound, fmt.Sprintf("no collection serves %s", path))
+}
+
+func (s *ContractServer) handleCollection(w http.ResponseWriter, c contractCollection, req ContractRequest) {
+	switch req.Method {
+	case http.MethodGet:
+		var items []interface{}
+		for path, obj := range s.objects {
+			if strings.HasPrefix(path, req.Path+"/") {
+				items = append(items, obj)
+			}
+		}
+		s.writeJSON(w, map[string]interface{}{c.listField: items})
+	case c.CreateVerb:
+		id := ""
+		if c.IdQueryParam != "" {
+			id = req.Query.Get(c.IdQueryParam)
+		}
+		if name, ok := req.Body["name"].(string); ok && id == "" {
+			id = name[strings.LastIndex(name, "/")+1:]
+		}
+		if id == "" {
+			id = fmt.Sprintf("contract-%d", len(s.objects)+1)
+		}
+		s.create(w, c, req.Path+"/"+id, req.Body)
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not supported on %s", req.Method, req.Path))
+	}
+}
+
+func (s *ContractServer) handleObject(w http.ResponseWriter, c contractCollection, path, action string, req ContractRequest) {
+	obj, exists := s.objects[path]
+	if !exists {
+		if action == "" && req.Method == c.CreateVerb && req.Method != http.MethodGet {
+			s.create(w, c, path, req.Body)
+			return
+		}
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("%s was not found", path))
+		return
+	}
+
+	switch {
+	case req.Method == http.MethodGet && action == "":
+		s.writeJSON(w, obj)
+	case req.Method == http.MethodDelete && action == "":
+		delete(s.objects, path)
+		s.respond(w, c, "delete", path, map[string]interface{}{})
+	case req.Method == http.MethodPut && action == "":
+		replaced := copyContractObject(req.Body)
+		for _, k := range []string{"name", "selfLink"} {
+			if _, ok := replaced[k]; !ok {
+				replaced[k] = obj[k]
+			}
+		}
+		s.objects[path] = replaced
+		s.respond(w, c, "update", path, replaced)
+	case req.Method == http.MethodPatch && action == "":
+		if mask := req.Query.Get("updateMask"); mask != "" {
+			for _, field := range strings.Split(mask, ",") {
+				key := contractCamelCase(strings.SplitN(strings.TrimSpace(field), ".", 2)[0])
+				if v, ok := req.Body[key]; ok {
+					obj[key] = v
+				} else {
+					delete(obj, key)
+				}
+			}
+		} else {
+			for k, v := range req.Body {
+				obj[k] = v
+			}
+		}
+		s.respond(w, c, "update", path, obj)
+	case req.Method == http.MethodPost && action != "":
+		for k, v := range req.Body {
+			obj[k] = v
+		}
+		s.respond(w, c, "update", path, obj)
+	default:
+		s.writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s is not supported on %s", req.Method, req.Path))
+	}
+}
+
+func (s *ContractServer) create(w http.ResponseWriter, c contractCollection, path string, body map[string]interface{}) {
+	if _, exists := s.objects[path]; exists {
+		s.writeError(w, http.StatusConflict, fmt.Sprintf("%s already exists", path))
+		return
+	}
+	obj := copyContractObject(body)
+	if _, ok := obj["name"]; !ok {
+		obj["name"] = path
+	}
+	if _, ok := obj["selfLink"]; !ok {
+		obj["selfLink"] = fmt.Sprintf("%s/%s", s.URL, path)
+	}
+	s.objects[path] = obj
+	s.respond(w, c, "create", path, obj)
+}
+
+// respond writes the result of a mutation, wrapped in a long-running
+// operation if the action is async. Operations are reported as running and
+// complete the first time they are polled.
+func (s *ContractServer) respond(w http.ResponseWriter, c contractCollection, action, path string, obj map[string]interface{}) {
+	if !slices.Contains(c.AsyncActions, action) {
+		s.writeJSON(w, obj)
+		return
+	}
+	s.opCount++
+	id := fmt.Sprintf("contract-op-%d", s.opCount)
+	name := "operations/" + id
+	target := fmt.Sprintf("%s/%s", s.URL, path)
+	s.operations[id] = map[string]interface{}{
+		"name":       name,
+		"done":       true,
+		"status":     "DONE",
+		"targetLink": target,
+		"response":   copyContractObject(obj),
+	}
+	s.writeJSON(w, map[string]interface{}{
+		"name":       name,
+		"done":       false,
+		"status":     "RUNNING",
+		"targetLink": target,
+	})
+}
+
+func (s *ContractServer) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.t.Errorf("contract server failed to encode response: %s", err)
+	}
+}
+
+func (s *ContractServer) writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	s.writeJSON(w, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"status":  http.StatusText(code),
+		},
+	})
+}
+
+func copyContractObject(obj map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		copied[k] = v
+	}
+	return copied
+}
+
+// contractCamelCase converts an update mask path segment, which may be in
+// snake_case, to the lowerCamelCase used in request bodies.
+func contractCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,113 @@
+package acctest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-google/google/acctest"
+)
+
+func contractRequest(t *testing.T, method, url string, body map[string]interface{}) (int, map[string]interface{}) {
+	t.Helper()
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			t.Fatalf("failed to encode body: %s", err)
+		}
+	}
+	req, err := http.NewRequest(method, url, &reqBody)
+	if err != nil {
+		t.Fatalf("failed to create request: %s", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %s", method, url, err)
+	}
+	defer resp.Body.Close()
+	var got map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response of %s %s: %s", method, url, err)
+	}
+	return resp.StatusCode, got
+}
+
+func TestContractServer_lifecycle(t *testing.T) {
+	s := acctest.NewContractServer(t, acctest.ContractCollection{
+		BaseUrl:      "projects/{{project}}/locations/{{location}}/widgets",
+		SelfLink:     "projects/{{project}}/locations/{{location}}/widgets/{{name}}",
+		IdQueryParam: "widgetId",
+		UpdateMask:   true,
+		AsyncActions: []string{"create", "update", "delete"},
+	})
+	collection := s.URL + "/projects/p/locations/l/widgets"
+	widget := collection + "/w1"
+
+	code, op := contractRequest(t, http.MethodPost, collection+"?widgetId=w1", map[string]interface{}{"size": 1.0})
+	if code != http.StatusOK || op["done"] != false {
+		t.Fatalf("create returned %d %v, want a running operation", code, op)
+	}
+	code, op = contractRequest(t, http.MethodGet, s.URL+"/"+op["name"].(string), nil)
+	if code != http.StatusOK || op["done"] != true {
+		t.Fatalf("polling returned %d %v, want a done operation", code, op)
+	}
+
+	code, obj := contractRequest(t, http.MethodGet, widget, nil)
+	if code != http.StatusOK || obj["name"] != "projects/p/locations/l/widgets/w1" || obj["size"] != 1.0 {
+		t.Fatalf("read returned %d %v", code, obj)
+	}
+
+	if code, _ := contractRequest(t, http.MethodPost, collection+"?widgetId=w1", map[string]interface{}{}); code != http.StatusConflict {
+		t.Errorf("duplicate create returned %d, want %d", code, http.StatusConflict)
+	}
+
+	if err := s.CheckUpdated()(nil); err == nil {
+		t.Errorf("CheckUpdated() succeeded before any update")
+	}
+
+	contractRequest(t, http.MethodPatch, widget+"?updateMask=size,display_name", map[string]interface{}{"size": 2.0, "displayName": "a", "ignored": true})
+	if err := s.CheckUpdated()(nil); err != nil {
+		t.Errorf("CheckUpdated() = %s", err)
+	}
+	_, obj = contractRequest(t, http.MethodGet, widget, nil)
+	if obj["size"] != 2.0 || obj["displayName"] != "a" || obj["ignored"] != nil {
+		t.Errorf("update did not respect the update mask: %v", obj)
+	}
+
+	contractRequest(t, http.MethodPatch, widget, map[string]interface{}{"size": 3.0})
+	if err := s.CheckUpdated()(nil); err == nil {
+		t.Errorf("CheckUpdated() succeeded for a PATCH without an update mask")
+	}
+
+	contractRequest(t, http.MethodDelete, widget, nil)
+	if err := s.CheckDestroy(nil); err != nil {
+		t.Errorf("CheckDestroy() = %s", err)
+	}
+	if code, _ := contractRequest(t, http.MethodGet, widget, nil); code != http.StatusNotFound {
+		t.Errorf("read after delete returned %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestContractServer_syncNameInBody(t *testing.T) {
+	s := acctest.NewContractServer(t, acctest.ContractCollection{
+		BaseUrl:  "projects/{{project}}/global/networks",
+		SelfLink: "projects/{{project}}/global/networks/{{name}}",
+	})
+
+	code, obj := contractRequest(t, http.MethodPost, s.URL+"/projects/p/global/networks", map[string]interface{}{"name": "n1"})
+	if code != http.StatusOK || obj["name"] != "n1" {
+		t.Fatalf("create returned %d %v, want the created object", code, obj)
+	}
+	if obj["selfLink"] != s.URL+"/projects/p/global/networks/n1" {
+		t.Errorf("selfLink = %v", obj["selfLink"])
+	}
+
+	_, list := contractRequest(t, http.MethodGet, s.URL+"/projects/p/global/networks", nil)
+	if items, ok := list["networks"].([]interface{}); !ok || len(items) != 1 {
+		t.Errorf("list returned %v, want one network", list)
+	}
+	if err := s.CheckDestroy(nil); err == nil {
+		t.Errorf("CheckDestroy() succeeded while a network exists")
+	}
+}
@@ -82,3 +82,26 @@ func DefaultZoneModify(ctx context.Context, req resource.ModifyPlanRequest, resp
 	}
 	return
 }
+
+// DefaultDeletionPolicyModify plans deletion_policy as the provider-level
+// deletion_policy, or else the resource default, when the resource
+// configuration doesn't set it.
+func DefaultDeletionPolicyModify(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, providerConfigDeletionPolicy, resourceDefault string) {
+	// Nothing is planned when the resource is destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var configured types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("deletion_policy"), &configured)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !configured.IsNull() {
+		return
+	}
+
+	deletionPolicy := resourceDefault
+	if providerConfigDeletionPolicy != "" {
+		deletionPolicy = providerConfigDeletionPolicy
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("deletion_policy"), types.StringValue(deletionPolicy))...)
+}
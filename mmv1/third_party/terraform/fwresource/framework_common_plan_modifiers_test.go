@@ -230,3 +230,74 @@ func TestDefaultProjectModify(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultDeletionPolicyModify(t *testing.T) {
+	testSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"deletion_policy": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+	objectType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"deletion_policy": tftypes.String,
+		},
+	}
+	object := func(v interface{}) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"deletion_policy": tftypes.NewValue(tftypes.String, v),
+		})
+	}
+
+	cases := map[string]struct {
+		providerDeletionPolicy string
+		config                 tftypes.Value
+		plan                   tftypes.Value
+		expected               tftypes.Value
+	}{
+		"Prioritizes set config value": {
+			providerDeletionPolicy: "ABANDON",
+			config:                 object("PREVENT"),
+			plan:                   object("PREVENT"),
+			expected:               object("PREVENT"),
+		},
+		"Falls back on provider default": {
+			providerDeletionPolicy: "ABANDON",
+			config:                 object(nil),
+			plan:                   object(tftypes.UnknownValue),
+			expected:               object("ABANDON"),
+		},
+		"Falls back on resource default": {
+			config:   object(nil),
+			plan:     object(tftypes.UnknownValue),
+			expected: object("DELETE"),
+		},
+		"Does nothing on destroy": {
+			providerDeletionPolicy: "ABANDON",
+			config:                 tftypes.NewValue(objectType, nil),
+			plan:                   tftypes.NewValue(objectType, nil),
+			expected:               tftypes.NewValue(objectType, nil),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Raw: tc.config, Schema: testSchema},
+				Plan:   tfsdk.Plan{Raw: tc.plan, Schema: testSchema},
+			}
+			resp := &resource.ModifyPlanResponse{
+				Plan: tfsdk.Plan{Raw: tc.plan.Copy(), Schema: testSchema},
+			}
+			DefaultDeletionPolicyModify(context.Background(), req, resp, tc.providerDeletionPolicy, "DELETE")
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+			if !resp.Plan.Raw.Equal(tc.expected) {
+				t.Fatalf("incorrect plan.\n- got:  %v\n- want: %v", resp.Plan.Raw, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package fwresource
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ExpandPrimitiveFramework converts a primitive framework value into the value
+// sent in an API request body. Null and unknown values expand to nil so that
+// callers can omit them from the request.
+func ExpandPrimitiveFramework(v attr.Value) interface{} {
+	if v == nil || v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+
+	switch val := v.(type) {
+	case types.String:
+		return val.ValueString()
+	case types.Int64:
+		return val.ValueInt64()
+	case types.Bool:
+		return val.ValueBool()
+	case types.Float64:
+		return val.ValueFloat64()
+	}
+
+	return nil
+}
+
+// FlattenStringFramework converts a value decoded from an API response into a
+// types.String. Missing values are returned as null.
+func FlattenStringFramework(v interface{}) types.String {
+	if val, ok := v.(string); ok {
+		return types.StringValue(val)
+	}
+	return types.StringNull()
+}
+
+// FlattenInt64Framework converts a value decoded from an API response into a
+// types.Int64. Integers are represented as float64 in JSON, or as strings when
+// the API uses the int64/fixed64 string format.
+func FlattenInt64Framework(v interface{}) types.Int64 {
+	switch val := v.(type) {
+	case float64:
+		return types.Int64Value(int64(val))
+	case string:
+		if intVal, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return types.Int64Value(intVal)
+		}
+	}
+	return types.Int64Null()
+}
+
+// FlattenBoolFramework converts a value decoded from an API response into a
+// types.Bool. Missing values are returned as null.
+func FlattenBoolFramework(v interface{}) types.Bool {
+	if val, ok := v.(bool); ok {
+		return types.BoolValue(val)
+	}
+	return types.BoolNull()
+}
+
+// FlattenFloat64Framework converts a value decoded from an API response into a
+// types.Float64. Missing values are returned as null.
+func FlattenFloat64Framework(v interface{}) types.Float64 {
+	switch val := v.(type) {
+	case float64:
+		return types.Float64Value(val)
+	case string:
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			return types.Float64Value(floatVal)
+		}
+	}
+	return types.Float64Null()
+}
@@ -0,0 +1,69 @@
+package fwresource
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestExpandPrimitiveFramework(t *testing.T) {
+	cases := map[string]struct {
+		Value    attr.Value
+		Expected interface{}
+	}{
+		"string":          {Value: types.StringValue("foo"), Expected: "foo"},
+		"int64":           {Value: types.Int64Value(42), Expected: int64(42)},
+		"bool":            {Value: types.BoolValue(true), Expected: true},
+		"float64":         {Value: types.Float64Value(1.5), Expected: 1.5},
+		"null string":     {Value: types.StringNull(), Expected: nil},
+		"unknown int64":   {Value: types.Int64Unknown(), Expected: nil},
+		"nil value":       {Value: nil, Expected: nil},
+		"empty string":    {Value: types.StringValue(""), Expected: ""},
+		"unsupported map": {Value: types.MapNull(types.StringType), Expected: nil},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got := ExpandPrimitiveFramework(tc.Value)
+			if got != tc.Expected {
+				t.Fatalf("ExpandPrimitiveFramework(%v) = %#v, want %#v", tc.Value, got, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestFlattenInt64Framework(t *testing.T) {
+	cases := map[string]struct {
+		Value    interface{}
+		Expected types.Int64
+	}{
+		"json number":          {Value: float64(10), Expected: types.Int64Value(10)},
+		"fixed64 string":       {Value: "9007199254740993", Expected: types.Int64Value(9007199254740993)},
+		"invalid string":       {Value: "ten", Expected: types.Int64Null()},
+		"missing value":        {Value: nil, Expected: types.Int64Null()},
+		"unexpected json bool": {Value: true, Expected: types.Int64Null()},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got := FlattenInt64Framework(tc.Value)
+			if !got.Equal(tc.Expected) {
+				t.Fatalf("FlattenInt64Framework(%v) = %s, want %s", tc.Value, got, tc.Expected)
+			}
+		})
+	}
+}
+
+func TestFlattenStringFramework(t *testing.T) {
+	if got := FlattenStringFramework("foo"); !got.Equal(types.StringValue("foo")) {
+		t.Fatalf("FlattenStringFramework(\"foo\") = %s", got)
+	}
+	if got := FlattenStringFramework(nil); !got.IsNull() {
+		t.Fatalf("FlattenStringFramework(nil) = %s, want null", got)
+	}
+	if got := FlattenBoolFramework(false); !got.Equal(types.BoolValue(false)) {
+		t.Fatalf("FlattenBoolFramework(false) = %s", got)
+	}
+	if got := FlattenFloat64Framework("2.5"); !got.Equal(types.Float64Value(2.5)) {
+		t.Fatalf("FlattenFloat64Framework(\"2.5\") = %s", got)
+	}
+}
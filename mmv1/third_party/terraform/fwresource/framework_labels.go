@@ -0,0 +1,175 @@
+package fwresource
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+// SetLabelsPlan sets the planned values of terraform_labels and
+// effective_labels from the configured labels and the provider default labels.
+// It is the plugin framework counterpart of tpgresource.SetLabelsDiff.
+func SetLabelsPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, config *transport_tpg.Config, skipAttribution bool) {
+	// Nothing to plan when the resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var labels types.Map
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("labels"), &labels)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// If "labels" field is computed, set "terraform_labels" and "effective_labels" to computed.
+	if labels.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("terraform_labels"), types.MapUnknown(types.StringType))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_labels"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	creating := req.State.Raw.IsNull()
+	oldTerraformLabels := map[string]string{}
+	oldEffectiveLabels := map[string]string{}
+	if !creating {
+		resp.Diagnostics.Append(mapAttribute(ctx, req.State.GetAttribute, path.Root("terraform_labels"), &oldTerraformLabels)...)
+		resp.Diagnostics.Append(mapAttribute(ctx, req.State.GetAttribute, path.Root("effective_labels"), &oldEffectiveLabels)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Merge provider default labels with the user defined labels in the resource to get terraform managed labels
+	terraformLabels := make(map[string]string)
+	for k, v := range config.DefaultLabels {
+		terraformLabels[k] = v
+	}
+
+	// Append optional label indicating the resource was provisioned using Terraform
+	if !skipAttribution && config.AddTerraformAttributionLabel {
+		_, hasExistingLabel := oldEffectiveLabels[transport_tpg.AttributionKey]
+		if hasExistingLabel ||
+			config.TerraformAttributionLabelAdditionStrategy == transport_tpg.ProactiveAttributionStrategy ||
+			(config.TerraformAttributionLabelAdditionStrategy == transport_tpg.CreateOnlyAttributionStrategy && creating) {
+			terraformLabels[transport_tpg.AttributionKey] = transport_tpg.AttributionValue
+		}
+	}
+
+	for k, v := range labels.Elements() {
+		if s, ok := v.(types.String); ok {
+			terraformLabels[k] = s.ValueString()
+		}
+	}
+
+	effectiveLabels := mergeEffective(oldEffectiveLabels, oldTerraformLabels, terraformLabels)
+
+	resp.Diagnostics.Append(setMapAttribute(ctx, resp, path.Root("terraform_labels"), terraformLabels)...)
+	resp.Diagnostics.Append(setMapAttribute(ctx, resp, path.Root("effective_labels"), effectiveLabels)...)
+}
+
+// SetAnnotationsPlan sets the planned value of effective_annotations from the
+// configured annotations. It is the plugin framework counterpart of
+// tpgresource.SetAnnotationsDiff.
+func SetAnnotationsPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan when the resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var annotations types.Map
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("annotations"), &annotations)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// If "annotations" field is computed, set "effective_annotations" to computed.
+	if annotations.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_annotations"), types.MapUnknown(types.StringType))...)
+		return
+	}
+
+	oldAnnotations := map[string]string{}
+	oldEffectiveAnnotations := map[string]string{}
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(mapAttribute(ctx, req.State.GetAttribute, path.Root("annotations"), &oldAnnotations)...)
+		resp.Diagnostics.Append(mapAttribute(ctx, req.State.GetAttribute, path.Root("effective_annotations"), &oldEffectiveAnnotations)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	newAnnotations := make(map[string]string)
+	for k, v := range annotations.Elements() {
+		if s, ok := v.(types.String); ok {
+			newAnnotations[k] = s.ValueString()
+		}
+	}
+
+	resp.Diagnostics.Append(setMapAttribute(ctx, resp, path.Root("effective_annotations"), mergeEffective(oldEffectiveAnnotations, oldAnnotations, newAnnotations))...)
+}
+
+// FlattenConfiguredLabelsFramework converts the labels or annotations returned
+// by the API into the value stored for a field that only tracks the keys
+// managed by Terraform. Only the keys present in configured are kept, so that
+// labels added outside of Terraform don't show up as a diff, and a field that
+// isn't set stays null.
+func FlattenConfiguredLabelsFramework(configured types.Map, v interface{}, diags *diag.Diagnostics) types.Map {
+	if configured.IsNull() || configured.IsUnknown() {
+		return types.MapNull(types.StringType)
+	}
+
+	m, _ := v.(map[string]interface{})
+	elems := make(map[string]attr.Value, len(configured.Elements()))
+	for k := range configured.Elements() {
+		if raw, ok := m[k]; ok {
+			elems[k] = FlattenStringFramework(raw)
+		}
+	}
+	transformed, d := types.MapValue(types.StringType, elems)
+	diags.Append(d...)
+	return transformed
+}
+
+// mergeEffective returns the planned effective values: the existing values
+// with the new managed values added, and the managed values that were removed
+// from the configuration deleted.
+func mergeEffective(effective, oldManaged, newManaged map[string]string) map[string]string {
+	merged := make(map[string]string, len(effective)+len(newManaged))
+	for k, v := range effective {
+		merged[k] = v
+	}
+	for k, v := range newManaged {
+		merged[k] = v
+	}
+	for k := range oldManaged {
+		if _, ok := newManaged[k]; !ok {
+			delete(merged, k)
+		}
+	}
+	return merged
+}
+
+func mapAttribute(ctx context.Context, get func(context.Context, path.Path, interface{}) diag.Diagnostics, p path.Path, target *map[string]string) diag.Diagnostics {
+	var m types.Map
+	diags := get(ctx, p, &m)
+	if diags.HasError() || m.IsNull() || m.IsUnknown() {
+		return diags
+	}
+	diags.Append(m.ElementsAs(ctx, target, false)...)
+	return diags
+}
+
+func setMapAttribute(ctx context.Context, resp *resource.ModifyPlanResponse, p path.Path, m map[string]string) diag.Diagnostics {
+	v, diags := types.MapValueFrom(ctx, types.StringType, m)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(resp.Plan.SetAttribute(ctx, p, v)...)
+	return diags
+}
@@ -0,0 +1,143 @@
+package fwresource
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	transport_tpg "github.com/hashicorp/terraform-provider-google/google/transport"
+)
+
+func TestSetLabelsPlan(t *testing.T) {
+	testSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"labels":           schema.MapAttribute{ElementType: types.StringType, Optional: true},
+			"terraform_labels": schema.MapAttribute{ElementType: types.StringType, Computed: true},
+			"effective_labels": schema.MapAttribute{ElementType: types.StringType, Computed: true},
+		},
+	}
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"labels":           tftypes.Map{ElementType: tftypes.String},
+		"terraform_labels": tftypes.Map{ElementType: tftypes.String},
+		"effective_labels": tftypes.Map{ElementType: tftypes.String},
+	}}
+	stringMap := func(m map[string]string) tftypes.Value {
+		if m == nil {
+			return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tftypes.UnknownValue)
+		}
+		vals := make(map[string]tftypes.Value, len(m))
+		for k, v := range m {
+			vals[k] = tftypes.NewValue(tftypes.String, v)
+		}
+		return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, vals)
+	}
+	object := func(labels, terraformLabels, effectiveLabels map[string]string) tftypes.Value {
+		return tftypes.NewValue(objType, map[string]tftypes.Value{
+			"labels":           stringMap(labels),
+			"terraform_labels": stringMap(terraformLabels),
+			"effective_labels": stringMap(effectiveLabels),
+		})
+	}
+
+	cases := map[string]struct {
+		config                  *transport_tpg.Config
+		state                   tftypes.Value
+		plan                    tftypes.Value
+		expectedTerraformLabels map[string]string
+		expectedEffectiveLabels map[string]string
+	}{
+		"create merges the provider default labels": {
+			config:                  &transport_tpg.Config{DefaultLabels: map[string]string{"env": "test", "team": "a"}},
+			state:                   tftypes.NewValue(objType, nil),
+			plan:                    object(map[string]string{"team": "b"}, nil, nil),
+			expectedTerraformLabels: map[string]string{"env": "test", "team": "b"},
+			expectedEffectiveLabels: map[string]string{"env": "test", "team": "b"},
+		},
+		"create adds the attribution label": {
+			config: &transport_tpg.Config{
+				AddTerraformAttributionLabel:              true,
+				TerraformAttributionLabelAdditionStrategy: transport_tpg.CreateOnlyAttributionStrategy,
+			},
+			state:                   tftypes.NewValue(objType, nil),
+			plan:                    object(map[string]string{"team": "b"}, nil, nil),
+			expectedTerraformLabels: map[string]string{"team": "b", transport_tpg.AttributionKey: transport_tpg.AttributionValue},
+			expectedEffectiveLabels: map[string]string{"team": "b", transport_tpg.AttributionKey: transport_tpg.AttributionValue},
+		},
+		"update keeps labels set outside of Terraform and drops removed labels": {
+			config: &transport_tpg.Config{},
+			state: object(
+				map[string]string{"team": "a", "old": "x"},
+				map[string]string{"team": "a", "old": "x"},
+				map[string]string{"team": "a", "old": "x", "external": "y"},
+			),
+			plan:                    object(map[string]string{"team": "b"}, nil, nil),
+			expectedTerraformLabels: map[string]string{"team": "b"},
+			expectedEffectiveLabels: map[string]string{"team": "b", "external": "y"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: testSchema, Raw: tc.state},
+				Plan:  tfsdk.Plan{Schema: testSchema, Raw: tc.plan},
+			}
+			resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+
+			SetLabelsPlan(context.Background(), req, resp, tc.config, false)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error: %v", resp.Diagnostics)
+			}
+
+			for field, expected := range map[string]map[string]string{
+				"terraform_labels": tc.expectedTerraformLabels,
+				"effective_labels": tc.expectedEffectiveLabels,
+			} {
+				var got map[string]string
+				resp.Diagnostics.Append(mapAttribute(context.Background(), resp.Plan.GetAttribute, path.Root(field), &got)...)
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("unexpected error reading %s: %v", field, resp.Diagnostics)
+				}
+				if !reflect.DeepEqual(got, expected) {
+					t.Errorf("%s = %v, want %v", field, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestFlattenConfiguredLabelsFramework(t *testing.T) {
+	configured := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"team": types.StringValue("a"),
+		"gone": types.StringValue("b"),
+	})
+	api := map[string]interface{}{
+		"team":     "c",
+		"external": "d",
+	}
+
+	var diags diag.Diagnostics
+	got := FlattenConfiguredLabelsFramework(configured, api, &diags)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	expected := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"team": types.StringValue("c"),
+	})
+	if !got.Equal(expected) {
+		t.Errorf("got %v, want %v", got, expected)
+	}
+
+	if got := FlattenConfiguredLabelsFramework(types.MapNull(types.StringType), api, &diags); !got.IsNull() {
+		t.Errorf("got %v for unset labels, want null", got)
+	}
+}
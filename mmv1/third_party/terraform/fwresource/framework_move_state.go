@@ -0,0 +1,134 @@
+package fwresource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// MoveRawState converts the raw state of a resource moved from another
+// resource type into the target state of resp. fields maps top-level
+// attribute names of the source to the target's; attributes mapped to "" are
+// dropped, attributes that aren't listed keep their name, and attributes that
+// the target doesn't have are ignored. Nested blocks that SDKv2 stores as a
+// list of one object are unwrapped wherever the target expects an object.
+func MoveRawState(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse, fields map[string]string) {
+	if req.SourceRawState == nil || req.SourceRawState.JSON == nil {
+		resp.Diagnostics.AddError(
+			"Unable to Move Resource State",
+			fmt.Sprintf("The state of %s has no JSON representation. Please report this issue to the provider developers.", req.SourceTypeName),
+		)
+		return
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(req.SourceRawState.JSON))
+	dec.UseNumber()
+	var source map[string]interface{}
+	if err := dec.Decode(&source); err != nil {
+		resp.Diagnostics.AddError("Unable to Move Resource State", fmt.Sprintf("Error decoding the state of %s: %s", req.SourceTypeName, err))
+		return
+	}
+
+	targetType, ok := resp.TargetState.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		resp.Diagnostics.AddError("Unable to Move Resource State", "The target schema is not an object. Please report this issue to the provider developers.")
+		return
+	}
+	target := map[string]interface{}{}
+	for name, v := range source {
+		if to, ok := fields[name]; ok {
+			if to == "" {
+				continue
+			}
+			name = to
+		}
+		if typ, ok := targetType.AttributeTypes[name]; ok {
+			target[name] = moveValue(v, typ)
+		}
+	}
+
+	b, err := json.Marshal(target)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Move Resource State", fmt.Sprintf("Error encoding the moved state of %s: %s", req.SourceTypeName, err))
+		return
+	}
+	raw, err := tfprotov6.RawState{JSON: b}.Unmarshal(targetType)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Move Resource State", fmt.Sprintf("The state of %s doesn't fit the schema of the target resource: %s", req.SourceTypeName, err))
+		return
+	}
+	resp.TargetState.Raw = raw
+}
+
+var movedFormatVar = regexp.MustCompile("{{([[:word:]]+)}}")
+
+// FormatMovedAttribute sets the string attribute of the target state of resp
+// to format, with each `{{field}}` replaced by the value of that top-level
+// field in the target state. Resources are configured after their state is
+// moved, so unlike fwtransport.ReplaceVars this can't fall back to provider
+// defaults: every field that format refers to must be set.
+func FormatMovedAttribute(ctx context.Context, resp *resource.MoveStateResponse, attribute, format string) {
+	value := movedFormatVar.ReplaceAllStringFunc(format, func(m string) string {
+		field := movedFormatVar.FindStringSubmatch(m)[1]
+		var v types.String
+		resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root(field), &v)...)
+		if v.IsNull() || v.IsUnknown() || v.ValueString() == "" {
+			resp.Diagnostics.AddError("Unable to Move Resource State", fmt.Sprintf("The moved state has no value for %s, which %s is built from.", field, attribute))
+		}
+		return v.ValueString()
+	})
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root(attribute), value)...)
+}
+
+// moveValue reshapes a decoded JSON state value to typ, unwrapping lists of a
+// single object where typ is an object.
+func moveValue(v interface{}, typ tftypes.Type) interface{} {
+	switch t := typ.(type) {
+	case tftypes.Object:
+		if l, ok := v.([]interface{}); ok {
+			if len(l) == 0 {
+				return nil
+			}
+			v = l[0]
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			for name, av := range m {
+				if at, ok := t.AttributeTypes[name]; ok {
+					m[name] = moveValue(av, at)
+				} else {
+					delete(m, name)
+				}
+			}
+		}
+	case tftypes.List:
+		moveElements(v, t.ElementType)
+	case tftypes.Set:
+		moveElements(v, t.ElementType)
+	case tftypes.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for k, ev := range m {
+				m[k] = moveValue(ev, t.ElementType)
+			}
+		}
+	}
+	return v
+}
+
+func moveElements(v interface{}, typ tftypes.Type) {
+	if l, ok := v.([]interface{}); ok {
+		for i, ev := range l {
+			l[i] = moveValue(ev, typ)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package fwresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestMoveRawState(t *testing.T) {
+	ctx := context.Background()
+	targetSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{Required: true},
+			"size": schema.Int64Attribute{Optional: true},
+			"config": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{Optional: true},
+				},
+			},
+			"id": schema.StringAttribute{Computed: true},
+		},
+	}
+	req := resource.MoveStateRequest{
+		SourceTypeName: "google_old_widget",
+		SourceRawState: &tfprotov6.RawState{
+			JSON: []byte(`{"id": "widgets/w", "widget_name": "w", "size": 9007199254740993, "config": [{"enabled": true, "legacy": "x"}], "legacy_flag": true}`),
+		},
+	}
+	resp := &resource.MoveStateResponse{
+		TargetState: tfsdk.State{Schema: targetSchema},
+	}
+
+	MoveRawState(ctx, req, resp, map[string]string{"widget_name": "name", "legacy_flag": ""})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("MoveRawState() returned errors: %v", resp.Diagnostics)
+	}
+
+	var name, id types.String
+	var size types.Int64
+	var config types.Object
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("id"), &id)...)
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("size"), &size)...)
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("config"), &config)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("error reading the moved state: %v", resp.Diagnostics)
+	}
+	if got, want := name.ValueString(), "w"; got != want {
+		t.Errorf("name = %q, want %q", got, want)
+	}
+	if got, want := id.ValueString(), "widgets/w"; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+	if got, want := size.ValueInt64(), int64(9007199254740993); got != want {
+		t.Errorf("size = %d, want %d", got, want)
+	}
+	wantConfig := types.ObjectValueMust(map[string]attr.Type{"enabled": types.BoolType}, map[string]attr.Value{"enabled": types.BoolValue(true)})
+	if !config.Equal(wantConfig) {
+		t.Errorf("config = %s, want %s", config, wantConfig)
+	}
+}
+
+func TestMoveRawState_noJSON(t *testing.T) {
+	req := resource.MoveStateRequest{SourceTypeName: "google_old_widget"}
+	resp := &resource.MoveStateResponse{}
+	MoveRawState(context.Background(), req, resp, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("MoveRawState() succeeded without a JSON state")
+	}
+}
+
+func TestFormatMovedAttribute(t *testing.T) {
+	ctx := context.Background()
+	targetSchema := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"project":  schema.StringAttribute{Optional: true},
+			"location": schema.StringAttribute{Optional: true},
+			"name":     schema.StringAttribute{Computed: true},
+			"id":       schema.StringAttribute{Computed: true},
+		},
+	}
+	req := resource.MoveStateRequest{
+		SourceTypeName: "google_old_widget",
+		SourceRawState: &tfprotov6.RawState{
+			JSON: []byte(`{"id": "projects/p/widgets/w", "project": "p", "name": "projects/p/widgets/w"}`),
+		},
+	}
+	resp := &resource.MoveStateResponse{
+		TargetState: tfsdk.State{Schema: targetSchema},
+	}
+	MoveRawState(ctx, req, resp, nil)
+	resp.Diagnostics.Append(resp.TargetState.SetAttribute(ctx, path.Root("location"), "global")...)
+
+	FormatMovedAttribute(ctx, resp, "id", "projects/{{project}}/locations/{{location}}/widgets/w")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("FormatMovedAttribute() returned errors: %v", resp.Diagnostics)
+	}
+	var id types.String
+	resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root("id"), &id)...)
+	if got, want := id.ValueString(), "projects/p/locations/global/widgets/w"; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+
+	FormatMovedAttribute(ctx, resp, "name", "projects/{{project}}/zones/{{zone}}/widgets/w")
+	if !resp.Diagnostics.HasError() {
+		t.Errorf("FormatMovedAttribute() succeeded with a field missing from the moved state")
+	}
+}
@@ -74,9 +74,22 @@ type SendRequestOptions struct {
 	Headers              http.Header
 	ErrorRetryPredicates []transport_tpg.RetryErrorPredicateFunc
 	ErrorAbortPredicates []transport_tpg.RetryErrorPredicateFunc
+	// ResourceType is the Terraform resource type the request is made for,
+	// recorded on the request's span
+	ResourceType string
+	// Context is the context of the caller, whose span is the parent of the
+	// request's span. context.Background() is used when it's nil.
+	Context context.Context
 }
 
 func SendRequest(opt SendRequestOptions, diags *diag.Diagnostics) (map[string]interface{}, error) {
+	ctx, span := transport_tpg.StartRequestSpan(opt.Context, "SendRequest", opt.ResourceType, opt.Method, opt.RawURL)
+	result, statusCode, err := sendRequest(ctx, opt, diags)
+	transport_tpg.EndRequestSpan(span, statusCode, err)
+	return result, err
+}
+
+func sendRequest(ctx context.Context, opt SendRequestOptions, diags *diag.Diagnostics) (map[string]interface{}, int, error) {
 	reqHeaders := opt.Headers
 	if reqHeaders == nil {
 		reqHeaders = make(http.Header)
@@ -115,7 +128,7 @@ func SendRequest(opt SendRequestOptions, diags *diag.Diagnostics) (map[string]in
 			if err != nil {
 				return err
 			}
-			req, err := http.NewRequest(opt.Method, u, &buf)
+			req, err := http.NewRequestWithContext(ctx, opt.Method, u, &buf)
 			if err != nil {
 				return err
 			}
@@ -139,12 +152,12 @@ func SendRequest(opt SendRequestOptions, diags *diag.Diagnostics) (map[string]in
 	})
 	if err != nil {
 		diags.AddError("Error when sending HTTP request: ", err.Error())
-		return nil, err
+		return nil, 0, err
 	}
 
 	if res == nil {
 		diags.AddError("Unable to parse server response. This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.", "")
-		return nil, fmt.Errorf("Unable to parse server response. This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.")
+		return nil, 0, fmt.Errorf("Unable to parse server response. This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.")
 	}
 
 	// The defer call must be made outside of the retryFunc otherwise it's closed too soon.
@@ -153,15 +166,15 @@ func SendRequest(opt SendRequestOptions, diags *diag.Diagnostics) (map[string]in
 	// 204 responses will have no body, so we're going to error with "EOF" if we
 	// try to parse it. Instead, we can just return nil.
 	if res.StatusCode == 204 {
-		return nil, nil
+		return nil, res.StatusCode, nil
 	}
 	result := make(map[string]interface{})
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
 		diags.AddError("Error when sending HTTP request: ", err.Error())
-		return nil, err
+		return nil, res.StatusCode, err
 	}
 
-	return result, nil
+	return result, res.StatusCode, nil
 }
 
 type DefaultVars struct {
@@ -228,21 +241,7 @@ func BuildReplacementFunc(ctx context.Context, re *regexp.Regexp, req interface{
 	}
 
 	if strings.Contains(linkTmpl, "{{project_id_or_project}}") {
-		var diagInfo diag.Diagnostics
-		switch req.(type) {
-		case resource.CreateRequest:
-			pReq := req.(resource.CreateRequest)
-			diagInfo = pReq.Plan.GetAttribute(ctx, path.Root("project_id"), &projectID)
-		case resource.UpdateRequest:
-			pReq := req.(resource.UpdateRequest)
-			diagInfo = pReq.Plan.GetAttribute(ctx, path.Root("project_id"), &projectID)
-		case resource.ReadRequest:
-			sReq := req.(resource.ReadRequest)
-			diagInfo = sReq.State.GetAttribute(ctx, path.Root("project_id"), &projectID)
-		case resource.DeleteRequest:
-			sReq := req.(resource.DeleteRequest)
-			diagInfo = sReq.State.GetAttribute(ctx, path.Root("project_id"), &projectID)
-		}
+		diagInfo := getRequestAttribute(ctx, req, path.Root("project_id"), &projectID)
 		diags.Append(diagInfo...)
 		if diags.HasError() {
 			return nil
@@ -270,12 +269,12 @@ func BuildReplacementFunc(ctx context.Context, re *regexp.Regexp, req interface{
 	}
 
 	if strings.Contains(linkTmpl, "{{zone}}") {
-		zone = fwresource.GetRegionFramework(data.Zone, types.StringValue(config.Zone), diags).ValueString()
+		zone = fwresource.GetZoneFramework(data.Zone, types.StringValue(config.Zone), diags).ValueString()
 		if diags.HasError() {
 			return nil
 		}
 		if shorten {
-			zone = strings.TrimPrefix(region, "zones/")
+			zone = strings.TrimPrefix(zone, "zones/")
 		}
 	}
 
@@ -299,21 +298,7 @@ func BuildReplacementFunc(ctx context.Context, re *regexp.Regexp, req interface{
 		}
 		if string(m[0]) == "%" {
 			var v types.String
-			var diagInfo diag.Diagnostics
-			switch req.(type) {
-			case resource.CreateRequest:
-				pReq := req.(resource.CreateRequest)
-				diagInfo = pReq.Plan.GetAttribute(ctx, path.Root(m[1:]), &v)
-			case resource.UpdateRequest:
-				pReq := req.(resource.UpdateRequest)
-				diagInfo = pReq.Plan.GetAttribute(ctx, path.Root(m[1:]), &v)
-			case resource.ReadRequest:
-				sReq := req.(resource.ReadRequest)
-				diagInfo = sReq.State.GetAttribute(ctx, path.Root(m[1:]), &v)
-			case resource.DeleteRequest:
-				sReq := req.(resource.DeleteRequest)
-				diagInfo = sReq.State.GetAttribute(ctx, path.Root(m[1:]), &v)
-			}
+			diagInfo := getRequestAttribute(ctx, req, path.Root(m[1:]), &v)
 			//an error here means the attribute was not found, we want to do nothing in that case
 			if !diagInfo.HasError() {
 				diags.Append(diagInfo...)
@@ -327,21 +312,7 @@ func BuildReplacementFunc(ctx context.Context, re *regexp.Regexp, req interface{
 			}
 		} else {
 			var v types.String
-			var diagInfo diag.Diagnostics
-			switch req.(type) {
-			case resource.CreateRequest:
-				pReq := req.(resource.CreateRequest)
-				diagInfo = pReq.Plan.GetAttribute(ctx, path.Root(m), &v)
-			case resource.UpdateRequest:
-				pReq := req.(resource.UpdateRequest)
-				diagInfo = pReq.Plan.GetAttribute(ctx, path.Root(m), &v)
-			case resource.ReadRequest:
-				sReq := req.(resource.ReadRequest)
-				diagInfo = sReq.State.GetAttribute(ctx, path.Root(m), &v)
-			case resource.DeleteRequest:
-				sReq := req.(resource.DeleteRequest)
-				diagInfo = sReq.State.GetAttribute(ctx, path.Root(m), &v)
-			}
+			diagInfo := getRequestAttribute(ctx, req, path.Root(m), &v)
 			//an error here means the attribute was not found, we want to do nothing in that case
 			if !diagInfo.HasError() {
 				diags.Append(diagInfo...)
@@ -372,3 +343,30 @@ func BuildReplacementFunc(ctx context.Context, re *regexp.Regexp, req interface{
 
 	return f
 }
+
+// getRequestAttribute reads a single attribute from the plan or state carried by
+// req. In addition to the CRUD request types, a tfsdk.State or tfsdk.Plan may be
+// passed directly, which allows URLs to be built from values that are only known
+// after an API call (e.g. server-assigned identifiers read back during Create).
+// Ephemeral resources, which have no plan or state, pass their tfsdk.Config.
+func getRequestAttribute(ctx context.Context, req interface{}, p path.Path, target interface{}) diag.Diagnostics {
+	switch r := req.(type) {
+	case resource.CreateRequest:
+		return r.Plan.GetAttribute(ctx, p, target)
+	case resource.UpdateRequest:
+		return r.Plan.GetAttribute(ctx, p, target)
+	case resource.ReadRequest:
+		return r.State.GetAttribute(ctx, p, target)
+	case resource.DeleteRequest:
+		return r.State.GetAttribute(ctx, p, target)
+	case tfsdk.State:
+		return r.GetAttribute(ctx, p, target)
+	case *tfsdk.State:
+		return r.GetAttribute(ctx, p, target)
+	case tfsdk.Plan:
+		return r.GetAttribute(ctx, p, target)
+	case tfsdk.Config:
+		return r.GetAttribute(ctx, p, target)
+	}
+	return nil
+}
@@ -9,9 +9,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	googleoauth "golang.org/x/oauth2/google"
 )
@@ -352,3 +355,79 @@ func (v TopicPrefixValidator) ValidateString(ctx context.Context, req validator.
 func NewTopicPrefixValidator() validator.String {
 	return TopicPrefixValidator{}
 }
+
+// SDK Validator
+var (
+	_ validator.String  = sdkValidator{}
+	_ validator.Int64   = sdkValidator{}
+	_ validator.Float64 = sdkValidator{}
+)
+
+// sdkValidator runs a plugin SDK validation function, such as the ones in the
+// verify package, against a framework attribute value.
+type sdkValidator struct {
+	validateFunc schema.SchemaValidateFunc
+}
+
+// Description describes the validation in plain text formatting.
+func (v sdkValidator) Description(_ context.Context) string {
+	return "value must pass the attribute's validation function"
+}
+
+// MarkdownDescription describes the validation in Markdown formatting.
+func (v sdkValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sdkValidator) validate(value interface{}, p path.Path, diags *diag.Diagnostics) {
+	warnings, errs := v.validateFunc(value, p.String())
+	for _, w := range warnings {
+		diags.AddAttributeWarning(p, "Attribute validation warning", w)
+	}
+	for _, err := range errs {
+		diags.AddAttributeError(p, "Invalid attribute value", err.Error())
+	}
+}
+
+// ValidateString performs the validation.
+func (v sdkValidator) ValidateString(ctx context.Context, request validator.StringRequest, response *validator.StringResponse) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(request.ConfigValue.ValueString(), request.Path, &response.Diagnostics)
+}
+
+// ValidateInt64 performs the validation. SDK validation functions for
+// integers expect an int.
+func (v sdkValidator) ValidateInt64(ctx context.Context, request validator.Int64Request, response *validator.Int64Response) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(int(request.ConfigValue.ValueInt64()), request.Path, &response.Diagnostics)
+}
+
+// ValidateFloat64 performs the validation.
+func (v sdkValidator) ValidateFloat64(ctx context.Context, request validator.Float64Request, response *validator.Float64Response) {
+	if request.ConfigValue.IsNull() || request.ConfigValue.IsUnknown() {
+		return
+	}
+	v.validate(request.ConfigValue.ValueFloat64(), request.Path, &response.Diagnostics)
+}
+
+// StringSDKValidator returns a validator that runs the plugin SDK validation
+// function f against a string attribute.
+func StringSDKValidator(f schema.SchemaValidateFunc) validator.String {
+	return sdkValidator{validateFunc: f}
+}
+
+// Int64SDKValidator returns a validator that runs the plugin SDK validation
+// function f against an integer attribute.
+func Int64SDKValidator(f schema.SchemaValidateFunc) validator.Int64 {
+	return sdkValidator{validateFunc: f}
+}
+
+// Float64SDKValidator returns a validator that runs the plugin SDK validation
+// function f against a number attribute.
+func Float64SDKValidator(f schema.SchemaValidateFunc) validator.Float64 {
+	return sdkValidator{validateFunc: f}
+}
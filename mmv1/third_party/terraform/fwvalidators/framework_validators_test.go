@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/hashicorp/terraform-provider-google/google/acctest"
 	"github.com/hashicorp/terraform-provider-google/google/fwvalidators"
@@ -539,3 +540,65 @@ func TestTopicPrefixValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestSDKValidator(t *testing.T) {
+	t.Parallel()
+
+	stringValidator := fwvalidators.StringSDKValidator(validation.StringLenBetween(1, 3))
+	int64Validator := fwvalidators.Int64SDKValidator(validation.IntBetween(1, 3))
+
+	tests := map[string]struct {
+		validate    func() diag.Diagnostics
+		expectError bool
+	}{
+		"valid string": {
+			validate: func() diag.Diagnostics {
+				response := validator.StringResponse{}
+				stringValidator.ValidateString(context.Background(), validator.StringRequest{Path: path.Root("name"), ConfigValue: types.StringValue("abc")}, &response)
+				return response.Diagnostics
+			},
+		},
+		"invalid string": {
+			validate: func() diag.Diagnostics {
+				response := validator.StringResponse{}
+				stringValidator.ValidateString(context.Background(), validator.StringRequest{Path: path.Root("name"), ConfigValue: types.StringValue("abcd")}, &response)
+				return response.Diagnostics
+			},
+			expectError: true,
+		},
+		"null string": {
+			validate: func() diag.Diagnostics {
+				response := validator.StringResponse{}
+				stringValidator.ValidateString(context.Background(), validator.StringRequest{Path: path.Root("name"), ConfigValue: types.StringNull()}, &response)
+				return response.Diagnostics
+			},
+		},
+		"valid integer": {
+			validate: func() diag.Diagnostics {
+				response := validator.Int64Response{}
+				int64Validator.ValidateInt64(context.Background(), validator.Int64Request{Path: path.Root("count"), ConfigValue: types.Int64Value(2)}, &response)
+				return response.Diagnostics
+			},
+		},
+		"invalid integer": {
+			validate: func() diag.Diagnostics {
+				response := validator.Int64Response{}
+				int64Validator.ValidateInt64(context.Background(), validator.Int64Request{Path: path.Root("count"), ConfigValue: types.Int64Value(4)}, &response)
+				return response.Diagnostics
+			},
+			expectError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diags := test.validate()
+			if test.expectError != diags.HasError() {
+				t.Errorf("expected error: %t, got: %v", test.expectError, diags)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package securitycenterv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+func TestSecurityCenterV2ProjectSccBigQueryExport_moveState(t *testing.T) {
+	ctx := context.Background()
+	r := NewSecurityCenterV2ProjectSccBigQueryExportFWResource().(resource.ResourceWithMoveState)
+
+	schemaResp := &resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("Schema() returned errors: %v", schemaResp.Diagnostics)
+	}
+
+	req := resource.MoveStateRequest{
+		SourceTypeName: "google_scc_project_scc_big_query_export",
+		SourceRawState: &tfprotov6.RawState{
+			JSON: []byte(`{
+				"id": "projects/my-project/bigQueryExports/my-export",
+				"name": "projects/my-project/bigQueryExports/my-export",
+				"project": "my-project",
+				"big_query_export_id": "my-export",
+				"dataset": "projects/my-project/datasets/my_dataset",
+				"description": "Cloud Security Command Center Findings Big Query Export Config"
+			}`),
+		},
+	}
+	resp := &resource.MoveStateResponse{
+		TargetState: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	for _, mover := range r.MoveState(ctx) {
+		mover.StateMover(ctx, req, resp)
+	}
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("MoveState() returned errors: %v", resp.Diagnostics)
+	}
+
+	want := map[string]string{
+		"id":                  "projects/my-project/locations/global/bigQueryExports/my-export",
+		"name":                "projects/my-project/locations/global/bigQueryExports/my-export",
+		"location":            "global",
+		"project":             "my-project",
+		"big_query_export_id": "my-export",
+		"dataset":             "projects/my-project/datasets/my_dataset",
+	}
+	for field, value := range want {
+		var got types.String
+		resp.Diagnostics.Append(resp.TargetState.GetAttribute(ctx, path.Root(field), &got)...)
+		if got.ValueString() != value {
+			t.Errorf("%s = %q, want %q", field, got.ValueString(), value)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("error reading the moved state: %v", resp.Diagnostics)
+	}
+}
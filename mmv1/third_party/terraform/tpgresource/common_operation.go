@@ -1,6 +1,7 @@
 package tpgresource
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -137,6 +138,13 @@ func CommonRefreshFunc(w Waiter) retry.StateRefreshFunc {
 }
 
 func OperationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
+	// Waiters are used by SDK resources, whose functions have no context
+	return transport_tpg.TraceWait(context.Background(), "OperationWait", activity, w.OpName(), func() error {
+		return operationWait(w, activity, timeout, pollInterval)
+	})
+}
+
+func operationWait(w Waiter, activity string, timeout time.Duration, pollInterval time.Duration) error {
 	if OperationDone(w) {
 		return w.Error()
 	}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/errwrap"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const DefaultBatchSendIntervalSec = 3
@@ -259,7 +260,12 @@ func (b *RequestBatcher) registerBatchRequest(batchKey string, newRequest *Batch
 
 func (b *RequestBatcher) sendBatchWithSingleRetry(batchKey string, batch *startedBatch) {
 	log.Printf("[DEBUG] Sending batch %q combining %d requests)", batchKey, len(batch.subscribers))
+	_, span := tracer().Start(b.parentCtx, "RequestBatcher.flush", trace.WithAttributes(
+		batchKeyAttribute.String(batchKey),
+		batchRequestsAttribute.Int(len(batch.subscribers)),
+	))
 	resp := batch.send()
+	endSpan(span, 0, resp.err)
 
 	// If the batch failed and combines more than one request, retry each single request.
 	if resp.IsError() && len(batch.subscribers) > 1 {
@@ -1,6 +1,7 @@
 package transport
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync"
@@ -36,15 +37,17 @@ func PollingWaitTime(pollF PollReadFunc, checkResponse PollCheckResponseFunc, ac
 	timeout time.Duration, targetOccurrences int) error {
 	log.Printf("[DEBUG] %s: Polling until expected state is read", activity)
 	log.Printf("[DEBUG] Target occurrences: %d", targetOccurrences)
-	if targetOccurrences == 1 {
-		return retry.Retry(timeout, func() *retry.RetryError {
+	return TraceWait(context.Background(), "PollingWaitTime", activity, "", func() error {
+		if targetOccurrences == 1 {
+			return retry.Retry(timeout, func() *retry.RetryError {
+				readResp, readErr := pollF()
+				return checkResponse(readResp, readErr)
+			})
+		}
+		return RetryWithTargetOccurrences(timeout, targetOccurrences, func() *retry.RetryError {
 			readResp, readErr := pollF()
 			return checkResponse(readResp, readErr)
 		})
-	}
-	return RetryWithTargetOccurrences(timeout, targetOccurrences, func() *retry.RetryError {
-		readResp, readErr := pollF()
-		return checkResponse(readResp, readErr)
 	})
 }
 
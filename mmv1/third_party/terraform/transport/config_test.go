@@ -4,6 +4,7 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -454,6 +455,144 @@ func TestConfigLoadAndValidate_customBatchingConfig(t *testing.T) {
 	}
 }
 
+func TestExpandProviderRateLimitingConfig(t *testing.T) {
+	cases := map[string]struct {
+		Input       interface{}
+		Expected    *transport_tpg.RateLimitConfig
+		ExpectError bool
+	}{
+		"nil": {
+			Input:    nil,
+			Expected: nil,
+		},
+		"empty block": {
+			Input:    []interface{}{},
+			Expected: nil,
+		},
+		"all fields": {
+			Input: []interface{}{
+				map[string]interface{}{
+					"requests_per_second": 2.5,
+					"burst":               5,
+					"max_concurrency":     10,
+				},
+			},
+			Expected: &transport_tpg.RateLimitConfig{
+				RequestsPerSecond: 2.5,
+				Burst:             5,
+				MaxConcurrency:    10,
+			},
+		},
+		"negative value": {
+			Input: []interface{}{
+				map[string]interface{}{
+					"requests_per_second": -1.0,
+				},
+			},
+			ExpectError: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			rateLimitCfg, err := transport_tpg.ExpandProviderRateLimitingConfig(tc.Input)
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(rateLimitCfg, tc.Expected) {
+				t.Fatalf("expected %#v, got %#v", tc.Expected, rateLimitCfg)
+			}
+		})
+	}
+}
+
+func TestExpandProviderTracingConfig(t *testing.T) {
+	cases := map[string]struct {
+		Input        interface{}
+		EnvVariables map[string]string
+		Expected     *transport_tpg.TracingConfig
+	}{
+		"not configured": {
+			Input:    []interface{}{},
+			Expected: nil,
+		},
+		"configured in the provider block": {
+			Input: []interface{}{
+				map[string]interface{}{
+					"otlp_endpoint": "localhost:4318",
+					"file":          "",
+				},
+			},
+			Expected: &transport_tpg.TracingConfig{
+				OTLPEndpoint: "localhost:4318",
+			},
+		},
+		"configured with environment variables": {
+			Input: []interface{}{},
+			EnvVariables: map[string]string{
+				"GOOGLE_TRACING_FILE": "trace.json",
+			},
+			Expected: &transport_tpg.TracingConfig{
+				File: "trace.json",
+			},
+		},
+		"provider block takes precedence over environment variables": {
+			Input: []interface{}{
+				map[string]interface{}{
+					"otlp_endpoint": "localhost:4318",
+					"file":          "",
+				},
+			},
+			EnvVariables: map[string]string{
+				"GOOGLE_TRACING_OTLP_ENDPOINT": "collector:4318",
+				"GOOGLE_TRACING_FILE":          "trace.json",
+			},
+			Expected: &transport_tpg.TracingConfig{
+				OTLPEndpoint: "localhost:4318",
+				File:         "trace.json",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			t.Setenv("GOOGLE_TRACING_OTLP_ENDPOINT", "")
+			t.Setenv("GOOGLE_TRACING_FILE", "")
+			for k, v := range tc.EnvVariables {
+				t.Setenv(k, v)
+			}
+
+			tracingCfg := transport_tpg.ExpandProviderTracingConfig(tc.Input)
+			if !reflect.DeepEqual(tracingCfg, tc.Expected) {
+				t.Fatalf("expected %#v, got %#v", tc.Expected, tracingCfg)
+			}
+		})
+	}
+}
+
+func TestConfigLoadAndValidate_rateLimitingConfig(t *testing.T) {
+	config := &transport_tpg.Config{
+		Credentials: transport_tpg.TestFakeCredentialsPath,
+		Project:     "my-gce-project",
+		Region:      "us-central1",
+		RateLimitConfig: &transport_tpg.RateLimitConfig{
+			RequestsPerSecond: 10,
+			MaxConcurrency:    5,
+		},
+	}
+
+	err := config.LoadAndValidate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestRemoveBasePathVersion(t *testing.T) {
 	cases := []struct {
 		BaseURL  string
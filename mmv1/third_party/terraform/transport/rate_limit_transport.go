@@ -0,0 +1,399 @@
+// A http.RoundTripper that throttles requests on the client side, before GCP
+// quotas are exhausted, rather than relying on the retry transport to back
+// off after requests have failed.
+//
+// Requests are throttled separately for each service and project, as most
+// GCP quotas are. Each service and project gets:
+//   - a token bucket, refilled at RequestsPerSecond, which holds up to Burst
+//     requests. The refill rate is lowered to a per-minute or per-second quota
+//     reported in the metadata of a quota error.
+//   - an adaptive concurrency limit on in-flight requests (AIMD). The limit is
+//     halved when a request is throttled by the API, and grows back by one
+//     request per limit's worth of successful requests, up to MaxConcurrency.
+//
+// When the API asks the client to wait, through a Retry-After header or a
+// RetryInfo error detail, no requests are sent to that service and project
+// until the delay has passed.
+//
+// The rate limit transport sits below the retry transport so that retried
+// requests are throttled as well.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// RateLimitConfig configures the client-side throttling of requests, applied
+// separately to each service and project.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained rate of requests. 0 means requests
+	// are only throttled by quota errors.
+	RequestsPerSecond float64
+	// Burst is the number of requests that can be sent at once. Defaults to
+	// one second's worth of requests.
+	Burst int
+	// MaxConcurrency is the upper bound of the adaptive limit on in-flight
+	// requests. 0 means the number of in-flight requests is not limited.
+	MaxConcurrency int
+}
+
+// rateLimitClock abstracts the passing of time so that the rate limit
+// transport can be tested deterministically.
+type rateLimitClock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realRateLimitClock struct{}
+
+func (realRateLimitClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realRateLimitClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTransportWithRateLimit constructs a rateLimitTransport that throttles
+// requests sent through t according to config.
+func NewTransportWithRateLimit(t http.RoundTripper, config RateLimitConfig) *rateLimitTransport {
+	return &rateLimitTransport{
+		config:   config,
+		internal: t,
+		clock:    realRateLimitClock{},
+		limiters: make(map[string]*rateLimiter),
+	}
+}
+
+type rateLimitTransport struct {
+	config   RateLimitConfig
+	internal http.RoundTripper
+	clock    rateLimitClock
+
+	// mu guards limiters and the state of every limiter
+	mu       sync.Mutex
+	limiters map[string]*rateLimiter
+}
+
+// rateLimiter holds the throttling state of a single service and project.
+type rateLimiter struct {
+	// Token bucket
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// Requests are held until pausedUntil, as requested by the API
+	pausedUntil time.Time
+
+	// Adaptive concurrency
+	limit    float64
+	inFlight int
+	waiters  []chan struct{}
+}
+
+var rateLimitProjectRegex = regexp.MustCompile(`/projects/([^/:]+)`)
+
+// rateLimitKey returns the service and project that a request counts against.
+func rateLimitKey(req *http.Request) string {
+	project := req.Header.Get("X-Goog-User-Project")
+	if project == "" {
+		if m := rateLimitProjectRegex.FindStringSubmatch(req.URL.Path); m != nil {
+			project = m[1]
+		}
+	}
+	return req.URL.Host + "/" + project
+}
+
+// RoundTrip implements the RoundTripper interface method. It waits until the
+// request can be sent without exceeding the limits of its service and
+// project, then adjusts those limits based on the response.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := rateLimitKey(req)
+	if err := t.acquire(req.Context(), key); err != nil {
+		return nil, err
+	}
+
+	resp, respErr := t.internal.RoundTrip(req)
+	t.release(key, resp, respErr)
+	return resp, respErr
+}
+
+// limiter returns the limiter of key, creating it if needed. t.mu must be held.
+func (t *rateLimitTransport) limiter(key string) *rateLimiter {
+	l, ok := t.limiters[key]
+	if !ok {
+		l = &rateLimiter{
+			rate:  t.config.RequestsPerSecond,
+			last:  t.clock.Now(),
+			limit: float64(t.config.MaxConcurrency),
+		}
+		l.burst = t.burst(l.rate)
+		l.tokens = l.burst
+		t.limiters[key] = l
+	}
+	return l
+}
+
+// burst returns the size of the token bucket for a given rate.
+func (t *rateLimitTransport) burst(rate float64) float64 {
+	if t.config.Burst > 0 {
+		return float64(t.config.Burst)
+	}
+	return math.Max(1, math.Ceil(rate))
+}
+
+// acquire waits until a request to key can be sent, taking an in-flight slot
+// and a token from its limiter.
+func (t *rateLimitTransport) acquire(ctx context.Context, key string) error {
+	if err := t.acquireSlot(ctx, key); err != nil {
+		return err
+	}
+
+	for {
+		t.mu.Lock()
+		wait := t.limiter(key).reserve(t.clock.Now())
+		t.mu.Unlock()
+		if wait <= 0 {
+			return nil
+		}
+
+		log.Printf("[DEBUG] Rate Limit Transport: Waiting %s before sending request to %s", wait, key)
+		select {
+		case <-ctx.Done():
+			t.releaseSlot(key)
+			return ctx.Err()
+		case <-t.clock.After(wait):
+		}
+	}
+}
+
+// reserve takes a token from the bucket, returning zero, or returns how long
+// to wait before trying again.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - l.tokens) / l.rate * float64(time.Second)))
+}
+
+// acquireSlot waits until fewer requests to key are in flight than its
+// concurrency limit allows.
+func (t *rateLimitTransport) acquireSlot(ctx context.Context, key string) error {
+	if t.config.MaxConcurrency <= 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	l := t.limiter(key)
+	if len(l.waiters) == 0 && l.inFlight < l.maxInFlight() {
+		l.inFlight++
+		t.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	inFlight := l.inFlight
+	t.mu.Unlock()
+
+	log.Printf("[DEBUG] Rate Limit Transport: Waiting for one of %d in-flight requests to %s to complete", inFlight, key)
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		for i, w := range l.waiters {
+			if w == ready {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// The slot was handed over after the context was done.
+		l.inFlight--
+		l.wakeWaiters()
+		return ctx.Err()
+	}
+}
+
+// releaseSlot frees the in-flight slot of a request to key.
+func (t *rateLimitTransport) releaseSlot(key string) {
+	if t.config.MaxConcurrency <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l := t.limiter(key)
+	l.inFlight--
+	l.wakeWaiters()
+}
+
+// maxInFlight returns the current concurrency limit, which is at least one.
+func (l *rateLimiter) maxInFlight() int {
+	return int(math.Max(1, math.Floor(l.limit)))
+}
+
+// wakeWaiters hands free in-flight slots to waiting requests, in order.
+func (l *rateLimiter) wakeWaiters() {
+	for len(l.waiters) > 0 && l.inFlight < l.maxInFlight() {
+		l.inFlight++
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+	}
+}
+
+// release frees the in-flight slot of a request to key and adjusts the
+// limits of key based on its response.
+func (t *rateLimitTransport) release(key string, resp *http.Response, respErr error) {
+	throttle := rateLimitThrottle{}
+	if respErr == nil {
+		throttle = checkRateLimitThrottle(resp, t.clock.Now())
+	}
+
+	t.mu.Lock()
+	l := t.limiter(key)
+	if t.config.MaxConcurrency > 0 {
+		if throttle.throttled {
+			// Multiplicative decrease
+			l.limit = math.Max(1, l.limit/2)
+			log.Printf("[DEBUG] Rate Limit Transport: Request to %s was throttled, lowering the concurrency limit to %d", key, l.maxInFlight())
+		} else if respErr == nil {
+			// Additive increase, by one request per limit's worth of requests
+			l.limit = math.Min(float64(t.config.MaxConcurrency), l.limit+1/l.limit)
+		}
+	}
+	if throttle.retryAfter.After(l.pausedUntil) {
+		l.pausedUntil = throttle.retryAfter
+		log.Printf("[DEBUG] Rate Limit Transport: Pausing requests to %s until %s", key, l.pausedUntil)
+	}
+	if throttle.quotaRate > 0 && (l.rate <= 0 || throttle.quotaRate < l.rate) {
+		l.rate = throttle.quotaRate
+		l.burst = t.burst(l.rate)
+		l.tokens = math.Min(l.tokens, l.burst)
+		log.Printf("[DEBUG] Rate Limit Transport: Lowering the rate of requests to %s to %g per second to match its quota", key, l.rate)
+	}
+	t.mu.Unlock()
+
+	t.releaseSlot(key)
+}
+
+// rateLimitThrottle describes how the API throttled a request.
+type rateLimitThrottle struct {
+	// throttled is set if the request failed due to a quota or rate limit
+	throttled bool
+	// retryAfter is the time before which requests shouldn't be retried
+	retryAfter time.Time
+	// quotaRate is the per-second rate of the quota that was exceeded
+	quotaRate float64
+}
+
+// checkRateLimitThrottle returns whether a response reports a quota or rate
+// limit error, reading the Retry-After header and the RetryInfo and
+// ErrorInfo details of the error. The response body is left unconsumed.
+func checkRateLimitThrottle(resp *http.Response, now time.Time) rateLimitThrottle {
+	throttle := rateLimitThrottle{}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return throttle
+	}
+	throttle.throttled = resp.StatusCode == http.StatusTooManyRequests
+
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			throttle.retryAfter = now.Add(time.Duration(seconds) * time.Second)
+		} else if date, err := http.ParseTime(v); err == nil {
+			throttle.retryAfter = date
+		}
+	}
+
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return throttle
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return throttle
+	}
+
+	respToCheck := *resp
+	respToCheck.Body = io.NopCloser(bytes.NewReader(body))
+	gerr, ok := googleapi.CheckResponse(&respToCheck).(*googleapi.Error)
+	if !ok {
+		return throttle
+	}
+	if retryable, _ := is403QuotaExceededPerMinuteError(gerr); retryable {
+		throttle.throttled = true
+	}
+	for _, d := range gerr.Details {
+		detail, ok := d.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch detail["@type"] {
+		case "type.googleapis.com/google.rpc.RetryInfo":
+			if v, ok := detail["retryDelay"].(string); ok {
+				if delay, err := time.ParseDuration(v); err == nil && now.Add(delay).After(throttle.retryAfter) {
+					throttle.retryAfter = now.Add(delay)
+				}
+			}
+		case "type.googleapis.com/google.rpc.ErrorInfo":
+			if reason, _ := detail["reason"].(string); reason == "RATE_LIMIT_EXCEEDED" || reason == "RESOURCE_EXHAUSTED" {
+				throttle.throttled = true
+			}
+			if metadata, ok := detail["metadata"].(map[string]interface{}); ok {
+				throttle.quotaRate = quotaRate(metadata)
+			}
+		}
+	}
+	return throttle
+}
+
+// quotaRate returns the per-second rate of a quota from the metadata of an
+// ErrorInfo error detail, such as a `quota_limit_value` of "600" with a
+// `quota_unit` of "1/min/{project}", or 0 if it isn't a rate quota.
+func quotaRate(metadata map[string]interface{}) float64 {
+	value, _ := metadata["quota_limit_value"].(string)
+	unit, _ := metadata["quota_unit"].(string)
+	limit, err := strconv.ParseFloat(value, 64)
+	if err != nil || limit <= 0 {
+		return 0
+	}
+
+	parts := strings.Split(unit, "/")
+	if len(parts) < 2 {
+		return 0
+	}
+	switch parts[1] {
+	case "s":
+		return limit
+	case "min":
+		return limit / 60
+	}
+	return 0
+}
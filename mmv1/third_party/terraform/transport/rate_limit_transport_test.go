@@ -0,0 +1,386 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRateLimitClock only moves forward when the rate limit transport waits,
+// recording every wait.
+type fakeRateLimitClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeRateLimitClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeRateLimitClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeRateLimitClock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.waits
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func testRateLimitResponse(code int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode: code,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func setUpRateLimitTransport(config RateLimitConfig, internal roundTripperFunc) (*rateLimitTransport, *fakeRateLimitClock) {
+	clock := &fakeRateLimitClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	t := NewTransportWithRateLimit(internal, config)
+	t.clock = clock
+	return t, clock
+}
+
+func sendRateLimitRequest(t *testing.T, transport http.RoundTripper, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func TestRateLimitTransport_TokenBucket(t *testing.T) {
+	transport, clock := setUpRateLimitTransport(RateLimitConfig{RequestsPerSecond: 2, Burst: 2}, func(*http.Request) (*http.Response, error) {
+		return testRateLimitResponse(200, nil, "{}"), nil
+	})
+
+	for i := 0; i < 5; i++ {
+		sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks")
+	}
+
+	// The first two requests use the burst, the others wait for a token.
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}
+	if got := clock.Waits(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected waits %v, got %v", want, got)
+	}
+}
+
+func TestRateLimitTransport_PerServiceAndProject(t *testing.T) {
+	transport, clock := setUpRateLimitTransport(RateLimitConfig{RequestsPerSecond: 1}, func(*http.Request) (*http.Response, error) {
+		return testRateLimitResponse(200, nil, "{}"), nil
+	})
+
+	sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/project-a/global/networks")
+	sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/project-b/global/networks")
+	sendRateLimitRequest(t, transport, "https://pubsub.googleapis.com/v1/projects/project-a/topics")
+	if got := clock.Waits(); len(got) != 0 {
+		t.Fatalf("expected requests to different services and projects not to wait, got %v", got)
+	}
+
+	sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/project-a/zones/us-central1-a/instances")
+	if got, want := clock.Waits(), []time.Duration{time.Second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected waits %v, got %v", want, got)
+	}
+}
+
+func TestRateLimitTransport_RetryAfterHeader(t *testing.T) {
+	attempts := 0
+	transport, clock := setUpRateLimitTransport(RateLimitConfig{}, func(*http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return testRateLimitResponse(429, http.Header{"Retry-After": []string{"30"}}, ""), nil
+		}
+		return testRateLimitResponse(200, nil, "{}"), nil
+	})
+
+	sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks")
+	sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks")
+
+	if got, want := clock.Waits(), []time.Duration{30 * time.Second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected waits %v, got %v", want, got)
+	}
+}
+
+const testRateLimitQuotaErrorBody = `{
+  "error": {
+    "code": 429,
+    "message": "Quota exceeded for quota metric 'Read requests' and limit 'Read requests per minute' of service 'compute.googleapis.com'",
+    "status": "RESOURCE_EXHAUSTED",
+    "details": [
+      {
+        "@type": "type.googleapis.com/google.rpc.ErrorInfo",
+        "reason": "RATE_LIMIT_EXCEEDED",
+        "domain": "googleapis.com",
+        "metadata": {
+          "quota_limit": "ReadRequestsPerMinutePerProject",
+          "quota_limit_value": "120",
+          "quota_unit": "1/min/{project}",
+          "service": "compute.googleapis.com"
+        }
+      },
+      {
+        "@type": "type.googleapis.com/google.rpc.RetryInfo",
+        "retryDelay": "12s"
+      }
+    ]
+  }
+}`
+
+func TestRateLimitTransport_QuotaErrorDetails(t *testing.T) {
+	attempts := 0
+	transport, clock := setUpRateLimitTransport(RateLimitConfig{}, func(*http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return testRateLimitResponse(429, nil, testRateLimitQuotaErrorBody), nil
+		}
+		return testRateLimitResponse(200, nil, "{}"), nil
+	})
+
+	resp := sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks")
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != testRateLimitQuotaErrorBody {
+		t.Errorf("expected the response body to be left unconsumed, got %q", body)
+	}
+
+	limiter := transport.limiters["compute.googleapis.com/my-project"]
+	if limiter.rate != 2 {
+		t.Errorf("expected the rate to match the quota of 2 requests per second, got %g", limiter.rate)
+	}
+
+	for i := 0; i < 3; i++ {
+		sendRateLimitRequest(t, transport, "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks")
+	}
+
+	// The RetryInfo delay is waited first, which refills the bucket with
+	// 2 tokens; the third request waits for a token.
+	want := []time.Duration{12 * time.Second, 500 * time.Millisecond}
+	if got := clock.Waits(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected waits %v, got %v", want, got)
+	}
+}
+
+func TestRateLimitTransport_AdaptiveConcurrencyLimit(t *testing.T) {
+	throttle := true
+	transport, _ := setUpRateLimitTransport(RateLimitConfig{MaxConcurrency: 4}, func(*http.Request) (*http.Response, error) {
+		if throttle {
+			return testRateLimitResponse(429, nil, ""), nil
+		}
+		return testRateLimitResponse(200, nil, "{}"), nil
+	})
+	url := "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks"
+	limiter := func() *rateLimiter {
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		return transport.limiter("compute.googleapis.com/my-project")
+	}
+
+	sendRateLimitRequest(t, transport, url)
+	if got := limiter().maxInFlight(); got != 2 {
+		t.Fatalf("expected a throttled request to halve the concurrency limit to 2, got %d", got)
+	}
+	sendRateLimitRequest(t, transport, url)
+	sendRateLimitRequest(t, transport, url)
+	if got := limiter().maxInFlight(); got != 1 {
+		t.Fatalf("expected the concurrency limit to be at least 1, got %d", got)
+	}
+
+	throttle = false
+	// 1 -> 2 -> 2.5 -> 2.9 -> 3.24 -> 3.55 -> 3.83 -> 4
+	var got []int
+	for i := 0; i < 7; i++ {
+		sendRateLimitRequest(t, transport, url)
+		got = append(got, limiter().maxInFlight())
+	}
+	if want := []int{2, 2, 2, 3, 3, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the concurrency limit to grow as %v, got %v", want, got)
+	}
+}
+
+func TestRateLimitTransport_ConcurrencyLimitQueuesRequests(t *testing.T) {
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	transport, _ := setUpRateLimitTransport(RateLimitConfig{MaxConcurrency: 1}, func(req *http.Request) (*http.Response, error) {
+		inFlight <- struct{}{}
+		<-release
+		return testRateLimitResponse(200, nil, "{}"), nil
+	})
+	url := "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks"
+	waiters := func() int {
+		transport.mu.Lock()
+		defer transport.mu.Unlock()
+		return len(transport.limiter("compute.googleapis.com/my-project").waiters)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", url, nil)
+			if _, err := transport.RoundTrip(req); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	<-inFlight
+	for waiters() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	release <- struct{}{}
+	<-inFlight
+	if got := waiters(); got != 0 {
+		t.Errorf("expected the queued request to be sent, %d requests are still waiting", got)
+	}
+	release <- struct{}{}
+	wg.Wait()
+}
+
+func TestRateLimitTransport_ContextCanceledWhileQueued(t *testing.T) {
+	release := make(chan struct{})
+	transport, _ := setUpRateLimitTransport(RateLimitConfig{MaxConcurrency: 1}, func(req *http.Request) (*http.Response, error) {
+		<-release
+		return testRateLimitResponse(200, nil, "{}"), nil
+	})
+	url := "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks"
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := http.NewRequest("GET", url, nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+	for {
+		transport.mu.Lock()
+		inFlight := transport.limiter("compute.googleapis.com/my-project").inFlight
+		transport.mu.Unlock()
+		if inFlight == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a canceled request to return %v, got %v", context.Canceled, err)
+	}
+
+	close(release)
+	<-done
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	l := transport.limiter("compute.googleapis.com/my-project")
+	if l.inFlight != 0 || len(l.waiters) != 0 {
+		t.Errorf("expected no requests in flight or waiting, got %d in flight and %d waiting", l.inFlight, len(l.waiters))
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	cases := map[string]struct {
+		url     string
+		project string
+		want    string
+	}{
+		"project in path": {
+			url:  "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks",
+			want: "compute.googleapis.com/my-project",
+		},
+		"project with a custom method": {
+			url:  "https://cloudresourcemanager.googleapis.com/v1/projects/my-project:getIamPolicy",
+			want: "cloudresourcemanager.googleapis.com/my-project",
+		},
+		"user project header": {
+			url:     "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks",
+			project: "billing-project",
+			want:    "compute.googleapis.com/billing-project",
+		},
+		"no project": {
+			url:  "https://storage.googleapis.com/storage/v1/b/my-bucket",
+			want: "storage.googleapis.com/",
+		},
+	}
+
+	for tn, tc := range cases {
+		req, err := http.NewRequest("GET", tc.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.project != "" {
+			req.Header.Set("X-Goog-User-Project", tc.project)
+		}
+		if got := rateLimitKey(req); got != tc.want {
+			t.Errorf("%s: expected key %q, got %q", tn, tc.want, got)
+		}
+	}
+}
+
+func TestQuotaRate(t *testing.T) {
+	cases := map[string]struct {
+		metadata map[string]interface{}
+		want     float64
+	}{
+		"per minute": {
+			metadata: map[string]interface{}{"quota_limit_value": "600", "quota_unit": "1/min/{project}"},
+			want:     10,
+		},
+		"per second": {
+			metadata: map[string]interface{}{"quota_limit_value": "5", "quota_unit": "1/s/{project}/{user}"},
+			want:     5,
+		},
+		"per day": {
+			metadata: map[string]interface{}{"quota_limit_value": "1000", "quota_unit": "1/d/{project}"},
+		},
+		"allocation": {
+			metadata: map[string]interface{}{"quota_limit_value": "24", "quota_unit": "1/{project}/{region}"},
+		},
+		"no limit": {
+			metadata: map[string]interface{}{"quota_unit": "1/min/{project}"},
+		},
+	}
+
+	for tn, tc := range cases {
+		if got := quotaRate(tc.metadata); got != tc.want {
+			t.Errorf("%s: expected %g, got %g", tn, tc.want, got)
+		}
+	}
+}
@@ -114,7 +114,9 @@ Retry:
 		if copyErr != nil {
 			log.Printf("[DEBUG] Retry Transport: Unable to copy request body: %v.", copyErr)
 			log.Printf("[DEBUG] Retry Transport: Running request as non-retryable")
-			resp, respErr = t.internal.RoundTrip(req)
+			spanCtx, span := startAttemptSpan(req, attempts)
+			resp, respErr = t.internal.RoundTrip(req.WithContext(spanCtx))
+			endAttemptSpan(span, resp, respErr)
 			break Retry
 		}
 
@@ -127,10 +129,12 @@ Retry:
 			log.Printf("[DEBUG] Retry Transport: request attempt %d", attempts)
 		}
 		// Do the wrapped Roundtrip. This is one request in the retry loop.
-		resp, respErr = t.internal.RoundTrip(newRequest)
+		spanCtx, span := startAttemptSpan(newRequest, attempts)
+		resp, respErr = t.internal.RoundTrip(newRequest.WithContext(spanCtx))
 		attempts++
 
 		retryErr := t.checkForRetryableError(resp, respErr)
+		endAttemptSpan(span, resp, respErr)
 		if retryErr == nil {
 			if attempts > 1 {
 				log.Printf("[DEBUG] Retry Transport: Stopping retries, last request was successful")
@@ -0,0 +1,268 @@
+// Tracing of the requests made by the provider with OpenTelemetry.
+//
+// Spans are recorded for every SendRequest and SendRequestRPC call, including
+// the plugin framework's SendRequest, for each attempt made by the retry
+// transport, for operation and resource polling, and for each batch sent by a
+// RequestBatcher. Request spans are children of the span of the caller's
+// context, when the caller has one. Spans are only exported when
+// tracing is configured, either through the provider's `tracing` block or
+// the GOOGLE_TRACING_OTLP_ENDPOINT and GOOGLE_TRACING_FILE environment
+// variables; otherwise the global no-op tracer provider discards them.
+//
+// Spans carry the Terraform resource type (when known), the HTTP method, a URL
+// template with the IDs of resources replaced by placeholders, and the HTTP
+// status of the response.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/googleapi"
+)
+
+const tracerName = "github.com/hashicorp/terraform-provider-google/google/transport"
+
+// Span attributes
+const (
+	resourceTypeAttribute  = attribute.Key("terraform.resource_type")
+	activityAttribute      = attribute.Key("terraform.activity")
+	operationAttribute     = attribute.Key("gcp.operation")
+	batchKeyAttribute      = attribute.Key("terraform.batch.key")
+	batchRequestsAttribute = attribute.Key("terraform.batch.requests")
+	httpMethodAttribute    = attribute.Key("http.request.method")
+	httpStatusAttribute    = attribute.Key("http.response.status_code")
+	resendCountAttribute   = attribute.Key("http.request.resend_count")
+	urlTemplateAttribute   = attribute.Key("url.template")
+	rpcServiceAttribute    = attribute.Key("rpc.service")
+	rpcMethodAttribute     = attribute.Key("rpc.method")
+)
+
+// TracingConfig configures where the spans recorded by the provider are
+// exported. Both exporters can be used at once.
+type TracingConfig struct {
+	// OTLPEndpoint is the host and port of an OTLP/HTTP collector, such as
+	// "localhost:4318".
+	OTLPEndpoint string
+	// File is the path of a file that spans are appended to as JSON.
+	File string
+}
+
+var (
+	// tracingMu guards tracingShutdown
+	tracingMu       sync.Mutex
+	tracingShutdown func(context.Context) error
+)
+
+// ConfigureTracing installs a tracer provider exporting spans as configured.
+// Tracing is configured once per provider process; later calls are no-ops.
+func ConfigureTracing(ctx context.Context, config TracingConfig) error {
+	tracingMu.Lock()
+	defer tracingMu.Unlock()
+
+	if tracingShutdown != nil {
+		return nil
+	}
+
+	var opts []sdktrace.TracerProviderOption
+	var file *os.File
+	if config.OTLPEndpoint != "" {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(config.OTLPEndpoint), otlptracehttp.WithInsecure())
+		if err != nil {
+			return fmt.Errorf("error creating OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	if config.File != "" {
+		var err error
+		file, err = os.OpenFile(config.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("error opening trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("error creating file trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+
+	res := sdkresource.NewSchemaless(attribute.String("service.name", "terraform-provider-google"))
+	tp := sdktrace.NewTracerProvider(append(opts, sdktrace.WithResource(res))...)
+	otel.SetTracerProvider(tp)
+	log.Printf("[INFO] Exporting traces (OTLP endpoint: %q, file: %q)", config.OTLPEndpoint, config.File)
+
+	tracingShutdown = func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// ShutdownTracing exports any spans that haven't been exported yet, and
+// stops exporting spans. It should be called before the provider exits.
+func ShutdownTracing(ctx context.Context) {
+	tracingMu.Lock()
+	defer tracingMu.Unlock()
+
+	if tracingShutdown == nil {
+		return
+	}
+	if err := tracingShutdown(ctx); err != nil {
+		log.Printf("[WARN] Error exporting traces: %v", err)
+	}
+	tracingShutdown = nil
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// startRequestSpan starts a span for a request sent with SendRequest or
+// SendRequestRPC, as a child of the span of opt.Context if it has one.
+func startRequestSpan(name string, opt SendRequestOptions, method, rawURL string) (context.Context, trace.Span) {
+	ctx, span := StartRequestSpan(opt.Context, name, opt.ResourceType, method, rawURL)
+	if opt.RPCService != "" {
+		span.SetAttributes(rpcServiceAttribute.String(opt.RPCService), rpcMethodAttribute.String(opt.Method))
+	}
+	return ctx, span
+}
+
+// StartRequestSpan starts a span for a request made for resourceType, as a
+// child of the span of ctx if it has one. ctx may be nil. It's used by the
+// plugin framework's SendRequest; the span is ended with EndRequestSpan.
+func StartRequestSpan(ctx context.Context, name, resourceType, method, rawURL string) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attrs := []attribute.KeyValue{
+		httpMethodAttribute.String(method),
+		urlTemplateAttribute.String(urlTemplate(rawURL)),
+	}
+	if resourceType != "" {
+		attrs = append(attrs, resourceTypeAttribute.String(resourceType))
+	}
+	return tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// EndRequestSpan records statusCode and err on a span started with
+// StartRequestSpan and ends it.
+func EndRequestSpan(span trace.Span, statusCode int, err error) {
+	endSpan(span, statusCode, err)
+}
+
+// startAttemptSpan starts a span for a single attempt of req made by the
+// retry transport.
+func startAttemptSpan(req *http.Request, attempt int) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		httpMethodAttribute.String(req.Method),
+		urlTemplateAttribute.String(urlTemplate(req.URL.String())),
+	}
+	if attempt > 0 {
+		attrs = append(attrs, resendCountAttribute.Int(attempt))
+	}
+	return tracer().Start(req.Context(), "HTTP "+req.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records statusCode and err on span and ends it. If statusCode is 0,
+// the status of a googleapi.Error is recorded instead.
+func endSpan(span trace.Span, statusCode int, err error) {
+	if statusCode == 0 {
+		var gerr *googleapi.Error
+		if errors.As(err, &gerr) {
+			statusCode = gerr.Code
+		}
+	}
+	if statusCode != 0 {
+		span.SetAttributes(httpStatusAttribute.Int(statusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// endAttemptSpan ends a span started with startAttemptSpan.
+func endAttemptSpan(span trace.Span, resp *http.Response, respErr error) {
+	var statusCode int
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	if respErr == nil && statusCode >= 400 {
+		respErr = fmt.Errorf("%s", http.StatusText(statusCode))
+	}
+	endSpan(span, statusCode, respErr)
+}
+
+// TraceWait records a span named name while wait runs, such as while waiting
+// for an operation to complete, as a child of the span of ctx if it has one.
+// operation is the name of the operation being waited on, if any.
+func TraceWait(ctx context.Context, name, activity, operation string, wait func() error) error {
+	attrs := []attribute.KeyValue{activityAttribute.String(activity)}
+	if operation != "" {
+		attrs = append(attrs, operationAttribute.String(operation))
+	}
+	_, span := tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+	err := wait()
+	endSpan(span, 0, err)
+	return err
+}
+
+// A segment of a resource name that names a collection, such as "projects"
+// or "instances". The segment following it is the ID of a resource.
+var collectionSegmentRegex = regexp.MustCompile(`^[a-z][a-zA-Z]*s$`)
+
+// urlTemplate returns rawURL without its query, with the ID of each resource
+// replaced by a placeholder named after its collection, following the format
+// of the flatPath of discovery documents. For example,
+// "https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic:publish"
+// becomes "https://pubsub.googleapis.com/v1/projects/{projectsId}/topics/{topicsId}:publish".
+func urlTemplate(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i := 1; i < len(segments); i++ {
+		collection := segments[i-1]
+		if !collectionSegmentRegex.MatchString(collection) || segments[i] == "" {
+			continue
+		}
+		placeholder := "{" + collection + "Id}"
+		if j := strings.LastIndex(segments[i], ":"); j > 0 {
+			placeholder += segments[i][j:]
+		}
+		segments[i] = placeholder
+		// The segment following an ID names a collection, or a method
+		i++
+	}
+
+	return u.Scheme + "://" + u.Host + strings.Join(segments, "/")
+}
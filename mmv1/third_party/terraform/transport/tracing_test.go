@@ -0,0 +1,341 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// setUpTracingExporter records the spans ended during the test in memory.
+func setUpTracingExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func findSpans(spans tracetest.SpanStubs, name string) tracetest.SpanStubs {
+	var found tracetest.SpanStubs
+	for _, s := range spans {
+		if s.Name == name {
+			found = append(found, s)
+		}
+	}
+	return found
+}
+
+func spanAttribute(s tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func checkSpanAttributes(t *testing.T, s tracetest.SpanStub, expected map[attribute.Key]interface{}) {
+	t.Helper()
+	for key, want := range expected {
+		got, ok := spanAttribute(s, key)
+		if !ok {
+			t.Errorf("span %q: expected attribute %q to be set", s.Name, key)
+			continue
+		}
+		if got.AsInterface() != want {
+			t.Errorf("span %q: expected attribute %q to be %v, got %v", s.Name, key, want, got.AsInterface())
+		}
+	}
+}
+
+func TestSendRequest_tracing(t *testing.T) {
+	exporter := setUpTracingExporter(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"name": "my-topic"}`)
+	}))
+	defer server.Close()
+
+	config := &Config{
+		Client: &http.Client{
+			Transport: NewTransportWithDefaultRetries(http.DefaultTransport),
+		},
+	}
+	ctx, parent := tracer().Start(context.Background(), "Read")
+	_, err := SendRequest(SendRequestOptions{
+		Config:       config,
+		Method:       "GET",
+		RawURL:       server.URL + "/v1/projects/my-project/topics/my-topic",
+		ResourceType: "google_pubsub_topic",
+		Context:      ctx,
+	})
+	parent.End()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	requestSpans := findSpans(spans, "SendRequest")
+	if len(requestSpans) != 1 {
+		t.Fatalf("expected 1 SendRequest span, got %d", len(requestSpans))
+	}
+	requestSpan := requestSpans[0]
+	if requestSpan.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected SendRequest span to be a child of the span of its context")
+	}
+	checkSpanAttributes(t, requestSpan, map[attribute.Key]interface{}{
+		resourceTypeAttribute: "google_pubsub_topic",
+		httpMethodAttribute:   "GET",
+		urlTemplateAttribute:  server.URL + "/v1/projects/{projectsId}/topics/{topicsId}",
+		httpStatusAttribute:   int64(200),
+	})
+	if requestSpan.Status.Code == codes.Error {
+		t.Errorf("expected SendRequest span not to have an error status")
+	}
+
+	attemptSpans := findSpans(spans, "HTTP GET")
+	if len(attemptSpans) != 2 {
+		t.Fatalf("expected 2 attempt spans, got %d", len(attemptSpans))
+	}
+	for _, s := range attemptSpans {
+		if s.Parent.SpanID() != requestSpan.SpanContext.SpanID() {
+			t.Errorf("expected attempt span to be a child of the SendRequest span")
+		}
+	}
+	checkSpanAttributes(t, attemptSpans[0], map[attribute.Key]interface{}{
+		httpStatusAttribute: int64(503),
+	})
+	if attemptSpans[0].Status.Code != codes.Error {
+		t.Errorf("expected the failed attempt span to have an error status")
+	}
+	if _, ok := spanAttribute(attemptSpans[0], resendCountAttribute); ok {
+		t.Errorf("expected the first attempt span not to have a resend count")
+	}
+	checkSpanAttributes(t, attemptSpans[1], map[attribute.Key]interface{}{
+		httpStatusAttribute:  int64(200),
+		resendCountAttribute: int64(1),
+	})
+}
+
+func TestSendRequest_tracingError(t *testing.T) {
+	exporter := setUpTracingExporter(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	config := &Config{
+		Client: &http.Client{
+			Transport: NewTransportWithDefaultRetries(http.DefaultTransport),
+		},
+	}
+	_, err := SendRequest(SendRequestOptions{
+		Config: config,
+		Method: "DELETE",
+		RawURL: server.URL + "/v1/projects/my-project/topics/my-topic",
+	})
+	if err == nil {
+		t.Fatalf("expected error, got none")
+	}
+
+	requestSpans := findSpans(exporter.GetSpans(), "SendRequest")
+	if len(requestSpans) != 1 {
+		t.Fatalf("expected 1 SendRequest span, got %d", len(requestSpans))
+	}
+	checkSpanAttributes(t, requestSpans[0], map[attribute.Key]interface{}{
+		httpMethodAttribute: "DELETE",
+		httpStatusAttribute: int64(404),
+	})
+	if _, ok := spanAttribute(requestSpans[0], resourceTypeAttribute); ok {
+		t.Errorf("expected no resource type attribute when the resource type is unknown")
+	}
+	if requestSpans[0].Status.Code != codes.Error {
+		t.Errorf("expected SendRequest span to have an error status")
+	}
+}
+
+func TestPollingWaitTime_tracing(t *testing.T) {
+	exporter := setUpTracingExporter(t)
+
+	polls := 0
+	pollF := func() (map[string]interface{}, error) {
+		polls++
+		return nil, nil
+	}
+	checkResponse := func(_ map[string]interface{}, _ error) PollResult {
+		if polls < 2 {
+			return PendingStatusPollResult("pending")
+		}
+		return SuccessPollResult()
+	}
+	if err := PollingWaitTime(pollF, checkResponse, "Creating Topic", time.Minute, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := findSpans(exporter.GetSpans(), "PollingWaitTime")
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 PollingWaitTime span, got %d", len(spans))
+	}
+	checkSpanAttributes(t, spans[0], map[attribute.Key]interface{}{
+		activityAttribute: "Creating Topic",
+	})
+}
+
+func TestTraceWait_error(t *testing.T) {
+	exporter := setUpTracingExporter(t)
+
+	waitErr := errors.New("operation failed")
+	ctx, parent := tracer().Start(context.Background(), "Delete")
+	err := TraceWait(ctx, "OperationWait", "Deleting Topic", "operations/123", func() error {
+		return waitErr
+	})
+	parent.End()
+	if err != waitErr {
+		t.Fatalf("expected the error of wait to be returned, got %v", err)
+	}
+
+	spans := findSpans(exporter.GetSpans(), "OperationWait")
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 OperationWait span, got %d", len(spans))
+	}
+	if spans[0].Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected OperationWait span to be a child of the span of its context")
+	}
+	checkSpanAttributes(t, spans[0], map[attribute.Key]interface{}{
+		activityAttribute:  "Deleting Topic",
+		operationAttribute: "operations/123",
+	})
+	if spans[0].Status.Code != codes.Error || spans[0].Status.Description != "operation failed" {
+		t.Errorf("expected span to have the error status %q, got %v", "operation failed", spans[0].Status)
+	}
+}
+
+func TestRequestBatcher_tracing(t *testing.T) {
+	exporter := setUpTracingExporter(t)
+
+	ctx, parent := tracer().Start(context.Background(), "Provider")
+	defer parent.End()
+	testBatcher := NewRequestBatcher(
+		"testBatcher",
+		ctx,
+		&BatchingConfig{
+			SendAfter:      time.Millisecond,
+			EnableBatching: true,
+		})
+	req := &BatchRequest{
+		DebugId:      "Test Tracing",
+		ResourceName: "testTracing",
+		Body:         1,
+		CombineF: func(currV interface{}, toAddV interface{}) (interface{}, error) {
+			return currV.(int) + toAddV.(int), nil
+		},
+		SendF: func(name string, body interface{}) (interface{}, error) {
+			return nil, errors.New("batch failed")
+		},
+	}
+	if _, err := testBatcher.SendRequestWithTimeout("testTracingKey", req, time.Second); err == nil {
+		t.Fatalf("expected error, got none")
+	}
+
+	spans := findSpans(exporter.GetSpans(), "RequestBatcher.flush")
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 RequestBatcher.flush span, got %d", len(spans))
+	}
+	if spans[0].Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("expected RequestBatcher.flush span to be a child of the span of the batcher's context")
+	}
+	checkSpanAttributes(t, spans[0], map[attribute.Key]interface{}{
+		batchKeyAttribute:      "testTracingKey",
+		batchRequestsAttribute: int64(1),
+	})
+	if spans[0].Status.Code != codes.Error {
+		t.Errorf("expected span to have an error status")
+	}
+}
+
+func TestConfigureTracing_file(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := ConfigureTracing(context.Background(), TracingConfig{File: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, span := tracer().Start(context.Background(), "TestSpan")
+	span.End()
+	ShutdownTracing(context.Background())
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(b), `"Name":"TestSpan"`) {
+		t.Errorf("expected trace file to contain the span, got %s", b)
+	}
+}
+
+func TestUrlTemplate(t *testing.T) {
+	cases := map[string]struct {
+		RawURL   string
+		Expected string
+	}{
+		"resource": {
+			RawURL:   "https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic?alt=json",
+			Expected: "https://pubsub.googleapis.com/v1/projects/{projectsId}/topics/{topicsId}",
+		},
+		"collection": {
+			RawURL:   "https://pubsub.googleapis.com/v1/projects/my-project/topics",
+			Expected: "https://pubsub.googleapis.com/v1/projects/{projectsId}/topics",
+		},
+		"custom method": {
+			RawURL:   "https://pubsub.googleapis.com/v1/projects/my-project/topics/my-topic:publish",
+			Expected: "https://pubsub.googleapis.com/v1/projects/{projectsId}/topics/{topicsId}:publish",
+		},
+		"singleton segment": {
+			RawURL:   "https://compute.googleapis.com/compute/v1/projects/my-project/global/networks/my-networks",
+			Expected: "https://compute.googleapis.com/compute/v1/projects/{projectsId}/global/networks/{networksId}",
+		},
+		"resource ID ending in s": {
+			RawURL:   "https://compute.googleapis.com/compute/v1/projects/projects/zones/us-central1-a/instances/my-instances",
+			Expected: "https://compute.googleapis.com/compute/v1/projects/{projectsId}/zones/{zonesId}/instances/{instancesId}",
+		},
+		"invalid URL": {
+			RawURL:   "://",
+			Expected: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if got := urlTemplate(tc.RawURL); got != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -27,14 +28,27 @@ type SendRequestOptions struct {
 	Headers              http.Header
 	ErrorRetryPredicates []RetryErrorPredicateFunc
 	ErrorAbortPredicates []RetryErrorPredicateFunc
+	// ResourceType is the Terraform resource type the request is made for,
+	// recorded on the request's span
+	ResourceType string
+	// Context is the context of the caller, whose span is the parent of the
+	// request's span. context.Background() is used when it's nil.
+	Context context.Context
 	// RPC related opts
 	Product    string
 	RPCService string
 }
 
 func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
+	ctx, span := startRequestSpan("SendRequest", opt, opt.Method, opt.RawURL)
+	result, statusCode, err := sendRequest(ctx, opt)
+	endSpan(span, statusCode, err)
+	return result, err
+}
+
+func sendRequest(ctx context.Context, opt SendRequestOptions) (map[string]interface{}, int, error) {
 	if opt.Config == nil || opt.Config.Client == nil {
-		return nil, fmt.Errorf("client is nil for request to %s", opt.RawURL)
+		return nil, 0, fmt.Errorf("client is nil for request to %s", opt.RawURL)
 	}
 
 	reqHeaders := opt.Headers
@@ -75,7 +89,7 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 			if err != nil {
 				return err
 			}
-			req, err := http.NewRequest(opt.Method, u, &buf)
+			req, err := http.NewRequestWithContext(ctx, opt.Method, u, &buf)
 			if err != nil {
 				return err
 			}
@@ -98,11 +112,11 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 		ErrorAbortPredicates: opt.ErrorAbortPredicates,
 	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if res == nil {
-		return nil, fmt.Errorf("Unable to parse server response. This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.")
+		return nil, 0, fmt.Errorf("Unable to parse server response. This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.")
 	}
 
 	// The defer call must be made outside of the retryFunc otherwise it's closed too soon.
@@ -111,22 +125,33 @@ func SendRequest(opt SendRequestOptions) (map[string]interface{}, error) {
 	// 204 responses will have no body, so we're going to error with "EOF" if we
 	// try to parse it. Instead, we can just return nil.
 	if res.StatusCode == 204 {
-		return nil, nil
+		return nil, res.StatusCode, nil
 	}
 	result := make(map[string]interface{})
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, err
+		return nil, res.StatusCode, err
 	}
 
-	return result, nil
+	return result, res.StatusCode, nil
 }
 
 func SendRequestRPC(opt SendRequestOptions) (map[string]interface{}, error) {
+	var proxyAddress string
+	if opt.Config != nil && opt.Config.RPCClients[opt.Product] != nil {
+		proxyAddress = opt.Config.RPCClients[opt.Product].ProxyAddress
+	}
+	ctx, span := startRequestSpan("SendRequestRPC", opt, "POST", proxyAddress+"/handleRPC")
+	result, statusCode, err := sendRequestRPC(ctx, opt)
+	endSpan(span, statusCode, err)
+	return result, err
+}
+
+func sendRequestRPC(ctx context.Context, opt SendRequestOptions) (map[string]interface{}, int, error) {
 	if opt.Config == nil || opt.Config.Client == nil {
-		return nil, fmt.Errorf("http client is nil for request to rpc proxy")
+		return nil, 0, fmt.Errorf("http client is nil for request to rpc proxy")
 	}
 	if opt.Product == "" || opt.Config.RPCClients[opt.Product] == nil {
-		return nil, fmt.Errorf("rpc client is nil for request to rpc proxy; product is %q", opt.Product)
+		return nil, 0, fmt.Errorf("rpc client is nil for request to rpc proxy; product is %q", opt.Product)
 	}
 
 	reqHeaders := opt.Headers
@@ -168,7 +193,7 @@ func SendRequestRPC(opt SendRequestOptions) (map[string]interface{}, error) {
 				}
 			}
 
-			req, err := http.NewRequest("POST", opt.Config.RPCClients[opt.Product].ProxyAddress+"/handleRPC", &buf)
+			req, err := http.NewRequestWithContext(ctx, "POST", opt.Config.RPCClients[opt.Product].ProxyAddress+"/handleRPC", &buf)
 			if err != nil {
 				return err
 			}
@@ -191,11 +216,11 @@ func SendRequestRPC(opt SendRequestOptions) (map[string]interface{}, error) {
 		ErrorAbortPredicates: opt.ErrorAbortPredicates,
 	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if res == nil {
-		return nil, fmt.Errorf("Unable to parse server response. This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.")
+		return nil, 0, fmt.Errorf("Unable to parse server response. This is most likely a terraform problem, please file a bug at https://github.com/hashicorp/terraform-provider-google/issues.")
 	}
 
 	// The defer call must be made outside of the retryFunc otherwise it's closed too soon.
@@ -204,25 +229,25 @@ func SendRequestRPC(opt SendRequestOptions) (map[string]interface{}, error) {
 	// 204 responses will have no body, so we're going to error with "EOF" if we
 	// try to parse it. Instead, we can just return nil.
 	if res.StatusCode == 204 {
-		return nil, nil
+		return nil, res.StatusCode, nil
 	}
 	var proxyResult map[string]interface{}
 	if err := json.NewDecoder(res.Body).Decode(&proxyResult); err != nil {
-		return nil, err
+		return nil, res.StatusCode, err
 	}
 
 	if errMsg, ok := proxyResult["errorMessage"].(string); ok && errMsg != "" {
-		return nil, fmt.Errorf("RPC proxy returned error: %s", errMsg)
+		return nil, res.StatusCode, fmt.Errorf("RPC proxy returned error: %s", errMsg)
 	}
 
 	var result map[string]interface{}
 	if respJsonStr, ok := proxyResult["responseJson"].(string); ok && respJsonStr != "" {
 		if err := json.Unmarshal([]byte(respJsonStr), &result); err != nil {
-			return nil, fmt.Errorf("Failed to unmarshal responseJson from proxy: %v", err)
+			return nil, res.StatusCode, fmt.Errorf("Failed to unmarshal responseJson from proxy: %v", err)
 		}
 	}
 
-	return result, nil
+	return result, res.StatusCode, nil
 }
 
 func AddQueryParams(rawurl string, params map[string]string) (string, error) {
@@ -20,6 +20,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -37,18 +40,27 @@ into Terraform HCL (HashiCorp Configuration Language) native syntax.
 Note:
   Only supported resources will be converted. Non supported resources are
   omitted from results.
+  References between the converted resources, such as the self-link of a
+  network, are written as references to the resources, and an import block
+  is written for each resource so that it can be imported into the state.
+
+  With --output-dir, the resources are written to files laid out as
+  <ancestor path>/<project>/<product>.tf under the directory, along with a
+  provider.tf and variables.tf file for each directory.
 
 Example:
 tgc cai2hcl convert ./example/caiassets.json
+tgc cai2hcl convert ./example/caiassets.json --output-dir ./export
 `
 
 type convertOptions struct {
 	rootOptions *common.RootOptions
 	outputPath  string
+	outputDir   string
 	dryRun      bool
 }
 
-var origConvertFunc = func(ctx context.Context, path string, errorLogger *zap.Logger) ([]byte, error) {
+func readAssets(path string) ([]caiasset.Asset, error) {
 	assetPayload, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file %s: %s", path, err)
@@ -58,6 +70,14 @@ var origConvertFunc = func(ctx context.Context, path string, errorLogger *zap.Lo
 	if err := json.Unmarshal(assetPayload, &assets); err != nil {
 		return nil, err
 	}
+	return assets, nil
+}
+
+var origConvertFunc = func(ctx context.Context, path string, errorLogger *zap.Logger) ([]byte, error) {
+	assets, err := readAssets(path)
+	if err != nil {
+		return nil, err
+	}
 
 	return cai2hcl.Convert(assets, &cai2hcl.Options{
 		ErrorLogger: errorLogger,
@@ -66,6 +86,19 @@ var origConvertFunc = func(ctx context.Context, path string, errorLogger *zap.Lo
 
 var convertFunc = origConvertFunc
 
+var origConvertToFilesFunc = func(ctx context.Context, path string, errorLogger *zap.Logger) (map[string][]byte, error) {
+	assets, err := readAssets(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return cai2hcl.ConvertToFiles(assets, &cai2hcl.Options{
+		ErrorLogger: errorLogger,
+	})
+}
+
+var convertToFilesFunc = origConvertToFilesFunc
+
 func newConvertCmd(rootOptions *common.RootOptions) *cobra.Command {
 	o := &convertOptions{
 		rootOptions: rootOptions,
@@ -88,6 +121,8 @@ func newConvertCmd(rootOptions *common.RootOptions) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&o.outputPath, "output-path", "", "If specified, write the convert result into the specified output file")
+	cmd.Flags().StringVar(&o.outputDir, "output-dir", "", "If specified, write the convert result into files under the specified directory, grouped by project and product")
+	cmd.MarkFlagsMutuallyExclusive("output-path", "output-dir")
 	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only parse & validate args")
 	cmd.Flags().MarkHidden("dry-run")
 
@@ -104,6 +139,10 @@ func (o *convertOptions) validateArgs(args []string) error {
 func (o *convertOptions) run(path string) error {
 	ctx := context.Background()
 
+	if len(o.outputDir) > 0 {
+		return o.runToFiles(ctx, path)
+	}
+
 	hclBlocks, err := convertFunc(ctx, path, o.rootOptions.ErrorLogger)
 	if err != nil {
 		return err
@@ -137,3 +176,55 @@ func (o *convertOptions) run(path string) error {
 
 	return nil
 }
+
+// runToFiles writes the files converted from the assets at path under the
+// output directory. Nothing is written if any of the files already exists.
+func (o *convertOptions) runToFiles(ctx context.Context, path string) error {
+	files, err := convertToFilesFunc(ctx, path, o.rootOptions.ErrorLogger)
+	if err != nil {
+		return err
+	}
+
+	var existing []string
+	for name := range files {
+		filePath := filepath.Join(o.outputDir, filepath.FromSlash(name))
+		if _, err := os.Lstat(filePath); err == nil {
+			existing = append(existing, filePath)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if len(existing) > 0 {
+		sort.Strings(existing)
+		return fmt.Errorf("files already exist in the output directory: %s", strings.Join(existing, ", "))
+	}
+
+	for name, content := range files {
+		filePath := filepath.Join(o.outputDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(content)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.rootOptions.UseStructuredLogging {
+		o.rootOptions.OutputLogger.Info(
+			"converted resources",
+			zap.String("output_dir", o.outputDir),
+			zap.Int("file_count", len(files)),
+		)
+	}
+
+	return nil
+}
@@ -145,3 +145,91 @@ func TestConvertRunOutputFile(t *testing.T) {
 	expectedHCLBlocks := testHCLBlocks()
 	a.Equal(expectedHCLBlocks, b)
 }
+
+func mockConvertHCLToFiles(ctx context.Context, path string, errorLogger *zap.Logger) (map[string][]byte, error) {
+	return map[string][]byte{
+		"organizations/123/myproj/compute.tf": testHCLBlocks(),
+		"organizations/123/myproj/provider.tf": []byte(`provider "google" {
+  project = var.project
+}
+`),
+	}, nil
+}
+
+func TestConvertRunOutputDir(t *testing.T) {
+	convertToFilesFunc = mockConvertHCLToFiles
+	defer func() {
+		convertToFilesFunc = origConvertToFilesFunc
+	}()
+	a := assert.New(t)
+	verbosity := "debug"
+	useStructuredLogging := false
+	errorLogger, errorBuf := common.NewTestErrorLogger(verbosity, useStructuredLogging)
+	outputLogger, outputBuf := common.NewTestOutputLogger()
+	ro := &common.RootOptions{
+		Verbosity:            verbosity,
+		UseStructuredLogging: useStructuredLogging,
+		ErrorLogger:          errorLogger,
+		OutputLogger:         outputLogger,
+	}
+	outputDir := t.TempDir()
+	o := convertOptions{
+		rootOptions: ro,
+		outputDir:   outputDir,
+	}
+
+	err := o.run("/path/to/cai_assets")
+	a.Nil(err)
+
+	a.Equal(errorBuf.String(), "")
+	a.Equal(outputBuf.String(), "")
+
+	expectedFiles, _ := mockConvertHCLToFiles(context.Background(), "", nil)
+	for name, expected := range expectedFiles {
+		b, err := os.ReadFile(path.Join(outputDir, name))
+		if err != nil {
+			a.Failf("Unable to read file %s: %s", name, err)
+		}
+		a.Equal(expected, b)
+	}
+
+	// Files of a previous conversion are not overwritten.
+	err = o.run("/path/to/cai_assets")
+	a.NotNil(err)
+}
+
+func TestConvertRunOutputDirExistingFile(t *testing.T) {
+	convertToFilesFunc = mockConvertHCLToFiles
+	defer func() {
+		convertToFilesFunc = origConvertToFilesFunc
+	}()
+	a := assert.New(t)
+	errorLogger, _ := common.NewTestErrorLogger("debug", false)
+	outputLogger, _ := common.NewTestOutputLogger()
+	outputDir := t.TempDir()
+	o := convertOptions{
+		rootOptions: &common.RootOptions{
+			Verbosity:    "debug",
+			ErrorLogger:  errorLogger,
+			OutputLogger: outputLogger,
+		},
+		outputDir: outputDir,
+	}
+
+	existing := path.Join(outputDir, "organizations/123/myproj/provider.tf")
+	if err := os.MkdirAll(path.Dir(existing), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(existing, []byte("# existing\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := o.run("/path/to/cai_assets")
+	a.ErrorContains(err, existing)
+
+	// Nothing is written when any of the files exists.
+	_, err = os.Stat(path.Join(outputDir, "organizations/123/myproj/compute.tf"))
+	a.True(os.IsNotExist(err))
+	b, _ := os.ReadFile(existing)
+	a.Equal("# existing\n", string(b))
+}
@@ -33,6 +33,13 @@ const convertDesc = `
 This command will convert a Terraform plan json file into Cloud Asset Inventory(CAI) assets
 and output them as a JSON array.
 
+With --changes, it will instead output the changes planned for each resource
+as a JSON array. Each change has the resource address, the action (create,
+update, delete or replace), the assets converted from the prior state of the
+resource ("before"), the assets converted from its planned state ("after") and
+the attributes whose values are unknown until the change is applied
+("after_unknown").
+
 Note:
   Only supported resources will be converted. Non supported resources are
   omitted from results.
@@ -42,6 +49,8 @@ Note:
 Example:
 tgc tfplan2cai convert ./example/terraform.tfplan --project my-project \
     --ancestry organization/my-org/folder/my-folder
+
+tgc tfplan2cai convert ./example/terraform.tfplan --changes
 `
 
 func multiEnvSearch(ks []string) string {
@@ -59,6 +68,7 @@ type convertOptions struct {
 	offline     bool
 	rootOptions *common.RootOptions
 	outputPath  string
+	changes     bool
 	dryRun      bool
 }
 
@@ -81,6 +91,25 @@ var origConvertFunc = func(ctx context.Context, path, project, zone, region stri
 
 var convertFunc = origConvertFunc
 
+var origConvertChangesFunc = func(ctx context.Context, path, project, zone, region string, ancestry map[string]string, offline bool, errorLogger *zap.Logger, userAgent string) ([]caiasset.AssetChange, error) {
+	jsonPlan, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %s", path, err)
+	}
+
+	return tfplan2cai.ConvertAssetChanges(ctx, jsonPlan, &tfplan2cai.Options{
+		ErrorLogger:    errorLogger,
+		Offline:        offline,
+		DefaultProject: project,
+		DefaultRegion:  region,
+		DefaultZone:    zone,
+		UserAgent:      userAgent,
+		AncestryCache:  ancestry,
+	})
+}
+
+var convertChangesFunc = origConvertChangesFunc
+
 func newConvertCmd(rootOptions *common.RootOptions) *cobra.Command {
 	o := &convertOptions{
 		rootOptions: rootOptions,
@@ -105,6 +134,7 @@ func newConvertCmd(rootOptions *common.RootOptions) *cobra.Command {
 	cmd.Flags().StringVar(&o.ancestry, "ancestry", "", "Override the ancestry location of the project when validating resources")
 	cmd.Flags().BoolVar(&o.offline, "offline", false, "Do not make network requests")
 	cmd.Flags().StringVar(&o.outputPath, "output-path", "", "If specified, write the convert result into the specified output file")
+	cmd.Flags().BoolVar(&o.changes, "changes", false, "Output the assets before and after the change planned for each resource, instead of the planned assets")
 	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only parse & validate args")
 	cmd.Flags().MarkHidden("dry-run")
 
@@ -139,9 +169,19 @@ func (o *convertOptions) run(plan string) error {
 		"CLOUDSDK_COMPUTE_REGION",
 	})
 	userAgent := "tfplan2cai"
-	assets, err := convertFunc(ctx, plan, o.project, zone, region, ancestryCache, o.offline, o.rootOptions.ErrorLogger, userAgent)
-	if err != nil {
-		return err
+	var result interface{}
+	if o.changes {
+		changes, err := convertChangesFunc(ctx, plan, o.project, zone, region, ancestryCache, o.offline, o.rootOptions.ErrorLogger, userAgent)
+		if err != nil {
+			return err
+		}
+		result = changes
+	} else {
+		assets, err := convertFunc(ctx, plan, o.project, zone, region, ancestryCache, o.offline, o.rootOptions.ErrorLogger, userAgent)
+		if err != nil {
+			return err
+		}
+		result = assets
 	}
 
 	if len(o.outputPath) > 0 {
@@ -151,7 +191,7 @@ func (o *convertOptions) run(plan string) error {
 		}
 		defer f.Close()
 
-		if err := json.NewEncoder(f).Encode(assets); err != nil {
+		if err := json.NewEncoder(f).Encode(result); err != nil {
 			return fmt.Errorf("encoding json: %w", err)
 		}
 		return nil
@@ -160,13 +200,13 @@ func (o *convertOptions) run(plan string) error {
 	if o.rootOptions.UseStructuredLogging {
 		o.rootOptions.OutputLogger.Info(
 			"converted resources",
-			zap.Any("resource_body", assets),
+			zap.Any("resource_body", result),
 		)
 		return nil
 	}
 
 	// Legacy behavior
-	if err := json.NewEncoder(os.Stdout).Encode(assets); err != nil {
+	if err := json.NewEncoder(os.Stdout).Encode(result); err != nil {
 		return fmt.Errorf("encoding json: %w", err)
 	}
 	return nil
@@ -359,3 +359,74 @@ func TestConvertRun_passesCorrectArguments(t *testing.T) {
 		})
 	}
 }
+
+func testAssetChanges(path string) []caiasset.AssetChange {
+	assets := testAssets(path, "", "", "", map[string]string{}, false, nil, "tfplan2cai")
+	return []caiasset.AssetChange{
+		{
+			Address:      "google_compute_disk.test-disk",
+			Action:       caiasset.ActionReplace,
+			Before:       assets,
+			After:        assets,
+			AfterUnknown: []string{"id"},
+		},
+		{
+			Address: "google_compute_disk.deleted-disk",
+			Action:  caiasset.ActionDelete,
+			Before:  assets,
+		},
+	}
+}
+
+func mockConvertAssetChanges(ctx context.Context, path, project, zone, region string, ancestry map[string]string, offline bool, errorLogger *zap.Logger, userAgent string) ([]caiasset.AssetChange, error) {
+	return testAssetChanges(path), nil
+}
+
+func TestConvertRunChanges(t *testing.T) {
+	convertFunc = func(context.Context, string, string, string, string, map[string]string, bool, *zap.Logger, string) ([]caiasset.Asset, error) {
+		t.Fatal("convertFunc shouldn't be called with --changes")
+		return nil, nil
+	}
+	convertChangesFunc = mockConvertAssetChanges
+	defer func() {
+		convertFunc = origConvertFunc
+		convertChangesFunc = origConvertChangesFunc
+	}()
+
+	a := assert.New(t)
+	verbosity := "debug"
+	useStructuredLogging := true
+	errorLogger, errorBuf := common.NewTestErrorLogger(verbosity, useStructuredLogging)
+	outputLogger, outputBuf := common.NewTestOutputLogger()
+	ro := &common.RootOptions{
+		Verbosity:            verbosity,
+		UseStructuredLogging: useStructuredLogging,
+		ErrorLogger:          errorLogger,
+		OutputLogger:         outputLogger,
+	}
+	o := convertOptions{
+		changes:     true,
+		rootOptions: ro,
+	}
+
+	path := "/path/to/plan"
+	err := o.run(path)
+	a.Nil(err)
+
+	errorJSON := errorBuf.String()
+	outputJSON := outputBuf.Bytes()
+
+	a.Equal(errorJSON, "")
+
+	var output map[string]interface{}
+	json.Unmarshal(outputJSON, &output)
+
+	// On a successful run, we should see a list of asset changes in the resource_body field
+	a.Contains(output, "resource_body")
+	a.Len(output["resource_body"], 2)
+
+	var expectedChanges []interface{}
+	expectedChangesJSON, _ := json.Marshal(testAssetChanges(path))
+	json.Unmarshal(expectedChangesJSON, &expectedChanges)
+	a.Equal(expectedChanges, output["resource_body"])
+}
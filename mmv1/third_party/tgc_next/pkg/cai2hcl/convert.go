@@ -1,7 +1,6 @@
 package cai2hcl
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
@@ -18,27 +17,55 @@ type Options struct {
 }
 
 // Converts CAI Assets into HCL string.
+//
+// Values referring to other converted resources, such as the self-link of a
+// network, are replaced by references to those resources. Unless the
+// resources are new, an import block is written for each resource.
 func Convert(assets []caiasset.Asset, options *Options) ([]byte, error) {
 	if options == nil || options.ErrorLogger == nil {
 		return nil, fmt.Errorf("logger is not initialized")
 	}
 
+	var allBlocks []*models.TerraformResourceBlock
+	r := newResolver()
+	err := convertAssets(assets, options, func(asset caiasset.Asset, blocks []*models.TerraformResourceBlock) {
+		r.add(asset, blocks[0])
+		allBlocks = append(allBlocks, blocks...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueLabels(allBlocks)
+	return models.HclWriteBlocksWithOptions(allBlocks, &models.HclWriteOptions{
+		ImportBlocks:     !options.AreNewResources,
+		ResolveReference: r.resolve,
+	})
+}
+
+// convertAssets converts each of assets, and calls add with the resource
+// blocks it is converted to, if any. Other resources refer to an asset as the
+// first block it is converted to.
+func convertAssets(assets []caiasset.Asset, options *Options, add func(asset caiasset.Asset, blocks []*models.TerraformResourceBlock)) error {
 	converterOptions := &models.ResourceConverterOptions{
 		AreNewResources: options.AreNewResources,
 	}
 
-	var allResourceBytes [][]byte
-	// TODO: add resolvers to resolve the assets into single resource assets
 	for _, asset := range assets {
-		resourceBytes, err := converters.ConvertResource([]caiasset.Asset{asset}, converterOptions)
+		blocks, err := converters.ConvertResourceBlocks([]caiasset.Asset{asset}, converterOptions)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		if resourceBytes != nil {
-			allResourceBytes = append(allResourceBytes, resourceBytes)
+		var converted []*models.TerraformResourceBlock
+		for _, block := range blocks {
+			if block != nil {
+				converted = append(converted, block)
+			}
+		}
+		if len(converted) > 0 {
+			add(asset, converted)
 		}
 	}
-
-	return bytes.Join(allResourceBytes, []byte("\n")), nil
+	return nil
 }
@@ -1,11 +1,13 @@
 package cai2hcl
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"go.uber.org/zap/zaptest"
 )
 
 func TestConvertWithResourceName(t *testing.T) {
@@ -77,3 +79,91 @@ func TestConvertWithoutResourceName(t *testing.T) {
 		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(got))
 	}
 }
+
+func TestConvertResolvesReferences(t *testing.T) {
+	assets := []caiasset.Asset{
+		{
+			Name: "//compute.googleapis.com/projects/my-project/global/networks/my-network",
+			Type: "compute.googleapis.com/Network",
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+				DiscoveryName:        "Network",
+				Data: map[string]interface{}{
+					"name":                  "my-network",
+					"autoCreateSubnetworks": false,
+					"selfLink":              "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-network",
+				},
+			},
+		},
+		{
+			Name: "//compute.googleapis.com/projects/my-project/regions/us-central1/subnetworks/my-subnetwork",
+			Type: "compute.googleapis.com/Subnetwork",
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+				DiscoveryName:        "Subnetwork",
+				Data: map[string]interface{}{
+					"name":        "my-subnetwork",
+					"ipCidrRange": "10.2.0.0/16",
+					"network":     "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/my-network",
+					"region":      "https://www.googleapis.com/compute/v1/projects/my-project/regions/us-central1",
+				},
+			},
+		},
+	}
+
+	got, err := Convert(assets, &Options{
+		ErrorLogger: zaptest.NewLogger(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"import {\n  to = google_compute_network.my-network\n  id = \"projects/my-project/global/networks/my-network\"\n}",
+		"import {\n  to = google_compute_subnetwork.my-subnetwork\n  id = \"projects/my-project/regions/us-central1/subnetworks/my-subnetwork\"\n}",
+		"= google_compute_network.my-network.id\n",
+	} {
+		if !strings.Contains(string(got), expected) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", expected, string(got))
+		}
+	}
+}
+
+func TestConvertNewResources(t *testing.T) {
+	assets := []caiasset.Asset{
+		{
+			Name: "//cloudresourcemanager.googleapis.com/projects/example-project",
+			Type: "cloudresourcemanager.googleapis.com/Project",
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/compute/v1/rest",
+				DiscoveryName:        "Project",
+				Parent:               "//cloudresourcemanager.googleapis.com/folders/456",
+				Data: map[string]interface{}{
+					"name":      "My Project",
+					"projectId": "example-project",
+				},
+			},
+		},
+	}
+
+	got, err := Convert(assets, &Options{
+		ErrorLogger:     zaptest.NewLogger(t),
+		AreNewResources: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `resource "google_project" "example-project" {
+  folder_id  = "456"
+  name       = "My Project"
+  project_id = "example-project"
+}
+`
+	if string(got) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(got))
+	}
+}
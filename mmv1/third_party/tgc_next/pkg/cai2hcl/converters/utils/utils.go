@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"regexp"
 	"strings"
 
 	hashicorpcty "github.com/hashicorp/go-cty/cty"
@@ -88,6 +89,40 @@ func getEndAssetIx(endTemplateIx int, templateFragments []string, assetFragments
 	return len(assetFragments)
 }
 
+// Matches a parameter of an import format, such as {{project}} or {{%name}}.
+var importFormatParamRegex = regexp.MustCompile(`\{\{%?(\w+)\}\}`)
+
+/*
+	ImportIdFromAssetName builds the ID that a resource is imported with from importFormat.
+	The values of its parameters are parsed from assetName using template, else taken from hclData.
+	It returns an empty string if any parameter has no value.
+
+template: //compute.googleapis.com/projects/{{project}}/global/networks/{{name}}
+assetName: //compute.googleapis.com/projects/my-project/global/networks/my-network
+importFormat: projects/{{project}}/global/networks/{{name}}
+importId: projects/my-project/global/networks/my-network
+*/
+func ImportIdFromAssetName(assetName, template, importFormat string, hclData map[string]any) string {
+	params := make(map[string]any)
+	ParseUrlParamValuesFromAssetName(assetName, template, nil, params)
+
+	missing := false
+	importId := importFormatParamRegex.ReplaceAllStringFunc(importFormat, func(param string) string {
+		name := importFormatParamRegex.FindStringSubmatch(param)[1]
+		for _, values := range []map[string]any{params, hclData} {
+			if v, ok := values[name].(string); ok && v != "" {
+				return v
+			}
+		}
+		missing = true
+		return ""
+	})
+	if missing {
+		return ""
+	}
+	return importId
+}
+
 // DecodeJSON decodes the map object into the target struct.
 func DecodeJSON(data map[string]interface{}, v interface{}) error {
 	b, err := json.Marshal(data)
@@ -268,3 +268,55 @@ func TestParseUrlParamValuesFromAssetName(t *testing.T) {
 		})
 	}
 }
+
+func TestImportIdFromAssetName(t *testing.T) {
+	testCases := []struct {
+		name         string
+		template     string
+		assetName    string
+		importFormat string
+		hclData      map[string]any
+		want         string
+	}{
+		{
+			name:         "ComputeNetwork",
+			template:     "//compute.googleapis.com/projects/{{project}}/global/networks/{{name}}",
+			assetName:    "//compute.googleapis.com/projects/my-project/global/networks/my-network",
+			importFormat: "projects/{{project}}/global/networks/{{name}}",
+			hclData:      map[string]any{},
+			want:         "projects/my-project/global/networks/my-network",
+		},
+		{
+			name:         "MultiFragmentParam",
+			template:     "//alloydb.googleapis.com/{{cluster}}/instances/{{instance_id}}",
+			assetName:    "//alloydb.googleapis.com/projects/ci-test/locations/us-central1/clusters/tf-test-cluster/instances/tf-test-instance",
+			importFormat: "{{cluster}}/instances/{{instance_id}}",
+			hclData:      map[string]any{},
+			want:         "projects/ci-test/locations/us-central1/clusters/tf-test-cluster/instances/tf-test-instance",
+		},
+		{
+			name:         "ParamFromHclData",
+			template:     "//pubsub.googleapis.com/projects/{{project}}/topics/{{name}}",
+			assetName:    "//pubsub.googleapis.com/projects/my-project/topics/my-topic",
+			importFormat: "projects/{{project}}/topics/{{name}}/{{%kind}}",
+			hclData:      map[string]any{"kind": "a/b"},
+			want:         "projects/my-project/topics/my-topic/a/b",
+		},
+		{
+			name:         "MissingParam",
+			template:     "//pubsub.googleapis.com/projects/{{project}}/topics/{{name}}",
+			assetName:    "//pubsub.googleapis.com/projects/my-project/topics/my-topic",
+			importFormat: "projects/{{project}}/locations/{{location}}/topics/{{name}}",
+			hclData:      map[string]any{"location": nil},
+			want:         "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := utils.ImportIdFromAssetName(tc.assetName, tc.template, tc.importFormat, tc.hclData); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package cai2hcl
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const projectAssetType = "cloudresourcemanager.googleapis.com/Project"
+
+// The variable that the ID of the project of a directory is replaced with.
+var projectVariable = hcl.Traversal{
+	hcl.TraverseRoot{Name: "var"},
+	hcl.TraverseAttr{Name: "project"},
+}
+
+// directory is a Terraform root module that the resources of a project, or
+// of a folder or organization outside of any project, are written to.
+type directory struct {
+	// project is the ID of the project of the resources, if any.
+	project string
+	// blocks are the resources of the directory. Resources can't refer to
+	// resources of other directories, which are separate root modules.
+	blocks map[*models.TerraformResourceBlock]bool
+	// blocksByProduct groups the resources by the product of their assets,
+	// such as "compute".
+	blocksByProduct map[string][]*models.TerraformResourceBlock
+}
+
+// resolveReference replaces values referring to resources of the directory
+// by references to them. r resolves the references across all directories,
+// so that a value identifying resources of several directories is ambiguous
+// rather than resolved to the resource of the directory.
+func (d *directory) resolveReference(r *resolver, block *models.TerraformResourceBlock, value string) hcl.Traversal {
+	target := r.lookup(block, value)
+	if target == nil || !d.blocks[target.block] {
+		return nil
+	}
+	return target.traversal()
+}
+
+// Converts CAI Assets into HCL files, keyed by their paths. The resources are
+// laid out as <ancestor path>/<project>/<product>.tf using the ancestors of
+// their assets, such as organizations/123/folders/456/my-project/compute.tf.
+// Resources outside of any project are written to <ancestor path>/<product>.tf.
+//
+// Each directory is a Terraform root module with a provider.tf file, and a
+// variables.tf file declaring the ID of its project. Resources are named
+// uniquely across all directories, regardless of the order of assets.
+func ConvertToFiles(assets []caiasset.Asset, options *Options) (map[string][]byte, error) {
+	if options == nil || options.ErrorLogger == nil {
+		return nil, fmt.Errorf("logger is not initialized")
+	}
+
+	sortedAssets := make([]caiasset.Asset, len(assets))
+	copy(sortedAssets, assets)
+	sort.SliceStable(sortedAssets, func(i, j int) bool {
+		return sortedAssets[i].Name < sortedAssets[j].Name
+	})
+
+	projectIds := projectIdsByNumber(assets)
+	r := newResolver()
+	directories := make(map[string]*directory)
+	var allBlocks []*models.TerraformResourceBlock
+	err := convertAssets(sortedAssets, options, func(asset caiasset.Asset, blocks []*models.TerraformResourceBlock) {
+		dirPath, project := assetDirectory(asset, projectIds)
+		dir, ok := directories[dirPath]
+		if !ok {
+			dir = &directory{
+				project:         project,
+				blocks:          make(map[*models.TerraformResourceBlock]bool),
+				blocksByProduct: make(map[string][]*models.TerraformResourceBlock),
+			}
+			directories[dirPath] = dir
+		}
+
+		r.add(asset, blocks[0])
+		for _, block := range blocks {
+			dir.blocks[block] = true
+		}
+		product := assetProduct(asset)
+		dir.blocksByProduct[product] = append(dir.blocksByProduct[product], blocks...)
+		allBlocks = append(allBlocks, blocks...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueLabels(allBlocks)
+
+	files := make(map[string][]byte)
+	for dirPath, dir := range directories {
+		writeOptions := &models.HclWriteOptions{
+			ImportBlocks: !options.AreNewResources,
+			ResolveReference: func(block *models.TerraformResourceBlock, value string) hcl.Traversal {
+				return dir.resolveReference(r, block, value)
+			},
+		}
+		if dir.project != "" {
+			writeOptions.Project = &models.ProjectVariable{
+				Id:       dir.project,
+				Variable: projectVariable,
+			}
+		}
+		for product, blocks := range dir.blocksByProduct {
+			b, err := models.HclWriteBlocksWithOptions(blocks, writeOptions)
+			if err != nil {
+				return nil, err
+			}
+			files[path.Join(dirPath, product+".tf")] = b
+		}
+
+		files[path.Join(dirPath, "provider.tf")] = providerFile(dir.project)
+		if dir.project != "" {
+			files[path.Join(dirPath, "variables.tf")] = variablesFile(dir.project)
+		}
+	}
+	return files, nil
+}
+
+// projectIdsByNumber returns the IDs of the projects of assets, keyed by
+// their numbers. Ancestors refer to projects by number, while the names of
+// most assets refer to them by ID.
+func projectIdsByNumber(assets []caiasset.Asset) map[string]string {
+	projectIds := make(map[string]string)
+	for _, asset := range assets {
+		if asset.Type != projectAssetType || asset.Resource == nil || len(asset.Ancestors) == 0 {
+			continue
+		}
+		number, ok := strings.CutPrefix(asset.Ancestors[0], "projects/")
+		projectId, _ := asset.Resource.Data["projectId"].(string)
+		if ok && projectId != "" {
+			projectIds[number] = projectId
+		}
+	}
+	return projectIds
+}
+
+// assetDirectory returns the path of the directory that the resources
+// converted from asset are written to, and the ID of the project of asset,
+// if any.
+func assetDirectory(asset caiasset.Asset, projectIds map[string]string) (string, string) {
+	var segments []string
+	var projectNumber string
+	for i, ancestor := range asset.Ancestors {
+		if number, ok := strings.CutPrefix(ancestor, "projects/"); ok {
+			if i == 0 {
+				projectNumber = number
+			}
+			continue
+		}
+		// Ancestors are ordered from the closest one to the organization.
+		segments = append(strings.Split(ancestor, "/"), segments...)
+	}
+
+	project := assetProjectId(asset, projectNumber, projectIds)
+	if project != "" {
+		segments = append(segments, project)
+	}
+	return path.Join(segments...), project
+}
+
+// assetProjectId returns the ID of the project of asset, if any. projectNumber
+// is the number of the project in the ancestors of asset.
+func assetProjectId(asset caiasset.Asset, projectNumber string, projectIds map[string]string) string {
+	if asset.Type == projectAssetType && asset.Resource != nil {
+		if projectId, ok := asset.Resource.Data["projectId"].(string); ok && projectId != "" {
+			return projectId
+		}
+	}
+
+	project := projectNumber
+	segments := strings.Split(asset.Name, "/")
+	for i, segment := range segments {
+		if segment == "projects" && i+1 < len(segments) && segments[i+1] != "" {
+			project = segments[i+1]
+			break
+		}
+	}
+	if projectId, ok := projectIds[project]; ok {
+		return projectId
+	}
+	return project
+}
+
+// assetProduct returns the product of asset, such as "compute" for
+// compute.googleapis.com/Instance assets.
+func assetProduct(asset caiasset.Asset) string {
+	product, _, _ := strings.Cut(asset.Type, ".")
+	if product == "" {
+		return "main"
+	}
+	return product
+}
+
+// providerFile returns the content of the provider.tf file of a directory.
+func providerFile(project string) []byte {
+	f := hclwrite.NewFile()
+	rootBody := f.Body()
+
+	terraformBlock := rootBody.AppendNewBlock("terraform", nil)
+	requiredProviders := terraformBlock.Body().AppendNewBlock("required_providers", nil)
+	requiredProviders.Body().SetAttributeValue("google", cty.ObjectVal(map[string]cty.Value{
+		"source": cty.StringVal("hashicorp/google"),
+	}))
+
+	rootBody.AppendNewline()
+	providerBlock := rootBody.AppendNewBlock("provider", []string{"google"})
+	if project != "" {
+		providerBlock.Body().SetAttributeTraversal("project", projectVariable)
+	}
+
+	return hclwrite.Format(f.Bytes())
+}
+
+// variablesFile returns the content of the variables.tf file of a directory
+// of the project.
+func variablesFile(project string) []byte {
+	f := hclwrite.NewFile()
+
+	variableBlock := f.Body().AppendNewBlock("variable", []string{"project"})
+	variableBlock.Body().SetAttributeValue("description", cty.StringVal("The ID of the project that the resources are in."))
+	variableBlock.Body().SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+	variableBlock.Body().SetAttributeValue("default", cty.StringVal(project))
+
+	return hclwrite.Format(f.Bytes())
+}
@@ -0,0 +1,269 @@
+package cai2hcl
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAssetDirectory(t *testing.T) {
+	projectIds := map[string]string{"123": "my-project"}
+
+	cases := map[string]struct {
+		Asset           caiasset.Asset
+		ExpectedPath    string
+		ExpectedProject string
+	}{
+		"project ID in name": {
+			Asset: caiasset.Asset{
+				Name:      "//compute.googleapis.com/projects/my-project/global/networks/default",
+				Type:      "compute.googleapis.com/Network",
+				Ancestors: []string{"projects/123", "folders/456", "organizations/789"},
+			},
+			ExpectedPath:    "organizations/789/folders/456/my-project",
+			ExpectedProject: "my-project",
+		},
+		"project number in name": {
+			Asset: caiasset.Asset{
+				Name:      "//pubsub.googleapis.com/projects/123/topics/my-topic",
+				Type:      "pubsub.googleapis.com/Topic",
+				Ancestors: []string{"projects/123", "organizations/789"},
+			},
+			ExpectedPath:    "organizations/789/my-project",
+			ExpectedProject: "my-project",
+		},
+		"unknown project number": {
+			Asset: caiasset.Asset{
+				Name:      "//storage.googleapis.com/my-bucket",
+				Type:      "storage.googleapis.com/Bucket",
+				Ancestors: []string{"projects/456", "organizations/789"},
+			},
+			ExpectedPath:    "organizations/789/456",
+			ExpectedProject: "456",
+		},
+		"project": {
+			Asset: caiasset.Asset{
+				Name:      "//cloudresourcemanager.googleapis.com/projects/123",
+				Type:      projectAssetType,
+				Ancestors: []string{"projects/123", "organizations/789"},
+				Resource: &caiasset.AssetResource{
+					Data: map[string]interface{}{"projectId": "my-project"},
+				},
+			},
+			ExpectedPath:    "organizations/789/my-project",
+			ExpectedProject: "my-project",
+		},
+		"folder": {
+			Asset: caiasset.Asset{
+				Name:      "//cloudresourcemanager.googleapis.com/folders/456",
+				Type:      "cloudresourcemanager.googleapis.com/Folder",
+				Ancestors: []string{"folders/456", "organizations/789"},
+			},
+			ExpectedPath: "organizations/789/folders/456",
+		},
+		"no ancestors": {
+			Asset: caiasset.Asset{
+				Name: "//compute.googleapis.com/projects/my-project/global/networks/default",
+				Type: "compute.googleapis.com/Network",
+			},
+			ExpectedPath:    "my-project",
+			ExpectedProject: "my-project",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			gotPath, gotProject := assetDirectory(tc.Asset, projectIds)
+			if gotPath != tc.ExpectedPath {
+				t.Errorf("expected path %q, got %q", tc.ExpectedPath, gotPath)
+			}
+			if gotProject != tc.ExpectedProject {
+				t.Errorf("expected project %q, got %q", tc.ExpectedProject, gotProject)
+			}
+		})
+	}
+}
+
+func TestConvertToFiles(t *testing.T) {
+	projectAsset := func(number, projectId string) caiasset.Asset {
+		return caiasset.Asset{
+			Name:      "//cloudresourcemanager.googleapis.com/projects/" + number,
+			Type:      projectAssetType,
+			Ancestors: []string{"projects/" + number, "folders/456", "organizations/789"},
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/cloudresourcemanager/v1/rest",
+				DiscoveryName:        "Project",
+				Parent:               "//cloudresourcemanager.googleapis.com/folders/456",
+				Data: map[string]interface{}{
+					"name":      "My Project",
+					"projectId": projectId,
+				},
+			},
+		}
+	}
+	// The project IDs are the same as the name of the other project, so
+	// that the names of their resources collide.
+	assets := []caiasset.Asset{
+		projectAsset("2", "my-project"),
+		projectAsset("1", "my-project"),
+	}
+	assets[1].Ancestors = []string{"projects/1", "organizations/789"}
+	assets[1].Resource.Parent = "//cloudresourcemanager.googleapis.com/organizations/789"
+
+	got, err := ConvertToFiles(assets, &Options{
+		ErrorLogger: zaptest.NewLogger(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := `terraform {
+  required_providers {
+    google = {
+      source = "hashicorp/google"
+    }
+  }
+}
+
+provider "google" {
+  project = var.project
+}
+`
+	variables := `variable "project" {
+  description = "The ID of the project that the resources are in."
+  type        = string
+  default     = "my-project"
+}
+`
+	expected := map[string]string{
+		"organizations/789/my-project/cloudresourcemanager.tf": `import {
+  to = google_project.my-project
+  id = "${var.project}"
+}
+resource "google_project" "my-project" {
+  name       = "My Project"
+  org_id     = "789"
+  project_id = "my-project"
+}
+`,
+		"organizations/789/my-project/provider.tf":  provider,
+		"organizations/789/my-project/variables.tf": variables,
+		"organizations/789/folders/456/my-project/cloudresourcemanager.tf": `import {
+  to = google_project.my-project_2
+  id = "${var.project}"
+}
+resource "google_project" "my-project_2" {
+  folder_id  = "456"
+  name       = "My Project"
+  project_id = "my-project"
+}
+`,
+		"organizations/789/folders/456/my-project/provider.tf":  provider,
+		"organizations/789/folders/456/my-project/variables.tf": variables,
+	}
+
+	gotFiles := make(map[string]string)
+	for path, b := range got {
+		gotFiles[path] = string(b)
+	}
+	if diff := cmp.Diff(expected, gotFiles); diff != "" {
+		t.Errorf("unexpected files (-want +got):\n%s", diff)
+	}
+}
+
+func TestDirectoryResolveReference(t *testing.T) {
+	network := &models.TerraformResourceBlock{Labels: []string{"google_compute_network", "default"}}
+	otherNetwork := &models.TerraformResourceBlock{Labels: []string{"google_compute_network", "default_2"}}
+	subnetwork := &models.TerraformResourceBlock{Labels: []string{"google_compute_subnetwork", "default"}}
+
+	r := newResolver()
+	r.add(caiasset.Asset{
+		Name: "//compute.googleapis.com/projects/my-project/global/networks/default",
+		Type: "compute.googleapis.com/Network",
+	}, network)
+	r.add(caiasset.Asset{
+		Name: "//compute.googleapis.com/projects/other-project/global/networks/default",
+		Type: "compute.googleapis.com/Network",
+	}, otherNetwork)
+	dir := &directory{
+		project: "my-project",
+		blocks: map[*models.TerraformResourceBlock]bool{
+			network:    true,
+			subnetwork: true,
+		},
+	}
+
+	cases := map[string]struct {
+		Value    string
+		Expected string
+	}{
+		"reference": {
+			Value:    "projects/my-project/global/networks/default",
+			Expected: "google_compute_network.default.id",
+		},
+		"resource of other directory": {
+			Value: "projects/other-project/global/networks/default",
+		},
+		"unknown resource": {
+			Value: "projects/my-project/global/networks/other",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var got string
+			if traversal := dir.resolveReference(r, subnetwork, tc.Value); traversal != nil {
+				got = traversal.RootName()
+				for _, step := range traversal[1:] {
+					got += "." + step.(hcl.TraverseAttr).Name
+				}
+			}
+			if got != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestHclWriteBlocksWithProjectVariable(t *testing.T) {
+	subnetwork := &models.TerraformResourceBlock{
+		Labels: []string{"google_compute_subnetwork", "default"},
+		Value: cty.ObjectVal(map[string]cty.Value{
+			"name":    cty.StringVal("my-project"),
+			"project": cty.StringVal("my-project"),
+			"region":  cty.StringVal("us-central1"),
+		}),
+		ImportId: "projects/my-project/regions/us-central1/subnetworks/my-project",
+	}
+
+	got, err := models.HclWriteBlocksWithOptions([]*models.TerraformResourceBlock{subnetwork}, &models.HclWriteOptions{
+		ImportBlocks: true,
+		Project: &models.ProjectVariable{
+			Id:       "my-project",
+			Variable: projectVariable,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import {
+  to = google_compute_subnetwork.default
+  id = "projects/${var.project}/regions/us-central1/subnetworks/my-project"
+}
+resource "google_compute_subnetwork" "default" {
+  name    = "my-project"
+  project = var.project
+  region  = "us-central1"
+}
+`
+	if string(got) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(got))
+	}
+}
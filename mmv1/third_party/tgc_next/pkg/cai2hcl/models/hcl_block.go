@@ -2,7 +2,10 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -11,15 +14,75 @@ import (
 type TerraformResourceBlock struct {
 	Labels []string
 	Value  cty.Value
+	// ImportId is the ID of the resource in its first import format, or empty
+	// if it can't be determined from the asset.
+	ImportId string
+}
+
+// HclWriteOptions changes how blocks are written by HclWriteBlocksWithOptions.
+type HclWriteOptions struct {
+	// ImportBlocks writes an import block before each block with an ImportId.
+	ImportBlocks bool
+	// ResolveReference returns the expression referring to the resource that a
+	// string value of block identifies, such as google_compute_network.default.id,
+	// or nil if the value should be written as it is.
+	ResolveReference func(block *TerraformResourceBlock, value string) hcl.Traversal
+	// Project, if set, replaces the ID of a project by a variable in the
+	// `project` attributes of blocks and in import IDs.
+	Project *ProjectVariable
+}
+
+// ProjectVariable is a variable that the ID of a project is replaced with.
+type ProjectVariable struct {
+	Id       string
+	Variable hcl.Traversal
 }
 
 func HclWriteBlocks(blocks []*TerraformResourceBlock) ([]byte, error) {
+	return HclWriteBlocksWithOptions(blocks, nil)
+}
+
+// HclWriteBlocksWithOptions writes blocks as resource blocks separated by
+// empty lines.
+func HclWriteBlocksWithOptions(blocks []*TerraformResourceBlock, options *HclWriteOptions) ([]byte, error) {
+	if options == nil {
+		options = &HclWriteOptions{}
+	}
+
 	f := hclwrite.NewFile()
 	rootBody := f.Body()
 
-	for _, resourceBlock := range blocks {
+	for i, resourceBlock := range blocks {
+		if i > 0 {
+			rootBody.AppendNewline()
+		}
+
+		if options.ImportBlocks && resourceBlock.ImportId != "" && len(resourceBlock.Labels) == 2 {
+			importBlock := rootBody.AppendNewBlock("import", nil)
+			importBlock.Body().SetAttributeTraversal("to", hcl.Traversal{
+				hcl.TraverseRoot{Name: resourceBlock.Labels[0]},
+				hcl.TraverseAttr{Name: resourceBlock.Labels[1]},
+			})
+			importBlock.Body().SetAttributeRaw("id", importIdTokens(resourceBlock.ImportId, options.Project))
+		}
+
+		var resolve func(attribute, value string) hcl.Traversal
+		if options.ResolveReference != nil || options.Project != nil {
+			resolve = func(attribute, value string) hcl.Traversal {
+				if options.ResolveReference != nil {
+					if traversal := options.ResolveReference(resourceBlock, value); traversal != nil {
+						return traversal
+					}
+				}
+				if options.Project != nil && attribute == "project" && value == options.Project.Id {
+					return options.Project.Variable
+				}
+				return nil
+			}
+		}
+
 		hclBlock := rootBody.AppendNewBlock("resource", resourceBlock.Labels)
-		if err := hclWriteBlock(resourceBlock.Value, hclBlock.Body()); err != nil {
+		if err := hclWriteBlock(resourceBlock.Value, hclBlock.Body(), resolve); err != nil {
 			return nil, err
 		}
 	}
@@ -27,7 +90,7 @@ func HclWriteBlocks(blocks []*TerraformResourceBlock) ([]byte, error) {
 	return hclwrite.Format(f.Bytes()), nil
 }
 
-func hclWriteBlock(val cty.Value, body *hclwrite.Body) error {
+func hclWriteBlock(val cty.Value, body *hclwrite.Body, resolve func(attribute, value string) hcl.Traversal) error {
 	if val.IsNull() {
 		return nil
 	}
@@ -44,7 +107,7 @@ func hclWriteBlock(val cty.Value, body *hclwrite.Body) error {
 		switch {
 		case objValType.IsObjectType():
 			newBlock := body.AppendNewBlock(objKey.AsString(), nil)
-			if err := hclWriteBlock(objVal, newBlock.Body()); err != nil {
+			if err := hclWriteBlock(objVal, newBlock.Body(), resolve); err != nil {
 				return err
 			}
 		case objValType.IsCollectionType():
@@ -54,7 +117,7 @@ func hclWriteBlock(val cty.Value, body *hclwrite.Body) error {
 				for listIterator.Next() {
 					_, listVal := listIterator.Element()
 					subBlock := body.AppendNewBlock(objKey.AsString(), nil)
-					if err := hclWriteBlock(listVal, subBlock.Body()); err != nil {
+					if err := hclWriteBlock(listVal, subBlock.Body(), resolve); err != nil {
 						return err
 					}
 				}
@@ -62,8 +125,74 @@ func hclWriteBlock(val cty.Value, body *hclwrite.Body) error {
 			}
 			fallthrough
 		default:
+			if tokens := referenceTokens(objKey.AsString(), objVal, resolve); tokens != nil {
+				body.SetAttributeRaw(objKey.AsString(), tokens)
+				continue
+			}
 			body.SetAttributeValue(objKey.AsString(), objVal)
 		}
 	}
 	return nil
 }
+
+// importIdTokens returns the tokens of the import ID id, a template with the
+// first segment that is the ID of project replaced by its variable. Later
+// segments are names of resources that happen to be the same as the ID.
+func importIdTokens(id string, project *ProjectVariable) hclwrite.Tokens {
+	if project == nil || project.Id == "" {
+		return hclwrite.TokensForValue(cty.StringVal(id))
+	}
+
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)}}
+	replaced := false
+	for i, segment := range strings.Split(id, "/") {
+		if i > 0 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenQuotedLit, Bytes: []byte("/")})
+		}
+		if segment == project.Id && !replaced {
+			replaced = true
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateInterp, Bytes: []byte("${")})
+			tokens = append(tokens, hclwrite.TokensForTraversal(project.Variable)...)
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenTemplateSeqEnd, Bytes: []byte("}")})
+			continue
+		}
+		// The tokens of a string value are its escaped content between quotes.
+		quoted := hclwrite.TokensForValue(cty.StringVal(segment))
+		tokens = append(tokens, quoted[1:len(quoted)-1]...)
+	}
+	return append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)})
+}
+
+// referenceTokens returns the tokens of val, the value of attribute, with the
+// strings that resolve resolves replaced, or nil if none of them are resolved.
+func referenceTokens(attribute string, val cty.Value, resolve func(attribute, value string) hcl.Traversal) hclwrite.Tokens {
+	if resolve == nil || val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+
+	valType := val.Type()
+	switch {
+	case valType == cty.String:
+		if traversal := resolve(attribute, val.AsString()); traversal != nil {
+			return hclwrite.TokensForTraversal(traversal)
+		}
+	case valType.IsListType() || valType.IsSetType():
+		var elems []hclwrite.Tokens
+		resolved := false
+		it := val.ElementIterator()
+		for it.Next() {
+			_, elem := it.Element()
+			tokens := referenceTokens(attribute, elem, resolve)
+			if tokens != nil {
+				resolved = true
+			} else {
+				tokens = hclwrite.TokensForValue(elem)
+			}
+			elems = append(elems, tokens)
+		}
+		if resolved {
+			return hclwrite.TokensForTuple(elems)
+		}
+	}
+	return nil
+}
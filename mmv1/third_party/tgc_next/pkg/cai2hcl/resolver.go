@@ -0,0 +1,165 @@
+package cai2hcl
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/hashicorp/hcl/v2"
+)
+
+const serviceAccountAssetType = "iam.googleapis.com/ServiceAccount"
+
+// The first segment of the relative name of a resource, such as
+// "projects/my-project/global/networks/default".
+var rootCollections = map[string]bool{
+	"projects":        true,
+	"folders":         true,
+	"organizations":   true,
+	"billingAccounts": true,
+}
+
+// referenceTarget is the attribute of a converted resource that values
+// referring to the resource are replaced with.
+type referenceTarget struct {
+	block     *models.TerraformResourceBlock
+	attribute string
+}
+
+// resolver resolves the values of attributes that refer to other converted
+// resources, such as the self-link of a network or the name of a KMS key,
+// into references to those resources. Only values that are exactly an
+// identifier of a resource are resolved, so that a value merely containing
+// the path of a resource, such as a URL of another service, is kept as it is.
+type resolver struct {
+	// targets indexes the converted resources by the names, relative names
+	// and self-links of their assets, and service accounts by email. A key
+	// identifying several resources maps to nil.
+	targets map[string]*referenceTarget
+}
+
+func newResolver() *resolver {
+	return &resolver{
+		targets: make(map[string]*referenceTarget),
+	}
+}
+
+// add indexes block, the resource converted from asset.
+func (r *resolver) add(asset caiasset.Asset, block *models.TerraformResourceBlock) {
+	if key := referenceKey(asset.Name); key != "" {
+		r.addTarget(asset.Name, block, "id")
+		r.addTarget(key, block, "id")
+	}
+	if asset.Resource == nil {
+		return
+	}
+	for _, field := range []string{"name", "selfLink"} {
+		if v, ok := asset.Resource.Data[field].(string); ok && referenceKey(v) != "" {
+			r.addTarget(v, block, "id")
+		}
+	}
+	if asset.Type == serviceAccountAssetType {
+		if email, ok := asset.Resource.Data["email"].(string); ok {
+			r.addTarget(email, block, "email")
+		}
+	}
+}
+
+func (r *resolver) addTarget(key string, block *models.TerraformResourceBlock, attribute string) {
+	if key == "" {
+		return
+	}
+	if target, ok := r.targets[key]; ok {
+		if target != nil && target.block != block {
+			r.targets[key] = nil
+		}
+		return
+	}
+	r.targets[key] = &referenceTarget{
+		block:     block,
+		attribute: attribute,
+	}
+}
+
+// resolve returns the reference to the resource that value identifies, or
+// nil if value isn't exactly an identifier of one converted resource other
+// than block.
+func (r *resolver) resolve(block *models.TerraformResourceBlock, value string) hcl.Traversal {
+	return r.lookup(block, value).traversal()
+}
+
+// lookup returns the target that value identifies, or nil if value isn't
+// exactly an identifier of one converted resource other than block.
+func (r *resolver) lookup(block *models.TerraformResourceBlock, value string) *referenceTarget {
+	target := r.targets[value]
+	if target == nil || target.block == block || len(target.block.Labels) != 2 {
+		return nil
+	}
+	return target
+}
+
+// traversal returns the reference to the attribute of t, or nil if t is nil.
+func (t *referenceTarget) traversal() hcl.Traversal {
+	if t == nil {
+		return nil
+	}
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: t.block.Labels[0]},
+		hcl.TraverseAttr{Name: t.block.Labels[1]},
+		hcl.TraverseAttr{Name: t.attribute},
+	}
+}
+
+// referenceKey returns the relative name of the resource that value refers
+// to, or an empty string if value isn't the name of a resource. value can be
+// an asset name, a self-link or a relative name. For example,
+// "//compute.googleapis.com/projects/my-project/global/networks/default" and
+// "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default"
+// both return "projects/my-project/global/networks/default".
+func referenceKey(value string) string {
+	path := value
+	if strings.HasPrefix(value, "//") || strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://") {
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		path = strings.TrimPrefix(u.Path, "/")
+		segments := strings.Split(path, "/")
+		for i, segment := range segments {
+			if rootCollections[segment] {
+				path = strings.Join(segments[i:], "/")
+				break
+			}
+		}
+	}
+
+	segments := strings.Split(path, "/")
+	if len(segments) < 2 || !rootCollections[segments[0]] {
+		return ""
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return ""
+		}
+	}
+	return path
+}
+
+// uniqueLabels renames the blocks whose labels are already used by a
+// previous block, so that every resource has a distinct address.
+func uniqueLabels(blocks []*models.TerraformResourceBlock) {
+	used := make(map[string]bool)
+	for _, block := range blocks {
+		if len(block.Labels) != 2 {
+			continue
+		}
+		name := block.Labels[1]
+		for i := 2; used[block.Labels[0]+"."+name]; i++ {
+			name = fmt.Sprintf("%s_%d", block.Labels[1], i)
+		}
+		used[block.Labels[0]+"."+name] = true
+		block.Labels[1] = name
+	}
+}
@@ -0,0 +1,237 @@
+package cai2hcl
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/models"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestReferenceKey(t *testing.T) {
+	cases := map[string]struct {
+		Value    string
+		Expected string
+	}{
+		"asset name": {
+			Value:    "//compute.googleapis.com/projects/my-project/global/networks/default",
+			Expected: "projects/my-project/global/networks/default",
+		},
+		"self-link": {
+			Value:    "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default",
+			Expected: "projects/my-project/global/networks/default",
+		},
+		"relative name": {
+			Value:    "projects/my-project/locations/us/keyRings/my-ring/cryptoKeys/my-key",
+			Expected: "projects/my-project/locations/us/keyRings/my-ring/cryptoKeys/my-key",
+		},
+		"short name": {
+			Value:    "default",
+			Expected: "",
+		},
+		"partial name": {
+			Value:    "global/networks/default",
+			Expected: "",
+		},
+		"URL without resource": {
+			Value:    "https://www.googleapis.com/compute/v1/",
+			Expected: "",
+		},
+		"empty segment": {
+			Value:    "projects//global/networks/default",
+			Expected: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if got := referenceKey(tc.Value); got != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestResolverResolve(t *testing.T) {
+	network := &models.TerraformResourceBlock{Labels: []string{"google_compute_network", "default"}}
+	serviceAccount := &models.TerraformResourceBlock{Labels: []string{"google_service_account", "my-sa"}}
+	instance := &models.TerraformResourceBlock{Labels: []string{"google_compute_instance", "my-instance"}}
+	otherInstance := &models.TerraformResourceBlock{Labels: []string{"google_compute_instance", "my-instance_2"}}
+
+	r := newResolver()
+	r.add(caiasset.Asset{
+		Name: "//compute.googleapis.com/projects/my-project/global/networks/default",
+		Type: "compute.googleapis.com/Network",
+		Resource: &caiasset.AssetResource{
+			Data: map[string]interface{}{
+				"name":     "default",
+				"selfLink": "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default",
+			},
+		},
+	}, network)
+	r.add(caiasset.Asset{
+		Name: "//iam.googleapis.com/projects/my-project/serviceAccounts/123",
+		Type: serviceAccountAssetType,
+		Resource: &caiasset.AssetResource{
+			Data: map[string]interface{}{
+				"name":  "projects/my-project/serviceAccounts/my-sa@my-project.iam.gserviceaccount.com",
+				"email": "my-sa@my-project.iam.gserviceaccount.com",
+			},
+		},
+	}, serviceAccount)
+	// Two assets with the same name can't be told apart
+	for _, block := range []*models.TerraformResourceBlock{instance, otherInstance} {
+		r.add(caiasset.Asset{
+			Name: "//compute.googleapis.com/projects/my-project/zones/us-central1-a/instances/my-instance",
+			Type: "compute.googleapis.com/Instance",
+		}, block)
+	}
+
+	cases := map[string]struct {
+		Block    *models.TerraformResourceBlock
+		Value    string
+		Expected string
+	}{
+		"self-link": {
+			Block:    instance,
+			Value:    "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default",
+			Expected: "google_compute_network.default.id",
+		},
+		"relative name": {
+			Block:    instance,
+			Value:    "projects/my-project/global/networks/default",
+			Expected: "google_compute_network.default.id",
+		},
+		"service account email": {
+			Block:    instance,
+			Value:    "my-sa@my-project.iam.gserviceaccount.com",
+			Expected: "google_service_account.my-sa.email",
+		},
+		"asset name": {
+			Block:    instance,
+			Value:    "//compute.googleapis.com/projects/my-project/global/networks/default",
+			Expected: "google_compute_network.default.id",
+		},
+		"self-link of another API version": {
+			Block: instance,
+			Value: "https://www.googleapis.com/compute/beta/projects/my-project/global/networks/default",
+		},
+		"URL containing the path of a resource": {
+			Block: instance,
+			Value: "https://console.cloud.google.com/projects/my-project/global/networks/default",
+		},
+		"service account name": {
+			Block:    instance,
+			Value:    "projects/my-project/serviceAccounts/my-sa@my-project.iam.gserviceaccount.com",
+			Expected: "google_service_account.my-sa.id",
+		},
+		"self reference": {
+			Block: network,
+			Value: "projects/my-project/global/networks/default",
+		},
+		"ambiguous": {
+			Block: network,
+			Value: "projects/my-project/zones/us-central1-a/instances/my-instance",
+		},
+		"unknown resource": {
+			Block: instance,
+			Value: "projects/my-project/global/networks/other",
+		},
+		"not a reference": {
+			Block: instance,
+			Value: "default",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var got string
+			if traversal := r.resolve(tc.Block, tc.Value); traversal != nil {
+				got = traversal.RootName()
+				for _, step := range traversal[1:] {
+					got += "." + step.(hcl.TraverseAttr).Name
+				}
+			}
+			if got != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestUniqueLabels(t *testing.T) {
+	blocks := []*models.TerraformResourceBlock{
+		{Labels: []string{"google_compute_network", "default"}},
+		{Labels: []string{"google_compute_network", "default"}},
+		{Labels: []string{"google_compute_subnetwork", "default"}},
+		{Labels: []string{"google_compute_network", "default"}},
+	}
+
+	uniqueLabels(blocks)
+
+	expected := []string{"default", "default_2", "default", "default_3"}
+	for i, block := range blocks {
+		if block.Labels[1] != expected[i] {
+			t.Errorf("block %d: expected name %q, got %q", i, expected[i], block.Labels[1])
+		}
+	}
+}
+
+func TestHclWriteBlocksWithReferences(t *testing.T) {
+	network := &models.TerraformResourceBlock{
+		Labels: []string{"google_compute_network", "default"},
+		Value: cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("default"),
+		}),
+		ImportId: "projects/my-project/global/networks/default",
+	}
+	router := &models.TerraformResourceBlock{
+		Labels: []string{"google_compute_router", "my-router"},
+		Value: cty.ObjectVal(map[string]cty.Value{
+			"name":    cty.StringVal("my-router"),
+			"network": cty.StringVal("https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default"),
+			"networks": cty.ListVal([]cty.Value{
+				cty.StringVal("projects/my-project/global/networks/default"),
+				cty.StringVal("projects/my-project/global/networks/other"),
+			}),
+		}),
+	}
+
+	r := newResolver()
+	r.add(caiasset.Asset{
+		Name: "//compute.googleapis.com/projects/my-project/global/networks/default",
+		Type: "compute.googleapis.com/Network",
+		Resource: &caiasset.AssetResource{
+			Data: map[string]interface{}{
+				"selfLink": "https://www.googleapis.com/compute/v1/projects/my-project/global/networks/default",
+			},
+		},
+	}, network)
+
+	got, err := models.HclWriteBlocksWithOptions([]*models.TerraformResourceBlock{network, router}, &models.HclWriteOptions{
+		ImportBlocks:     true,
+		ResolveReference: r.resolve,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `import {
+  to = google_compute_network.default
+  id = "projects/my-project/global/networks/default"
+}
+resource "google_compute_network" "default" {
+  name = "default"
+}
+
+resource "google_compute_router" "my-router" {
+  name     = "my-router"
+  network  = google_compute_network.default.id
+  networks = [google_compute_network.default.id, "projects/my-project/global/networks/other"]
+}
+`
+	if string(got) != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, string(got))
+	}
+}
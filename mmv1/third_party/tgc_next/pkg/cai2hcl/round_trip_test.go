@@ -0,0 +1,115 @@
+package cai2hcl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/cai2hcl/converters"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai"
+	tfjson "github.com/hashicorp/terraform-json"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestConvertRoundTripCustomTgcExpand converts resources whose properties have
+// a custom_tgc_expand to CAI and back, checking that their custom_tgc_flatten
+// restores the configured values.
+func TestConvertRoundTripCustomTgcExpand(t *testing.T) {
+	cases := []struct {
+		resourceType string
+		after        map[string]interface{}
+	}{
+		{
+			resourceType: "google_secret_manager_secret_version",
+			after: map[string]interface{}{
+				"secret":      "projects/test-project/secrets/my-secret",
+				"secret_data": "my-data",
+			},
+		},
+		{
+			resourceType: "google_secret_manager_regional_secret_version",
+			after: map[string]interface{}{
+				"secret":      "projects/test-project/locations/us-central1/secrets/my-secret",
+				"secret_data": "my-data",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		for _, enabled := range []bool{true, false} {
+			t.Run(fmt.Sprintf("%s/enabled=%t", tc.resourceType, enabled), func(t *testing.T) {
+				after := map[string]interface{}{"enabled": enabled}
+				for k, v := range tc.after {
+					after[k] = v
+				}
+				jsonPlan, err := json.Marshal(&tfjson.Plan{
+					FormatVersion:    "0.1",
+					TerraformVersion: "1.0.0",
+					ResourceChanges: []*tfjson.ResourceChange{
+						{
+							Address: tc.resourceType + ".default",
+							Type:    tc.resourceType,
+							Name:    "default",
+							Change: &tfjson.Change{
+								Actions: []tfjson.Action{tfjson.ActionCreate},
+								After:   after,
+							},
+						},
+					},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assets, err := tfplan2cai.Convert(context.Background(), jsonPlan, &tfplan2cai.Options{
+					ErrorLogger:         zaptest.NewLogger(t),
+					Offline:             true,
+					DefaultProject:      "test-project",
+					DefaultRegion:       "us-central1",
+					NoOpAncestryManager: true,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(assets) != 1 {
+					t.Fatalf("expected 1 asset, got %d", len(assets))
+				}
+
+				got, err := converters.ConvertResource(assets, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := regexp.MustCompile(fmt.Sprintf(`\n\s+enabled\s+= %t\n`, enabled))
+				if !want.Match(got) {
+					t.Errorf("expected enabled = %t in:\n%s", enabled, got)
+				}
+			})
+		}
+	}
+}
+
+func TestConvertSecretVersionWithoutState(t *testing.T) {
+	assets := []caiasset.Asset{
+		{
+			Name: "//secretmanager.googleapis.com/projects/test-project/secrets/my-secret/versions/1",
+			Type: "secretmanager.googleapis.com/SecretVersion",
+			Resource: &caiasset.AssetResource{
+				Version:              "v1",
+				DiscoveryDocumentURI: "https://www.googleapis.com/discovery/v1/apis/secretmanager/v1/rest",
+				DiscoveryName:        "SecretVersion",
+				Data:                 map[string]interface{}{},
+			},
+		},
+	}
+
+	got, err := converters.ConvertResource(assets, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`\n\s+enabled\s+= true\n`).Match(got) {
+		t.Errorf("expected enabled = true in:\n%s", got)
+	}
+}
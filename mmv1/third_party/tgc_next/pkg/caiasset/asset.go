@@ -20,15 +20,44 @@ type Asset struct {
 	TfplanAddress []string         `json:"tfplan_address,omitempty"`
 }
 
+// Actions planned for a resource in an AssetChange.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionReplace = "replace"
+)
+
+// AssetChange is the change of the CAI assets of a resource planned by
+// Terraform.
+type AssetChange struct {
+	// The address of the resource in the Terraform plan.
+	Address string `json:"address"`
+	// The planned action: create, update, delete or replace.
+	Action string `json:"action"`
+	// The assets converted from the prior state of the resource. Empty if
+	// the resource is created.
+	Before []Asset `json:"before,omitempty"`
+	// The assets converted from the planned state of the resource. Empty if
+	// the resource is deleted, except for IAM resources, whose After is the
+	// policy their bindings are removed from.
+	After []Asset `json:"after,omitempty"`
+	// The attributes of the planned state whose values are unknown until the
+	// change is applied, like "id" or "network_interface.0.network_ip". They
+	// are converted as empty values, or as "null" in asset names.
+	AfterUnknown []string `json:"after_unknown,omitempty"`
+}
+
 // IAMPolicy is the representation of a Cloud IAM policy set on a cloud resource.
 type IAMPolicy struct {
 	Bindings []IAMBinding `json:"bindings"`
 }
 
-// IAMBinding binds a role to a set of members.
+// IAMBinding binds a role to a set of members, optionally under a condition.
 type IAMBinding struct {
-	Role    string   `json:"role"`
-	Members []string `json:"members"`
+	Role      string   `json:"role"`
+	Members   []string `json:"members"`
+	Condition *Expr    `json:"condition,omitempty"`
 }
 
 // AssetResource is nested within the Asset type.
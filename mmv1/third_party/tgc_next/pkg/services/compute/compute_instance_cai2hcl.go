@@ -133,8 +133,9 @@ func (c *ComputeInstanceCai2hclConverter) convertResourceData(asset caiasset.Ass
 		hclBlockName = instanceName
 	}
 	return &models.TerraformResourceBlock{
-		Labels: []string{c.name, hclBlockName},
-		Value:  ctyVal,
+		Labels:   []string{c.name, hclBlockName},
+		Value:    ctyVal,
+		ImportId: utils.ImportIdFromAssetName(asset.Name, "//compute.googleapis.com/projects/{{project}}/zones/{{zone}}/instances/{{name}}", "projects/{{project}}/zones/{{zone}}/instances/{{name}}", hclData),
 	}, nil
 }
 
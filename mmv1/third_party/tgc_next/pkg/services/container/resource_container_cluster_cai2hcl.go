@@ -243,8 +243,9 @@ func (c *ContainerClusterCai2hclConverter) convertResourceData(asset caiasset.As
 		hclBlockName = name
 	}
 	return &models.TerraformResourceBlock{
-		Labels: []string{c.name, hclBlockName},
-		Value:  ctyVal,
+		Labels:   []string{c.name, hclBlockName},
+		Value:    ctyVal,
+		ImportId: utils.ImportIdFromAssetName(an, "//container.googleapis.com/projects/{{project}}/locations/{{location}}/clusters/{{name}}", "projects/{{project}}/locations/{{location}}/clusters/{{name}}", hclData),
 	}, nil
 }
 
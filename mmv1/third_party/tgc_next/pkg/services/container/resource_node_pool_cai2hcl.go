@@ -82,8 +82,9 @@ func (c *ContainerNodePoolCai2hclConverter) convertResourceData(asset caiasset.A
 		hclBlockName = asset.Resource.Data["name"].(string)
 	}
 	return &models.TerraformResourceBlock{
-		Labels: []string{c.name, hclBlockName},
-		Value:  ctyVal,
+		Labels:   []string{c.name, hclBlockName},
+		Value:    ctyVal,
+		ImportId: utils.ImportIdFromAssetName(an, "//container.googleapis.com/projects/{{project}}/locations/{{location}}/clusters/{{cluster}}/nodePools/{{name}}", "{{project}}/{{location}}/{{cluster}}/{{name}}", hclData),
 	}, nil
 }
 
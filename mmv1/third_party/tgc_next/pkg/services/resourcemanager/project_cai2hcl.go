@@ -70,8 +70,10 @@ func (c *ProjectCai2hclConverter) convertResourceData(asset caiasset.Asset, opti
 	} else {
 		hclBlockName = assetResourceData["projectId"].(string)
 	}
+	projectId, _ := assetResourceData["projectId"].(string)
 	return &models.TerraformResourceBlock{
-		Labels: []string{c.name, hclBlockName},
-		Value:  ctyVal,
+		Labels:   []string{c.name, hclBlockName},
+		Value:    ctyVal,
+		ImportId: projectId,
 	}, nil
 }
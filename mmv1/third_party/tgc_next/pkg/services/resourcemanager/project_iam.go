@@ -0,0 +1,31 @@
+package resourcemanager
+
+import (
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/registry"
+
+	tpgresourcemanager "github.com/hashicorp/terraform-provider-google-beta/google-beta/services/resourcemanager"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/tpgiamresource"
+)
+
+// The schemas of the project IAM resources are the provider's, so that the
+// converters read the same fields as the provider does.
+func init() {
+	registry.Schema{
+		Name:        "google_project_iam_policy",
+		ProductName: "resourcemanager",
+		Type:        registry.SchemaTypeResource,
+		Schema:      tpgiamresource.ResourceIamPolicy(tpgresourcemanager.IamProjectSchema, tpgresourcemanager.NewProjectIamUpdater, tpgresourcemanager.ProjectIdParseFunc),
+	}.Register()
+	registry.Schema{
+		Name:        "google_project_iam_binding",
+		ProductName: "resourcemanager",
+		Type:        registry.SchemaTypeResource,
+		Schema:      tpgiamresource.ResourceIamBinding(tpgresourcemanager.IamProjectSchema, tpgresourcemanager.NewProjectIamUpdater, tpgresourcemanager.ProjectIdParseFunc),
+	}.Register()
+	registry.Schema{
+		Name:        "google_project_iam_member",
+		ProductName: "resourcemanager",
+		Type:        registry.SchemaTypeResource,
+		Schema:      tpgresourcemanager.ProjectIamMemberResource(),
+	}.Register()
+}
@@ -0,0 +1,120 @@
+package resourcemanager
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/converters/cai"
+
+	rmClient "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/services/resourcemanager/client"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tpgresource"
+	transport_tpg "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/transport"
+
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// Ideally we should use project_number, but since that is generated server-side,
+// we substitute project_id.
+const projectIamAssetNameTmpl = "//cloudresourcemanager.googleapis.com/projects/{{project}}"
+
+func ProjectIamPolicyTfplan2caiConverter() cai.Tfplan2caiConverter {
+	return cai.Tfplan2caiConverter{
+		Convert:           GetProjectIamPolicyCaiObject,
+		MergeCreateUpdate: cai.MergeIamPolicy,
+	}
+}
+
+func ProjectIamBindingTfplan2caiConverter() cai.Tfplan2caiConverter {
+	return cai.Tfplan2caiConverter{
+		Convert:           GetProjectIamBindingCaiObject,
+		FetchFullResource: FetchProjectIamPolicy,
+		MergeCreateUpdate: MergeProjectIamBinding,
+		MergeDelete:       MergeProjectIamBindingDelete,
+	}
+}
+
+func ProjectIamMemberTfplan2caiConverter() cai.Tfplan2caiConverter {
+	return cai.Tfplan2caiConverter{
+		Convert:           GetProjectIamMemberCaiObject,
+		FetchFullResource: FetchProjectIamPolicy,
+		MergeCreateUpdate: MergeProjectIamMember,
+		MergeDelete:       MergeProjectIamMemberDelete,
+	}
+}
+
+func GetProjectIamPolicyCaiObject(d tpgresource.TerraformResourceData, config *transport_tpg.Config) ([]caiasset.Asset, error) {
+	return newProjectIamAsset(d, config, cai.ExpandIamPolicyBindings)
+}
+
+func GetProjectIamBindingCaiObject(d tpgresource.TerraformResourceData, config *transport_tpg.Config) ([]caiasset.Asset, error) {
+	return newProjectIamAsset(d, config, cai.ExpandIamRoleBindings)
+}
+
+func GetProjectIamMemberCaiObject(d tpgresource.TerraformResourceData, config *transport_tpg.Config) ([]caiasset.Asset, error) {
+	return newProjectIamAsset(d, config, cai.ExpandIamMemberBindings)
+}
+
+func MergeProjectIamBinding(existing, incoming caiasset.Asset) caiasset.Asset {
+	return cai.MergeIamAssets(existing, incoming, cai.MergeAuthoritativeBindings)
+}
+
+func MergeProjectIamBindingDelete(existing, incoming caiasset.Asset) caiasset.Asset {
+	return cai.MergeDeleteIamAssets(existing, incoming, cai.MergeDeleteAuthoritativeBindings)
+}
+
+func MergeProjectIamMember(existing, incoming caiasset.Asset) caiasset.Asset {
+	return cai.MergeIamAssets(existing, incoming, cai.MergeAdditiveBindings)
+}
+
+func MergeProjectIamMemberDelete(existing, incoming caiasset.Asset) caiasset.Asset {
+	return cai.MergeDeleteIamAssets(existing, incoming, cai.MergeDeleteAdditiveBindings)
+}
+
+func newProjectIamAsset(
+	d tpgresource.TerraformResourceData,
+	config *transport_tpg.Config,
+	expandBindings func(d tpgresource.TerraformResourceData) ([]caiasset.IAMBinding, error),
+) ([]caiasset.Asset, error) {
+	bindings, err := expandBindings(d)
+	if err != nil {
+		return []caiasset.Asset{}, fmt.Errorf("expanding bindings: %v", err)
+	}
+
+	name, err := cai.AssetName(d, config, projectIamAssetNameTmpl)
+	if err != nil {
+		return []caiasset.Asset{}, err
+	}
+
+	return []caiasset.Asset{{
+		Name: name,
+		Type: ProjectAssetType,
+		IAMPolicy: &caiasset.IAMPolicy{
+			Bindings: bindings,
+		},
+	}}, nil
+}
+
+// FetchProjectIamPolicy reads the current IAM policy of the project, so that
+// the bindings of google_project_iam_binding and google_project_iam_member
+// resources can be merged into it.
+func FetchProjectIamPolicy(d tpgresource.TerraformResourceData, config *transport_tpg.Config) (caiasset.Asset, error) {
+	project, ok := d.GetOk("project")
+	if !ok {
+		return caiasset.Asset{}, cai.ErrEmptyIdentityField
+	}
+
+	getPolicy := func() (*cloudresourcemanager.Policy, error) {
+		client := rmClient.NewClient(config, config.UserAgent)
+		if client == nil {
+			return nil, fmt.Errorf("creating resource manager client")
+		}
+		return client.Projects.GetIamPolicy(project.(string), &cloudresourcemanager.GetIamPolicyRequest{
+			Options: &cloudresourcemanager.GetPolicyOptions{
+				RequestedPolicyVersion: 3,
+			},
+		}).Do()
+	}
+
+	// We use project_id in the asset name template to be consistent with newProjectIamAsset.
+	return cai.FetchIamPolicy(getPolicy, d, config, projectIamAssetNameTmpl, ProjectAssetType)
+}
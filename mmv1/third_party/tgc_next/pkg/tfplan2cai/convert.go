@@ -12,9 +12,11 @@ import (
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/ancestrymanager"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/converters"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/models"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/resolvers"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/tfplan"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/transport"
+	transport_tpg "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/transport"
 	tfjson "github.com/hashicorp/terraform-json"
 )
 
@@ -53,10 +55,143 @@ func ConvertChanges(ctx context.Context, jsonPlan []byte, changes []*tfjson.Reso
 		return nil, fmt.Errorf("logger is not initialized")
 	}
 
-	// IAM resource resolver, do not run until IAM resources included
-	resolvers.NewIamAdvancedResolver(o.ErrorLogger).Resolve(jsonPlan)
+	cfg, ancestryManager, err := newConfigAndAncestryManager(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	return convertChanges(jsonPlan, changes, cfg, ancestryManager, o)
+}
+
+// ConvertAssetChanges converts terraform json plan to the changes of CAI
+// Assets planned for each resource. The assets before a change are converted
+// from the prior state of the resources, and the assets after a change from
+// their planned state.
+func ConvertAssetChanges(ctx context.Context, jsonPlan []byte, o *Options) ([]caiasset.AssetChange, error) {
+	if o == nil || o.ErrorLogger == nil {
+		return nil, fmt.Errorf("logger is not initialized")
+	}
+
+	changes, err := tfplan.ReadResourceChanges(jsonPlan)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, ancestryManager, err := newConfigAndAncestryManager(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	afterAssets, err := convertChanges(jsonPlan, changes, cfg, ancestryManager, o)
+	if err != nil {
+		return nil, err
+	}
+	beforeAssets, err := convertChanges(jsonPlan, priorStateChanges(changes), cfg, ancestryManager, o)
+	if err != nil {
+		return nil, fmt.Errorf("converting prior state: %w", err)
+	}
+
+	after := assetsByAddress(afterAssets)
+	before := assetsByAddress(beforeAssets)
+	iamAddresses := iamAddressSet(resolvers.NewIamAdvancedResolver(o.ErrorLogger).ResolveChanges(jsonPlan, changes))
+
+	var assetChanges []caiasset.AssetChange
+	for _, rc := range changes {
+		action := tfplan.Action(rc)
+		if action == "" {
+			continue
+		}
+
+		assetChange := caiasset.AssetChange{
+			Address: rc.Address,
+			Action:  action,
+		}
+		if action != caiasset.ActionCreate {
+			assetChange.Before = before[rc.Address]
+		}
+		if action != caiasset.ActionDelete {
+			assetChange.After = after[rc.Address]
+			assetChange.AfterUnknown = tfplan.UnknownAfterApply(rc)
+		} else if iamAddresses[rc.Address] {
+			// The bindings of a deleted IAM resource are removed from the policy
+			// of the resource it targets, which remains after the change.
+			assetChange.After = after[rc.Address]
+		}
+		if len(assetChange.Before) == 0 && len(assetChange.After) == 0 {
+			// The resource can't be converted.
+			continue
+		}
+		assetChanges = append(assetChanges, assetChange)
+	}
+	return assetChanges, nil
+}
+
+// priorStateChanges returns the resources that exist before the changes are
+// applied, as changes creating their prior state, so that they are converted
+// the same way as the planned state.
+func priorStateChanges(changes []*tfjson.ResourceChange) []*tfjson.ResourceChange {
+	var priorChanges []*tfjson.ResourceChange
+	for _, rc := range changes {
+		switch tfplan.Action(rc) {
+		case caiasset.ActionUpdate, caiasset.ActionDelete, caiasset.ActionReplace:
+		default:
+			continue
+		}
+		before, ok := rc.Change.Before.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		priorChange := *rc
+		priorChange.Change = &tfjson.Change{
+			Actions: tfjson.Actions{tfjson.ActionCreate},
+			After:   before,
+		}
+		priorChanges = append(priorChanges, &priorChange)
+	}
+	return priorChanges
+}
+
+// assetsByAddress returns the assets converted from each resource, by the
+// address of the resource. Assets merged from several resources, like IAM
+// policies, are returned for each of them.
+func assetsByAddress(assets []caiasset.Asset) map[string][]caiasset.Asset {
+	byAddress := make(map[string][]caiasset.Asset)
+	for _, asset := range assets {
+		for _, address := range asset.TfplanAddress {
+			byAddress[address] = append(byAddress[address], asset)
+		}
+	}
+	return byAddress
+}
+
+// newConfigAndAncestryManager sets up config and ancestry manager using the
+// same user agent. Config and ancestry manager are shared among resources.
+func newConfigAndAncestryManager(ctx context.Context, o *Options) (*transport_tpg.Config, ancestrymanager.AncestryManager, error) {
+	cfg, err := transport.NewConfig(ctx, o.DefaultProject, o.DefaultZone, o.DefaultRegion, o.Offline, o.UserAgent, o.HTTPClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building config: %w", err)
+	}
+
+	if o.NoOpAncestryManager {
+		return cfg, &ancestrymanager.NoOpAncestryManager{}, nil
+	}
+	ancestryManager, err := ancestrymanager.New(cfg, o.Offline, o.AncestryCache, o.ErrorLogger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building ancestry manager: %w", err)
+	}
+	return cfg, ancestryManager, nil
+}
+
+// convertChanges converts the resource changes of terraform json plan to CAI
+// Assets.
+func convertChanges(jsonPlan []byte, changes []*tfjson.ResourceChange, cfg *transport_tpg.Config, ancestryManager ancestrymanager.AncestryManager, o *Options) ([]caiasset.Asset, error) {
+	// IAM resources are grouped by the resource they target, and converted after
+	// the other resources so that their bindings are merged into one policy.
+	iamChanges := resolvers.NewIamAdvancedResolver(o.ErrorLogger).ResolveChanges(jsonPlan, changes)
 	resourceDataMap := resolvers.NewDefaultPreResolver(o.ErrorLogger).Resolve(changes)
 
+	iamAddresses := iamAddressSet(iamChanges)
+
 	// TODO: add remaining advanced resolvers for resources
 	ParentResolver := resolvers.NewParentResourceResolver(o.ErrorLogger)
 	dependencyMap := ParentResolver.Resolve(jsonPlan)
@@ -77,24 +212,6 @@ func ConvertChanges(ctx context.Context, jsonPlan []byte, changes []*tfjson.Reso
 		return nil, fmt.Errorf("sorting traversal order: %w", err)
 	}
 
-	// Set up config and ancestry manager using the same user agent.
-	// Config and ancestry manager are shared among resources.
-
-	cfg, err := transport.NewConfig(ctx, o.DefaultProject, o.DefaultZone, o.DefaultRegion, o.Offline, o.UserAgent, o.HTTPClient)
-	if err != nil {
-		return nil, fmt.Errorf("building config: %w", err)
-	}
-
-	var ancestryManager ancestrymanager.AncestryManager
-	if !o.NoOpAncestryManager {
-		ancestryManager, err = ancestrymanager.New(cfg, o.Offline, o.AncestryCache, o.ErrorLogger)
-		if err != nil {
-			return nil, fmt.Errorf("building ancestry manager: %w", err)
-		}
-	} else {
-		ancestryManager = &ancestrymanager.NoOpAncestryManager{}
-	}
-
 	var assets []caiasset.Asset
 	convertedAssetsByAddress := make(map[string][]caiasset.Asset)
 	convertedAddresses := make(map[string]bool)
@@ -130,6 +247,10 @@ func ConvertChanges(ctx context.Context, jsonPlan []byte, changes []*tfjson.Reso
 					}
 				}
 
+				if iamAddresses[address] {
+					continue
+				}
+
 				convertedAssets, err := converters.ConvertResource(resourceDataList, cfg, ancestryManager, o.ErrorLogger)
 				if err != nil {
 					return nil, fmt.Errorf("tfplan2cai converting: %w", err)
@@ -149,7 +270,7 @@ func ConvertChanges(ctx context.Context, jsonPlan []byte, changes []*tfjson.Reso
 	}
 
 	for address, resourceDataList := range resourceDataMap {
-		if !convertedAddresses[address] {
+		if !convertedAddresses[address] && !iamAddresses[address] {
 			convertedAssets, err := converters.ConvertResource(resourceDataList, cfg, ancestryManager, o.ErrorLogger)
 			if err != nil {
 				return nil, fmt.Errorf("tfplan2cai converting: %w", err)
@@ -167,5 +288,43 @@ func ConvertChanges(ctx context.Context, jsonPlan []byte, changes []*tfjson.Reso
 		}
 	}
 
-	return assets, nil
+	iamAssets, err := converters.ConvertIamResources(iamResourceData(iamChanges, resourceDataMap), cfg, ancestryManager, o.Offline, o.ErrorLogger)
+	if err != nil {
+		return nil, fmt.Errorf("tfplan2cai converting IAM resources: %w", err)
+	}
+
+	return converters.MergeIamPolicies(assets, iamAssets), nil
+}
+
+// iamAddressSet returns the addresses of the IAM resource changes.
+func iamAddressSet(iamChanges map[string][]*tfjson.ResourceChange) map[string]bool {
+	iamAddresses := make(map[string]bool)
+	for _, rcs := range iamChanges {
+		for _, rc := range rcs {
+			iamAddresses[rc.Address] = true
+		}
+	}
+	return iamAddresses
+}
+
+// iamResourceData returns the resource data of each group of IAM resource
+// changes, ordered by the ID of the group.
+func iamResourceData(iamChanges map[string][]*tfjson.ResourceChange, resourceDataMap map[string][]*models.FakeResourceDataWithMeta) [][]*models.FakeResourceDataWithMeta {
+	ids := make([]string, 0, len(iamChanges))
+	for id := range iamChanges {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var groups [][]*models.FakeResourceDataWithMeta
+	for _, id := range ids {
+		var group []*models.FakeResourceDataWithMeta
+		for _, rc := range iamChanges[id] {
+			group = append(group, resourceDataMap[rc.Address]...)
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
 }
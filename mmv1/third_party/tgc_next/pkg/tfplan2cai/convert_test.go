@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"os"
 
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
 	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/provider"
+	"github.com/google/go-cmp/cmp"
 	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -259,3 +263,377 @@ func TestConvert_GkeCustomNodeInit(t *testing.T) {
 
 	assert.Equal(t, "gs://my-bucket/init.sh", initScript["gcsUri"])
 }
+
+// roundTripperFunc serves the requests of a test without a network connection.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newIamPolicyClient returns a client whose requests for the IAM policy of a
+// project return the given status code and body. If statusCode is 0, no
+// request is expected.
+func newIamPolicyClient(t *testing.T, statusCode int, body string) *http.Client {
+	return &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if statusCode == 0 || !strings.HasSuffix(req.URL.Path, ":getIamPolicy") {
+				t.Errorf("unexpected request %s %s", req.Method, req.URL)
+			}
+			return &http.Response{
+				StatusCode: statusCode,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	}
+}
+
+func TestConvert_iamResources(t *testing.T) {
+	existingPolicy := `{
+		"bindings": [
+			{"role": "roles/viewer", "members": ["user:a@example.com", "user:existing@example.com"]},
+			{"role": "roles/editor", "members": ["user:existing@example.com"]},
+			{"role": "roles/owner", "members": ["user:owner-existing@example.com"]},
+			{"role": "roles/viewer", "members": ["user:existing@example.com"], "condition": {"title": "expires", "expression": "request.time < timestamp(\"2030-01-01T00:00:00Z\")"}}
+		]
+	}`
+	projectAsset := func(project string, addresses []string, bindings ...caiasset.IAMBinding) caiasset.Asset {
+		return caiasset.Asset{
+			Name:          "//cloudresourcemanager.googleapis.com/projects/" + project,
+			Type:          "cloudresourcemanager.googleapis.com/Project",
+			IAMPolicy:     &caiasset.IAMPolicy{Bindings: bindings},
+			TfplanAddress: addresses,
+		}
+	}
+	binding := func(role string, members ...string) caiasset.IAMBinding {
+		return caiasset.IAMBinding{Role: role, Members: members}
+	}
+	expiring := func(b caiasset.IAMBinding) caiasset.IAMBinding {
+		b.Condition = &caiasset.Expr{Title: "expires", Expression: `request.time < timestamp("2030-01-01T00:00:00Z")`}
+		return b
+	}
+
+	cases := []struct {
+		name     string
+		plan     string
+		offline  bool
+		status   int
+		expected []caiasset.Asset
+	}{
+		{
+			name:    "members offline",
+			plan:    "project_iam_member.tfplan.json",
+			offline: true,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_member.viewer_a", "google_project_iam_member.viewer_b", "google_project_iam_member.editor_c"},
+					binding("roles/viewer", "user:a@example.com", "user:b@example.com"),
+					binding("roles/editor", "user:c@example.com"),
+				),
+			},
+		},
+		{
+			name:   "members online",
+			plan:   "project_iam_member.tfplan.json",
+			status: http.StatusOK,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_member.viewer_a", "google_project_iam_member.viewer_b", "google_project_iam_member.editor_c"},
+					binding("roles/viewer", "user:a@example.com", "user:b@example.com", "user:existing@example.com"),
+					binding("roles/editor", "user:c@example.com", "user:existing@example.com"),
+					binding("roles/owner", "user:owner-existing@example.com"),
+					expiring(binding("roles/viewer", "user:existing@example.com")),
+				),
+			},
+		},
+		{
+			name:   "members online without permission",
+			plan:   "project_iam_member.tfplan.json",
+			status: http.StatusForbidden,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_member.viewer_a", "google_project_iam_member.viewer_b", "google_project_iam_member.editor_c"},
+					binding("roles/viewer", "user:a@example.com", "user:b@example.com"),
+					binding("roles/editor", "user:c@example.com"),
+				),
+			},
+		},
+		{
+			name:    "binding and members offline",
+			plan:    "project_iam_binding.tfplan.json",
+			offline: true,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_binding.viewer", "google_project_iam_member.viewer_c", "google_project_iam_member.editor_d"},
+					binding("roles/viewer", "user:a@example.com", "user:b@example.com", "user:c@example.com"),
+					binding("roles/editor", "user:d@example.com"),
+				),
+			},
+		},
+		{
+			name:   "binding and members online",
+			plan:   "project_iam_binding.tfplan.json",
+			status: http.StatusOK,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_binding.viewer", "google_project_iam_member.viewer_c", "google_project_iam_member.editor_d"},
+					binding("roles/viewer", "user:a@example.com", "user:b@example.com", "user:c@example.com"),
+					binding("roles/editor", "user:d@example.com", "user:existing@example.com"),
+					binding("roles/owner", "user:owner-existing@example.com"),
+					expiring(binding("roles/viewer", "user:existing@example.com")),
+				),
+			},
+		},
+		{
+			name:    "policy, binding and member offline",
+			plan:    "project_iam_policy.tfplan.json",
+			offline: true,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_policy.policy", "google_project_iam_binding.viewer", "google_project_iam_member.owner_b"},
+					binding("roles/owner", "user:b@example.com", "user:owner@example.com"),
+					binding("roles/viewer", "user:a@example.com"),
+				),
+			},
+		},
+		{
+			// The policy is authoritative, so the existing policy isn't fetched.
+			name: "policy, binding and member online",
+			plan: "project_iam_policy.tfplan.json",
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_policy.policy", "google_project_iam_binding.viewer", "google_project_iam_member.owner_b"},
+					binding("roles/owner", "user:b@example.com", "user:owner@example.com"),
+					binding("roles/viewer", "user:a@example.com"),
+				),
+			},
+		},
+		{
+			name:    "deletes offline",
+			plan:    "project_iam_delete.tfplan.json",
+			offline: true,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_member.viewer_a", "google_project_iam_binding.editor", "google_project_iam_member.browser_e"},
+					binding("roles/browser", "user:e@example.com"),
+				),
+			},
+		},
+		{
+			name:   "deletes online",
+			plan:   "project_iam_delete.tfplan.json",
+			status: http.StatusOK,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_member.viewer_a", "google_project_iam_binding.editor", "google_project_iam_member.browser_e"},
+					binding("roles/viewer", "user:existing@example.com"),
+					binding("roles/owner", "user:owner-existing@example.com"),
+					expiring(binding("roles/viewer", "user:existing@example.com")),
+					binding("roles/browser", "user:e@example.com"),
+				),
+			},
+		},
+		{
+			name:    "conditions offline",
+			plan:    "project_iam_condition.tfplan.json",
+			offline: true,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_binding.viewer_expiring", "google_project_iam_member.viewer_a", "google_project_iam_member.viewer_b"},
+					expiring(binding("roles/viewer", "user:b@example.com", "user:c@example.com")),
+					binding("roles/viewer", "user:a@example.com"),
+				),
+			},
+		},
+		{
+			name:   "conditions online",
+			plan:   "project_iam_condition.tfplan.json",
+			status: http.StatusOK,
+			expected: []caiasset.Asset{
+				projectAsset(testProject,
+					[]string{"google_project_iam_binding.viewer_expiring", "google_project_iam_member.viewer_a", "google_project_iam_member.viewer_b"},
+					binding("roles/viewer", "user:a@example.com", "user:existing@example.com"),
+					binding("roles/editor", "user:existing@example.com"),
+					binding("roles/owner", "user:owner-existing@example.com"),
+					expiring(binding("roles/viewer", "user:b@example.com", "user:c@example.com")),
+				),
+			},
+		},
+		{
+			name:    "multiple projects offline",
+			plan:    "project_iam_multiple_projects.tfplan.json",
+			offline: true,
+			expected: []caiasset.Asset{
+				projectAsset("project-a",
+					[]string{"google_project_iam_member.viewer_a"},
+					binding("roles/viewer", "user:a@example.com"),
+				),
+				projectAsset("project-b",
+					[]string{"google_project_iam_binding.editor", "google_project_iam_member.viewer_b"},
+					binding("roles/editor", "user:c@example.com"),
+					binding("roles/viewer", "user:b@example.com"),
+				),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jsonPlan, err := os.ReadFile(c.plan)
+			if err != nil {
+				t.Fatalf("Error reading %s: %s", c.plan, err)
+			}
+
+			logger, _ := newTestErrorLogger()
+			o := &Options{
+				ErrorLogger:         logger,
+				Offline:             c.offline,
+				DefaultProject:      testProject,
+				NoOpAncestryManager: true,
+			}
+			if !c.offline {
+				body := existingPolicy
+				if c.status != http.StatusOK {
+					body = `{"error": {"code": 403, "message": "The caller does not have permission"}}`
+				}
+				o.HTTPClient = newIamPolicyClient(t, c.status, body)
+			}
+
+			assets, err := Convert(context.Background(), jsonPlan, o)
+			if err != nil {
+				t.Fatalf("Convert() = %v", err)
+			}
+			if diff := cmp.Diff(c.expected, assets); diff != "" {
+				t.Errorf("Convert() returned unexpected assets (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertAssetChanges(t *testing.T) {
+	existingPolicy := `{
+		"bindings": [
+			{"role": "roles/viewer", "members": ["user:existing@example.com"]},
+			{"role": "roles/editor", "members": ["user:existing@example.com"]}
+		]
+	}`
+	projectAsset := func(addresses []string, bindings ...caiasset.IAMBinding) caiasset.Asset {
+		return caiasset.Asset{
+			Name:          "//cloudresourcemanager.googleapis.com/projects/" + testProject,
+			Type:          "cloudresourcemanager.googleapis.com/Project",
+			IAMPolicy:     &caiasset.IAMPolicy{Bindings: bindings},
+			TfplanAddress: addresses,
+		}
+	}
+	binding := func(role string, members ...string) caiasset.IAMBinding {
+		return caiasset.IAMBinding{Role: role, Members: members}
+	}
+
+	offlineBefore := projectAsset(
+		[]string{"google_project_iam_binding.viewer", "google_project_iam_member.editor_x"},
+		binding("roles/viewer", "user:a@example.com"),
+		binding("roles/editor", "user:x@example.com"),
+	)
+	offlineAfter := projectAsset(
+		[]string{"google_project_iam_member.editor_x", "google_project_iam_binding.viewer", "google_project_iam_member.browser_c"},
+		binding("roles/viewer", "user:a@example.com", "user:b@example.com"),
+		binding("roles/browser", "user:c@example.com"),
+	)
+	onlineBefore := projectAsset(
+		[]string{"google_project_iam_binding.viewer", "google_project_iam_member.editor_x"},
+		binding("roles/viewer", "user:a@example.com"),
+		binding("roles/editor", "user:existing@example.com", "user:x@example.com"),
+	)
+	onlineAfter := projectAsset(
+		[]string{"google_project_iam_member.editor_x", "google_project_iam_binding.viewer", "google_project_iam_member.browser_c"},
+		binding("roles/viewer", "user:a@example.com", "user:b@example.com"),
+		binding("roles/editor", "user:existing@example.com"),
+		binding("roles/browser", "user:c@example.com"),
+	)
+
+	cases := []struct {
+		name     string
+		offline  bool
+		expected []caiasset.AssetChange
+	}{
+		{
+			name:    "offline",
+			offline: true,
+			expected: []caiasset.AssetChange{
+				{
+					Address:      "google_project_iam_binding.viewer",
+					Action:       caiasset.ActionUpdate,
+					Before:       []caiasset.Asset{offlineBefore},
+					After:        []caiasset.Asset{offlineAfter},
+					AfterUnknown: []string{"etag"},
+				},
+				{
+					Address: "google_project_iam_member.editor_x",
+					Action:  caiasset.ActionDelete,
+					Before:  []caiasset.Asset{offlineBefore},
+					After:   []caiasset.Asset{offlineAfter},
+				},
+				{
+					Address:      "google_project_iam_member.browser_c",
+					Action:       caiasset.ActionCreate,
+					After:        []caiasset.Asset{offlineAfter},
+					AfterUnknown: []string{"etag", "id"},
+				},
+			},
+		},
+		{
+			name: "online",
+			expected: []caiasset.AssetChange{
+				{
+					Address:      "google_project_iam_binding.viewer",
+					Action:       caiasset.ActionUpdate,
+					Before:       []caiasset.Asset{onlineBefore},
+					After:        []caiasset.Asset{onlineAfter},
+					AfterUnknown: []string{"etag"},
+				},
+				{
+					Address: "google_project_iam_member.editor_x",
+					Action:  caiasset.ActionDelete,
+					Before:  []caiasset.Asset{onlineBefore},
+					After:   []caiasset.Asset{onlineAfter},
+				},
+				{
+					Address:      "google_project_iam_member.browser_c",
+					Action:       caiasset.ActionCreate,
+					After:        []caiasset.Asset{onlineAfter},
+					AfterUnknown: []string{"etag", "id"},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			jsonPlan, err := os.ReadFile("project_iam_changes.tfplan.json")
+			if err != nil {
+				t.Fatalf("Error reading project_iam_changes.tfplan.json: %s", err)
+			}
+
+			logger, _ := newTestErrorLogger()
+			o := &Options{
+				ErrorLogger:         logger,
+				Offline:             c.offline,
+				DefaultProject:      testProject,
+				NoOpAncestryManager: true,
+			}
+			if !c.offline {
+				o.HTTPClient = newIamPolicyClient(t, http.StatusOK, existingPolicy)
+			}
+
+			changes, err := ConvertAssetChanges(context.Background(), jsonPlan, o)
+			if err != nil {
+				t.Fatalf("ConvertAssetChanges() = %v", err)
+			}
+			if diff := cmp.Diff(c.expected, changes); diff != "" {
+				t.Errorf("ConvertAssetChanges() returned unexpected changes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
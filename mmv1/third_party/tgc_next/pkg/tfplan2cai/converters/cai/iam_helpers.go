@@ -0,0 +1,296 @@
+package cai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tpgresource"
+	transport_tpg "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/transport"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// ExpandIamPolicyBindings is used in google_<type>_iam_policy resources.
+func ExpandIamPolicyBindings(d tpgresource.TerraformResourceData) ([]caiasset.IAMBinding, error) {
+	ps := d.Get("policy_data").(string)
+	var bindings []caiasset.IAMBinding
+	// policy_data is (known after apply) in terraform plan, hence an empty string
+	if ps == "" {
+		return bindings, nil
+	}
+	// The policy string is just a marshaled cloudresourcemanager.Policy.
+	policy := &cloudresourcemanager.Policy{}
+	if err := json.Unmarshal([]byte(ps), policy); err != nil {
+		return nil, fmt.Errorf("Could not unmarshal %s: %v", ps, err)
+	}
+
+	for _, b := range policy.Bindings {
+		bindings = append(bindings, caiasset.IAMBinding{
+			Role:      b.Role,
+			Members:   b.Members,
+			Condition: convertIamCondition(b.Condition),
+		})
+	}
+
+	return bindings, nil
+}
+
+// ExpandIamRoleBindings is used in google_<type>_iam_binding resources.
+func ExpandIamRoleBindings(d tpgresource.TerraformResourceData) ([]caiasset.IAMBinding, error) {
+	var members []string
+	for _, m := range d.Get("members").(*schema.Set).List() {
+		members = append(members, m.(string))
+	}
+	sort.Strings(members)
+	return []caiasset.IAMBinding{
+		{
+			Role:      d.Get("role").(string),
+			Members:   members,
+			Condition: expandIamCondition(d),
+		},
+	}, nil
+}
+
+// ExpandIamMemberBindings is used in google_<type>_iam_member resources.
+func ExpandIamMemberBindings(d tpgresource.TerraformResourceData) ([]caiasset.IAMBinding, error) {
+	return []caiasset.IAMBinding{
+		{
+			Role:      d.Get("role").(string),
+			Members:   []string{d.Get("member").(string)},
+			Condition: expandIamCondition(d),
+		},
+	}, nil
+}
+
+// expandIamCondition returns the condition of a google_<type>_iam_binding or
+// google_<type>_iam_member resource, or nil if it has none.
+func expandIamCondition(d tpgresource.TerraformResourceData) *caiasset.Expr {
+	l, ok := d.Get("condition").([]interface{})
+	if !ok || len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	c := l[0].(map[string]interface{})
+	expr := &caiasset.Expr{}
+	expr.Expression, _ = c["expression"].(string)
+	expr.Title, _ = c["title"].(string)
+	expr.Description, _ = c["description"].(string)
+	return expr
+}
+
+func convertIamCondition(c *cloudresourcemanager.Expr) *caiasset.Expr {
+	if c == nil {
+		return nil
+	}
+	return &caiasset.Expr{
+		Expression:  c.Expression,
+		Title:       c.Title,
+		Description: c.Description,
+		Location:    c.Location,
+	}
+}
+
+// iamBindingKey identifies the binding of a role under a condition. Bindings
+// of the same role with different conditions are distinct in a policy.
+func iamBindingKey(b caiasset.IAMBinding) string {
+	if b.Condition == nil {
+		return b.Role
+	}
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s", b.Role, b.Condition.Expression, b.Condition.Title, b.Condition.Description)
+}
+
+// MergeIamAssets merges an existing asset with the IAM bindings of an incoming
+// Asset.
+func MergeIamAssets(
+	existing, incoming caiasset.Asset,
+	MergeBindings func(existing, incoming []caiasset.IAMBinding) []caiasset.IAMBinding,
+) caiasset.Asset {
+	if existing.IAMPolicy != nil {
+		existing.IAMPolicy.Bindings = MergeBindings(existing.IAMPolicy.Bindings, incoming.IAMPolicy.Bindings)
+	} else {
+		existing.IAMPolicy = incoming.IAMPolicy
+	}
+	return existing
+}
+
+// MergeDeleteIamAssets removes the IAM bindings of an incoming asset from an
+// existing asset. incoming is the last known state of an asset prior to deletion.
+func MergeDeleteIamAssets(
+	existing, incoming caiasset.Asset,
+	MergeBindings func(existing, incoming []caiasset.IAMBinding) []caiasset.IAMBinding,
+) caiasset.Asset {
+	if existing.IAMPolicy != nil {
+		existing.IAMPolicy.Bindings = MergeBindings(existing.IAMPolicy.Bindings, incoming.IAMPolicy.Bindings)
+	}
+	return existing
+}
+
+// MergeIamPolicy replaces the IAM policy of an existing asset with the IAM
+// policy of an incoming asset, as google_<type>_iam_policy resources are
+// authoritative for the whole policy.
+func MergeIamPolicy(existing, incoming caiasset.Asset) caiasset.Asset {
+	existing.IAMPolicy = incoming.IAMPolicy
+	return existing
+}
+
+// MergeAdditiveBindings adds members to bindings with the same roles and
+// conditions and adds new bindings for the ones that dont exist.
+func MergeAdditiveBindings(existing, incoming []caiasset.IAMBinding) []caiasset.IAMBinding {
+	existingIdxs := make(map[string]int)
+	for i, binding := range existing {
+		existingIdxs[iamBindingKey(binding)] = i
+	}
+
+	for _, binding := range incoming {
+		if ei, ok := existingIdxs[iamBindingKey(binding)]; ok {
+			memberExists := make(map[string]bool)
+			for _, m := range existing[ei].Members {
+				memberExists[m] = true
+			}
+			for _, m := range binding.Members {
+				// Only add members that don't exist.
+				if !memberExists[m] {
+					existing[ei].Members = append(existing[ei].Members, m)
+				}
+			}
+		} else {
+			existingIdxs[iamBindingKey(binding)] = len(existing)
+			existing = append(existing, binding)
+		}
+	}
+
+	// Sort members
+	for i := range existing {
+		sort.Strings(existing[i].Members)
+	}
+
+	return existing
+}
+
+// MergeDeleteAdditiveBindings eliminates listed members from the bindings with
+// the same roles and conditions in the existing list. incoming is the last
+// known state of the bindings being deleted.
+func MergeDeleteAdditiveBindings(existing, incoming []caiasset.IAMBinding) []caiasset.IAMBinding {
+	toDelete := make(map[string]struct{})
+	for _, binding := range incoming {
+		for _, m := range binding.Members {
+			key := iamBindingKey(binding) + "-" + m
+			toDelete[key] = struct{}{}
+		}
+	}
+
+	var newExisting []caiasset.IAMBinding
+	for _, binding := range existing {
+		var newMembers []string
+		for _, m := range binding.Members {
+			key := iamBindingKey(binding) + "-" + m
+			_, delete := toDelete[key]
+			if !delete {
+				newMembers = append(newMembers, m)
+			}
+		}
+		if newMembers != nil {
+			newExisting = append(newExisting, caiasset.IAMBinding{
+				Role:      binding.Role,
+				Members:   newMembers,
+				Condition: binding.Condition,
+			})
+		}
+	}
+
+	return newExisting
+}
+
+// MergeAuthoritativeBindings clobbers members to bindings with the same roles
+// and conditions and adds new bindings for the ones that dont exist.
+func MergeAuthoritativeBindings(existing, incoming []caiasset.IAMBinding) []caiasset.IAMBinding {
+	existingIdxs := make(map[string]int)
+	for i, binding := range existing {
+		existingIdxs[iamBindingKey(binding)] = i
+	}
+
+	for _, binding := range incoming {
+		if ei, ok := existingIdxs[iamBindingKey(binding)]; ok {
+			existing[ei].Members = binding.Members
+		} else {
+			existingIdxs[iamBindingKey(binding)] = len(existing)
+			existing = append(existing, binding)
+		}
+	}
+
+	// Sort members
+	for i := range existing {
+		sort.Strings(existing[i].Members)
+	}
+
+	return existing
+}
+
+// MergeDeleteAuthoritativeBindings eliminates any bindings with matching roles
+// and conditions in the existing list. incoming is the last known state of the
+// bindings being deleted.
+func MergeDeleteAuthoritativeBindings(existing, incoming []caiasset.IAMBinding) []caiasset.IAMBinding {
+	toDelete := make(map[string]struct{})
+	for _, binding := range incoming {
+		key := iamBindingKey(binding)
+		toDelete[key] = struct{}{}
+	}
+
+	var newExisting []caiasset.IAMBinding
+	for _, binding := range existing {
+		key := iamBindingKey(binding)
+		_, delete := toDelete[key]
+		if !delete {
+			newExisting = append(newExisting, binding)
+		}
+	}
+
+	return newExisting
+}
+
+// FetchIamPolicy builds an asset from the IAM policy returned by getPolicy,
+// which reads the current IAM policy of the resource from the API.
+func FetchIamPolicy(
+	getPolicy func() (*cloudresourcemanager.Policy, error),
+	d tpgresource.TerraformResourceData,
+	config *transport_tpg.Config,
+	assetNameTmpl string,
+	assetType string,
+) (caiasset.Asset, error) {
+	iamPolicy, err := getPolicy()
+	if transport_tpg.IsGoogleApiErrorWithCode(err, 403) || transport_tpg.IsGoogleApiErrorWithCode(err, 404) {
+		return caiasset.Asset{}, ErrResourceInaccessible
+	}
+
+	if err != nil {
+		return caiasset.Asset{}, err
+	}
+
+	var bindings []caiasset.IAMBinding
+	for _, b := range iamPolicy.Bindings {
+		bindings = append(
+			bindings,
+			caiasset.IAMBinding{
+				Role:      b.Role,
+				Members:   b.Members,
+				Condition: convertIamCondition(b.Condition),
+			},
+		)
+	}
+
+	name, err := AssetName(d, config, assetNameTmpl)
+	if err != nil {
+		return caiasset.Asset{}, err
+	}
+
+	return caiasset.Asset{
+		Name: name,
+		Type: assetType,
+		IAMPolicy: &caiasset.IAMPolicy{
+			Bindings: bindings,
+		},
+	}, nil
+}
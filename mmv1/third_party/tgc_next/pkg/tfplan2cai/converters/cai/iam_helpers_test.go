@@ -0,0 +1,424 @@
+package cai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+)
+
+func TestMergeBindings(t *testing.T) {
+	cases := []struct {
+		name string
+		// Inputs
+		existing []caiasset.IAMBinding
+		incoming []caiasset.IAMBinding
+		// Expected outputs
+		expectedAdditive      []caiasset.IAMBinding
+		expectedAuthoritative []caiasset.IAMBinding
+	}{
+		{
+			name:                  "EmptyAddEmpty",
+			existing:              []caiasset.IAMBinding{},
+			incoming:              []caiasset.IAMBinding{},
+			expectedAdditive:      []caiasset.IAMBinding{},
+			expectedAuthoritative: []caiasset.IAMBinding{},
+		},
+		{
+			name:     "EmptyAddOne",
+			existing: []caiasset.IAMBinding{},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			expectedAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			expectedAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+		},
+		{
+			name: "OneAddEmpty",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{},
+			expectedAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			expectedAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+		},
+		{
+			name: "OneAddOne",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-b"},
+				},
+			},
+			expectedAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+			},
+			expectedAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-b"},
+				},
+			},
+		},
+		{
+			name:     "EmptyAddSameRoleTwice",
+			existing: []caiasset.IAMBinding{},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-b"},
+				},
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			expectedAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+			},
+			expectedAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+		},
+		{
+			name: "GrandFinale",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+				{
+					Role:    "role-b",
+					Members: []string{"member-c", "member-d"},
+				},
+				{
+					Role:    "role-c",
+					Members: []string{"member-c"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b", "member-c"},
+				},
+				{
+					Role:    "role-b",
+					Members: []string{"member-b", "member-c"},
+				},
+			},
+			expectedAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b", "member-c"},
+				},
+				{
+					Role:    "role-b",
+					Members: []string{"member-b", "member-c", "member-d"},
+				},
+				{
+					Role:    "role-c",
+					Members: []string{"member-c"},
+				},
+			},
+			expectedAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b", "member-c"},
+				},
+				{
+					Role:    "role-b",
+					Members: []string{"member-b", "member-c"},
+				},
+				{
+					Role:    "role-c",
+					Members: []string{"member-c"},
+				},
+			},
+		},
+		{
+			name: "SameRoleOtherCondition",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-b"},
+					Condition: &caiasset.Expr{Title: "expires", Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:      "role-a",
+					Members:   []string{"member-c"},
+					Condition: &caiasset.Expr{Title: "expires", Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-d"},
+					Condition: &caiasset.Expr{Title: "other", Expression: "true"},
+				},
+			},
+			expectedAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-b", "member-c"},
+					Condition: &caiasset.Expr{Title: "expires", Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-d"},
+					Condition: &caiasset.Expr{Title: "other", Expression: "true"},
+				},
+			},
+			expectedAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-c"},
+					Condition: &caiasset.Expr{Title: "expires", Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-d"},
+					Condition: &caiasset.Expr{Title: "other", Expression: "true"},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name+"/MergeAdditiveBindings", func(t *testing.T) {
+			assert.EqualValues(t,
+				c.expectedAdditive,
+				MergeAdditiveBindings(c.existing, c.incoming),
+			)
+		})
+		t.Run(c.name+"/MergeAuthoritativeBindings", func(t *testing.T) {
+			assert.EqualValues(t,
+				c.expectedAuthoritative,
+				MergeAuthoritativeBindings(c.existing, c.incoming),
+			)
+		})
+	}
+}
+
+func TestMergeDeleteBindings(t *testing.T) {
+	cases := []struct {
+		name string
+		// Inputs
+		existing []caiasset.IAMBinding
+		incoming []caiasset.IAMBinding
+		// Expected outputs
+		expectedDeleteAdditive      []caiasset.IAMBinding
+		expectedDeleteAuthoritative []caiasset.IAMBinding
+	}{
+		{
+			name:                        "EmptyDeleteEmpty",
+			existing:                    []caiasset.IAMBinding{},
+			incoming:                    []caiasset.IAMBinding{},
+			expectedDeleteAdditive:      nil,
+			expectedDeleteAuthoritative: nil,
+		},
+		{
+			name:     "EmptyDeleteOne",
+			existing: []caiasset.IAMBinding{},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			expectedDeleteAdditive:      nil,
+			expectedDeleteAuthoritative: nil,
+		},
+		{
+			name: "OneDeleteEmpty",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{},
+			expectedDeleteAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			expectedDeleteAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+		},
+		{
+			name: "OneDeleteOne",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-b"},
+				},
+			},
+			expectedDeleteAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a"},
+				},
+			},
+			expectedDeleteAuthoritative: nil,
+		},
+		{
+			name: "GrandFinale",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+				{
+					Role:    "role-b",
+					Members: []string{"member-c", "member-d"},
+				},
+				{
+					Role:    "role-c",
+					Members: []string{"member-c"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b", "member-c"},
+				},
+				{
+					Role:    "role-b",
+					Members: []string{"member-b", "member-c"},
+				},
+			},
+			expectedDeleteAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-b",
+					Members: []string{"member-d"},
+				},
+				{
+					Role:    "role-c",
+					Members: []string{"member-c"},
+				},
+			},
+			expectedDeleteAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-c",
+					Members: []string{"member-c"},
+				},
+			},
+		},
+		{
+			name: "SameRoleOtherCondition",
+			existing: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-a", "member-c"},
+					Condition: &caiasset.Expr{Title: "expires", Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"},
+				},
+			},
+			incoming: []caiasset.IAMBinding{
+				{
+					Role:      "role-a",
+					Members:   []string{"member-a"},
+					Condition: &caiasset.Expr{Title: "expires", Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"},
+				},
+			},
+			expectedDeleteAdditive: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+				{
+					Role:      "role-a",
+					Members:   []string{"member-c"},
+					Condition: &caiasset.Expr{Title: "expires", Expression: "request.time < timestamp('2030-01-01T00:00:00Z')"},
+				},
+			},
+			expectedDeleteAuthoritative: []caiasset.IAMBinding{
+				{
+					Role:    "role-a",
+					Members: []string{"member-a", "member-b"},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name+"/MergeDeleteAdditiveBindings", func(t *testing.T) {
+			assert.EqualValues(t,
+				c.expectedDeleteAdditive,
+				MergeDeleteAdditiveBindings(c.existing, c.incoming),
+			)
+		})
+		t.Run(c.name+"/MergeDeleteAuthoritativeBindings", func(t *testing.T) {
+			assert.EqualValues(t,
+				c.expectedDeleteAuthoritative,
+				MergeDeleteAuthoritativeBindings(c.existing, c.incoming),
+			)
+		})
+	}
+}
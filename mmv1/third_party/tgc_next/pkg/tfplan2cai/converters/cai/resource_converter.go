@@ -14,7 +14,14 @@ type ConvertFunc func(d tpgresource.TerraformResourceData, config *transport_tpg
 // by Terraform, like IAM policies managed with member/binding resources.
 type FetchFullResourceFunc func(d tpgresource.TerraformResourceData, config *transport_tpg.Config) (caiasset.Asset, error)
 
+// MergeFunc merges an incoming asset into an existing asset with the same name,
+// like the IAM bindings of a google_<type>_iam_member resource into the IAM
+// policy converted from other resources or fetched from the API.
+type MergeFunc func(existing, incoming caiasset.Asset) caiasset.Asset
+
 type Tfplan2caiConverter struct {
 	Convert           ConvertFunc
 	FetchFullResource FetchFullResourceFunc
+	MergeCreateUpdate MergeFunc
+	MergeDelete       MergeFunc
 }
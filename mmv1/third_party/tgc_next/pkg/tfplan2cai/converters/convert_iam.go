@@ -0,0 +1,176 @@
+package converters
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/ancestrymanager"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/converters/cai"
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/tfplan2cai/models"
+
+	transport_tpg "github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/transport"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Converts groups of IAM resources into CAI assets. The IAM resources of a
+// group target the same resource, and their bindings are merged into a single
+// IAM policy: deleted bindings and members are removed first, then
+// google_<type>_iam_policy resources replace the whole policy,
+// google_<type>_iam_binding resources replace the members of their role and
+// google_<type>_iam_member resources add their member to their role.
+//
+// If online, the bindings are merged into the current IAM policy of the
+// resource fetched from the API. If offline, the policy only contains the
+// bindings in the plan.
+func ConvertIamResources(groups [][]*models.FakeResourceDataWithMeta, cfg *transport_tpg.Config, am ancestrymanager.AncestryManager, offline bool, errLogger *zap.Logger) ([]caiasset.Asset, error) {
+	var assets []caiasset.Asset
+	for _, rdList := range groups {
+		groupAssets, err := convertIamGroup(rdList, cfg, am, offline, errLogger)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, groupAssets...)
+	}
+	return assets, nil
+}
+
+func convertIamGroup(rdList []*models.FakeResourceDataWithMeta, cfg *transport_tpg.Config, am ancestrymanager.AncestryManager, offline bool, errLogger *zap.Logger) ([]caiasset.Asset, error) {
+	rdList = slices.Clone(rdList)
+	sort.SliceStable(rdList, func(i, j int) bool {
+		return iamMergeOrder(rdList[i]) < iamMergeOrder(rdList[j])
+	})
+
+	// Merged assets by asset type and name, in the order they are first seen
+	var keys []string
+	merged := make(map[string]*caiasset.Asset)
+	// The first resource merged into each asset, used to look up its ancestors
+	resourceData := make(map[string]*models.FakeResourceDataWithMeta)
+	fetched := make(map[string]bool)
+	// The addresses of deleted resources merged before the asset is known
+	deletedAddresses := make(map[string][]string)
+
+	for _, rd := range rdList {
+		converter, ok := ConverterMap[rd.Kind()]
+		if !ok {
+			errLogger.Debug(fmt.Sprintf("%s: resource type cannot be converted for CAI-based policies: %s. For details, see https://cloud.google.com/docs/terraform/policy-validation/create-cai-constraints#supported_resources", rd.Address(), rd.Kind()))
+			continue
+		}
+
+		convertedAssets, err := converter.Convert(rd, cfg)
+		if err != nil {
+			if errors.Cause(err) == cai.ErrNoConversion {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, asset := range convertedAssets {
+			key := asset.Type + "/" + asset.Name
+			if _, ok := merged[key]; !ok && !fetched[key] && !offline && converter.FetchFullResource != nil {
+				fetched[key] = true
+				existing, err := fetchIamAsset(rd, converter, cfg, key, errLogger)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil {
+					keys = append(keys, key)
+					merged[key] = existing
+				}
+			}
+
+			existing, ok := merged[key]
+			if rd.IsDeleted() {
+				if !ok || converter.MergeDelete == nil {
+					// There are no known bindings to remove the deleted ones from,
+					// so the address is recorded on the asset if it is created.
+					deletedAddresses[key] = append(deletedAddresses[key], rd.Address())
+					continue
+				}
+				*existing = converter.MergeDelete(*existing, asset)
+			} else if !ok {
+				keys = append(keys, key)
+				existing = &asset
+				existing.TfplanAddress = append(deletedAddresses[key], existing.TfplanAddress...)
+				merged[key] = existing
+			} else {
+				if converter.MergeCreateUpdate == nil {
+					return nil, fmt.Errorf("%s: cannot merge %s into the IAM policy of %s", rd.Address(), rd.Kind(), asset.Name)
+				}
+				*existing = converter.MergeCreateUpdate(*existing, asset)
+			}
+
+			existing.TfplanAddress = append(existing.TfplanAddress, rd.Address())
+			if resourceData[key] == nil {
+				resourceData[key] = rd
+			}
+		}
+	}
+
+	var assets []caiasset.Asset
+	for _, key := range keys {
+		asset := *merged[key]
+		if err := am.SetAncestors(resourceData[key], cfg, &asset); err != nil {
+			return nil, err
+		}
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
+
+// Returns the order in which IAM resources are merged, so that the result
+// doesn't depend on the order of the resources in the plan.
+func iamMergeOrder(rd *models.FakeResourceDataWithMeta) int {
+	switch {
+	case rd.IsDeleted():
+		return 0
+	case strings.HasSuffix(rd.Kind(), "_iam_policy"):
+		return 1
+	case strings.HasSuffix(rd.Kind(), "_iam_binding"):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// Fetches the current IAM policy of the resource targeted by an IAM resource.
+// Returns nil if the policy can't be fetched because the resource isn't
+// created yet or is inaccessible.
+func fetchIamAsset(rd *models.FakeResourceDataWithMeta, converter cai.Tfplan2caiConverter, cfg *transport_tpg.Config, key string, errLogger *zap.Logger) (*caiasset.Asset, error) {
+	asset, err := converter.FetchFullResource(rd, cfg)
+	if errors.Cause(err) == cai.ErrEmptyIdentityField {
+		errLogger.Debug(fmt.Sprintf("%s: Unable to fetch and merge remote %s asset due to unset or (known after apply) identity fields on the TF resource.", rd.Address(), key))
+		return nil, nil
+	} else if errors.Cause(err) == cai.ErrResourceInaccessible {
+		errLogger.Warn(fmt.Sprintf("%s: Fetching %s for merge failed due to not existing or insufficient permission.", rd.Address(), key))
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("fetching remote asset %s: %w", key, err)
+	}
+	return &asset, nil
+}
+
+// Sets the IAM policies of iamAssets on the assets converted from the resources
+// they target, and appends the IAM assets targeting resources that aren't in
+// assets.
+func MergeIamPolicies(assets, iamAssets []caiasset.Asset) []caiasset.Asset {
+	indexes := make(map[string]int)
+	for i, asset := range assets {
+		indexes[asset.Type+"/"+asset.Name] = i
+	}
+
+	for _, iamAsset := range iamAssets {
+		i, ok := indexes[iamAsset.Type+"/"+iamAsset.Name]
+		if !ok {
+			assets = append(assets, iamAsset)
+			continue
+		}
+		assets[i].IAMPolicy = iamAsset.IAMPolicy
+		assets[i].TfplanAddress = append(assets[i].TfplanAddress, iamAsset.TfplanAddress...)
+	}
+	return assets
+}
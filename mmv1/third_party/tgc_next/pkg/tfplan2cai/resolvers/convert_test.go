@@ -184,3 +184,21 @@ func TestSortTraversalOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestConvert_iamDelete(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		t.Fatalf("Error initializing logger %s", err)
+	}
+	f := "../project_iam_delete.tfplan.json"
+	jsonPlan, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatalf("Error parsing %s: %s", f, err)
+	}
+
+	// Deleted IAM resources are grouped with the created ones by their prior state.
+	idToResourceChangeMap := NewIamAdvancedResolver(logger).Resolve(jsonPlan)
+
+	assert.Equal(t, 1, len(idToResourceChangeMap), "Expected map size is 1")
+	assert.Equal(t, 3, len(idToResourceChangeMap["project/test-project/"]), "Expected iam list to be size 3")
+}
@@ -32,13 +32,20 @@ func NewIamAdvancedResolver(errorLogger *zap.Logger) *IamAdvancedPreResolver {
 }
 
 func (r *IamAdvancedPreResolver) Resolve(jsonPlan []byte) map[string][]*tfjson.ResourceChange {
-	// Keys are resource IDs, and values are resource change objects.
-	idToResourceChange := make(map[string][]*tfjson.ResourceChange)
 	// ReadChanges
 	planChanges, err := tfplan.ReadResourceChanges(jsonPlan)
 	if err != nil {
-		return idToResourceChange
+		return make(map[string][]*tfjson.ResourceChange)
 	}
+	return r.ResolveChanges(jsonPlan, planChanges)
+}
+
+// ResolveChanges groups the IAM resource changes in planChanges by the resource
+// they target. The configuration of jsonPlan is used to resolve the references
+// to resources that are unknown until applied.
+func (r *IamAdvancedPreResolver) ResolveChanges(jsonPlan []byte, planChanges []*tfjson.ResourceChange) map[string][]*tfjson.ResourceChange {
+	// Keys are resource IDs, and values are resource change objects.
+	idToResourceChange := make(map[string][]*tfjson.ResourceChange)
 
 	// Read elements from the resouce config
 	resourceConfig, err := tfplan.ReadResourceConfigurations(jsonPlan)
@@ -65,8 +72,12 @@ func (r *IamAdvancedPreResolver) Resolve(jsonPlan []byte) map[string][]*tfjson.R
 		// Handle iam resources, build an id for each of them and group them together
 		if strings.Contains(rc.Type, "iam_member") || strings.Contains(rc.Type, "iam_binding") || strings.Contains(rc.Type, "iam_policy") {
 			var keys []string
-			// Take all keys from Change.After and store them in a list
+			// Take all keys from Change.After and store them in a list. Deleted
+			// resources have no Change.After, so Change.Before is used instead.
 			afterMap, ok := rc.Change.After.(map[string]interface{})
+			if !ok {
+				afterMap, ok = rc.Change.Before.(map[string]interface{})
+			}
 			if ok {
 				for k := range afterMap {
 					if !slices.Contains(filterList, k) {
@@ -99,9 +110,9 @@ func (r *IamAdvancedPreResolver) Resolve(jsonPlan []byte) map[string][]*tfjson.R
 
 				if _, ok = afterUnknownMap[key]; ok {
 					resourceId = resourceId + key + "/"
-					unknownValue := addressToExpressionMap[rc.Address][key].References[0]
-					resourceId = resourceId + unknownValue + "/"
-
+					if expression := addressToExpressionMap[rc.Address][key]; expression != nil && len(expression.References) > 0 {
+						resourceId = resourceId + expression.References[0] + "/"
+					}
 				}
 
 			}
@@ -15,6 +15,10 @@ package tfplan
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
 
 	tfjson "github.com/hashicorp/terraform-json"
 )
@@ -39,6 +43,58 @@ func IsNoOp(rc *tfjson.ResourceChange) bool {
 	return rc.Change.Actions.NoOp()
 }
 
+// Action returns the action planned for a resource change: create, update,
+// delete or replace. It returns "" for no-op and read changes.
+func Action(rc *tfjson.ResourceChange) string {
+	switch {
+	case IsCreate(rc):
+		return caiasset.ActionCreate
+	case IsUpdate(rc):
+		return caiasset.ActionUpdate
+	case IsDelete(rc):
+		return caiasset.ActionDelete
+	case rc.Change.Actions.Replace():
+		return caiasset.ActionReplace
+	default:
+		return ""
+	}
+}
+
+// UnknownAfterApply returns the sorted paths of the attributes of a resource
+// change whose values are unknown until the change is applied, like "id" or
+// "network_interface.0.network_ip".
+func UnknownAfterApply(rc *tfjson.ResourceChange) []string {
+	var paths []string
+	addUnknownPaths(rc.Change.AfterUnknown, "", &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+// addUnknownPaths adds the paths of the values of afterUnknown that are true.
+// Nested blocks and collections are maps and lists of such values.
+func addUnknownPaths(afterUnknown interface{}, path string, paths *[]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch v := afterUnknown.(type) {
+	case bool:
+		if v && path != "" {
+			*paths = append(*paths, path)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			addUnknownPaths(value, join(key), paths)
+		}
+	case []interface{}:
+		for i, value := range v {
+			addUnknownPaths(value, join(strconv.Itoa(i)), paths)
+		}
+	}
+}
+
 // ReadResourceChanges returns the list of resource changes from a json plan
 func ReadResourceChanges(data []byte) ([]*tfjson.ResourceChange, error) {
 	plan := tfjson.Plan{}
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/terraform-google-conversion/v7/pkg/caiasset"
+
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/stretchr/testify/require"
 )
 
@@ -169,3 +172,81 @@ func TestReadResourceChanges(t *testing.T) {
 	}
 	require.JSONEq(t, string(wantJSON), string(gotJSON))
 }
+
+func TestAction(t *testing.T) {
+	cases := []struct {
+		name    string
+		actions tfjson.Actions
+		want    string
+	}{
+		{name: "create", actions: tfjson.Actions{tfjson.ActionCreate}, want: caiasset.ActionCreate},
+		{name: "update", actions: tfjson.Actions{tfjson.ActionUpdate}, want: caiasset.ActionUpdate},
+		{name: "delete", actions: tfjson.Actions{tfjson.ActionDelete}, want: caiasset.ActionDelete},
+		{name: "delete then create", actions: tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate}, want: caiasset.ActionReplace},
+		{name: "create then delete", actions: tfjson.Actions{tfjson.ActionCreate, tfjson.ActionDelete}, want: caiasset.ActionReplace},
+		{name: "noop", actions: tfjson.Actions{tfjson.ActionNoop}, want: ""},
+		{name: "read", actions: tfjson.Actions{tfjson.ActionRead}, want: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rc := &tfjson.ResourceChange{Change: &tfjson.Change{Actions: c.actions}}
+			if got := Action(rc); got != c.want {
+				t.Errorf("Action(%v) = %q, want %q", c.actions, got, c.want)
+			}
+		})
+	}
+}
+
+func TestUnknownAfterApply(t *testing.T) {
+	cases := []struct {
+		name         string
+		afterUnknown string
+		want         []string
+	}{
+		{
+			name:         "not set",
+			afterUnknown: `null`,
+			want:         nil,
+		},
+		{
+			name:         "whole resource",
+			afterUnknown: `true`,
+			want:         nil,
+		},
+		{
+			name:         "known",
+			afterUnknown: `{"labels": {}, "name": false, "tags": []}`,
+			want:         nil,
+		},
+		{
+			name: "nested",
+			afterUnknown: `{
+				"id": true,
+				"name": false,
+				"labels": {"env": true},
+				"network_interface": [
+					{"network_ip": false, "access_config": [{"nat_ip": true}]},
+					{"network_ip": true}
+				],
+				"tags": [false, true]
+			}`,
+			want: []string{
+				"id",
+				"labels.env",
+				"network_interface.0.access_config.0.nat_ip",
+				"network_interface.1.network_ip",
+				"tags.1",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var afterUnknown interface{}
+			if err := json.Unmarshal([]byte(c.afterUnknown), &afterUnknown); err != nil {
+				t.Fatalf("parsing %s: %v", c.afterUnknown, err)
+			}
+			rc := &tfjson.ResourceChange{Change: &tfjson.Change{AfterUnknown: afterUnknown}}
+			require.Equal(t, c.want, UnknownAfterApply(rc))
+		})
+	}
+}
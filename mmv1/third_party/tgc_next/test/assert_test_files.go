@@ -184,8 +184,10 @@ func testSingleResource(t *testing.T, testName string, testData ResourceTestData
 	// Step 1: Use cai2hcl to convert export assets into a Terraform configuration (export config).
 	// Compare all of the fields in raw config are in export config.
 
+	// The export config is planned as new resources, so it must not import the exported resources.
 	exportConfigData, err := cai2hcl.Convert(assets, &cai2hcl.Options{
-		ErrorLogger: logger,
+		ErrorLogger:     logger,
+		AreNewResources: true,
 	})
 	if err != nil {
 		return fmt.Errorf("error when converting the export assets into export config: %v", err)
@@ -242,6 +244,19 @@ func testSingleResource(t *testing.T, testName string, testData ResourceTestData
 	}
 	log.Printf("%s: Step 1 passes for resource %s. All of the fields in raw config are in export config", testName, testData.ResourceAddress)
 
+	// Convert the export assets again with import blocks, as when adopting the
+	// exported resources, and check that every resource of the export config is imported.
+	importConfigData, err := cai2hcl.Convert(assets, &cai2hcl.Options{
+		ErrorLogger: logger,
+	})
+	if err != nil {
+		return fmt.Errorf("error when converting the export assets into import config: %v", err)
+	}
+	if err := compareImportConfig(importConfigData, exportConfigData); err != nil {
+		return fmt.Errorf("%s: %v", testName, err)
+	}
+	log.Printf("%s: The import config of resource %s imports every resource of the export config", testName, testData.ResourceAddress)
+
 	// Step 2
 	// Run a terraform plan using export_config.
 	// Use tfplan2cai to convert the generated plan into CAI assets (roundtrip_assets).
@@ -295,6 +310,38 @@ func testSingleResource(t *testing.T, testName string, testData ResourceTestData
 	return nil
 }
 
+// Checks that the import config, converted with import blocks, has the same
+// resources as the export config, and an import block with an ID for each.
+func compareImportConfig(importConfig, exportConfig []byte) error {
+	importResources, err := parseHCLBytes(importConfig, "import.tf")
+	if err != nil {
+		return fmt.Errorf("error when parsing the import config: %v", err)
+	}
+	exportResources, err := parseHCLBytes(exportConfig, "export.tf")
+	if err != nil {
+		return fmt.Errorf("error when parsing the export config: %v", err)
+	}
+	if diff := cmp.Diff(exportResources, importResources); diff != "" {
+		return fmt.Errorf("import config has different resources than the export config (-want +got): %s", diff)
+	}
+
+	imports, err := parseImportBlocks(importConfig, "import.tf")
+	if err != nil {
+		return fmt.Errorf("error when parsing the import blocks of the import config: %v", err)
+	}
+	var missing []string
+	for address := range exportResources {
+		if imports[address] == "" {
+			missing = append(missing, address)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing import blocks for resources %v in the import config", missing)
+	}
+	return nil
+}
+
 // Gets the ancestry cache for tfplan2cai conversion and the default project
 func getAncestryCache(assets []caiasset.Asset) (map[string]string, string) {
 	ancestryCache := make(map[string]string, 0)
@@ -565,7 +612,8 @@ func getRoundtripConfig(t *testing.T, testName string, tfDir string, ancestryCac
 	}
 
 	roundtripConfig, err := cai2hcl.Convert(roundtripAssetsCopy, &cai2hcl.Options{
-		ErrorLogger: logger,
+		ErrorLogger:     logger,
+		AreNewResources: true,
 	})
 	if err != nil {
 		return nil, nil, err
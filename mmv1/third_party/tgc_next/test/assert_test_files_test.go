@@ -139,3 +139,56 @@ func TestCheckDiffSuppressFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareImportConfig(t *testing.T) {
+	exportConfig := []byte(`
+resource "google_compute_network" "default" {
+  name = "default"
+}
+`)
+	cases := map[string]struct {
+		importConfig string
+		expectErr    bool
+	}{
+		"imported": {
+			importConfig: `
+import {
+  to = google_compute_network.default
+  id = "projects/my-project/global/networks/default"
+}
+resource "google_compute_network" "default" {
+  name = "default"
+}
+`,
+		},
+		"missing import block": {
+			importConfig: `
+resource "google_compute_network" "default" {
+  name = "default"
+}
+`,
+			expectErr: true,
+		},
+		"different resources": {
+			importConfig: `
+import {
+  to = google_compute_network.default
+  id = "projects/my-project/global/networks/default"
+}
+resource "google_compute_network" "default" {
+  name = "other"
+}
+`,
+			expectErr: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			err := compareImportConfig([]byte(tc.importConfig), exportConfig)
+			if tc.expectErr != (err != nil) {
+				t.Errorf("expected error: %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
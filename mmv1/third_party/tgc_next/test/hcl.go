@@ -53,6 +53,33 @@ func parseHCLBytes(src []byte, filePath string) (map[string]map[string]any, erro
 	return parsed, nil
 }
 
+// parseImportBlocks returns the IDs of the import blocks of src, keyed by the
+// address of the resource they import to.
+func parseImportBlocks(src []byte, filePath string) (map[string]string, error) {
+	parser := hclparse.NewParser()
+	hclFile, diags := parser.ParseHCL(src, filePath)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("parse HCL: %w", diags)
+	}
+
+	imports := make(map[string]string)
+	for _, block := range hclFile.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "import" {
+			continue
+		}
+		to, ok := block.Body.Attributes["to"]
+		if !ok {
+			return nil, fmt.Errorf("import block without `to` in %s", filePath)
+		}
+		var id string
+		if attr, ok := block.Body.Attributes["id"]; ok {
+			id, _ = getValue(attr.Expr).(string)
+		}
+		imports[fmt.Sprint(getValue(to.Expr))] = id
+	}
+	return imports, nil
+}
+
 // parseHCLBody recursively parses attributes and nested blocks from an HCL body.
 func parseHCLBody(body hcl.Body) (
 	attributes map[string]any,
@@ -257,3 +257,31 @@ func TestParseHCLBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseImportBlocks(t *testing.T) {
+	t.Parallel()
+	src := `
+import {
+  to = google_compute_network.default
+  id = "projects/my-project/global/networks/default"
+}
+import {
+  to = google_storage_bucket.bucket
+  id = "${var.project}/my-bucket"
+}
+resource "google_compute_network" "default" {
+  name = "default"
+}
+`
+	got, err := parseImportBlocks([]byte(src), "test.hcl")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp := map[string]string{
+		"google_compute_network.default": "projects/my-project/global/networks/default",
+		"google_storage_bucket.bucket":   "var.project/my-bucket",
+	}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("unexpected diff (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/yamlkeys"
+)
+
+const modulePath = "github.com/GoogleCloudPlatform/magic-modules/mmv1/"
+
+// Docs holds the doc comments of the structs YAML files are decoded into and
+// of their fields, keyed by "<package directory>.<type>" and
+// "<package directory>.<type>.<field>".
+type Docs map[string]string
+
+// LoadDocs reads doc comments from the Go sources of the api packages under
+// baseDirectory. Comments that can't be read are left empty.
+func LoadDocs(baseDirectory string) Docs {
+	docs := Docs{}
+	for _, dir := range []string{"api", "api/product", "api/resource"} {
+		fset := token.NewFileSet()
+		files, _ := filepath.Glob(filepath.Join(baseDirectory, dir, "*.go"))
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+			if err != nil {
+				continue
+			}
+			for _, decl := range f.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					spec := spec.(*ast.TypeSpec)
+					st, ok := spec.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					doc := spec.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					docs[dir+"."+spec.Name.Name] = cleanComment(doc.Text())
+					for _, field := range st.Fields.List {
+						text := cleanComment(field.Doc.Text())
+						if text == "" {
+							text = cleanComment(field.Comment.Text())
+						}
+						for _, name := range field.Names {
+							docs[dir+"."+spec.Name.Name+"."+name.Name] = text
+						}
+					}
+				}
+			}
+		}
+	}
+	return docs
+}
+
+// cleanComment drops the banner lines some structs use to group fields.
+func cleanComment(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Trim(line, "= ") == "" && strings.Contains(line, "=") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Type returns the doc comment of a struct.
+func (d Docs) Type(t reflect.Type) string {
+	return d[strings.TrimPrefix(t.PkgPath(), modulePath)+"."+t.Name()]
+}
+
+// Field returns the doc comment of a field.
+func (d Docs) Field(f yamlkeys.Field) string {
+	return d[strings.TrimPrefix(f.Owner.PkgPath(), modulePath)+"."+f.Owner.Name()+"."+f.Name]
+}
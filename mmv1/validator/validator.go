@@ -0,0 +1,548 @@
+// Package validator checks product and resource YAML files and reports every
+// problem it finds, each with the file, line and column it comes from.
+//
+// It runs in two passes. The schema pass walks each file's yaml.Node tree
+// alongside the Go type it is decoded into, catching unknown keys, values of
+// the wrong kind, duplicate keys, Enum defaults outside enum_values and
+// ResourceRef fields that reference a resource the product doesn't have. If
+// the schema pass is clean, products are loaded and each resource's own
+// Validate checks are collected as well.
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/loader"
+)
+
+// Rule identifiers, used as SARIF rule ids.
+const (
+	RuleSyntax           = "yaml-syntax"
+	RuleUnknownKey       = "unknown-key"
+	RuleDuplicateKey     = "duplicate-key"
+	RuleInvalidValue     = "invalid-value"
+	RuleEnumDefault      = "enum-default"
+	RuleDanglingRef      = "dangling-resource-ref"
+	RuleResourceSemantic = "resource"
+)
+
+var ruleDescriptions = map[string]string{
+	RuleSyntax:           "The file is not valid YAML.",
+	RuleUnknownKey:       "A key that doesn't correspond to any field, usually a typo.",
+	RuleDuplicateKey:     "A key that appears more than once in the same mapping.",
+	RuleInvalidValue:     "A value of the wrong kind for its field, such as a list where a string is expected.",
+	RuleEnumDefault:      "An Enum field whose default_value is not one of its enum_values.",
+	RuleDanglingRef:      "A ResourceRef field whose resource is not defined by any product or handwritten resource.",
+	RuleResourceSemantic: "A resource that fails the generator's own validation.",
+}
+
+// Diagnostic is a single problem in a YAML file. Line and Column are 1-based
+// and zero if the problem isn't tied to a position.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Rule    string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	location := d.File
+	if d.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	}
+	return fmt.Sprintf("%s: %s [%s]", location, d.Message, d.Rule)
+}
+
+type Config struct {
+	BaseDirectory     string // required; the mmv1 directory
+	OverrideDirectory string // optional
+	Version           string // optional; the version resources are loaded at for the semantic pass, defaults to ga
+	// Product limits the checks to the files of one product directory, such as
+	// products/compute. Other products are only read for the names of their
+	// resources, and the semantic pass loads the product and the products of
+	// its successors rather than every product.
+	Product string // optional
+}
+
+// Validate checks every product and resource YAML file under the configured
+// directories, or those of config.Product if it is set. Diagnostics are sorted by file and position. The returned
+// error is only set if the files could not be read.
+func Validate(config Config) ([]Diagnostic, error) {
+	if config.Version == "" {
+		config.Version = "ga"
+	}
+	v := &validator{baseDirectory: config.BaseDirectory, product: config.Product}
+	if err := v.addDirectory(config.BaseDirectory, false); err != nil {
+		return nil, err
+	}
+	if config.OverrideDirectory != "" {
+		if err := v.addDirectory(config.OverrideDirectory, true); err != nil {
+			return nil, err
+		}
+	}
+	v.checkResourceRefs()
+
+	if len(v.diagnostics) == 0 {
+		if err := v.semanticPass(config); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		a, b := v.diagnostics[i], v.diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return v.diagnostics, nil
+}
+
+// ValidateFile runs the schema pass over a single file decoded into obj's
+// type, for example an *api.Resource. References to other resources aren't
+// checked, since they need the rest of the product.
+func ValidateFile(file string, content []byte, obj interface{}) []Diagnostic {
+	v := &validator{}
+	v.validateContent(file, "", content, reflect.TypeOf(obj))
+	return v.diagnostics
+}
+
+type resourceRef struct {
+	file    string
+	node    *yaml.Node
+	product string
+}
+
+type validator struct {
+	baseDirectory string
+	product       string // the only product directory checked, if set
+	diagnostics   []Diagnostic
+
+	// resources holds the names of the resources in each product directory
+	// (products/<name>), across the base and override directories.
+	resources map[string]map[string]bool
+	refs      []resourceRef
+}
+
+var (
+	productType  = reflect.TypeOf(api.Product{})
+	resourceType = reflect.TypeOf(api.Resource{})
+	typeType     = reflect.TypeOf(api.Type{})
+)
+
+func (v *validator) addDirectory(dir string, override bool) error {
+	files, err := filepath.Glob(filepath.Join(dir, "products", "*", "*.yaml"))
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		// Base files are reported relative to the base directory, so that the
+		// paths match the repository; override files keep their full path.
+		file := filepath.ToSlash(rel)
+		if override {
+			file = filepath.ToSlash(path)
+		}
+		product := filepath.ToSlash(filepath.Dir(rel))
+		t := resourceType
+		if filepath.Base(path) == "product.yaml" {
+			t = productType
+		}
+		if v.product != "" && product != v.product {
+			// Resource refs may still point into other products.
+			if t == resourceType {
+				var doc yaml.Node
+				if yaml.Unmarshal(content, &doc) == nil && len(doc.Content) > 0 {
+					v.indexResource(product, doc.Content[0])
+				}
+			}
+			continue
+		}
+		v.validateContent(file, product, content, t)
+	}
+	return nil
+}
+
+func (v *validator) validateContent(file, product string, content []byte, t reflect.Type) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		line, message := splitYamlError(err)
+		v.report(file, &yaml.Node{Line: line, Column: 1}, RuleSyntax, message)
+		return
+	}
+	if len(doc.Content) == 0 {
+		return
+	}
+	root := doc.Content[0]
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == resourceType && product != "" {
+		v.indexResource(product, root)
+	}
+	v.walk(file, product, root, t)
+}
+
+// indexResource records the name of the resource decoded from root as one of
+// product's resources.
+func (v *validator) indexResource(product string, root *yaml.Node) {
+	name := mappingValue(root, "name")
+	if name == nil {
+		return
+	}
+	if v.resources == nil {
+		v.resources = map[string]map[string]bool{}
+	}
+	if v.resources[product] == nil {
+		v.resources[product] = map[string]bool{}
+	}
+	v.resources[product][name.Value] = true
+}
+
+// walk checks node against the Go type it will be decoded into.
+func (v *validator) walk(file, product string, node *yaml.Node, t reflect.Type) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if isNull(node) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			v.report(file, node, RuleInvalidValue, fmt.Sprintf("expected a mapping, got %s", describe(node)))
+			return
+		}
+		fields := knownFields(t)
+		seen := map[string]bool{}
+		v.walkMapping(file, product, node, t, fields, seen)
+		if t == typeType {
+			v.checkType(file, product, node)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.report(file, node, RuleInvalidValue, fmt.Sprintf("expected a list, got %s", describe(node)))
+			return
+		}
+		for _, item := range node.Content {
+			v.walk(file, product, item, t.Elem())
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.report(file, node, RuleInvalidValue, fmt.Sprintf("expected a mapping, got %s", describe(node)))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.walk(file, product, node.Content[i+1], t.Elem())
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			v.report(file, node, RuleInvalidValue, fmt.Sprintf("expected a %s, got %s", t.Kind(), describe(node)))
+			return
+		}
+		if err := node.Decode(reflect.New(t).Interface()); err != nil {
+			_, message := splitYamlError(err)
+			v.report(file, node, RuleInvalidValue, message)
+		}
+	}
+}
+
+func (v *validator) walkMapping(file, product string, node *yaml.Node, t reflect.Type, fields map[string]reflect.Type, seen map[string]bool) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Value == "<<" && key.Tag == "!!merge" {
+			if value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			merged := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				merged = value.Content
+			}
+			for _, m := range merged {
+				if m.Kind == yaml.AliasNode {
+					m = m.Alias
+				}
+				if m.Kind == yaml.MappingNode {
+					v.walkMapping(file, product, m, t, fields, map[string]bool{})
+				}
+			}
+			continue
+		}
+		if seen[key.Value] {
+			v.report(file, key, RuleDuplicateKey, fmt.Sprintf("duplicate key `%s`", key.Value))
+		}
+		seen[key.Value] = true
+
+		ft, ok := fields[key.Value]
+		if !ok {
+			message := fmt.Sprintf("unknown key `%s` for %s", key.Value, typeName(t))
+			if suggestion := closest(key.Value, fields); suggestion != "" {
+				message += fmt.Sprintf(" (did you mean `%s`?)", suggestion)
+			}
+			v.report(file, key, RuleUnknownKey, message)
+			continue
+		}
+		v.walk(file, product, value, ft)
+	}
+}
+
+// checkType checks constraints on an api.Type that span several of its keys.
+func (v *validator) checkType(file, product string, node *yaml.Node) {
+	typ := mappingValue(node, "type")
+	if typ == nil {
+		return
+	}
+	switch typ.Value {
+	case "Enum":
+		def, values := mappingValue(node, "default_value"), mappingValue(node, "enum_values")
+		if def == nil || values == nil || def.Kind != yaml.ScalarNode || values.Kind != yaml.SequenceNode || isNull(def) {
+			return
+		}
+		var allowed []string
+		for _, value := range values.Content {
+			if value.Value == def.Value {
+				return
+			}
+			allowed = append(allowed, value.Value)
+		}
+		v.report(file, def, RuleEnumDefault, fmt.Sprintf("default_value `%s` is not one of enum_values %s", def.Value, strings.Join(allowed, ", ")))
+	case "ResourceRef":
+		if ref := mappingValue(node, "resource"); ref != nil && product != "" {
+			v.refs = append(v.refs, resourceRef{file: file, node: ref, product: product})
+		}
+	}
+}
+
+// checkResourceRefs reports ResourceRef fields referencing a resource that
+// doesn't exist. References usually point within the product, but may point to
+// another product or to a handwritten resource.
+func (v *validator) checkResourceRefs() {
+	for _, ref := range v.refs {
+		name := ref.node.Value
+		if v.resources[ref.product][name] || v.definedElsewhere(name) {
+			continue
+		}
+		candidates := map[string]reflect.Type{}
+		for n := range v.resources[ref.product] {
+			candidates[n] = nil
+		}
+		message := fmt.Sprintf("resource `%s` is not defined in %s", name, ref.product)
+		if suggestion := closest(name, candidates); suggestion != "" {
+			message += fmt.Sprintf(" (did you mean `%s`?)", suggestion)
+		}
+		v.report(ref.file, ref.node, RuleDanglingRef, message)
+	}
+}
+
+func (v *validator) definedElsewhere(name string) bool {
+	for _, names := range v.resources {
+		if names[name] {
+			return true
+		}
+	}
+	if v.baseDirectory == "" {
+		return false
+	}
+	for _, pattern := range []string{"resource_*_%s.go*", "data_source_*_%s.go*"} {
+		matches, _ := filepath.Glob(filepath.Join(v.baseDirectory, "third_party", "terraform", "services", "*", fmt.Sprintf(pattern, google.Underscore(name))))
+		if len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// semanticPass loads products the way the generator does and collects the
+// errors each resource's Validate reports.
+func (v *validator) semanticPass(config Config) error {
+	ofs, err := google.NewOverlayFS(config.OverrideDirectory, config.BaseDirectory)
+	if err != nil {
+		return err
+	}
+	l := loader.NewLoader(loader.Config{Version: config.Version, BaseDirectory: config.BaseDirectory, OverrideDirectory: config.OverrideDirectory, Sysfs: loader.NewVarsReplacingFS(ofs)})
+	if config.Product != "" {
+		l.LoadProductsNamed(config.Product)
+	} else {
+		l.LoadProducts()
+	}
+	if err := l.AddExtraFields(); err != nil {
+		return err
+	}
+	for file, es := range l.ResourceErrors() {
+		// Successors' products are loaded, but not checked.
+		if config.Product != "" && filepath.ToSlash(filepath.Dir(file)) != config.Product {
+			continue
+		}
+		for _, e := range es {
+			v.diagnostics = append(v.diagnostics, Diagnostic{File: file, Rule: RuleResourceSemantic, Message: e.Error()})
+		}
+	}
+	return nil
+}
+
+func (v *validator) report(file string, node *yaml.Node, rule, message string) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{File: file, Line: node.Line, Column: node.Column, Rule: rule, Message: message})
+}
+
+var knownFieldsCache = map[reflect.Type]map[string]reflect.Type{}
+
+// knownFields returns the keys a struct accepts, following the same rules as
+// yaml.v3: the yaml tag name, the lowercased field name if there is none, and
+// the fields of inlined structs.
+func knownFields(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := knownFieldsCache[t]; ok {
+		return fields
+	}
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(","+opts+",", ",inline,") {
+			inner := f.Type
+			for inner.Kind() == reflect.Ptr {
+				inner = inner.Elem()
+			}
+			for k, ft := range knownFields(inner) {
+				fields[k] = ft
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	knownFieldsCache[t] = fields
+	return fields
+}
+
+// closest returns the candidate most similar to s, if one is close enough to
+// be a likely typo.
+func closest(s string, candidates map[string]reflect.Type) string {
+	var names []string
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	if len(s) < 6 {
+		bestDistance = 2
+	}
+	for _, name := range names {
+		if d := levenshtein(s, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value := node.Content[i+1]
+			if value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			return value
+		}
+	}
+	return nil
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func describe(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return fmt.Sprintf("%q", node.Value)
+	}
+}
+
+func typeName(t reflect.Type) string {
+	switch t {
+	case productType:
+		return "product"
+	case resourceType:
+		return "resource"
+	case typeType:
+		return "field"
+	}
+	return t.Name()
+}
+
+var yamlErrorRegexp = regexp.MustCompile(`^(?:yaml: )?(?:unmarshal errors:\s*)?line (\d+): (.*)$`)
+
+// splitYamlError extracts the line number yaml.v3 embeds in its messages.
+func splitYamlError(err error) (int, string) {
+	message := strings.TrimSpace(err.Error())
+	if m := yamlErrorRegexp.FindStringSubmatch(message); m != nil {
+		var line int
+		fmt.Sscanf(m[1], "%d", &line)
+		return line, m[2]
+	}
+	return 0, strings.TrimPrefix(message, "yaml: ")
+}
@@ -0,0 +1,312 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+)
+
+const testProduct = `name: Widgets
+versions:
+  - name: ga
+    base_url: https://widgets.googleapis.com/v1/
+scopes:
+  - https://www.googleapis.com/auth/cloud-platform
+`
+
+const testResource = `name: Widget
+description: A widget.
+base_url: projects/{{project}}/widgets
+self_link: projects/{{project}}/widgets/{{name}}
+create_url: projects/{{project}}/widgets?widgetId={{name}}
+properties:
+  - name: name
+    type: String
+    required: true
+`
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+		want  []Diagnostic
+	}{
+		{
+			name: "valid",
+			files: map[string]string{
+				"products/widgets/product.yaml": testProduct,
+				"products/widgets/Widget.yaml":  testResource,
+			},
+		},
+		{
+			name: "unknown keys",
+			files: map[string]string{
+				"products/widgets/product.yaml": testProduct + "packagepath: widgets\n",
+				"products/widgets/Widget.yaml": testResource + `  - name: size
+    type: Integer
+    requried: true
+iam_policy:
+  method_name_separator: ':'
+  method_name_suffix: Widget
+`,
+			},
+			want: []Diagnostic{
+				{File: "products/widgets/Widget.yaml", Line: 12, Column: 5, Rule: RuleUnknownKey, Message: "unknown key `requried` for field (did you mean `required`?)"},
+				{File: "products/widgets/Widget.yaml", Line: 15, Column: 3, Rule: RuleUnknownKey, Message: "unknown key `method_name_suffix` for IamPolicy"},
+				{File: "products/widgets/product.yaml", Line: 7, Column: 1, Rule: RuleUnknownKey, Message: "unknown key `packagepath` for product (did you mean `package_path`?)"},
+			},
+		},
+		{
+			name: "enum default",
+			files: map[string]string{
+				"products/widgets/product.yaml": testProduct,
+				"products/widgets/Widget.yaml": testResource + `  - name: color
+    type: Enum
+    enum_values:
+      - RED
+      - BLUE
+    default_value: GREEN
+  - name: shapes
+    type: Array
+    item_type:
+      type: Enum
+      enum_values:
+        - ROUND
+      default_value: ROUND
+`,
+			},
+			want: []Diagnostic{
+				{File: "products/widgets/Widget.yaml", Line: 15, Column: 20, Rule: RuleEnumDefault, Message: "default_value `GREEN` is not one of enum_values RED, BLUE"},
+			},
+		},
+		{
+			name: "resource refs",
+			files: map[string]string{
+				"products/widgets/product.yaml": testProduct,
+				"products/widgets/Widget.yaml": testResource + `  - name: gadget
+    type: ResourceRef
+    resource: Gadget
+    imports: name
+  - name: sprocket
+    type: ResourceRef
+    resource: sprocket
+    imports: name
+  - name: network
+    type: ResourceRef
+    resource: Network
+    imports: selfLink
+  - name: pool
+    type: ResourceRef
+    resource: TargetPool
+    imports: selfLink
+  - name: missing
+    type: ResourceRef
+    resource: Gizmo
+    imports: name
+`,
+				"products/widgets/Gadget.yaml":                                                "name: Gadget\n",
+				"products/widgets/Sprocket.yaml":                                              "name: Sprocket\n",
+				"products/compute/product.yaml":                                               "name: Compute\n",
+				"products/compute/Network.yaml":                                               "name: Network\n",
+				"third_party/terraform/services/compute/resource_compute_target_pool.go.tmpl": "",
+			},
+			want: []Diagnostic{
+				{File: "products/widgets/Widget.yaml", Line: 16, Column: 15, Rule: RuleDanglingRef, Message: "resource `sprocket` is not defined in products/widgets (did you mean `Sprocket`?)"},
+				{File: "products/widgets/Widget.yaml", Line: 28, Column: 15, Rule: RuleDanglingRef, Message: "resource `Gizmo` is not defined in products/widgets"},
+			},
+		},
+		{
+			name: "invalid values and duplicates",
+			files: map[string]string{
+				"products/widgets/product.yaml": testProduct,
+				"products/widgets/Widget.yaml": testResource + `  - name: size
+    type: Integer
+    required: maybe
+    required: true
+  - name: labels
+    type: KeyValueLabels
+    properties: labels
+`,
+			},
+			want: []Diagnostic{
+				{File: "products/widgets/Widget.yaml", Line: 12, Column: 15, Rule: RuleInvalidValue, Message: "cannot unmarshal !!str `maybe` into bool"},
+				{File: "products/widgets/Widget.yaml", Line: 13, Column: 5, Rule: RuleDuplicateKey, Message: "duplicate key `required`"},
+				{File: "products/widgets/Widget.yaml", Line: 16, Column: 17, Rule: RuleInvalidValue, Message: "expected a list, got \"labels\""},
+			},
+		},
+		{
+			name: "syntax error",
+			files: map[string]string{
+				"products/widgets/product.yaml": testProduct,
+				"products/widgets/Widget.yaml":  testResource + "  - name: [\n",
+			},
+			want: []Diagnostic{
+				{File: "products/widgets/Widget.yaml", Line: 10, Column: 1, Rule: RuleSyntax, Message: "did not find expected node content"},
+			},
+		},
+		{
+			name: "resource validation",
+			files: map[string]string{
+				"products/widgets/product.yaml": testProduct,
+				"products/widgets/Widget.yaml":  strings.Replace(testResource, "description: A widget.\n", "", 1),
+			},
+			want: []Diagnostic{
+				{File: "products/widgets/Widget.yaml", Rule: RuleResourceSemantic, Message: "missing `description` for resource Widget"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Validate(Config{BaseDirectory: writeTree(t, tc.files)})
+			if err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Validate() diagnostics differ (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	base := writeTree(t, map[string]string{
+		"products/widgets/product.yaml": testProduct,
+		"products/widgets/Widget.yaml":  testResource,
+	})
+	overrides := writeTree(t, map[string]string{
+		"products/widgets/Gadget.yaml": strings.Replace(testResource, "Widget", "Gadget", 1) + `  - name: widget
+    type: ResourceRef
+    resource: Widget
+    imports: name
+    exclde: true
+`,
+	})
+
+	got, err := Validate(Config{BaseDirectory: base, OverrideDirectory: overrides})
+	if err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	want := []Diagnostic{
+		{File: filepath.ToSlash(filepath.Join(overrides, "products/widgets/Gadget.yaml")), Line: 14, Column: 5, Rule: RuleUnknownKey, Message: "unknown key `exclde` for field (did you mean `exclude`?)"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Validate() diagnostics differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateProduct(t *testing.T) {
+	base := writeTree(t, map[string]string{
+		"products/widgets/product.yaml": testProduct,
+		"products/widgets/Widget.yaml": strings.Replace(testResource, "description: A widget.\n", "", 1) + `  - name: gizmo
+    type: ResourceRef
+    resource: Gizmo
+    imports: name
+`,
+		"products/gizmos/product.yaml": strings.Replace(testProduct, "Widgets", "Gizmos", 1),
+		"products/gizmos/Gizmo.yaml":   "name: Gizmo\nexclde: true\n",
+	})
+
+	got, err := Validate(Config{BaseDirectory: base, Product: "products/widgets"})
+	if err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	want := []Diagnostic{
+		{File: "products/widgets/Widget.yaml", Rule: RuleResourceSemantic, Message: "missing `description` for resource Widget"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Validate() diagnostics differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	got := ValidateFile("Widget.yaml", []byte("name: Widget\nasync:\n  operation:\n    result:\n      resource_inside_response: false\n"), &api.Resource{})
+	want := []Diagnostic{
+		{File: "Widget.yaml", Line: 4, Column: 5, Rule: RuleUnknownKey, Message: "unknown key `result` for Operation"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ValidateFile() diagnostics differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteText(&buf, []Diagnostic{
+		{File: "products/widgets/Widget.yaml", Line: 3, Column: 5, Rule: RuleUnknownKey, Message: "unknown key `requried`"},
+		{File: "products/widgets/Gadget.yaml", Rule: RuleResourceSemantic, Message: "missing `description`"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "products/widgets/Widget.yaml:3:5: unknown key `requried` [unknown-key]\n" +
+		"products/widgets/Gadget.yaml: missing `description` [resource]\n"
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteText() differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteSARIF(&buf, []Diagnostic{
+		{File: "products/widgets/Widget.yaml", Line: 3, Column: 5, Rule: RuleUnknownKey, Message: "unknown key `requried`"},
+		{File: "products/widgets/Gadget.yaml", Rule: RuleResourceSemantic, Message: "missing `description`"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("WriteSARIF() wrote invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("WriteSARIF() wrote version %q with %d runs, want 2.1.0 with 1 run", log.Version, len(log.Runs))
+	}
+	if got, want := len(log.Runs[0].Tool.Driver.Rules), len(ruleDescriptions); got != want {
+		t.Errorf("WriteSARIF() described %d rules, want %d", got, want)
+	}
+	want := []sarifResult{
+		{
+			RuleID:  RuleUnknownKey,
+			Level:   "error",
+			Message: sarifMessage{Text: "unknown key `requried`"},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "products/widgets/Widget.yaml"},
+				Region:           &sarifRegion{StartLine: 3, StartColumn: 5},
+			}}},
+		},
+		{
+			RuleID:  RuleResourceSemantic,
+			Level:   "error",
+			Message: sarifMessage{Text: "missing `description`"},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "products/widgets/Gadget.yaml"},
+			}}},
+		},
+	}
+	if diff := cmp.Diff(want, log.Runs[0].Results); diff != "" {
+		t.Errorf("WriteSARIF() results differ (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,68 @@
+// Package yamlkeys lists the keys that the structs product and resource YAML
+// files are decoded into accept, for tools such as the language server and
+// the JSON Schema generator that describe those files.
+package yamlkeys
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Field is a key a struct accepts in YAML.
+type Field struct {
+	// Type is the Go type the value is decoded into.
+	Type reflect.Type
+	// Owner and Name identify the struct field, which may be declared by an
+	// inlined struct rather than the struct that was asked about.
+	Owner reflect.Type
+	Name  string
+}
+
+var (
+	fieldsMu    sync.Mutex
+	fieldsCache = map[reflect.Type]map[string]Field{}
+)
+
+// Fields returns the keys a struct accepts, following the same rules as
+// yaml.v3: the yaml tag name, the lowercased field name if there is none, and
+// the fields of inlined structs.
+func Fields(t reflect.Type) map[string]Field {
+	fieldsMu.Lock()
+	defer fieldsMu.Unlock()
+	return fields(t)
+}
+
+func fields(t reflect.Type) map[string]Field {
+	if fs, ok := fieldsCache[t]; ok {
+		return fs
+	}
+	fs := map[string]Field{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if strings.Contains(","+opts+",", ",inline,") {
+			inner := f.Type
+			for inner.Kind() == reflect.Ptr {
+				inner = inner.Elem()
+			}
+			for k, field := range fields(inner) {
+				fs[k] = field
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fs[name] = Field{Type: f.Type, Owner: t, Name: f.Name}
+	}
+	fieldsCache[t] = fs
+	return fs
+}
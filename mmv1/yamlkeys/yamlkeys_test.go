@@ -0,0 +1,30 @@
+package yamlkeys
+
+import (
+	"reflect"
+	"testing"
+)
+
+type inlined struct {
+	Inner string `yaml:"inner"`
+}
+
+type example struct {
+	Tagged   string `yaml:"tagged_name,omitempty"`
+	Untagged int
+	Skipped  string `yaml:"-"`
+	hidden   string
+	inlined  `yaml:",inline"`
+}
+
+func TestFields(t *testing.T) {
+	exampleType := reflect.TypeOf(example{})
+	want := map[string]Field{
+		"tagged_name": {Type: reflect.TypeOf(""), Owner: exampleType, Name: "Tagged"},
+		"untagged":    {Type: reflect.TypeOf(0), Owner: exampleType, Name: "Untagged"},
+		"inner":       {Type: reflect.TypeOf(""), Owner: reflect.TypeOf(inlined{}), Name: "Inner"},
+	}
+	if got := Fields(exampleType); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+}
@@ -2,6 +2,7 @@ package breaking_changes
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
 )
@@ -23,12 +24,43 @@ func NewBreakingChange(message, identifier string) BreakingChange {
 	}
 }
 
+// RuleIdentifiers returns the identifiers of every rule, which findings and
+// allowlist entries refer to.
+func RuleIdentifiers() []string {
+	var identifiers []string
+	for _, r := range ResourceConfigDiffRules {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	for _, r := range ResourceDiffRules {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	for _, r := range FieldDiffRules {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	for _, r := range FunctionDiffRules {
+		identifiers = append(identifiers, r.Identifier)
+	}
+	return identifiers
+}
+
+// messageFieldRegexp matches the field that resource-level rules name at the
+// start of their messages.
+var messageFieldRegexp = regexp.MustCompile("^Field `([^`]+)`")
+
+func newBreakingChange(message, identifier, resource, field string) BreakingChange {
+	breakingChange := NewBreakingChange(message, identifier)
+	breakingChange.Resource = resource
+	breakingChange.Field = field
+	breakingChange.RuleName = identifier
+	return breakingChange
+}
+
 func ComputeBreakingChanges(schemaDiff diff.SchemaDiff) []BreakingChange {
 	var breakingChanges []BreakingChange
 	for resource, resourceDiff := range schemaDiff {
 		for _, rule := range ResourceConfigDiffRules {
 			for _, message := range rule.Messages(resource, resourceDiff.ResourceConfig) {
-				breakingChanges = append(breakingChanges, NewBreakingChange(message, rule.Identifier))
+				breakingChanges = append(breakingChanges, newBreakingChange(message, rule.Identifier, resource, ""))
 			}
 		}
 
@@ -39,7 +71,11 @@ func ComputeBreakingChanges(schemaDiff diff.SchemaDiff) []BreakingChange {
 
 		for _, rule := range ResourceDiffRules {
 			for _, message := range rule.Messages(resource, resourceDiff) {
-				breakingChanges = append(breakingChanges, NewBreakingChange(message, rule.Identifier))
+				var field string
+				if m := messageFieldRegexp.FindStringSubmatch(message); m != nil {
+					field = m[1]
+				}
+				breakingChanges = append(breakingChanges, newBreakingChange(message, rule.Identifier, resource, field))
 			}
 		}
 
@@ -47,10 +83,22 @@ func ComputeBreakingChanges(schemaDiff diff.SchemaDiff) []BreakingChange {
 			for _, rule := range FieldDiffRules {
 				rd := schemaDiff[resource]
 				for _, message := range rule.Messages(resource, field, fieldDiff, rd) {
-					breakingChanges = append(breakingChanges, NewBreakingChange(message, rule.Identifier))
+					breakingChanges = append(breakingChanges, newBreakingChange(message, rule.Identifier, resource, field))
 				}
 			}
 		}
 	}
 	return breakingChanges
 }
+
+func ComputeFunctionBreakingChanges(functionSchemaDiff diff.FunctionSchemaDiff) []BreakingChange {
+	var breakingChanges []BreakingChange
+	for function, functionDiff := range functionSchemaDiff {
+		for _, rule := range FunctionDiffRules {
+			for _, message := range rule.Messages(function, functionDiff) {
+				breakingChanges = append(breakingChanges, newBreakingChange(message, rule.Identifier, function, ""))
+			}
+		}
+	}
+	return breakingChanges
+}
@@ -93,8 +93,10 @@ func TestComputeBreakingChanges(t *testing.T) {
 			newResourceMap: map[string]*schema.Resource{},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
 					Message:                "Resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-map-resource-removal-or-rename",
+					RuleName:               "resource-map-resource-removal-or-rename",
 				},
 			},
 		},
@@ -117,8 +119,11 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-b",
 					Message:                "Field `field-b` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
 				},
 			},
 		},
@@ -142,8 +147,11 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` changed from optional to required on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required",
+					RuleName:               "field-optional-to-required",
 				},
 			},
 		},
@@ -166,12 +174,18 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` changed from optional to required on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required",
+					RuleName:               "field-optional-to-required",
 				},
 				{
+					Resource:               "google-x",
+					Field:                  "field-b",
 					Message:                "Field `field-b` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
 				},
 			},
 		},
@@ -199,16 +213,24 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` changed from optional to required on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-optional-to-required",
+					RuleName:               "field-optional-to-required",
 				},
 				{
+					Resource:               "google-x",
+					Field:                  "field-b",
 					Message:                "Field `field-b` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
 				},
 				{
+					Resource:               "google-y",
 					Message:                "Resource `google-y` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-map-resource-removal-or-rename",
+					RuleName:               "resource-map-resource-removal-or-rename",
 				},
 			},
 		},
@@ -253,8 +275,11 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a.sub-field-2",
 					Message:                "Field `field-a.sub-field-2` within resource `google-x` was either removed or renamed",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#resource-schema-field-removal-or-rename",
+					RuleName:               "resource-schema-field-removal-or-rename",
 				},
 			},
 		},
@@ -298,8 +323,11 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a.sub-field-1",
 					Message:                "Field `field-a.sub-field-1` MaxItems went from 100 to 25 on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-shrinking-max",
+					RuleName:               "field-shrinking-max",
 				},
 			},
 		},
@@ -343,8 +371,11 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a.sub-field-1",
 					Message:                "Field `field-a.sub-field-1` MaxItems went from 100 to 25 on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-shrinking-max",
+					RuleName:               "field-shrinking-max",
 				},
 			},
 		},
@@ -374,8 +405,11 @@ func TestComputeBreakingChanges(t *testing.T) {
 			},
 			wantViolations: []BreakingChange{
 				{
+					Resource:               "google-x",
+					Field:                  "field-a",
 					Message:                "Field `field-a` MinItems went from 1 to 4 on `google-x`",
 					DocumentationReference: "https://googlecloudplatform.github.io/magic-modules/breaking-changes/breaking-changes#field-growing-min",
+					RuleName:               "field-growing-min",
 				},
 			},
 		},
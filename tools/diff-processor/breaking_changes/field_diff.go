@@ -3,6 +3,7 @@ package breaking_changes
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -29,6 +30,15 @@ var FieldDiffRules = []FieldDiffRule{
 	FieldGrowingMin,
 	FieldShrinkingMax,
 	FieldRemovingDiffSuppress,
+	FieldBecomingForceNew,
+	FieldAddingConflictsWith,
+	FieldAddingRequiredWith,
+	FieldBecomingSensitive,
+	FieldNoLongerSensitive,
+	FieldChangingSetHash,
+	FieldRemovingEnumValues,
+	FieldChangingValidationRegex,
+	FieldChangingConfigMode,
 }
 
 var FieldChangingType = FieldDiffRule{
@@ -257,3 +267,223 @@ func FieldNewOptionalFieldWithDefaultMessages(resource, field string, fieldDiff
 	}
 	return nil
 }
+
+var FieldBecomingForceNew = FieldDiffRule{
+	Identifier: "field-becoming-force-new",
+	Messages:   FieldBecomingForceNewMessages,
+}
+
+func FieldBecomingForceNewMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	// output-only fields can't be changed by users
+	if fieldDiff.New.Computed && !fieldDiff.New.Optional {
+		return nil
+	}
+	tmpl := "Field `%s` changed to ForceNew on `%s`, so changing it will recreate the resource"
+	if !fieldDiff.Old.ForceNew && fieldDiff.New.ForceNew {
+		return []string{fmt.Sprintf(tmpl, field, resource)}
+	}
+	return nil
+}
+
+var FieldAddingConflictsWith = FieldDiffRule{
+	Identifier: "field-adding-conflicts-with",
+	Messages:   FieldAddingConflictsWithMessages,
+}
+
+func FieldAddingConflictsWithMessages(resource, field string, fieldDiff diff.FieldDiff, resourceDiff diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	tmpl := "Field `%s` now conflicts with `%s` on `%s`"
+	var messages []string
+	for _, other := range addedFieldReferences(fieldDiff.Old.ConflictsWith, fieldDiff.New.ConflictsWith) {
+		// Conflicting with a new field can't break existing configurations.
+		if resourceDiff.IsNewField(other) {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf(tmpl, field, other, resource))
+	}
+	return messages
+}
+
+var FieldAddingRequiredWith = FieldDiffRule{
+	Identifier: "field-adding-required-with",
+	Messages:   FieldAddingRequiredWithMessages,
+}
+
+func FieldAddingRequiredWithMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	tmpl := "Field `%s` now requires `%s` to be set on `%s`"
+	var messages []string
+	for _, other := range addedFieldReferences(fieldDiff.Old.RequiredWith, fieldDiff.New.RequiredWith) {
+		messages = append(messages, fmt.Sprintf(tmpl, field, other, resource))
+	}
+	return messages
+}
+
+// addedFieldReferences returns the fields in newFields but not oldFields,
+// ignoring whether list indexes are written out.
+func addedFieldReferences(oldFields, newFields []string) []string {
+	old := make(map[string]bool, len(oldFields))
+	for _, f := range oldFields {
+		old[strings.ReplaceAll(f, ".0.", ".")] = true
+	}
+	var added []string
+	for _, f := range newFields {
+		if !old[strings.ReplaceAll(f, ".0.", ".")] {
+			added = append(added, f)
+		}
+	}
+	return added
+}
+
+var FieldBecomingSensitive = FieldDiffRule{
+	Identifier: "field-becoming-sensitive",
+	Messages:   FieldBecomingSensitiveMessages,
+}
+
+func FieldBecomingSensitiveMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	tmpl := "Field `%s` became sensitive on `%s`"
+	if !fieldDiff.Old.Sensitive && fieldDiff.New.Sensitive {
+		return []string{fmt.Sprintf(tmpl, field, resource)}
+	}
+	return nil
+}
+
+var FieldNoLongerSensitive = FieldDiffRule{
+	Identifier: "field-no-longer-sensitive",
+	Messages:   FieldNoLongerSensitiveMessages,
+}
+
+// FieldNoLongerSensitiveMessages reports the opposite flip. Configurations
+// still apply, but values that plans and outputs used to redact are shown.
+func FieldNoLongerSensitiveMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	tmpl := "Field `%s` is no longer sensitive on `%s`"
+	if fieldDiff.Old.Sensitive && !fieldDiff.New.Sensitive {
+		return []string{fmt.Sprintf(tmpl, field, resource)}
+	}
+	return nil
+}
+
+var FieldChangingSetHash = FieldDiffRule{
+	Identifier: "field-changing-set-hash",
+	Messages:   FieldChangingSetHashMessages,
+}
+
+func FieldChangingSetHashMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	if fieldDiff.Old.Type != schema.TypeSet || fieldDiff.New.Type != schema.TypeSet {
+		return nil
+	}
+	oldName := diff.FuncName(fieldDiff.Old.Set)
+	newName := diff.FuncName(fieldDiff.New.Set)
+	if oldName == newName {
+		return nil
+	}
+	// Closures and methods can't be compared by name, so only a hash function
+	// being added or removed is detected for them.
+	if oldName != "" && newName != "" && (!diff.IsNamedFunc(oldName) || !diff.IsNamedFunc(newName)) {
+		return nil
+	}
+	tmpl := "Field `%s` set hash function changed from %s to %s on `%s`"
+	return []string{fmt.Sprintf(tmpl, field, formatSetHash(oldName), formatSetHash(newName), resource)}
+}
+
+func formatSetHash(name string) string {
+	if name == "" {
+		return "the default"
+	}
+	return "`" + name + "`"
+}
+
+var FieldRemovingEnumValues = FieldDiffRule{
+	Identifier: "field-removing-enum-values",
+	Messages:   FieldRemovingEnumValuesMessages,
+}
+
+func FieldRemovingEnumValuesMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields and fields without enum metadata
+	if fieldDiff.Old == nil || fieldDiff.New == nil || fieldDiff.OldMetadata == nil || fieldDiff.NewMetadata == nil {
+		return nil
+	}
+	// A field that is no longer an enum accepts any value.
+	if len(fieldDiff.OldMetadata.EnumValues) == 0 || len(fieldDiff.NewMetadata.EnumValues) == 0 {
+		return nil
+	}
+	accepted := make(map[string]bool, len(fieldDiff.NewMetadata.EnumValues))
+	for _, v := range fieldDiff.NewMetadata.EnumValues {
+		accepted[v] = true
+	}
+	var removed []string
+	for _, v := range fieldDiff.OldMetadata.EnumValues {
+		if !accepted[v] {
+			removed = append(removed, v)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	tmpl := "Field `%s` no longer accepts %s on `%s`"
+	return []string{fmt.Sprintf(tmpl, field, "`"+strings.Join(removed, "`, `")+"`", resource)}
+}
+
+var FieldChangingValidationRegex = FieldDiffRule{
+	Identifier: "field-changing-validation-regex",
+	Messages:   FieldChangingValidationRegexMessages,
+}
+
+func FieldChangingValidationRegexMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields and fields without validation metadata
+	if fieldDiff.Old == nil || fieldDiff.New == nil || fieldDiff.OldMetadata == nil || fieldDiff.NewMetadata == nil {
+		return nil
+	}
+	oldRegex, newRegex := fieldDiff.OldMetadata.ValidationRegex, fieldDiff.NewMetadata.ValidationRegex
+	// Whether a regex is narrower than another can't be decided in general,
+	// so any change to an existing regex is reported.
+	if oldRegex == "" || newRegex == "" || oldRegex == newRegex {
+		return nil
+	}
+	tmpl := "Field `%s` validation regex changed from `%s` to `%s` on `%s`, which may reject previously valid values"
+	return []string{fmt.Sprintf(tmpl, field, oldRegex, newRegex, resource)}
+}
+
+var FieldChangingConfigMode = FieldDiffRule{
+	Identifier: "field-changing-config-mode",
+	Messages:   FieldChangingConfigModeMessages,
+}
+
+func FieldChangingConfigModeMessages(resource, field string, fieldDiff diff.FieldDiff, _ diff.ResourceDiffInterface) []string {
+	// ignore for added / removed fields
+	if fieldDiff.Old == nil || fieldDiff.New == nil {
+		return nil
+	}
+	// Read-only fields aren't written in configurations.
+	if fieldDiff.Old.Computed && !fieldDiff.Old.Optional {
+		return nil
+	}
+	oldMode, newMode := getConfigMode(fieldDiff.Old), getConfigMode(fieldDiff.New)
+	if oldMode == newMode {
+		return nil
+	}
+	tmpl := "Field `%s` changed from %s to %s on `%s`"
+	return []string{fmt.Sprintf(tmpl, field, oldMode, newMode, resource)}
+}
@@ -1,10 +1,12 @@
 package breaking_changes
 
 import (
+	"context"
 	"regexp"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -12,6 +14,8 @@ type fieldTestCase struct {
 	name              string
 	oldField          *schema.Schema
 	newField          *schema.Schema
+	oldMetadata       *diff.FieldMetadata
+	newMetadata       *diff.FieldMetadata
 	resourceDiff      diff.ResourceDiffInterface
 	expectedViolation bool
 	messageRegex      string // Optional regex to validate the message content
@@ -718,9 +722,410 @@ var FieldShrinkingMaxTestCases = []fieldTestCase{
 	},
 }
 
+func TestFieldBecomingForceNew(t *testing.T) {
+	for _, tc := range FieldBecomingForceNewTestCases {
+		tc.check(FieldBecomingForceNew, t)
+	}
+}
+
+var FieldBecomingForceNewTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, ForceNew: true},
+		newField:          &schema.Schema{Optional: true, ForceNew: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "becoming force new",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, ForceNew: true},
+		expectedViolation: true,
+		messageRegex:      "changed to ForceNew",
+	},
+	{
+		name:              "no longer force new",
+		oldField:          &schema.Schema{Optional: true, ForceNew: true},
+		newField:          &schema.Schema{Optional: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "output only",
+		oldField:          &schema.Schema{Computed: true},
+		newField:          &schema.Schema{Computed: true, ForceNew: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "field added",
+		newField:          &schema.Schema{Optional: true, ForceNew: true},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldAddingConflictsWith(t *testing.T) {
+	for _, tc := range FieldAddingConflictsWithTestCases {
+		tc.check(FieldAddingConflictsWith, t)
+	}
+}
+
+var FieldAddingConflictsWithTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, ConflictsWith: []string{"other"}},
+		newField:          &schema.Schema{Optional: true, ConflictsWith: []string{"other"}},
+		expectedViolation: false,
+	},
+	{
+		name:              "adding conflict with existing field",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, ConflictsWith: []string{"other"}},
+		expectedViolation: true,
+		messageRegex:      "now conflicts with `other`",
+	},
+	{
+		name:     "adding conflict with new field",
+		oldField: &schema.Schema{Optional: true},
+		newField: &schema.Schema{Optional: true, ConflictsWith: []string{"other"}},
+		resourceDiff: MockSchemaDiff{
+			newFields: map[string]bool{"other": true},
+		},
+		expectedViolation: false,
+	},
+	{
+		name:              "adding list index",
+		oldField:          &schema.Schema{Optional: true, ConflictsWith: []string{"parent.other"}},
+		newField:          &schema.Schema{Optional: true, ConflictsWith: []string{"parent.0.other"}},
+		expectedViolation: false,
+	},
+	{
+		name:              "removing conflict",
+		oldField:          &schema.Schema{Optional: true, ConflictsWith: []string{"other"}},
+		newField:          &schema.Schema{Optional: true},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldAddingRequiredWith(t *testing.T) {
+	for _, tc := range FieldAddingRequiredWithTestCases {
+		tc.check(FieldAddingRequiredWith, t)
+	}
+}
+
+var FieldAddingRequiredWithTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, RequiredWith: []string{"other"}},
+		newField:          &schema.Schema{Optional: true, RequiredWith: []string{"other"}},
+		expectedViolation: false,
+	},
+	{
+		name:              "adding required with",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, RequiredWith: []string{"other"}},
+		expectedViolation: true,
+		messageRegex:      "now requires `other`",
+	},
+	{
+		name:     "adding required with new field",
+		oldField: &schema.Schema{Optional: true},
+		newField: &schema.Schema{Optional: true, RequiredWith: []string{"other"}},
+		resourceDiff: MockSchemaDiff{
+			newFields: map[string]bool{"other": true},
+		},
+		expectedViolation: true,
+	},
+	{
+		name:              "field added",
+		newField:          &schema.Schema{Optional: true, RequiredWith: []string{"other"}},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldBecomingSensitive(t *testing.T) {
+	for _, tc := range FieldBecomingSensitiveTestCases {
+		tc.check(FieldBecomingSensitive, t)
+	}
+}
+
+var FieldBecomingSensitiveTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		newField:          &schema.Schema{Optional: true, Sensitive: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "becoming sensitive",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, Sensitive: true},
+		expectedViolation: true,
+		messageRegex:      "became sensitive",
+	},
+	{
+		name:              "no longer sensitive",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		newField:          &schema.Schema{Optional: true},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldNoLongerSensitive(t *testing.T) {
+	for _, tc := range FieldNoLongerSensitiveTestCases {
+		tc.check(FieldNoLongerSensitive, t)
+	}
+}
+
+var FieldNoLongerSensitiveTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		newField:          &schema.Schema{Optional: true, Sensitive: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "becoming sensitive",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true, Sensitive: true},
+		expectedViolation: false,
+	},
+	{
+		name:              "no longer sensitive",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		newField:          &schema.Schema{Optional: true},
+		expectedViolation: true,
+		messageRegex:      "no longer sensitive",
+	},
+	{
+		name:              "field removed",
+		oldField:          &schema.Schema{Optional: true, Sensitive: true},
+		expectedViolation: false,
+	},
+}
+
+func hashA(interface{}) int { return 0 }
+
+func hashB(interface{}) int { return 1 }
+
+func TestFieldChangingSetHash(t *testing.T) {
+	for _, tc := range FieldChangingSetHashTestCases {
+		tc.check(FieldChangingSetHash, t)
+	}
+}
+
+var FieldChangingSetHashTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Type: schema.TypeSet, Set: hashA},
+		newField:          &schema.Schema{Type: schema.TypeSet, Set: hashA},
+		expectedViolation: false,
+	},
+	{
+		name:              "changing hash function",
+		oldField:          &schema.Schema{Type: schema.TypeSet, Set: hashA},
+		newField:          &schema.Schema{Type: schema.TypeSet, Set: hashB},
+		expectedViolation: true,
+		messageRegex:      "from `breaking_changes.hashA` to `breaking_changes.hashB`",
+	},
+	{
+		name:              "adding hash function",
+		oldField:          &schema.Schema{Type: schema.TypeSet},
+		newField:          &schema.Schema{Type: schema.TypeSet, Set: hashA},
+		expectedViolation: true,
+		messageRegex:      "from the default to",
+	},
+	{
+		name:              "changing to a closure",
+		oldField:          &schema.Schema{Type: schema.TypeSet, Set: hashA},
+		newField:          &schema.Schema{Type: schema.TypeSet, Set: func(interface{}) int { return 0 }},
+		expectedViolation: false,
+	},
+	{
+		name:              "not a set",
+		oldField:          &schema.Schema{Type: schema.TypeList},
+		newField:          &schema.Schema{Type: schema.TypeList},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldRemovingEnumValues(t *testing.T) {
+	for _, tc := range FieldRemovingEnumValuesTestCases {
+		tc.check(FieldRemovingEnumValues, t)
+	}
+}
+
+var FieldRemovingEnumValuesTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		oldMetadata:       &diff.FieldMetadata{EnumValues: []string{"A", "B"}},
+		newMetadata:       &diff.FieldMetadata{EnumValues: []string{"A", "B"}},
+		expectedViolation: false,
+	},
+	{
+		name:              "adding value",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		oldMetadata:       &diff.FieldMetadata{EnumValues: []string{"A"}},
+		newMetadata:       &diff.FieldMetadata{EnumValues: []string{"A", "B"}},
+		expectedViolation: false,
+	},
+	{
+		name:              "removing value",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		oldMetadata:       &diff.FieldMetadata{EnumValues: []string{"A", "B", "C"}},
+		newMetadata:       &diff.FieldMetadata{EnumValues: []string{"A"}},
+		expectedViolation: true,
+		messageRegex:      "no longer accepts `B`, `C`",
+	},
+	{
+		name:              "no longer an enum",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		oldMetadata:       &diff.FieldMetadata{EnumValues: []string{"A", "B"}},
+		newMetadata:       &diff.FieldMetadata{},
+		expectedViolation: false,
+	},
+	{
+		name:              "no metadata",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		expectedViolation: false,
+	},
+}
+
+func TestFieldChangingValidationRegex(t *testing.T) {
+	for _, tc := range FieldChangingValidationRegexTestCases {
+		tc.check(FieldChangingValidationRegex, t)
+	}
+}
+
+var FieldChangingValidationRegexTestCases = []fieldTestCase{
+	{
+		name:              "control",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		oldMetadata:       &diff.FieldMetadata{ValidationRegex: "^[a-z]+$"},
+		newMetadata:       &diff.FieldMetadata{ValidationRegex: "^[a-z]+$"},
+		expectedViolation: false,
+	},
+	{
+		name:              "changing regex",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		oldMetadata:       &diff.FieldMetadata{ValidationRegex: "^[a-z]+$"},
+		newMetadata:       &diff.FieldMetadata{ValidationRegex: "^[a-z]{1,10}$"},
+		expectedViolation: true,
+		messageRegex:      "may reject previously valid values",
+	},
+	{
+		name:              "removing regex",
+		oldField:          &schema.Schema{Optional: true},
+		newField:          &schema.Schema{Optional: true},
+		oldMetadata:       &diff.FieldMetadata{ValidationRegex: "^[a-z]+$"},
+		newMetadata:       nil,
+		expectedViolation: false,
+	},
+}
+
 // Extended check method that also validates message content when expected
+func TestFieldChangingConfigMode(t *testing.T) {
+	for _, tc := range FieldChangingConfigModeTestCases {
+		tc.check(FieldChangingConfigMode, t)
+	}
+}
+
+// sdkConfigBlock is an SDKv2 nested object, written as `config { ... }`.
+var sdkConfigBlock = &schema.Schema{
+	Type:     schema.TypeList,
+	Optional: true,
+	MaxItems: 1,
+	Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+		"mode": {Type: schema.TypeString, Required: true},
+	}},
+}
+
+// frameworkField converts a Plugin Framework schema with a single field named
+// "config" the way the diff processor reads framework resources, and returns
+// that field.
+func frameworkField(attributes map[string]fwschema.Attribute, blocks map[string]fwschema.Block) *schema.Schema {
+	return diff.ConvertFrameworkSchema(context.Background(), fwschema.Schema{Attributes: attributes, Blocks: blocks}).Schema["config"]
+}
+
+var fwConfigAttributes = map[string]fwschema.Attribute{
+	"mode": fwschema.StringAttribute{Required: true},
+}
+
+var FieldChangingConfigModeTestCases = []fieldTestCase{
+	{
+		name:     "block to framework list block",
+		oldField: sdkConfigBlock,
+		newField: frameworkField(nil, map[string]fwschema.Block{
+			"config": fwschema.ListNestedBlock{
+				NestedObject: fwschema.NestedBlockObject{Attributes: fwConfigAttributes},
+			},
+		}),
+		expectedViolation: false,
+	},
+	{
+		name:     "block to framework single nested attribute",
+		oldField: sdkConfigBlock,
+		newField: frameworkField(map[string]fwschema.Attribute{
+			"config": fwschema.SingleNestedAttribute{Optional: true, Attributes: fwConfigAttributes},
+		}, nil),
+		expectedViolation: true,
+		messageRegex:      "changed from a block to an attribute",
+	},
+	{
+		name: "framework attribute to framework block",
+		oldField: frameworkField(map[string]fwschema.Attribute{
+			"config": fwschema.ListNestedAttribute{
+				Optional:     true,
+				NestedObject: fwschema.NestedAttributeObject{Attributes: fwConfigAttributes},
+			},
+		}, nil),
+		newField: frameworkField(nil, map[string]fwschema.Block{
+			"config": fwschema.ListNestedBlock{
+				NestedObject: fwschema.NestedBlockObject{Attributes: fwConfigAttributes},
+			},
+		}),
+		expectedViolation: true,
+		messageRegex:      "changed from an attribute to a block",
+	},
+	{
+		name: "output-only block to framework computed attribute",
+		oldField: &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"mode": {Type: schema.TypeString, Computed: true},
+			}},
+		},
+		newField: frameworkField(map[string]fwschema.Attribute{
+			"config": fwschema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: fwschema.NestedAttributeObject{Attributes: map[string]fwschema.Attribute{
+					"mode": fwschema.StringAttribute{Computed: true},
+				}},
+			},
+		}, nil),
+		expectedViolation: false,
+	},
+	{
+		name:              "primitive list to framework list attribute",
+		oldField:          &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		newField:          &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}, ConfigMode: schema.SchemaConfigModeAttr},
+		expectedViolation: false,
+	},
+}
+
 func (tc *fieldTestCase) check(rule FieldDiffRule, t *testing.T) {
-	messages := rule.Messages("resource", "field", diff.FieldDiff{Old: tc.oldField, New: tc.newField}, tc.resourceDiff)
+	resourceDiff := tc.resourceDiff
+	if resourceDiff == nil {
+		resourceDiff = existingResourceSchemaDiff
+	}
+	fieldDiff := diff.FieldDiff{Old: tc.oldField, New: tc.newField, OldMetadata: tc.oldMetadata, NewMetadata: tc.newMetadata}
+	messages := rule.Messages("resource", "field", fieldDiff, resourceDiff)
 	violation := len(messages) > 0
 
 	// Check violation expectation
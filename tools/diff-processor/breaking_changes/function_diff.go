@@ -0,0 +1,119 @@
+package breaking_changes
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// FunctionDiffRule provides structure for
+// rules regarding provider-defined function changes
+type FunctionDiffRule struct {
+	Identifier string
+	Messages   func(function string, functionDiff diff.FunctionDiff) []string
+}
+
+// FunctionDiffRules is a list of FunctionDiffRule
+// guarding against provider breaking changes
+var FunctionDiffRules = []FunctionDiffRule{
+	FunctionRemovingAFunction,
+	FunctionChangingParameters,
+	FunctionChangingParameterType,
+	FunctionChangingReturnType,
+}
+
+var FunctionRemovingAFunction = FunctionDiffRule{
+	Identifier: "function-removal-or-rename",
+	Messages:   FunctionRemovingAFunctionMessages,
+}
+
+func FunctionRemovingAFunctionMessages(function string, functionDiff diff.FunctionDiff) []string {
+	if functionDiff.Old != nil && functionDiff.New == nil {
+		tmpl := "Function `%s` was either removed or renamed"
+		return []string{fmt.Sprintf(tmpl, function)}
+	}
+	return nil
+}
+
+var FunctionChangingParameters = FunctionDiffRule{
+	Identifier: "function-parameter-change",
+	Messages:   FunctionChangingParametersMessages,
+}
+
+func FunctionChangingParametersMessages(function string, functionDiff diff.FunctionDiff) []string {
+	if functionDiff.Old == nil || functionDiff.New == nil {
+		return nil
+	}
+	var messages []string
+	oldParams, newParams := functionDiff.Old.Parameters, functionDiff.New.Parameters
+	if len(oldParams) != len(newParams) {
+		tmpl := "Function `%s` changed from %d to %d parameters"
+		messages = append(messages, fmt.Sprintf(tmpl, function, len(oldParams), len(newParams)))
+	}
+	if functionDiff.Old.VariadicParameter != nil && functionDiff.New.VariadicParameter == nil {
+		tmpl := "Function `%s` no longer accepts variadic parameter `%s`"
+		messages = append(messages, fmt.Sprintf(tmpl, function, functionDiff.Old.VariadicParameter.GetName()))
+	}
+	for i := 0; i < len(oldParams) && i < len(newParams); i++ {
+		if oldParams[i].GetAllowNullValue() && !newParams[i].GetAllowNullValue() {
+			tmpl := "Parameter `%s` of function `%s` no longer allows null values"
+			messages = append(messages, fmt.Sprintf(tmpl, newParams[i].GetName(), function))
+		}
+		if oldParams[i].GetAllowUnknownValues() && !newParams[i].GetAllowUnknownValues() {
+			tmpl := "Parameter `%s` of function `%s` no longer allows unknown values"
+			messages = append(messages, fmt.Sprintf(tmpl, newParams[i].GetName(), function))
+		}
+	}
+	return messages
+}
+
+var FunctionChangingParameterType = FunctionDiffRule{
+	Identifier: "function-parameter-changing-type",
+	Messages:   FunctionChangingParameterTypeMessages,
+}
+
+func FunctionChangingParameterTypeMessages(function string, functionDiff diff.FunctionDiff) []string {
+	if functionDiff.Old == nil || functionDiff.New == nil {
+		return nil
+	}
+	var messages []string
+	tmpl := "Parameter `%s` of function `%s` changed from %s to %s"
+	oldParams, newParams := functionDiff.Old.Parameters, functionDiff.New.Parameters
+	for i := 0; i < len(oldParams) && i < len(newParams); i++ {
+		oldType, newType := oldParams[i].GetType(), newParams[i].GetType()
+		if !oldType.Equal(newType) {
+			messages = append(messages, fmt.Sprintf(tmpl, newParams[i].GetName(), function, oldType, newType))
+		}
+	}
+	oldVariadic, newVariadic := functionDiff.Old.VariadicParameter, functionDiff.New.VariadicParameter
+	if oldVariadic != nil && newVariadic != nil && !oldVariadic.GetType().Equal(newVariadic.GetType()) {
+		messages = append(messages, fmt.Sprintf(tmpl, newVariadic.GetName(), function, oldVariadic.GetType(), newVariadic.GetType()))
+	}
+	return messages
+}
+
+var FunctionChangingReturnType = FunctionDiffRule{
+	Identifier: "function-return-changing-type",
+	Messages:   FunctionChangingReturnTypeMessages,
+}
+
+func FunctionChangingReturnTypeMessages(function string, functionDiff diff.FunctionDiff) []string {
+	if functionDiff.Old == nil || functionDiff.New == nil {
+		return nil
+	}
+	oldType, newType := getReturnType(functionDiff.Old.Return), getReturnType(functionDiff.New.Return)
+	if oldType == nil || newType == nil || oldType.Equal(newType) {
+		return nil
+	}
+	tmpl := "Return type of function `%s` changed from %s to %s"
+	return []string{fmt.Sprintf(tmpl, function, oldType, newType)}
+}
+
+func getReturnType(r function.Return) attr.Type {
+	if r == nil {
+		return nil
+	}
+	return r.GetType()
+}
@@ -0,0 +1,211 @@
+package breaking_changes
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+)
+
+type functionDiffTestCase struct {
+	name           string
+	old            *function.Definition
+	new            *function.Definition
+	wantViolations bool
+}
+
+func TestFunctionDiffRule_RemovingAFunction(t *testing.T) {
+	for _, tc := range functionRemovingAFunctionTestCases {
+		tc.check(FunctionRemovingAFunction, t)
+	}
+}
+
+var functionRemovingAFunctionTestCases = []functionDiffTestCase{
+	{
+		name:           "control",
+		old:            &function.Definition{},
+		new:            &function.Definition{},
+		wantViolations: false,
+	},
+	{
+		name:           "function added",
+		old:            nil,
+		new:            &function.Definition{},
+		wantViolations: false,
+	},
+	{
+		name:           "function removed",
+		old:            &function.Definition{},
+		new:            nil,
+		wantViolations: true,
+	},
+}
+
+func TestFunctionDiffRule_ChangingParameters(t *testing.T) {
+	for _, tc := range functionChangingParametersTestCases {
+		tc.check(FunctionChangingParameters, t)
+	}
+}
+
+var functionChangingParametersTestCases = []functionDiffTestCase{
+	{
+		name: "control",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		new: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		wantViolations: false,
+	},
+	{
+		name: "parameter renamed",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		new: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "self_link"}},
+		},
+		wantViolations: false,
+	},
+	{
+		name: "parameter added",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		new: &function.Definition{
+			Parameters: []function.Parameter{
+				function.StringParameter{Name: "id"},
+				function.StringParameter{Name: "element"},
+			},
+		},
+		wantViolations: true,
+	},
+	{
+		name: "parameter removed",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		new:            &function.Definition{},
+		wantViolations: true,
+	},
+	{
+		name: "variadic parameter added",
+		old:  &function.Definition{},
+		new: &function.Definition{
+			VariadicParameter: function.StringParameter{Name: "ids"},
+		},
+		wantViolations: false,
+	},
+	{
+		name: "variadic parameter removed",
+		old: &function.Definition{
+			VariadicParameter: function.StringParameter{Name: "ids"},
+		},
+		new:            &function.Definition{},
+		wantViolations: true,
+	},
+	{
+		name: "parameter no longer allows null",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id", AllowNullValue: true}},
+		},
+		new: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		wantViolations: true,
+	},
+	{
+		name: "parameter now allows unknown",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		new: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id", AllowUnknownValues: true}},
+		},
+		wantViolations: false,
+	},
+}
+
+func TestFunctionDiffRule_ChangingParameterType(t *testing.T) {
+	for _, tc := range functionChangingParameterTypeTestCases {
+		tc.check(FunctionChangingParameterType, t)
+	}
+}
+
+var functionChangingParameterTypeTestCases = []functionDiffTestCase{
+	{
+		name: "control",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		new: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		wantViolations: false,
+	},
+	{
+		name: "parameter changing type",
+		old: &function.Definition{
+			Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		},
+		new: &function.Definition{
+			Parameters: []function.Parameter{function.Int64Parameter{Name: "id"}},
+		},
+		wantViolations: true,
+	},
+	{
+		name: "variadic parameter changing type",
+		old: &function.Definition{
+			VariadicParameter: function.StringParameter{Name: "ids"},
+		},
+		new: &function.Definition{
+			VariadicParameter: function.BoolParameter{Name: "ids"},
+		},
+		wantViolations: true,
+	},
+	{
+		name: "parameter added",
+		old:  &function.Definition{},
+		new: &function.Definition{
+			Parameters: []function.Parameter{function.Int64Parameter{Name: "id"}},
+		},
+		wantViolations: false,
+	},
+}
+
+func TestFunctionDiffRule_ChangingReturnType(t *testing.T) {
+	for _, tc := range functionChangingReturnTypeTestCases {
+		tc.check(FunctionChangingReturnType, t)
+	}
+}
+
+var functionChangingReturnTypeTestCases = []functionDiffTestCase{
+	{
+		name:           "control",
+		old:            &function.Definition{Return: function.StringReturn{}},
+		new:            &function.Definition{Return: function.StringReturn{}},
+		wantViolations: false,
+	},
+	{
+		name:           "return changing type",
+		old:            &function.Definition{Return: function.StringReturn{}},
+		new:            &function.Definition{Return: function.Int64Return{}},
+		wantViolations: true,
+	},
+	{
+		name:           "function removed",
+		old:            &function.Definition{Return: function.StringReturn{}},
+		new:            nil,
+		wantViolations: false,
+	},
+}
+
+func (tc *functionDiffTestCase) check(rule FunctionDiffRule, t *testing.T) {
+	got := rule.Messages("provider::google::function", diff.FunctionDiff{Old: tc.old, New: tc.new})
+	gotViolations := len(got) > 0
+	if tc.wantViolations != gotViolations {
+		t.Errorf("%s.Messages(%v) violations not expected. Got %v, want %v. Messages: %v", rule.Identifier, tc.name, gotViolations, tc.wantViolations, got)
+	}
+}
@@ -4,6 +4,7 @@ package breaking_changes
 type MockSchemaDiff struct {
 	isNewResource        bool
 	fieldsInNewStructure map[string]bool // Maps field names to whether they're in a new structure
+	newFields            map[string]bool // Maps field names to whether they're absent from the old schema
 }
 
 func (sd MockSchemaDiff) IsNewResource() bool {
@@ -14,6 +15,10 @@ func (sd MockSchemaDiff) IsFieldInNewNestedStructure(field string) bool {
 	return sd.fieldsInNewStructure[field]
 }
 
+func (sd MockSchemaDiff) IsNewField(field string) bool {
+	return sd.isNewResource || sd.newFields[field]
+}
+
 // Create mock schema diffs for testing
 var (
 	// Mock for existing resource (not new, field not in new structure)
@@ -8,7 +8,7 @@ import (
 )
 
 func TestUniqueRuleIdentifiers(t *testing.T) {
-	identifiers := getArrayOfIdentifiers()
+	identifiers := RuleIdentifiers()
 	// Create a map to track the identifiers
 	identifierMap := make(map[string]bool)
 	for _, id := range identifiers {
@@ -35,7 +35,7 @@ func TestMarkdownIdentifiers(t *testing.T) {
 	mdString := string(mdContent)
 
 	// Define the identifiers to check
-	identifiers := getArrayOfIdentifiers()
+	identifiers := RuleIdentifiers()
 
 	// Iterate over the identifiers and check if they have a corresponding <h4> tag
 	for _, identifier := range identifiers {
@@ -48,21 +48,3 @@ func TestMarkdownIdentifiers(t *testing.T) {
 		}
 	}
 }
-
-func getArrayOfIdentifiers() []string {
-	var identifiers []string
-
-	for _, r := range ResourceDiffRules {
-		identifiers = append(identifiers, r.Identifier)
-	}
-
-	for _, r := range ResourceConfigDiffRules {
-		identifiers = append(identifiers, r.Identifier)
-	}
-
-	for _, r := range FieldDiffRules {
-		identifiers = append(identifiers, r.Identifier)
-	}
-
-	return identifiers
-}
@@ -23,3 +23,19 @@ func getValueType(valueType schema.ValueType) string {
 	}
 	return "TypeUndefined"
 }
+
+// getConfigMode returns whether a field is written as a block or an attribute
+// in configurations. Fields with SchemaConfigModeAuto are blocks when their
+// elements are resources and they can be set, as in SDKv2.
+func getConfigMode(s *schema.Schema) string {
+	switch s.ConfigMode {
+	case schema.SchemaConfigModeAttr:
+		return "an attribute"
+	case schema.SchemaConfigModeBlock:
+		return "a block"
+	}
+	if _, ok := s.Elem.(*schema.Resource); ok && (s.Optional || s.Required || !s.Computed) {
+		return "a block"
+	}
+	return "an attribute"
+}
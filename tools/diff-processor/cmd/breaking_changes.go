@@ -10,15 +10,18 @@ import (
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/breaking_changes"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/report"
 	"github.com/spf13/cobra"
 )
 
 const breakingChangesDesc = `Check for breaking changes between the new / old Terraform provider versions.`
 
 type breakingChangesOptions struct {
-	rootOptions       *rootOptions
-	computeSchemaDiff func() diff.SchemaDiff
-	stdout            io.Writer
+	rootOptions               *rootOptions
+	computeSchemaDiff         func() diff.SchemaDiff
+	computeFunctionSchemaDiff func() (diff.FunctionSchemaDiff, error)
+	output                    outputOptions
+	stdout                    io.Writer
 }
 
 func newBreakingChangesCmd(rootOptions *rootOptions) *cobra.Command {
@@ -27,7 +30,8 @@ func newBreakingChangesCmd(rootOptions *rootOptions) *cobra.Command {
 		computeSchemaDiff: func() diff.SchemaDiff {
 			return schemaDiff
 		},
-		stdout: os.Stdout,
+		computeFunctionSchemaDiff: computeFunctionSchemaDiff,
+		stdout:                    os.Stdout,
 	}
 	cmd := &cobra.Command{
 		Use:   "breaking-changes",
@@ -37,16 +41,40 @@ func newBreakingChangesCmd(rootOptions *rootOptions) *cobra.Command {
 			return o.run()
 		},
 	}
+	o.output.addFlags(cmd)
 	return cmd
 }
 func (o *breakingChangesOptions) run() error {
 	schemaDiff := o.computeSchemaDiff()
 	breakingChanges := breaking_changes.ComputeBreakingChanges(schemaDiff)
+	if o.computeFunctionSchemaDiff != nil {
+		functionSchemaDiff, err := o.computeFunctionSchemaDiff()
+		if err != nil {
+			return err
+		}
+		breakingChanges = append(breakingChanges, breaking_changes.ComputeFunctionBreakingChanges(functionSchemaDiff)...)
+	}
 	sort.Slice(breakingChanges, func(i, j int) bool {
 		return breakingChanges[i].Message < breakingChanges[j].Message
 	})
-	if err := json.NewEncoder(o.stdout).Encode(breakingChanges); err != nil {
-		return fmt.Errorf("error encoding json: %w", err)
+	findings := func() []report.Finding {
+		var findings []report.Finding
+		for _, bc := range breakingChanges {
+			findings = append(findings, report.Finding{
+				Rule:                   bc.RuleName,
+				Level:                  report.LevelError,
+				Resource:               bc.Resource,
+				Field:                  bc.Field,
+				Message:                bc.Message,
+				DocumentationReference: bc.DocumentationReference,
+			})
+		}
+		return findings
 	}
-	return nil
+	return o.output.write(o.stdout, "breaking-changes", breaking_changes.RuleIdentifiers(), findings, func() error {
+		if err := json.NewEncoder(o.stdout).Encode(breakingChanges); err != nil {
+			return fmt.Errorf("error encoding json: %w", err)
+		}
+		return nil
+	})
 }
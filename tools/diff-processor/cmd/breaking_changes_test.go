@@ -3,11 +3,17 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/breaking_changes"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/report"
 
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -105,3 +111,127 @@ func TestBreakingChangesCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestBreakingChangesCmd_functions(t *testing.T) {
+	var buf bytes.Buffer
+	o := breakingChangesOptions{
+		computeSchemaDiff: func() diff.SchemaDiff {
+			return diff.SchemaDiff{}
+		},
+		computeFunctionSchemaDiff: func() (diff.FunctionSchemaDiff, error) {
+			return diff.ComputeFunctionSchemaDiff(
+				map[string]function.Definition{
+					"name_from_id":    {Parameters: []function.Parameter{function.StringParameter{Name: "id"}}, Return: function.StringReturn{}},
+					"project_from_id": {Parameters: []function.Parameter{function.StringParameter{Name: "id"}}, Return: function.StringReturn{}},
+				},
+				map[string]function.Definition{
+					"name_from_id": {Parameters: []function.Parameter{function.StringParameter{Name: "id"}}, Return: function.Int64Return{}},
+				},
+			), nil
+		},
+		stdout: &buf,
+	}
+
+	if err := o.run(); err != nil {
+		t.Errorf("Error running command: %s", err)
+	}
+
+	var got []breaking_changes.BreakingChange
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshall output: %s", err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("Unexpected number of violations. Want 2, got %d. Output: %s", len(got), buf.String())
+	}
+}
+
+func TestBreakingChangesCmd_functionsError(t *testing.T) {
+	o := breakingChangesOptions{
+		computeSchemaDiff: func() diff.SchemaDiff { return diff.SchemaDiff{} },
+		computeFunctionSchemaDiff: func() (diff.FunctionSchemaDiff, error) {
+			return nil, errors.New("provider does not serve provider-defined functions")
+		},
+		stdout: &bytes.Buffer{},
+	}
+	if err := o.run(); err == nil {
+		t.Error("Expected an error when the functions can't be loaded")
+	}
+}
+
+func TestBreakingChangesCmd_allowlist(t *testing.T) {
+	allowlist := filepath.Join(t.TempDir(), "allowlist.yaml")
+	content := `suppressions:
+- resource: google-x
+  field: field-b
+  rule: resource-schema-field-removal-or-rename
+  justification: Removed in the major release.
+- resource: google-x
+  rule: resource-map-resource-removal-or-rename
+  justification: No longer removed.
+- resource: google-x
+  field: field-a
+  rule: missing-test
+  justification: Belongs to detect-missing-tests.
+`
+	if err := os.WriteFile(allowlist, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	o := breakingChangesOptions{
+		computeSchemaDiff: func() diff.SchemaDiff {
+			return diff.ComputeSchemaDiff(
+				map[string]*schema.Resource{
+					"google-x": {Schema: map[string]*schema.Schema{
+						"field-a": {Description: "beep", Optional: true},
+						"field-b": {Description: "beep", Optional: true},
+					}},
+				},
+				map[string]*schema.Resource{
+					"google-x": {Schema: map[string]*schema.Schema{
+						"field-a": {Description: "beep", Required: true},
+					}},
+				},
+			)
+		},
+		output: outputOptions{format: formatJSON, allowlist: allowlist},
+		stdout: &buf,
+	}
+	if err := o.run(); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+
+	var got report.Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to unmarshall output: %s", err)
+	}
+	if got.Version != report.Version || got.Command != "breaking-changes" {
+		t.Errorf("Unexpected report header: version %d, command %q", got.Version, got.Command)
+	}
+	var unsuppressed []string
+	for _, f := range got.Unsuppressed() {
+		unsuppressed = append(unsuppressed, f.Rule+" "+f.Resource+"."+f.Field)
+	}
+	if want := []string{"field-optional-to-required google-x.field-a"}; !reflect.DeepEqual(unsuppressed, want) {
+		t.Errorf("Unexpected unsuppressed findings. Want %v, got %v", want, unsuppressed)
+	}
+	if len(got.Findings) != 2 {
+		t.Errorf("Unexpected number of findings. Want 2, got %d. Output: %s", len(got.Findings), buf.String())
+	}
+	if len(got.UnusedSuppressions) != 1 || got.UnusedSuppressions[0].Rule != "resource-map-resource-removal-or-rename" {
+		t.Errorf("Unexpected unused suppressions: %v", got.UnusedSuppressions)
+	}
+}
+
+func TestBreakingChangesCmd_allowlistRequiresFormat(t *testing.T) {
+	o := breakingChangesOptions{
+		computeSchemaDiff:         func() diff.SchemaDiff { return diff.SchemaDiff{} },
+		computeFunctionSchemaDiff: func() (diff.FunctionSchemaDiff, error) { return diff.FunctionSchemaDiff{}, nil },
+		output:                    outputOptions{allowlist: "allowlist.yaml"},
+		stdout:                    &bytes.Buffer{},
+	}
+	if err := o.run(); err == nil {
+		t.Error("Expected an error using --allowlist with the legacy format")
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	newProvider "google/provider/new/google/provider"
+
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/coverage"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
+	"github.com/golang/glog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cobra"
+)
+
+const coverageDesc = `Report which fields of every resource in NEW_REF are set by an acceptance test in the given services directory, and which are updated between test steps. With --previous, compare the report with the JSON report of an earlier run.`
+
+type coverageOptions struct {
+	rootOptions *rootOptions
+	resourceMap func() map[string]*schema.Resource
+	format      string
+	previous    string
+	stdout      io.Writer
+}
+
+func newCoverageCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &coverageOptions{
+		rootOptions: rootOptions,
+		resourceMap: func() map[string]*schema.Resource {
+			return mergeResourceMaps(newProvider.ResourceMap(), diff.FrameworkResourceMap(context.Background(), newFrameworkProvider))
+		},
+		stdout: os.Stdout,
+	}
+	cmd := &cobra.Command{
+		Use:   "coverage SERVICES_DIR",
+		Short: coverageDesc,
+		Long:  coverageDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(args)
+		},
+	}
+	cmd.Flags().StringVar(&o.format, "format", formatJSON, "output format: json or html")
+	cmd.Flags().StringVar(&o.previous, "previous", "", "JSON report of a previous run to compare with")
+	return cmd
+}
+
+func (o *coverageOptions) run(args []string) error {
+	if o.format != formatJSON && o.format != "html" {
+		return fmt.Errorf("unknown format %q, want %s or html", o.format, formatJSON)
+	}
+	allTests, errs := reader.ReadAllTests(args[0])
+	for path, err := range errs {
+		glog.Infof("error reading path: %s, err: %v", path, err)
+	}
+
+	report := coverage.Compute(o.resourceMap(), allTests)
+	if o.previous != "" {
+		previous, err := coverage.Load(o.previous)
+		if err != nil {
+			return fmt.Errorf("error loading previous report: %w", err)
+		}
+		coverage.Compare(previous, report)
+	}
+
+	if o.format == "html" {
+		return coverage.WriteHTML(o.stdout, report)
+	}
+	enc := json.NewEncoder(o.stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("error encoding json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/coverage"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const coverageTestFile = `package service
+
+func TestAccWidget_update(t *testing.T) {
+	acctest.VcrTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWidget_basic(context),
+			},
+			{
+				Config: testAccWidget_update(context),
+			},
+		},
+	})
+}
+
+func testAccWidget_basic(context map[string]interface{}) string {
+	return acctest.Nprintf(` + "`" + `
+resource "google_widget" "primary" {
+  name = "widget-%{random_suffix}"
+  size = 1
+}
+` + "`" + `, context)
+}
+
+func testAccWidget_update(context map[string]interface{}) string {
+	return acctest.Nprintf(` + "`" + `
+resource "google_widget" "primary" {
+  name = "widget-%{random_suffix}"
+  size = 2
+}
+` + "`" + `, context)
+}
+`
+
+func TestCoverageCmd(t *testing.T) {
+	servicesDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(servicesDir, "widget"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(servicesDir, "widget", "resource_widget_test.go"), []byte(coverageTestFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resourceMap := func() map[string]*schema.Resource {
+		return map[string]*schema.Resource{
+			"google_widget": {
+				Schema: map[string]*schema.Schema{
+					"name":   {Type: schema.TypeString, Required: true, ForceNew: true},
+					"size":   {Type: schema.TypeInt, Optional: true},
+					"labels": {Type: schema.TypeMap, Optional: true},
+				},
+			},
+		}
+	}
+
+	var buf bytes.Buffer
+	o := coverageOptions{resourceMap: resourceMap, format: formatJSON, stdout: &buf}
+	if err := o.run([]string{servicesDir}); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+	previous := filepath.Join(t.TempDir(), "coverage.json")
+	if err := os.WriteFile(previous, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := coverage.Load(previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*coverage.FieldCoverage{
+		"name":   {Tested: true},
+		"size":   {Tested: true, Updatable: true, Updated: true},
+		"labels": {Updatable: true},
+	}
+	if diff := cmp.Diff(want, got.Resources["google_widget"].Fields); diff != "" {
+		t.Errorf("Unexpected coverage (-want +got):\n%s", diff)
+	}
+
+	buf.Reset()
+	o = coverageOptions{resourceMap: resourceMap, format: "html", previous: previous, stdout: &buf}
+	if err := o.run([]string{servicesDir}); err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+	if want := "<h2>Changes since the previous run</h2>"; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected the html report to contain %q, got %s", want, buf.String())
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/detector"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/report"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 
@@ -19,6 +20,8 @@ import (
 
 const detectMissingDocDesc = `Compute list of fields missing documents`
 
+const missingDocRule = "missing-doc"
+
 type MissingDocsInfo struct {
 	Name     string
 	FilePath string
@@ -29,6 +32,7 @@ type detectMissingDocsOptions struct {
 	rootOptions                 *rootOptions
 	computeSchemaDiff           func() diff.SchemaDiff
 	computeDatasourceSchemaDiff func() diff.SchemaDiff
+	output                      outputOptions
 	stdout                      io.Writer
 }
 
@@ -57,6 +61,7 @@ func newDetectMissingDocsCmd(rootOptions *rootOptions) *cobra.Command {
 			return o.run(args)
 		},
 	}
+	o.output.addFlags(cmd)
 	return cmd
 }
 func (o *detectMissingDocsOptions) run(args []string) error {
@@ -77,11 +82,27 @@ func (o *detectMissingDocsOptions) run(args []string) error {
 		DataSource: sortMissingDocDetails(detectedDataSources),
 	}
 
-	if err := json.NewEncoder(o.stdout).Encode(sum); err != nil {
-		return fmt.Errorf("error encoding json: %w", err)
+	findings := func() []report.Finding {
+		var findings []report.Finding
+		for _, details := range append(sum.Resource, sum.DataSource...) {
+			for _, field := range details.Fields {
+				findings = append(findings, report.Finding{
+					Rule:     missingDocRule,
+					Level:    report.LevelWarning,
+					Resource: details.Name,
+					Field:    field,
+					Message:  fmt.Sprintf("Field `%s` on `%s` is missing from %s", field, details.Name, details.FilePath),
+				})
+			}
+		}
+		return findings
 	}
-
-	return nil
+	return o.output.write(o.stdout, "detect-missing-docs", []string{missingDocRule}, findings, func() error {
+		if err := json.NewEncoder(o.stdout).Encode(sum); err != nil {
+			return fmt.Errorf("error encoding json: %w", err)
+		}
+		return nil
+	})
 }
 
 func sortMissingDocDetails(m map[string]detector.MissingDocDetails) []detector.MissingDocDetails {
@@ -8,14 +8,21 @@ import (
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/detector"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/report"
 	"github.com/spf13/cobra"
 )
 
 const detectMissingIdentityDesc = "Detect resources with ResourceIdentity that are missing SetResourceIdentityAttributes in CRUD functions or missing import identity tests"
 
+const (
+	missingIdentityCRUDRule       = "missing-identity-crud"
+	missingIdentityImportTestRule = "missing-identity-import-test"
+)
+
 type detectMissingIdentityOptions struct {
 	rootOptions       *rootOptions
 	computeSchemaDiff func() diff.SchemaDiff
+	output            outputOptions
 	stdout            io.Writer
 }
 
@@ -27,7 +34,7 @@ func newDetectMissingIdentityCmd(rootOptions *rootOptions) *cobra.Command {
 		},
 		stdout: os.Stdout,
 	}
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "detect-missing-identity SERVICES_DIR",
 		Short: detectMissingIdentityDesc,
 		Long:  detectMissingIdentityDesc,
@@ -36,6 +43,8 @@ func newDetectMissingIdentityCmd(rootOptions *rootOptions) *cobra.Command {
 			return o.run(args)
 		},
 	}
+	o.output.addFlags(cmd)
+	return cmd
 }
 
 func (o *detectMissingIdentityOptions) run(args []string) error {
@@ -52,8 +61,33 @@ func (o *detectMissingIdentityOptions) run(args []string) error {
 	if err != nil {
 		return fmt.Errorf("error detecting missing identity coverage: %v", err)
 	}
-	if err := json.NewEncoder(o.stdout).Encode(results); err != nil {
-		return fmt.Errorf("error encoding json: %w", err)
+	findings := func() []report.Finding {
+		var findings []report.Finding
+		for resource, info := range results {
+			for _, crud := range info.MissingCRUD {
+				findings = append(findings, report.Finding{
+					Rule:     missingIdentityCRUDRule,
+					Level:    report.LevelWarning,
+					Resource: resource,
+					Message:  fmt.Sprintf("%s of `%s` doesn't call SetResourceIdentityAttributes", crud, resource),
+				})
+			}
+			if info.MissingImportTest {
+				findings = append(findings, report.Finding{
+					Rule:     missingIdentityImportTestRule,
+					Level:    report.LevelWarning,
+					Resource: resource,
+					Message:  fmt.Sprintf("`%s` has no test importing it by resource identity", resource),
+				})
+			}
+		}
+		return findings
 	}
-	return nil
+	rules := []string{missingIdentityCRUDRule, missingIdentityImportTestRule}
+	return o.output.write(o.stdout, "detect-missing-identity", rules, findings, func() error {
+		if err := json.NewEncoder(o.stdout).Encode(results); err != nil {
+			return fmt.Errorf("error encoding json: %w", err)
+		}
+		return nil
+	})
 }
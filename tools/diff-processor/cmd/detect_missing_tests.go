@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/detector"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/report"
 	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
@@ -15,8 +16,11 @@ import (
 
 const detectMissingTestsDesc = "Run the missing test detector using the given services directory"
 
+const missingTestRule = "missing-test"
+
 type detectMissingTestsOptions struct {
 	rootOptions *rootOptions
+	output      outputOptions
 	stdout      io.Writer
 }
 
@@ -25,7 +29,7 @@ func newDetectMissingTestsCmd(rootOptions *rootOptions) *cobra.Command {
 		rootOptions: rootOptions,
 		stdout:      os.Stdout,
 	}
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "detect-missing-tests SERVICES_DIR",
 		Short: detectMissingTestsDesc,
 		Long:  detectMissingTestsDesc,
@@ -34,6 +38,8 @@ func newDetectMissingTestsCmd(rootOptions *rootOptions) *cobra.Command {
 			return o.run(args)
 		},
 	}
+	o.output.addFlags(cmd)
+	return cmd
 }
 
 func (o *detectMissingTestsOptions) run(args []string) error {
@@ -46,8 +52,25 @@ func (o *detectMissingTestsOptions) run(args []string) error {
 	if err != nil {
 		return fmt.Errorf("error detecting missing tests: %v", err)
 	}
-	if err := json.NewEncoder(o.stdout).Encode(missingTests); err != nil {
-		return fmt.Errorf("error encoding json: %w", err)
+	findings := func() []report.Finding {
+		var findings []report.Finding
+		for resource, info := range missingTests {
+			for _, field := range info.UntestedFields {
+				findings = append(findings, report.Finding{
+					Rule:     missingTestRule,
+					Level:    report.LevelWarning,
+					Resource: resource,
+					Field:    field,
+					Message:  fmt.Sprintf("Field `%s` on `%s` is not covered by any test", field, resource),
+				})
+			}
+		}
+		return findings
 	}
-	return nil
+	return o.output.write(o.stdout, "detect-missing-tests", []string{missingTestRule}, findings, func() error {
+		if err := json.NewEncoder(o.stdout).Encode(missingTests); err != nil {
+			return fmt.Errorf("error encoding json: %w", err)
+		}
+		return nil
+	})
 }
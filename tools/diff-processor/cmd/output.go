@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/report"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatLegacy = "legacy"
+	formatJSON   = "json"
+	formatSARIF  = "sarif"
+)
+
+// outputOptions are the output flags shared by commands that report
+// findings. The zero value writes the legacy format.
+type outputOptions struct {
+	format    string
+	allowlist string
+}
+
+func (o *outputOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.format, "format", formatLegacy, "output format: legacy, json (versioned) or sarif")
+	cmd.Flags().StringVar(&o.allowlist, "allowlist", "", "allowlist file of findings to suppress; requires --format json or sarif")
+}
+
+// write writes findings in the versioned formats, or calls legacy to write
+// the command's original output.
+func (o *outputOptions) write(w io.Writer, command string, rules []string, findings func() []report.Finding, legacy func() error) error {
+	switch o.format {
+	case "", formatLegacy:
+		if o.allowlist != "" {
+			return fmt.Errorf("--allowlist requires --format %s or %s", formatJSON, formatSARIF)
+		}
+		return legacy()
+	case formatJSON, formatSARIF:
+	default:
+		return fmt.Errorf("unknown format %q, want %s, %s or %s", o.format, formatLegacy, formatJSON, formatSARIF)
+	}
+
+	var allowlist []report.Suppression
+	if o.allowlist != "" {
+		var err error
+		if allowlist, err = report.LoadAllowlist(o.allowlist); err != nil {
+			return fmt.Errorf("error loading allowlist: %w", err)
+		}
+	}
+	r := report.New(command, findings(), allowlist, rules)
+	if o.format == formatSARIF {
+		return report.WriteSARIF(w, r)
+	}
+	return report.WriteJSON(w, r)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	newProvider "google/provider/new/google/provider"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/reverse"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/spf13/cobra"
+)
+
+const reverseYAMLDesc = `Write mmv1 resource YAML for a handwritten SDKv2 resource, as a starting point for moving it to the generator. Anything that can't be converted is listed as TODO comments. With --check, exit with an error if the YAML would generate a different schema.`
+
+type reverseYAMLOptions struct {
+	rootOptions *rootOptions
+	resourceMap func() map[string]*schema.Resource
+	check       bool
+	year        int
+	stdout      io.Writer
+}
+
+func newReverseYAMLCmd(rootOptions *rootOptions) *cobra.Command {
+	o := &reverseYAMLOptions{
+		rootOptions: rootOptions,
+		resourceMap: func() map[string]*schema.Resource {
+			return newProvider.ResourceMap()
+		},
+		year:   time.Now().Year(),
+		stdout: os.Stdout,
+	}
+	cmd := &cobra.Command{
+		Use:   "reverse-yaml RESOURCE_NAME",
+		Short: reverseYAMLDesc,
+		Long:  reverseYAMLDesc,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.run(args)
+		},
+	}
+	cmd.Flags().BoolVar(&o.check, "check", false, "fail if the YAML doesn't reproduce the resource's schema")
+	return cmd
+}
+
+func (o *reverseYAMLOptions) run(args []string) error {
+	name := args[0]
+	r, ok := o.resourceMap()[name]
+	if !ok {
+		return fmt.Errorf("resource %s not found in the provider", name)
+	}
+	result := reverse.Convert(name, r)
+	if err := result.WriteYAML(o.stdout, o.year); err != nil {
+		return fmt.Errorf("error writing yaml: %w", err)
+	}
+	if !o.check {
+		return nil
+	}
+	drift, err := reverse.Drift(r, result.Resource)
+	if err != nil {
+		return fmt.Errorf("error checking the yaml for %s: %w", name, err)
+	}
+	if len(drift) > 0 {
+		return fmt.Errorf("the yaml for %s generates a different schema for: %s", name, strings.Join(drift, ", "))
+	}
+	return nil
+}
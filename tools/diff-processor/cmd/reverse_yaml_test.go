@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestReverseYAMLCmdRun(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"google_x_widget": {
+			Description: "A widget.",
+			Schema: map[string]*schema.Schema{
+				"name":        {Type: schema.TypeString, Required: true, ForceNew: true, Description: "beep"},
+				"size_gb":     {Type: schema.TypeInt, Optional: true, Computed: true, Description: "beep"},
+				"create_time": {Type: schema.TypeString, Computed: true, Description: "beep"},
+			},
+		},
+		"google_x_gadget": {
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "beep",
+					DefaultFunc: func() (interface{}, error) { return "gadget", nil },
+				},
+			},
+		},
+	}
+	cases := []struct {
+		name         string
+		args         []string
+		check        bool
+		wantContains []string
+		wantErr      string
+	}{
+		{
+			name:         "converts resource",
+			args:         []string{"google_x_widget"},
+			wantContains: []string{"name: Widget\n", "  - name: sizeGb\n    type: Integer\n    description: beep\n    default_from_api: true\n"},
+		},
+		{
+			name:         "check passes",
+			args:         []string{"google_x_widget"},
+			check:        true,
+			wantContains: []string{"name: Widget\n"},
+		},
+		{
+			name:         "check fails on drift",
+			args:         []string{"google_x_gadget"},
+			check:        true,
+			wantContains: []string{"# TODO: name: DefaultFunc"},
+			wantErr:      "different schema for: name",
+		},
+		{
+			name:    "unknown resource",
+			args:    []string{"google_x_missing"},
+			wantErr: "not found",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			o := reverseYAMLOptions{
+				rootOptions: &rootOptions{},
+				resourceMap: func() map[string]*schema.Resource { return resources },
+				check:       tc.check,
+				year:        2026,
+				stdout:      &stdout,
+			}
+			err := o.run(tc.args)
+			if tc.wantErr == "" && err != nil {
+				t.Fatalf("run() = %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("run() = %v, want error containing %q", err, tc.wantErr)
+			}
+			for _, want := range tc.wantContains {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("run() wrote %q, want it to contain %q", stdout.String(), want)
+				}
+			}
+		})
+	}
+}
@@ -26,6 +26,8 @@ func newRootCmd() (*cobra.Command, *rootOptions, error) {
 	cmd.AddCommand(newSchemaDiffCmd(o))
 	cmd.AddCommand(newDetectMissingDocsCmd(o))
 	cmd.AddCommand(newDetectMissingIdentityCmd(o))
+	cmd.AddCommand(newReverseYAMLCmd(o))
+	cmd.AddCommand(newCoverageCmd(o))
 	return cmd, o, nil
 }
 
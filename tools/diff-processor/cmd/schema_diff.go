@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	newFwprovider "google/provider/new/google/fwprovider"
 	newProvider "google/provider/new/google/provider"
+	oldFwprovider "google/provider/old/google/fwprovider"
 	oldProvider "google/provider/old/google/provider"
 
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,12 +14,60 @@ import (
 	"sort"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/spf13/cobra"
 )
 
 const schemaDiffDesc = `Return a simple summary of the schema diff for this build.`
 
-var schemaDiff = diff.ComputeSchemaDiff(oldProvider.ResourceMap(), newProvider.ResourceMap())
+var (
+	oldFrameworkProvider = oldFwprovider.New(oldProvider.Provider())
+	newFrameworkProvider = newFwprovider.New(newProvider.Provider())
+
+	schemaDiff = diff.ComputeSchemaDiffWithMetadata(
+		mergeResourceMaps(oldProvider.ResourceMap(), diff.FrameworkResourceMap(context.Background(), oldFrameworkProvider)),
+		mergeResourceMaps(newProvider.ResourceMap(), diff.FrameworkResourceMap(context.Background(), newFrameworkProvider)),
+		loadMetadata("old/google/services"),
+		loadMetadata("new/google/services"),
+	)
+)
+
+// computeFunctionSchemaDiff diffs the provider-defined functions of the old and
+// new providers.
+func computeFunctionSchemaDiff() (diff.FunctionSchemaDiff, error) {
+	oldFunctions, err := diff.FrameworkFunctionMap(context.Background(), oldFrameworkProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error loading functions of the old provider: %w", err)
+	}
+	newFunctions, err := diff.FrameworkFunctionMap(context.Background(), newFrameworkProvider)
+	if err != nil {
+		return nil, fmt.Errorf("error loading functions of the new provider: %w", err)
+	}
+	return diff.ComputeFunctionSchemaDiff(oldFunctions, newFunctions), nil
+}
+
+// loadMetadata reads the meta.yaml files of a provider checked out by `make
+// build`. Without them, rules that rely on metadata don't report anything.
+func loadMetadata(dir string) diff.Metadata {
+	metadata, err := diff.LoadMetadata(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading metadata from %s: %v\n", dir, err)
+	}
+	return metadata
+}
+
+// mergeResourceMaps combines the SDKv2 and Plugin Framework resource maps so
+// that a resource moving between the two is diffed as a single resource.
+func mergeResourceMaps(sdkResources, frameworkResources map[string]*schema.Resource) map[string]*schema.Resource {
+	merged := make(map[string]*schema.Resource, len(sdkResources)+len(frameworkResources))
+	for name, r := range sdkResources {
+		merged[name] = r
+	}
+	for name, r := range frameworkResources {
+		merged[name] = r
+	}
+	return merged
+}
 
 type simpleSchemaDiff struct {
 	AddedResources, ModifiedResources, RemovedResources []string
@@ -0,0 +1,227 @@
+// Package coverage reports which fields of every resource in the provider are
+// set by an acceptance test, and which are changed between the steps of a
+// test so that their update path is exercised.
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Report is the coverage of every resource in a provider.
+type Report struct {
+	Summary   Summary                      `json:"summary"`
+	Resources map[string]*ResourceCoverage `json:"resources"`
+	// Trend compares the report with a previous run, if one was given.
+	Trend *Trend `json:"trend,omitempty"`
+}
+
+// ResourceCoverage is the coverage of a single resource.
+type ResourceCoverage struct {
+	Summary Summary `json:"summary"`
+	// Tests are the names of the tests that configure the resource.
+	Tests  []string                  `json:"tests"`
+	Fields map[string]*FieldCoverage `json:"fields"`
+	// Previous is the summary of the resource in the previous run, if it was
+	// in it.
+	Previous *Summary `json:"previous,omitempty"`
+}
+
+// FieldCoverage is the coverage of a single field, keyed by its flattened
+// name such as "build_config.source".
+type FieldCoverage struct {
+	// Tested is true when a test step sets the field.
+	Tested bool `json:"tested"`
+	// Updatable is false when changing the field recreates the resource.
+	Updatable bool `json:"updatable"`
+	// Updated is true when the field is changed, set or unset between two
+	// steps of a test that both configure the resource.
+	Updated bool `json:"updated"`
+}
+
+// Summary counts the fields of a resource, or of every resource.
+type Summary struct {
+	Fields    int `json:"fields"`
+	Tested    int `json:"tested"`
+	Updatable int `json:"updatable"`
+	// Updated counts the updatable fields that are updated by a test.
+	Updated int `json:"updated"`
+}
+
+func (s *Summary) add(other Summary) {
+	s.Fields += other.Fields
+	s.Tested += other.Tested
+	s.Updatable += other.Updatable
+	s.Updated += other.Updated
+}
+
+// TestedPercent returns the percentage of fields that are tested.
+func (s Summary) TestedPercent() float64 {
+	return percent(s.Tested, s.Fields)
+}
+
+// UpdatedPercent returns the percentage of updatable fields that are updated.
+func (s Summary) UpdatedPercent() float64 {
+	return percent(s.Updated, s.Updatable)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(n) / float64(total)
+}
+
+// Compute returns the coverage of resources by tests.
+func Compute(resources map[string]*schema.Resource, tests []*reader.Test) *Report {
+	report := &Report{Resources: make(map[string]*ResourceCoverage, len(resources))}
+	for name, r := range resources {
+		rc := &ResourceCoverage{Tests: []string{}, Fields: make(map[string]*FieldCoverage)}
+		addFields(rc.Fields, name, "", r.Schema, false)
+		report.Resources[name] = rc
+	}
+
+	for _, test := range tests {
+		for i, step := range test.Steps {
+			for resourceType, configs := range step {
+				rc, ok := report.Resources[resourceType]
+				if !ok {
+					continue
+				}
+				rc.Tests = append(rc.Tests, test.Name)
+				for resourceName, config := range configs {
+					for field := range config {
+						if fc, ok := rc.Fields[field]; ok {
+							fc.Tested = true
+						}
+					}
+					if i == 0 {
+						continue
+					}
+					previous, ok := test.Steps[i-1][resourceType][resourceName]
+					if !ok {
+						continue
+					}
+					for _, field := range changedFields(previous, config) {
+						if fc, ok := rc.Fields[field]; ok {
+							fc.Updated = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, rc := range report.Resources {
+		rc.Tests = dedupe(rc.Tests)
+		for _, fc := range rc.Fields {
+			rc.Summary.Fields++
+			if fc.Tested {
+				rc.Summary.Tested++
+			}
+			if fc.Updatable {
+				rc.Summary.Updatable++
+				if fc.Updated {
+					rc.Summary.Updated++
+				}
+			}
+		}
+		report.Summary.add(rc.Summary)
+	}
+	return report
+}
+
+// addFields adds the fields a test can set, skipping output-only fields and
+// the blocks that hold nested fields. Fields under a ForceNew block aren't
+// updatable either.
+func addFields(fields map[string]*FieldCoverage, resource, parent string, s map[string]*schema.Schema, forceNew bool) {
+	for name, field := range s {
+		key := name
+		if parent != "" {
+			key = parent + "." + name
+		}
+		// Framework resources declare their timeouts block in the schema,
+		// where SDKv2 resources keep it apart.
+		if key == "timeouts" {
+			continue
+		}
+		if skipField(resource, key) {
+			continue
+		}
+		if nested, ok := field.Elem.(*schema.Resource); ok {
+			addFields(fields, resource, key, nested.Schema, forceNew || field.ForceNew)
+			continue
+		}
+		if field.Computed && !field.Optional {
+			continue
+		}
+		fields[key] = &FieldCoverage{Updatable: !forceNew && !field.ForceNew}
+	}
+}
+
+// skipField matches the fields the missing test detector doesn't ask tests
+// for.
+func skipField(resource, field string) bool {
+	switch field {
+	case "project", "deletion_policy":
+		return true
+	}
+	if field == "condition" || strings.HasPrefix(field, "condition.") {
+		for _, suffix := range []string{"_iam_member", "_iam_policy", "_iam_binding"} {
+			if strings.HasSuffix(resource, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// changedFields returns the fields whose configuration differs between two
+// steps. Values are compared as written in the test, so a field set to the
+// same format substitution in both steps is not detected as changed.
+func changedFields(before, after reader.Resource) []string {
+	var changed []string
+	for field, value := range after {
+		if fmt.Sprint(before[field]) != fmt.Sprint(value) {
+			changed = append(changed, field)
+		}
+	}
+	for field := range before {
+		if _, ok := after[field]; !ok {
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// Load reads a report written as JSON by a previous run.
+func Load(path string) (*Report, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report Report
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,182 @@
+package coverage
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
+	"github.com/google/go-cmp/cmp"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var testResources = map[string]*schema.Resource{
+	"google_x": {
+		Schema: map[string]*schema.Schema{
+			"name":    {Type: schema.TypeString, Required: true, ForceNew: true},
+			"labels":  {Type: schema.TypeMap, Optional: true},
+			"size":    {Type: schema.TypeInt, Optional: true},
+			"id_out":  {Type: schema.TypeString, Computed: true},
+			"project": {Type: schema.TypeString, Optional: true},
+			"config": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {Type: schema.TypeBool, Optional: true},
+					},
+				},
+			},
+			"source": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uri": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+		},
+	},
+	"google_y": {
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Required: true},
+		},
+	},
+}
+
+var testTests = []*reader.Test{
+	{
+		Name: "TestAccX_update",
+		Steps: []reader.Step{
+			{"google_x": reader.Resources{"primary": reader.Resource{"name": `"x"`, "labels": `{a = "b"}`, "config.enabled": "true"}}},
+			{"google_x": reader.Resources{"primary": reader.Resource{"name": `"x"`, "labels": `{a = "c"}`}}},
+		},
+	},
+	{
+		Name: "TestAccX_basic",
+		Steps: []reader.Step{
+			{
+				"google_x":   reader.Resources{"primary": reader.Resource{"name": `"x"`, "size": "1"}},
+				"google_net": reader.Resources{"default": reader.Resource{"name": `"net"`}},
+			},
+			{"google_x": reader.Resources{"secondary": reader.Resource{"name": `"x"`, "size": "2"}}},
+		},
+	},
+}
+
+func TestCompute(t *testing.T) {
+	want := &Report{
+		Summary: Summary{Fields: 6, Tested: 4, Updatable: 4, Updated: 2},
+		Resources: map[string]*ResourceCoverage{
+			"google_x": {
+				Summary: Summary{Fields: 5, Tested: 4, Updatable: 3, Updated: 2},
+				Tests:   []string{"TestAccX_basic", "TestAccX_update"},
+				Fields: map[string]*FieldCoverage{
+					"name":           {Tested: true},
+					"labels":         {Tested: true, Updatable: true, Updated: true},
+					"size":           {Tested: true, Updatable: true},
+					"config.enabled": {Tested: true, Updatable: true, Updated: true},
+					"source.uri":     {},
+				},
+			},
+			"google_y": {
+				Summary: Summary{Fields: 1, Updatable: 1},
+				Tests:   []string{},
+				Fields: map[string]*FieldCoverage{
+					"name": {Updatable: true},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, Compute(testResources, testTests)); diff != "" {
+		t.Errorf("Compute() differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestComputeFramework(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"google_x": diff.ConvertFrameworkSchema(context.Background(), fwschema.Schema{
+			Attributes: map[string]fwschema.Attribute{
+				"name": fwschema.StringAttribute{Required: true},
+			},
+			Blocks: map[string]fwschema.Block{
+				// The shape of the block timeouts.Block returns.
+				"timeouts": fwschema.SingleNestedBlock{
+					Attributes: map[string]fwschema.Attribute{
+						"create": fwschema.StringAttribute{Optional: true},
+						"delete": fwschema.StringAttribute{Optional: true},
+					},
+				},
+			},
+		}),
+	}
+	want := map[string]*FieldCoverage{
+		"name": {Tested: true, Updatable: true},
+	}
+	if diff := cmp.Diff(want, Compute(resources, testTests).Resources["google_x"].Fields); diff != "" {
+		t.Errorf("Compute() fields differ (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	previous := &Report{
+		Summary: Summary{Fields: 2, Tested: 1, Updatable: 2, Updated: 1},
+		Resources: map[string]*ResourceCoverage{
+			"google_x": {
+				Summary: Summary{Fields: 2, Tested: 1, Updatable: 2, Updated: 1},
+				Fields: map[string]*FieldCoverage{
+					"labels": {Updatable: true},
+					"size":   {Tested: true, Updatable: true, Updated: true},
+				},
+			},
+			"google_removed": {},
+		},
+	}
+	current := Compute(testResources, testTests)
+	Compare(previous, current)
+
+	want := &Trend{
+		Previous:        previous.Summary,
+		NewlyTested:     []string{"google_x.labels"},
+		NoLongerTested:  []string{},
+		NewlyUpdated:    []string{"google_x.labels"},
+		NoLongerUpdated: []string{"google_x.size"},
+	}
+	if diff := cmp.Diff(want, current.Trend); diff != "" {
+		t.Errorf("Compare() trend differs (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(&previous.Resources["google_x"].Summary, current.Resources["google_x"].Previous); diff != "" {
+		t.Errorf("Compare() previous summary differs (-want +got):\n%s", diff)
+	}
+	if current.Resources["google_y"].Previous != nil {
+		t.Errorf("Compare() set a previous summary for a new resource")
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	report := Compute(testResources, testTests)
+	Compare(report, report)
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	// html/template escapes the sign of the changes.
+	for _, want := range []string{
+		"4 of 6 fields tested (66.7%, &#43;0.0)",
+		"<td><code>google_x</code></td>\n<td>2</td>\n<td>4/5 (&#43;0)</td>",
+		`<td class="missing"><code>size</code> </td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteHTML() = %s\nwant it to contain %q", got, want)
+		}
+	}
+	if strings.Index(got, "google_y") > strings.Index(got, "google_x") {
+		t.Errorf("WriteHTML() didn't list the least tested resource first")
+	}
+}
@@ -0,0 +1,96 @@
+package coverage
+
+import (
+	"html/template"
+	"io"
+	"sort"
+)
+
+var htmlTemplate = template.Must(template.New("coverage").Funcs(template.FuncMap{
+	"delta":  func(current, previous float64) float64 { return current - previous },
+	"change": func(current, previous int) int { return current - previous },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Test coverage</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; }
+.missing { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Test coverage</h1>
+<p>
+{{.Summary.Tested}} of {{.Summary.Fields}} fields tested ({{printf "%.1f" .Summary.TestedPercent}}%{{with .Trend}}, {{printf "%+.1f" (delta $.Summary.TestedPercent .Previous.TestedPercent)}}{{end}}),
+{{.Summary.Updated}} of {{.Summary.Updatable}} updatable fields updated ({{printf "%.1f" .Summary.UpdatedPercent}}%{{with .Trend}}, {{printf "%+.1f" (delta $.Summary.UpdatedPercent .Previous.UpdatedPercent)}}{{end}}).
+</p>
+{{- with .Trend}}
+<h2>Changes since the previous run</h2>
+<ul>
+<li>Newly tested: {{len .NewlyTested}}</li>
+<li class="missing">No longer tested: {{range .NoLongerTested}}<code>{{.}}</code> {{end}}</li>
+<li>Newly updated: {{len .NewlyUpdated}}</li>
+<li class="missing">No longer updated: {{range .NoLongerUpdated}}<code>{{.}}</code> {{end}}</li>
+</ul>
+{{- end}}
+<h2>Resources</h2>
+<table>
+<tr><th>Resource</th><th>Tests</th><th>Tested</th><th>Updated</th><th>Untested fields</th><th>Fields without an update test</th></tr>
+{{- range $r := .Resources}}
+<tr>
+<td><code>{{.Name}}</code></td>
+<td>{{.Tests}}</td>
+<td>{{.Summary.Tested}}/{{.Summary.Fields}}{{with .Previous}} ({{printf "%+d" (change $r.Summary.Tested .Tested)}}){{end}}</td>
+<td>{{.Summary.Updated}}/{{.Summary.Updatable}}{{with .Previous}} ({{printf "%+d" (change $r.Summary.Updated .Updated)}}){{end}}</td>
+<td class="missing">{{range .Untested}}<code>{{.}}</code> {{end}}</td>
+<td class="missing">{{range .NotUpdated}}<code>{{.}}</code> {{end}}</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+type htmlResource struct {
+	Name       string
+	Tests      int
+	Summary    Summary
+	Previous   *Summary
+	Untested   []string
+	NotUpdated []string
+}
+
+// WriteHTML writes the report as an HTML page, listing the resources with the
+// fewest tested fields first.
+func WriteHTML(w io.Writer, r *Report) error {
+	var resources []htmlResource
+	for name, rc := range r.Resources {
+		hr := htmlResource{Name: name, Tests: len(rc.Tests), Summary: rc.Summary, Previous: rc.Previous}
+		for field, fc := range rc.Fields {
+			if !fc.Tested {
+				hr.Untested = append(hr.Untested, field)
+			}
+			if fc.Updatable && !fc.Updated {
+				hr.NotUpdated = append(hr.NotUpdated, field)
+			}
+		}
+		sort.Strings(hr.Untested)
+		sort.Strings(hr.NotUpdated)
+		resources = append(resources, hr)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		pi, pj := resources[i].Summary.TestedPercent(), resources[j].Summary.TestedPercent()
+		if pi != pj {
+			return pi < pj
+		}
+		return resources[i].Name < resources[j].Name
+	})
+	return htmlTemplate.Execute(w, struct {
+		Summary   Summary
+		Trend     *Trend
+		Resources []htmlResource
+	}{r.Summary, r.Trend, resources})
+}
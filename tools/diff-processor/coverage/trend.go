@@ -0,0 +1,57 @@
+package coverage
+
+import "sort"
+
+// Trend lists the changes in coverage since a previous run. Fields are named
+// "resource.field".
+type Trend struct {
+	Previous        Summary  `json:"previous"`
+	NewlyTested     []string `json:"newly_tested"`
+	NoLongerTested  []string `json:"no_longer_tested"`
+	NewlyUpdated    []string `json:"newly_updated"`
+	NoLongerUpdated []string `json:"no_longer_updated"`
+}
+
+// Compare sets the trend of current against a previous report, and the
+// previous summary of each resource that was in it. Fields that are only in
+// one of the reports are not part of the trend.
+func Compare(previous, current *Report) {
+	trend := &Trend{
+		Previous:        previous.Summary,
+		NewlyTested:     []string{},
+		NoLongerTested:  []string{},
+		NewlyUpdated:    []string{},
+		NoLongerUpdated: []string{},
+	}
+	for name, rc := range current.Resources {
+		prc, ok := previous.Resources[name]
+		if !ok {
+			continue
+		}
+		summary := prc.Summary
+		rc.Previous = &summary
+		for field, fc := range rc.Fields {
+			pfc, ok := prc.Fields[field]
+			if !ok {
+				continue
+			}
+			key := name + "." + field
+			switch {
+			case fc.Tested && !pfc.Tested:
+				trend.NewlyTested = append(trend.NewlyTested, key)
+			case !fc.Tested && pfc.Tested:
+				trend.NoLongerTested = append(trend.NoLongerTested, key)
+			}
+			switch {
+			case fc.Updated && !pfc.Updated:
+				trend.NewlyUpdated = append(trend.NewlyUpdated, key)
+			case !fc.Updated && pfc.Updated:
+				trend.NoLongerUpdated = append(trend.NoLongerUpdated, key)
+			}
+		}
+	}
+	for _, fields := range [][]string{trend.NewlyTested, trend.NoLongerTested, trend.NewlyUpdated, trend.NoLongerUpdated} {
+		sort.Strings(fields)
+	}
+	current.Trend = trend
+}
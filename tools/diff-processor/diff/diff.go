@@ -2,6 +2,7 @@ package diff
 
 import (
 	"reflect"
+	"runtime"
 	"strings"
 
 	"github.com/google/go-cmp/cmp"
@@ -14,6 +15,7 @@ type SchemaDiff map[string]ResourceDiff
 type ResourceDiffInterface interface {
 	IsNewResource() bool
 	IsFieldInNewNestedStructure(fieldPath string) bool
+	IsNewField(fieldPath string) bool
 }
 
 type ResourceDiff struct {
@@ -43,6 +45,10 @@ type ResourceConfigDiff struct {
 type FieldDiff struct {
 	Old *schema.Schema
 	New *schema.Schema
+	// OldMetadata and NewMetadata are set for fields with validation in
+	// their meta.yaml file.
+	OldMetadata *FieldMetadata
+	NewMetadata *FieldMetadata
 }
 
 type FlattenedSchemaRaw struct {
@@ -51,6 +57,13 @@ type FlattenedSchemaRaw struct {
 }
 
 func ComputeSchemaDiff(oldResourceMap, newResourceMap map[string]*schema.Resource) SchemaDiff {
+	return ComputeSchemaDiffWithMetadata(oldResourceMap, newResourceMap, nil, nil)
+}
+
+// ComputeSchemaDiffWithMetadata is ComputeSchemaDiff that also diffs the
+// validation recorded in meta.yaml files, since validation functions can only
+// be compared by whether they are set.
+func ComputeSchemaDiffWithMetadata(oldResourceMap, newResourceMap map[string]*schema.Resource, oldMetadata, newMetadata Metadata) SchemaDiff {
 	schemaDiff := make(SchemaDiff)
 	for resource := range union(oldResourceMap, newResourceMap) {
 		// Compute diff between old and new resources and fields.
@@ -75,9 +88,14 @@ func ComputeSchemaDiff(oldResourceMap, newResourceMap map[string]*schema.Resourc
 		for key := range union(flattenedOldSchema, flattenedNewSchema) {
 			oldField := flattenedOldSchema[key]
 			newField := flattenedNewSchema[key]
+			oldFieldMetadata := fieldMetadata(oldMetadata, resource, key, oldField)
+			newFieldMetadata := fieldMetadata(newMetadata, resource, key, newField)
 			if fieldDiff, fieldSetsDiff, changed := diffFields(oldField, newField, key); changed {
+				fieldDiff.OldMetadata, fieldDiff.NewMetadata = oldFieldMetadata, newFieldMetadata
 				resourceDiff.Fields[key] = fieldDiff
 				resourceDiff.FieldSets = mergeFieldSetsDiff(resourceDiff.FieldSets, fieldSetsDiff)
+			} else if !cmp.Equal(oldFieldMetadata, newFieldMetadata) {
+				resourceDiff.Fields[key] = FieldDiff{Old: oldField, New: newField, OldMetadata: oldFieldMetadata, NewMetadata: newFieldMetadata}
 			}
 		}
 		if len(resourceDiff.Fields) > 0 || !cmp.Equal(resourceDiff.ResourceConfig.Old, resourceDiff.ResourceConfig.New) {
@@ -87,6 +105,16 @@ func ComputeSchemaDiff(oldResourceMap, newResourceMap map[string]*schema.Resourc
 	return schemaDiff
 }
 
+func fieldMetadata(metadata Metadata, resource, field string, s *schema.Schema) *FieldMetadata {
+	if s == nil {
+		return nil
+	}
+	if m, ok := metadata[resource][field]; ok {
+		return &m
+	}
+	return nil
+}
+
 func flattenSchema(parentKey string, schemaObj map[string]*schema.Schema) map[string]*schema.Schema {
 	flattened := make(map[string]*schema.Schema)
 
@@ -270,6 +298,11 @@ func funcsChanged(oldField, newField *schema.Schema) bool {
 	if funcChanged(oldField.Set, newField.Set) {
 		return true
 	}
+	// Set hash functions decide which elements are equal, so unlike other
+	// functions a named one being replaced is treated as a change.
+	if oldName, newName := FuncName(oldField.Set), FuncName(newField.Set); IsNamedFunc(oldName) && IsNamedFunc(newName) && oldName != newName {
+		return true
+	}
 	if funcChanged(oldField.ValidateFunc, newField.ValidateFunc) {
 		return true
 	}
@@ -293,6 +326,24 @@ func funcChanged(oldFunc, newFunc interface{}) bool {
 	return false
 }
 
+// FuncName returns the name of a function qualified by its package name but
+// not its import path, for example tpgresource.SelfLinkRelativePathHash, so
+// that names match between the old and new provider. It returns "" for nil.
+func FuncName(f interface{}) string {
+	v := reflect.ValueOf(f)
+	if !v.IsValid() || v.IsNil() {
+		return ""
+	}
+	name := runtime.FuncForPC(v.Pointer()).Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// IsNamedFunc returns whether name, as returned by FuncName, is a top-level
+// function rather than a closure or method, whose names aren't stable.
+func IsNamedFunc(name string) bool {
+	return strings.Count(name, ".") == 1
+}
+
 func mergeFieldSetsDiff(allFields ResourceFieldSetsDiff, currentField ResourceFieldSetsDiff) ResourceFieldSetsDiff {
 	allFields.Old = mergeResourceFieldSets(allFields.Old, currentField.Old)
 	allFields.New = mergeResourceFieldSets(allFields.New, currentField.New)
@@ -354,3 +405,14 @@ func (rd ResourceDiff) IsFieldInNewNestedStructure(fieldPath string) bool {
 
 	return !parentExistsInOld && parentExistsInNew
 }
+
+// IsNewField determines if a field is absent from the old schema. fieldPath
+// may use either the flattened form or the indexed form used by ConflictsWith,
+// such as "parent.0.child".
+func (rd ResourceDiff) IsNewField(fieldPath string) bool {
+	if rd.IsNewResource() {
+		return true
+	}
+	_, existsInOld := rd.FlattenedSchema.Old[strings.ReplaceAll(fieldPath, ".0.", ".")]
+	return !existsInOld
+}
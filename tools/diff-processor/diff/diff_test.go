@@ -1906,3 +1906,39 @@ func TestIsFieldInNewNestedStructure(t *testing.T) {
 		})
 	}
 }
+
+func hashA(interface{}) int { return 0 }
+
+func hashB(interface{}) int { return 1 }
+
+func TestSetHashChanged(t *testing.T) {
+	closure := func(interface{}) int { return 0 }
+	cases := []struct {
+		name     string
+		old, new schema.SchemaSetFunc
+		want     bool
+	}{
+		{name: "same function", old: hashA, new: hashA, want: false},
+		{name: "different function", old: hashA, new: hashB, want: true},
+		{name: "closure", old: hashA, new: closure, want: false},
+		{name: "added", old: nil, new: hashA, want: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldField := &schema.Schema{Type: schema.TypeSet, Set: tc.old}
+			newField := &schema.Schema{Type: schema.TypeSet, Set: tc.new}
+			if got := funcsChanged(oldField, newField); got != tc.want {
+				t.Errorf("funcsChanged() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFuncName(t *testing.T) {
+	if got := FuncName(hashA); got != "diff.hashA" {
+		t.Errorf("FuncName(hashA) = %q, want diff.hashA", got)
+	}
+	if got := FuncName((schema.SchemaSetFunc)(nil)); got != "" {
+		t.Errorf("FuncName(nil) = %q, want empty", got)
+	}
+}
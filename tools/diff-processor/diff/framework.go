@@ -0,0 +1,346 @@
+package diff
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// FrameworkResourceMap returns the resources served by a Plugin Framework
+// provider, keyed by resource type name. Each schema is converted to its
+// closest SDKv2 equivalent so that it can be merged with the SDKv2 resource
+// map and passed to ComputeSchemaDiff.
+func FrameworkResourceMap(ctx context.Context, p provider.Provider) map[string]*schema.Resource {
+	providerMetadata := provider.MetadataResponse{}
+	p.Metadata(ctx, provider.MetadataRequest{}, &providerMetadata)
+
+	resourceMap := make(map[string]*schema.Resource)
+	for _, newResource := range p.Resources(ctx) {
+		r := newResource()
+
+		metadata := resource.MetadataResponse{}
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: providerMetadata.TypeName}, &metadata)
+
+		resp := resource.SchemaResponse{}
+		r.Schema(ctx, resource.SchemaRequest{}, &resp)
+		resourceMap[metadata.TypeName] = ConvertFrameworkSchema(ctx, resp.Schema)
+	}
+	return resourceMap
+}
+
+// ConvertFrameworkSchema converts a Plugin Framework resource schema to an
+// SDKv2 resource. Only the schema properties checked by the breaking change
+// rules are populated.
+func ConvertFrameworkSchema(ctx context.Context, s fwschema.Schema) *schema.Resource {
+	return &schema.Resource{
+		Schema:             convertFrameworkObject(ctx, s.Attributes, s.Blocks),
+		DeprecationMessage: s.DeprecationMessage,
+		Description:        s.Description,
+	}
+}
+
+func convertFrameworkObject(ctx context.Context, attributes map[string]fwschema.Attribute, blocks map[string]fwschema.Block) map[string]*schema.Schema {
+	converted := make(map[string]*schema.Schema, len(attributes)+len(blocks))
+	for name, a := range attributes {
+		converted[name] = convertFrameworkAttribute(ctx, a)
+	}
+	for name, b := range blocks {
+		converted[name] = convertFrameworkBlock(ctx, b)
+	}
+	return converted
+}
+
+func convertFrameworkAttribute(ctx context.Context, a fwschema.Attribute) *schema.Schema {
+	s := &schema.Schema{
+		Required:    a.IsRequired(),
+		Optional:    a.IsOptional(),
+		Computed:    a.IsComputed(),
+		Sensitive:   a.IsSensitive(),
+		Deprecated:  a.GetDeprecationMessage(),
+		Description: a.GetDescription(),
+		ConfigMode:  schema.SchemaConfigModeAttr,
+		ForceNew:    frameworkRequiresReplace(ctx, a),
+		Default:     frameworkDefault(ctx, a),
+	}
+	s.Type, s.Elem, s.MaxItems = convertFrameworkType(a.GetType())
+
+	switch v := a.(type) {
+	case fwschema.ListNestedAttribute:
+		s.Elem = &schema.Resource{Schema: convertFrameworkObject(ctx, v.NestedObject.Attributes, nil)}
+	case fwschema.SetNestedAttribute:
+		s.Elem = &schema.Resource{Schema: convertFrameworkObject(ctx, v.NestedObject.Attributes, nil)}
+	case fwschema.MapNestedAttribute:
+		s.Elem = &schema.Resource{Schema: convertFrameworkObject(ctx, v.NestedObject.Attributes, nil)}
+	case fwschema.SingleNestedAttribute:
+		s.Elem = &schema.Resource{Schema: convertFrameworkObject(ctx, v.Attributes, nil)}
+	}
+	return s
+}
+
+func convertFrameworkBlock(ctx context.Context, b fwschema.Block) *schema.Schema {
+	s := &schema.Schema{
+		Optional:    true,
+		Deprecated:  b.GetDeprecationMessage(),
+		Description: b.GetDescription(),
+		ConfigMode:  schema.SchemaConfigModeBlock,
+	}
+	switch v := b.(type) {
+	case fwschema.ListNestedBlock:
+		s.Type = schema.TypeList
+		s.Elem = &schema.Resource{Schema: convertFrameworkObject(ctx, v.NestedObject.Attributes, v.NestedObject.Blocks)}
+	case fwschema.SetNestedBlock:
+		s.Type = schema.TypeSet
+		s.Elem = &schema.Resource{Schema: convertFrameworkObject(ctx, v.NestedObject.Attributes, v.NestedObject.Blocks)}
+	case fwschema.SingleNestedBlock:
+		s.Type = schema.TypeList
+		s.MaxItems = 1
+		s.Elem = &schema.Resource{Schema: convertFrameworkObject(ctx, v.Attributes, v.Blocks)}
+	default:
+		s.Type, s.Elem, s.MaxItems = convertFrameworkType(b.Type())
+	}
+	return s
+}
+
+// convertFrameworkType returns the SDKv2 type, element and max items that
+// correspond to a framework attribute type. Objects are treated like SDKv2
+// single-item lists.
+func convertFrameworkType(t attr.Type) (schema.ValueType, interface{}, int) {
+	switch v := t.(type) {
+	case basetypes.StringTypable:
+		return schema.TypeString, nil, 0
+	case basetypes.BoolTypable:
+		return schema.TypeBool, nil, 0
+	case basetypes.Int64Typable, basetypes.Int32Typable:
+		return schema.TypeInt, nil, 0
+	case basetypes.Float64Typable, basetypes.Float32Typable, basetypes.NumberTypable:
+		return schema.TypeFloat, nil, 0
+	case attr.TypeWithElementType:
+		elem := convertFrameworkElemType(v.ElementType())
+		switch t.(type) {
+		case basetypes.SetTypable:
+			return schema.TypeSet, elem, 0
+		case basetypes.MapTypable:
+			return schema.TypeMap, elem, 0
+		default:
+			return schema.TypeList, elem, 0
+		}
+	case attr.TypeWithAttributeTypes:
+		object := make(map[string]*schema.Schema)
+		for name, attrType := range v.AttributeTypes() {
+			valueType, elem, maxItems := convertFrameworkType(attrType)
+			object[name] = &schema.Schema{Type: valueType, Elem: elem, MaxItems: maxItems}
+		}
+		return schema.TypeList, &schema.Resource{Schema: object}, 1
+	}
+	return schema.TypeInvalid, nil, 0
+}
+
+func convertFrameworkElemType(t attr.Type) interface{} {
+	valueType, elem, maxItems := convertFrameworkType(t)
+	if r, ok := elem.(*schema.Resource); ok && maxItems == 0 {
+		return r
+	}
+	return &schema.Schema{Type: valueType, Elem: elem, MaxItems: maxItems}
+}
+
+// frameworkRequiresReplace reports whether an attribute has a plan modifier
+// that requires replacing the resource when its value changes, such as the
+// framework's RequiresReplace helpers. It's the equivalent of ForceNew. Each
+// modifier is run against an update changing the value from null to unknown.
+func frameworkRequiresReplace(ctx context.Context, a fwschema.Attribute) bool {
+	t := a.GetType()
+	oldValue, err := t.ValueFromTerraform(ctx, tftypes.NewValue(t.TerraformType(ctx), nil))
+	if err != nil {
+		return false
+	}
+	newValue, err := t.ValueFromTerraform(ctx, tftypes.NewValue(t.TerraformType(ctx), tftypes.UnknownValue))
+	if err != nil {
+		return false
+	}
+	// Neither the prior state nor the plan is null, so that modifiers don't
+	// skip the change as a create or destroy.
+	resourceValue := tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})
+	config := tfsdk.Config{Raw: resourceValue}
+	plan := tfsdk.Plan{Raw: resourceValue}
+	state := tfsdk.State{Raw: resourceValue}
+
+	switch v := a.(type) {
+	case interface{ StringPlanModifiers() []planmodifier.String }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.StringValuable.ToStringValue); ok {
+			for _, m := range v.StringPlanModifiers() {
+				resp := &planmodifier.StringResponse{PlanValue: n}
+				m.PlanModifyString(ctx, planmodifier.StringRequest{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ BoolPlanModifiers() []planmodifier.Bool }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.BoolValuable.ToBoolValue); ok {
+			for _, m := range v.BoolPlanModifiers() {
+				resp := &planmodifier.BoolResponse{PlanValue: n}
+				m.PlanModifyBool(ctx, planmodifier.BoolRequest{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ Int64PlanModifiers() []planmodifier.Int64 }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.Int64Valuable.ToInt64Value); ok {
+			for _, m := range v.Int64PlanModifiers() {
+				resp := &planmodifier.Int64Response{PlanValue: n}
+				m.PlanModifyInt64(ctx, planmodifier.Int64Request{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ Int32PlanModifiers() []planmodifier.Int32 }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.Int32Valuable.ToInt32Value); ok {
+			for _, m := range v.Int32PlanModifiers() {
+				resp := &planmodifier.Int32Response{PlanValue: n}
+				m.PlanModifyInt32(ctx, planmodifier.Int32Request{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ Float64PlanModifiers() []planmodifier.Float64 }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.Float64Valuable.ToFloat64Value); ok {
+			for _, m := range v.Float64PlanModifiers() {
+				resp := &planmodifier.Float64Response{PlanValue: n}
+				m.PlanModifyFloat64(ctx, planmodifier.Float64Request{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ Float32PlanModifiers() []planmodifier.Float32 }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.Float32Valuable.ToFloat32Value); ok {
+			for _, m := range v.Float32PlanModifiers() {
+				resp := &planmodifier.Float32Response{PlanValue: n}
+				m.PlanModifyFloat32(ctx, planmodifier.Float32Request{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ NumberPlanModifiers() []planmodifier.Number }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.NumberValuable.ToNumberValue); ok {
+			for _, m := range v.NumberPlanModifiers() {
+				resp := &planmodifier.NumberResponse{PlanValue: n}
+				m.PlanModifyNumber(ctx, planmodifier.NumberRequest{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ ListPlanModifiers() []planmodifier.List }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.ListValuable.ToListValue); ok {
+			for _, m := range v.ListPlanModifiers() {
+				resp := &planmodifier.ListResponse{PlanValue: n}
+				m.PlanModifyList(ctx, planmodifier.ListRequest{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ SetPlanModifiers() []planmodifier.Set }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.SetValuable.ToSetValue); ok {
+			for _, m := range v.SetPlanModifiers() {
+				resp := &planmodifier.SetResponse{PlanValue: n}
+				m.PlanModifySet(ctx, planmodifier.SetRequest{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ MapPlanModifiers() []planmodifier.Map }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.MapValuable.ToMapValue); ok {
+			for _, m := range v.MapPlanModifiers() {
+				resp := &planmodifier.MapResponse{PlanValue: n}
+				m.PlanModifyMap(ctx, planmodifier.MapRequest{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	case interface{ ObjectPlanModifiers() []planmodifier.Object }:
+		if o, n, ok := typedValues(ctx, oldValue, newValue, basetypes.ObjectValuable.ToObjectValue); ok {
+			for _, m := range v.ObjectPlanModifiers() {
+				resp := &planmodifier.ObjectResponse{PlanValue: n}
+				m.PlanModifyObject(ctx, planmodifier.ObjectRequest{Config: config, ConfigValue: n, Plan: plan, PlanValue: n, State: state, StateValue: o}, resp)
+				if resp.RequiresReplace {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// typedValues converts oldValue and newValue to the value type that plan
+// modifiers of their attribute take, using to, the conversion method of the
+// type's Valuable interface such as basetypes.StringValuable.ToStringValue.
+func typedValues[I any, V any](ctx context.Context, oldValue, newValue attr.Value, to func(I, context.Context) (V, diag.Diagnostics)) (V, V, bool) {
+	var zero V
+	oldValuable, ok := oldValue.(I)
+	if !ok {
+		return zero, zero, false
+	}
+	newValuable, ok := newValue.(I)
+	if !ok {
+		return zero, zero, false
+	}
+	o, diags := to(oldValuable, ctx)
+	if diags.HasError() {
+		return zero, zero, false
+	}
+	n, diags := to(newValuable, ctx)
+	if diags.HasError() {
+		return zero, zero, false
+	}
+	return o, n, true
+}
+
+// frameworkDefault returns the static default of a primitive attribute using
+// the same Go types as SDKv2 defaults, or nil if there is no default.
+func frameworkDefault(ctx context.Context, a fwschema.Attribute) interface{} {
+	switch v := a.(type) {
+	case interface{ StringDefaultValue() defaults.String }:
+		if d := v.StringDefaultValue(); d != nil {
+			resp := defaults.StringResponse{}
+			d.DefaultString(ctx, defaults.StringRequest{}, &resp)
+			return resp.PlanValue.ValueString()
+		}
+	case interface{ BoolDefaultValue() defaults.Bool }:
+		if d := v.BoolDefaultValue(); d != nil {
+			resp := defaults.BoolResponse{}
+			d.DefaultBool(ctx, defaults.BoolRequest{}, &resp)
+			return resp.PlanValue.ValueBool()
+		}
+	case interface{ Int64DefaultValue() defaults.Int64 }:
+		if d := v.Int64DefaultValue(); d != nil {
+			resp := defaults.Int64Response{}
+			d.DefaultInt64(ctx, defaults.Int64Request{}, &resp)
+			return int(resp.PlanValue.ValueInt64())
+		}
+	case interface{ Float64DefaultValue() defaults.Float64 }:
+		if d := v.Float64DefaultValue(); d != nil {
+			resp := defaults.Float64Response{}
+			d.DefaultFloat64(ctx, defaults.Float64Request{}, &resp)
+			return resp.PlanValue.ValueFloat64()
+		}
+	}
+	return nil
+}
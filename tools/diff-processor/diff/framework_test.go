@@ -0,0 +1,400 @@
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestConvertFrameworkSchema(t *testing.T) {
+	cases := map[string]struct {
+		attributes map[string]fwschema.Attribute
+		blocks     map[string]fwschema.Block
+		want       map[string]*schema.Schema
+	}{
+		"primitive attributes": {
+			attributes: map[string]fwschema.Attribute{
+				"name": fwschema.StringAttribute{
+					Required:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				},
+				"size": fwschema.Int64Attribute{
+					Optional: true,
+					Computed: true,
+					Default:  int64default.StaticInt64(3),
+				},
+				"enabled": fwschema.BoolAttribute{
+					Computed:           true,
+					DeprecationMessage: "deprecated",
+				},
+				"ratio": fwschema.Float64Attribute{Optional: true, Sensitive: true},
+			},
+			want: map[string]*schema.Schema{
+				"name":    {Type: schema.TypeString, Required: true, ForceNew: true, ConfigMode: schema.SchemaConfigModeAttr},
+				"size":    {Type: schema.TypeInt, Optional: true, Computed: true, Default: 3, ConfigMode: schema.SchemaConfigModeAttr},
+				"enabled": {Type: schema.TypeBool, Computed: true, Deprecated: "deprecated", ConfigMode: schema.SchemaConfigModeAttr},
+				"ratio":   {Type: schema.TypeFloat, Optional: true, Sensitive: true, ConfigMode: schema.SchemaConfigModeAttr},
+			},
+		},
+		"requires replace": {
+			attributes: map[string]fwschema.Attribute{
+				"zone": fwschema.StringAttribute{
+					Optional:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplaceIfConfigured()},
+				},
+				"networks": fwschema.ListAttribute{
+					ElementType:   types.StringType,
+					Optional:      true,
+					PlanModifiers: []planmodifier.List{listplanmodifier.RequiresReplace()},
+				},
+				"config": fwschema.SingleNestedAttribute{
+					Optional:      true,
+					PlanModifiers: []planmodifier.Object{objectplanmodifier.RequiresReplace()},
+					Attributes: map[string]fwschema.Attribute{
+						"mode": fwschema.StringAttribute{Required: true},
+					},
+				},
+				"note": fwschema.StringAttribute{
+					Optional:      true,
+					PlanModifiers: []planmodifier.String{describedModifier{}},
+				},
+				"region": fwschema.StringAttribute{
+					Optional:      true,
+					Computed:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				},
+			},
+			want: map[string]*schema.Schema{
+				"zone":     {Type: schema.TypeString, Optional: true, ForceNew: true, ConfigMode: schema.SchemaConfigModeAttr},
+				"networks": {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true, ForceNew: true, ConfigMode: schema.SchemaConfigModeAttr},
+				"config": {
+					Type:       schema.TypeList,
+					MaxItems:   1,
+					Optional:   true,
+					ForceNew:   true,
+					ConfigMode: schema.SchemaConfigModeAttr,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"mode": {Type: schema.TypeString, Required: true, ConfigMode: schema.SchemaConfigModeAttr},
+					}},
+				},
+				"note":   {Type: schema.TypeString, Optional: true, ConfigMode: schema.SchemaConfigModeAttr},
+				"region": {Type: schema.TypeString, Optional: true, Computed: true, ConfigMode: schema.SchemaConfigModeAttr},
+			},
+		},
+		"collection attributes": {
+			attributes: map[string]fwschema.Attribute{
+				"tags":   fwschema.ListAttribute{ElementType: types.StringType, Optional: true},
+				"ports":  fwschema.SetAttribute{ElementType: types.Int64Type, Optional: true},
+				"labels": fwschema.MapAttribute{ElementType: types.StringType, Optional: true},
+			},
+			want: map[string]*schema.Schema{
+				"tags":   {Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true, ConfigMode: schema.SchemaConfigModeAttr},
+				"ports":  {Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeInt}, Optional: true, ConfigMode: schema.SchemaConfigModeAttr},
+				"labels": {Type: schema.TypeMap, Elem: &schema.Schema{Type: schema.TypeString}, Optional: true, ConfigMode: schema.SchemaConfigModeAttr},
+			},
+		},
+		"nested attributes": {
+			attributes: map[string]fwschema.Attribute{
+				"config": fwschema.SingleNestedAttribute{
+					Optional: true,
+					Attributes: map[string]fwschema.Attribute{
+						"mode": fwschema.StringAttribute{Required: true},
+					},
+				},
+				"rules": fwschema.ListNestedAttribute{
+					Optional: true,
+					NestedObject: fwschema.NestedAttributeObject{
+						Attributes: map[string]fwschema.Attribute{
+							"priority": fwschema.Int64Attribute{Optional: true},
+						},
+					},
+				},
+			},
+			want: map[string]*schema.Schema{
+				"config": {
+					Type:       schema.TypeList,
+					MaxItems:   1,
+					Optional:   true,
+					ConfigMode: schema.SchemaConfigModeAttr,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"mode": {Type: schema.TypeString, Required: true, ConfigMode: schema.SchemaConfigModeAttr},
+					}},
+				},
+				"rules": {
+					Type:       schema.TypeList,
+					Optional:   true,
+					ConfigMode: schema.SchemaConfigModeAttr,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"priority": {Type: schema.TypeInt, Optional: true, ConfigMode: schema.SchemaConfigModeAttr},
+					}},
+				},
+			},
+		},
+		"blocks": {
+			blocks: map[string]fwschema.Block{
+				"timeouts": fwschema.SingleNestedBlock{
+					Attributes: map[string]fwschema.Attribute{
+						"create": fwschema.StringAttribute{Optional: true},
+					},
+				},
+				"rule": fwschema.SetNestedBlock{
+					NestedObject: fwschema.NestedBlockObject{
+						Attributes: map[string]fwschema.Attribute{
+							"action": fwschema.StringAttribute{Required: true},
+						},
+					},
+				},
+			},
+			want: map[string]*schema.Schema{
+				"timeouts": {
+					Type:       schema.TypeList,
+					MaxItems:   1,
+					Optional:   true,
+					ConfigMode: schema.SchemaConfigModeBlock,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"create": {Type: schema.TypeString, Optional: true, ConfigMode: schema.SchemaConfigModeAttr},
+					}},
+				},
+				"rule": {
+					Type:       schema.TypeSet,
+					Optional:   true,
+					ConfigMode: schema.SchemaConfigModeBlock,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"action": {Type: schema.TypeString, Required: true, ConfigMode: schema.SchemaConfigModeAttr},
+					}},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		tc := tc
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			got := ConvertFrameworkSchema(context.Background(), fwschema.Schema{
+				Attributes: tc.attributes,
+				Blocks:     tc.blocks,
+			})
+			if diff := cmp.Diff(tc.want, got.Schema, cmpopts.IgnoreUnexported(schema.Schema{}, schema.Resource{})); diff != "" {
+				t.Errorf("ConvertFrameworkSchema() unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestComputeSchemaDiff_frameworkMigration(t *testing.T) {
+	oldResourceMap := map[string]*schema.Resource{
+		"google_x": {
+			Schema: map[string]*schema.Schema{
+				"name":     {Type: schema.TypeString, Required: true, ForceNew: true},
+				"location": {Type: schema.TypeString, Optional: true},
+			},
+		},
+	}
+	newResourceMap := map[string]*schema.Resource{
+		"google_x": ConvertFrameworkSchema(context.Background(), fwschema.Schema{
+			Attributes: map[string]fwschema.Attribute{
+				"name": fwschema.StringAttribute{
+					Required:      true,
+					PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+				},
+			},
+		}),
+	}
+
+	schemaDiff := ComputeSchemaDiff(oldResourceMap, newResourceMap)
+	resourceDiff, ok := schemaDiff["google_x"]
+	if !ok {
+		t.Fatalf("ComputeSchemaDiff() did not report google_x as changed")
+	}
+	if fieldDiff, ok := resourceDiff.Fields["location"]; !ok || fieldDiff.New != nil {
+		t.Errorf("ComputeSchemaDiff() did not report location as removed, got %v", resourceDiff.Fields)
+	}
+}
+
+func TestFrameworkProviderMaps(t *testing.T) {
+	p := &testFrameworkProvider{}
+
+	resources := FrameworkResourceMap(context.Background(), p)
+	if r, ok := resources["google_widget"]; !ok || r.Schema["name"] == nil {
+		t.Errorf("FrameworkResourceMap() = %v, want google_widget with a name field", resources)
+	}
+
+	functions, err := FrameworkFunctionMap(context.Background(), p)
+	if err != nil {
+		t.Fatalf("FrameworkFunctionMap() returned error: %v", err)
+	}
+	if f, ok := functions["widget_from_id"]; !ok || len(f.Parameters) != 1 {
+		t.Errorf("FrameworkFunctionMap() = %v, want widget_from_id with one parameter", functions)
+	}
+
+	// Embedding the interface hides the provider's Functions method.
+	withoutFunctions := struct{ provider.Provider }{p}
+	if _, err := FrameworkFunctionMap(context.Background(), withoutFunctions); err == nil {
+		t.Error("FrameworkFunctionMap() returned no error for a provider without functions")
+	}
+}
+
+func TestComputeFunctionSchemaDiff(t *testing.T) {
+	cases := map[string]struct {
+		old         map[string]function.Definition
+		new         map[string]function.Definition
+		wantChanged []string
+	}{
+		"unchanged": {
+			old: map[string]function.Definition{
+				"f": {Parameters: []function.Parameter{function.StringParameter{Name: "id"}}, Return: function.StringReturn{}},
+			},
+			new: map[string]function.Definition{
+				"f": {Parameters: []function.Parameter{function.StringParameter{Name: "id"}}, Return: function.StringReturn{}},
+			},
+		},
+		"description only": {
+			old: map[string]function.Definition{
+				"f": {Summary: "old", Return: function.StringReturn{}},
+			},
+			new: map[string]function.Definition{
+				"f": {Summary: "new", Return: function.StringReturn{}},
+			},
+		},
+		"added and removed": {
+			old: map[string]function.Definition{
+				"removed": {Return: function.StringReturn{}},
+			},
+			new: map[string]function.Definition{
+				"added": {Return: function.StringReturn{}},
+			},
+			wantChanged: []string{"added", "removed"},
+		},
+		"parameter type changed": {
+			old: map[string]function.Definition{
+				"f": {Parameters: []function.Parameter{function.StringParameter{Name: "id"}}, Return: function.StringReturn{}},
+			},
+			new: map[string]function.Definition{
+				"f": {Parameters: []function.Parameter{function.ListParameter{Name: "id", ElementType: types.StringType}}, Return: function.StringReturn{}},
+			},
+			wantChanged: []string{"f"},
+		},
+		"return type changed": {
+			old: map[string]function.Definition{
+				"f": {Return: function.StringReturn{}},
+			},
+			new: map[string]function.Definition{
+				"f": {Return: function.ObjectReturn{AttributeTypes: map[string]attr.Type{"id": types.StringType}}},
+			},
+			wantChanged: []string{"f"},
+		},
+	}
+
+	for tn, tc := range cases {
+		tc := tc
+		t.Run(tn, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for name := range ComputeFunctionSchemaDiff(tc.old, tc.new) {
+				got = append(got, name)
+			}
+			if diff := cmp.Diff(tc.wantChanged, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+				t.Errorf("ComputeFunctionSchemaDiff() unexpected diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// describedModifier describes itself like RequiresReplace, but never
+// requires replacement.
+type describedModifier struct{}
+
+func (m describedModifier) Description(context.Context) string {
+	return "If the value of this attribute changes, Terraform will destroy and recreate the resource."
+}
+
+func (m describedModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m describedModifier) PlanModifyString(context.Context, planmodifier.StringRequest, *planmodifier.StringResponse) {
+}
+
+type testFrameworkProvider struct{}
+
+func (p *testFrameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "google"
+}
+
+func (p *testFrameworkProvider) Schema(context.Context, provider.SchemaRequest, *provider.SchemaResponse) {
+}
+
+func (p *testFrameworkProvider) Configure(context.Context, provider.ConfigureRequest, *provider.ConfigureResponse) {
+}
+
+func (p *testFrameworkProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *testFrameworkProvider) Resources(context.Context) []func() resource.Resource {
+	return []func() resource.Resource{func() resource.Resource { return &testFrameworkResource{} }}
+}
+
+func (p *testFrameworkProvider) Functions(context.Context) []func() function.Function {
+	return []func() function.Function{func() function.Function { return &testFrameworkFunction{} }}
+}
+
+type testFrameworkResource struct{}
+
+func (r *testFrameworkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_widget"
+}
+
+func (r *testFrameworkResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = fwschema.Schema{
+		Attributes: map[string]fwschema.Attribute{
+			"name": fwschema.StringAttribute{Required: true},
+		},
+	}
+}
+
+func (r *testFrameworkResource) Create(context.Context, resource.CreateRequest, *resource.CreateResponse) {
+}
+
+func (r *testFrameworkResource) Read(context.Context, resource.ReadRequest, *resource.ReadResponse) {
+}
+
+func (r *testFrameworkResource) Update(context.Context, resource.UpdateRequest, *resource.UpdateResponse) {
+}
+
+func (r *testFrameworkResource) Delete(context.Context, resource.DeleteRequest, *resource.DeleteResponse) {
+}
+
+type testFrameworkFunction struct{}
+
+func (f *testFrameworkFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "widget_from_id"
+}
+
+func (f *testFrameworkFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Parameters: []function.Parameter{function.StringParameter{Name: "id"}},
+		Return:     function.StringReturn{},
+	}
+}
+
+func (f *testFrameworkFunction) Run(context.Context, function.RunRequest, *function.RunResponse) {
+}
@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+)
+
+// FunctionSchemaDiff is a map of provider-defined function names to the
+// definitions that changed between provider versions.
+type FunctionSchemaDiff map[string]FunctionDiff
+
+type FunctionDiff struct {
+	Old *function.Definition
+	New *function.Definition
+}
+
+// FrameworkFunctionMap returns the definitions of the provider-defined
+// functions served by a Plugin Framework provider, keyed by function name.
+func FrameworkFunctionMap(ctx context.Context, p provider.Provider) (map[string]function.Definition, error) {
+	fp, ok := p.(provider.ProviderWithFunctions)
+	if !ok {
+		return nil, fmt.Errorf("provider %T does not serve provider-defined functions", p)
+	}
+
+	functionMap := make(map[string]function.Definition)
+	for _, newFunction := range fp.Functions(ctx) {
+		f := newFunction()
+
+		metadata := function.MetadataResponse{}
+		f.Metadata(ctx, function.MetadataRequest{}, &metadata)
+
+		resp := function.DefinitionResponse{}
+		f.Definition(ctx, function.DefinitionRequest{}, &resp)
+		functionMap[metadata.Name] = resp.Definition
+	}
+	return functionMap, nil
+}
+
+func ComputeFunctionSchemaDiff(oldFunctionMap, newFunctionMap map[string]function.Definition) FunctionSchemaDiff {
+	functionSchemaDiff := make(FunctionSchemaDiff)
+	for name := range union(oldFunctionMap, newFunctionMap) {
+		functionDiff := FunctionDiff{}
+		if oldFunction, ok := oldFunctionMap[name]; ok {
+			functionDiff.Old = &oldFunction
+		}
+		if newFunction, ok := newFunctionMap[name]; ok {
+			functionDiff.New = &newFunction
+		}
+		if functionChanged(functionDiff.Old, functionDiff.New) {
+			functionSchemaDiff[name] = functionDiff
+		}
+	}
+	return functionSchemaDiff
+}
+
+func functionChanged(oldFunction, newFunction *function.Definition) bool {
+	if oldFunction == nil || newFunction == nil {
+		return oldFunction != newFunction
+	}
+	if len(oldFunction.Parameters) != len(newFunction.Parameters) {
+		return true
+	}
+	for i := range oldFunction.Parameters {
+		if !parameterEqual(oldFunction.Parameters[i], newFunction.Parameters[i]) {
+			return true
+		}
+	}
+	if !parameterEqual(oldFunction.VariadicParameter, newFunction.VariadicParameter) {
+		return true
+	}
+	if !typeEqual(returnType(oldFunction.Return), returnType(newFunction.Return)) {
+		return true
+	}
+	return oldFunction.DeprecationMessage != newFunction.DeprecationMessage
+}
+
+func parameterEqual(oldParam, newParam function.Parameter) bool {
+	if oldParam == nil || newParam == nil {
+		return oldParam == nil && newParam == nil
+	}
+	return oldParam.GetName() == newParam.GetName() &&
+		oldParam.GetAllowNullValue() == newParam.GetAllowNullValue() &&
+		oldParam.GetAllowUnknownValues() == newParam.GetAllowUnknownValues() &&
+		typeEqual(oldParam.GetType(), newParam.GetType())
+}
+
+func returnType(r function.Return) attr.Type {
+	if r == nil {
+		return nil
+	}
+	return r.GetType()
+}
+
+func typeEqual(oldType, newType attr.Type) bool {
+	if oldType == nil || newType == nil {
+		return oldType == nil && newType == nil
+	}
+	return oldType.Equal(newType)
+}
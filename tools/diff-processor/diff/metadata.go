@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/google"
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata holds what meta.yaml files say about fields that the schema can't,
+// keyed by resource and then by flattened field name, such as
+// "build_config.source".
+type Metadata map[string]map[string]FieldMetadata
+
+// FieldMetadata is the validation of a field, which the schema only holds as
+// a function.
+type FieldMetadata struct {
+	EnumValues      []string
+	ValidationRegex string
+}
+
+type metadataFile struct {
+	Resource string `yaml:"resource"`
+	Fields   []struct {
+		ApiField        string   `yaml:"api_field"`
+		Field           string   `yaml:"field"`
+		EnumValues      []string `yaml:"enum_values"`
+		ValidationRegex string   `yaml:"validation_regex"`
+	} `yaml:"fields"`
+}
+
+// LoadMetadata reads the meta.yaml files under dir, keeping the fields that
+// have validation. A missing dir has no metadata.
+func LoadMetadata(dir string) (Metadata, error) {
+	metadata := make(Metadata)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return metadata, nil
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, "_meta.yaml") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var file metadataFile
+		if err := yaml.Unmarshal(content, &file); err != nil {
+			// Unparseable files are skipped rather than failing the diff.
+			return nil
+		}
+		for _, f := range file.Fields {
+			if len(f.EnumValues) == 0 && f.ValidationRegex == "" {
+				continue
+			}
+			name := f.Field
+			if name == "" {
+				name = terraformFieldName(f.ApiField)
+			}
+			if metadata[file.Resource] == nil {
+				metadata[file.Resource] = make(map[string]FieldMetadata)
+			}
+			metadata[file.Resource][name] = FieldMetadata{EnumValues: f.EnumValues, ValidationRegex: f.ValidationRegex}
+		}
+		return nil
+	})
+	return metadata, err
+}
+
+func terraformFieldName(apiField string) string {
+	parts := strings.Split(apiField, ".")
+	for i, part := range parts {
+		parts[i] = google.Underscore(part)
+	}
+	return strings.Join(parts, ".")
+}
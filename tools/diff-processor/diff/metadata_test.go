@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestLoadMetadata(t *testing.T) {
+	dir := t.TempDir()
+	content := `resource: google_x_widget
+generation_type: mmv1
+fields:
+  - api_field: name
+  - api_field: sizeClass
+    enum_values:
+      - SMALL
+      - LARGE
+  - api_field: shape.ipv4Range
+    validation_regex: ^[0-9./]+$
+  - api_field: labels
+    field: user_labels
+    enum_values:
+      - A
+`
+	if err := os.MkdirAll(filepath.Join(dir, "x"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "x", "resource_x_widget_generated_meta.yaml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadMetadata(dir)
+	if err != nil {
+		t.Fatalf("LoadMetadata() = %v", err)
+	}
+	want := Metadata{
+		"google_x_widget": {
+			"size_class":       {EnumValues: []string{"SMALL", "LARGE"}},
+			"shape.ipv4_range": {ValidationRegex: "^[0-9./]+$"},
+			"user_labels":      {EnumValues: []string{"A"}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LoadMetadata() differs (-want +got):\n%s", diff)
+	}
+
+	missing, err := LoadMetadata(filepath.Join(dir, "missing"))
+	if err != nil || len(missing) != 0 {
+		t.Errorf("LoadMetadata(missing) = %v, %v, want no metadata", missing, err)
+	}
+}
+
+func TestComputeSchemaDiffWithMetadata(t *testing.T) {
+	resources := map[string]*schema.Resource{
+		"google_x_widget": {
+			Schema: map[string]*schema.Schema{
+				"size":  {Type: schema.TypeString, Optional: true},
+				"color": {Type: schema.TypeString, Optional: true},
+			},
+		},
+	}
+	oldMetadata := Metadata{"google_x_widget": {
+		"size":  {EnumValues: []string{"SMALL", "LARGE"}},
+		"color": {EnumValues: []string{"RED"}},
+	}}
+	newMetadata := Metadata{"google_x_widget": {
+		"size":  {EnumValues: []string{"SMALL"}},
+		"color": {EnumValues: []string{"RED"}},
+	}}
+	got := ComputeSchemaDiffWithMetadata(resources, resources, oldMetadata, newMetadata)
+	field := resources["google_x_widget"].Schema["size"]
+	want := map[string]FieldDiff{
+		"size": {
+			Old:         field,
+			New:         field,
+			OldMetadata: &FieldMetadata{EnumValues: []string{"SMALL", "LARGE"}},
+			NewMetadata: &FieldMetadata{EnumValues: []string{"SMALL"}},
+		},
+	}
+	if diff := cmp.Diff(want, got["google_x_widget"].Fields); diff != "" {
+		t.Errorf("ComputeSchemaDiffWithMetadata() fields differ (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Suppression is an allowlist entry for a finding that is intended, such as
+// a breaking change approved for a major release.
+type Suppression struct {
+	Resource string `yaml:"resource" json:"resource"`
+	// Field is empty for findings about the whole resource.
+	Field         string `yaml:"field,omitempty" json:"field,omitempty"`
+	Rule          string `yaml:"rule" json:"rule"`
+	Justification string `yaml:"justification" json:"justification"`
+}
+
+type allowlistFile struct {
+	Suppressions []Suppression `yaml:"suppressions"`
+}
+
+// LoadAllowlist reads the suppressions in an allowlist file. Every entry
+// needs a resource, rule and justification.
+func LoadAllowlist(path string) ([]Suppression, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file allowlistFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	for i, s := range file.Suppressions {
+		if s.Resource == "" || s.Rule == "" || s.Justification == "" {
+			return nil, fmt.Errorf("%s: suppression %d needs a resource, rule and justification", path, i+1)
+		}
+	}
+	return file.Suppressions, nil
+}
+
+// Apply marks the findings matched by an allowlist entry and returns the
+// entries for rules that matched nothing. Entries for other rules, which
+// belong to other commands, are ignored.
+func Apply(findings []Finding, allowlist []Suppression, rules []string) []Suppression {
+	checked := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		checked[rule] = true
+	}
+	var unused []Suppression
+	for _, s := range allowlist {
+		if !checked[s.Rule] {
+			continue
+		}
+		used := false
+		for i := range findings {
+			f := &findings[i]
+			if f.Rule == s.Rule && f.Resource == s.Resource && f.Field == s.Field {
+				s := s
+				f.Suppression = &s
+				used = true
+			}
+		}
+		if !used {
+			unused = append(unused, s)
+		}
+	}
+	return unused
+}
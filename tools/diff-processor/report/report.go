@@ -0,0 +1,82 @@
+// Package report defines the versioned JSON and SARIF output of the
+// diff-processor commands, and the allowlist used to suppress findings that
+// have been approved, such as breaking changes in a major release.
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// Version is the version of the JSON report format. It changes only when a
+// change would break existing readers, not when fields are added.
+const Version = 1
+
+// Levels of findings, matching SARIF result levels.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// Report is the output of a command.
+type Report struct {
+	Version int `json:"version"`
+	// Command is the diff-processor command that produced the report, such
+	// as "breaking-changes".
+	Command  string    `json:"command"`
+	Findings []Finding `json:"findings"`
+	// UnusedSuppressions are allowlist entries for the command's rules that
+	// matched no finding, and can be removed.
+	UnusedSuppressions []Suppression `json:"unused_suppressions"`
+}
+
+// Finding is a single problem reported by a command.
+type Finding struct {
+	Rule  string `json:"rule"`
+	Level string `json:"level"`
+	// Resource is the Terraform resource, data source or provider-defined
+	// function the finding is about.
+	Resource string `json:"resource"`
+	// Field is the flattened field name, such as "build_config.source", or
+	// empty for findings about the whole resource.
+	Field                  string `json:"field,omitempty"`
+	Message                string `json:"message"`
+	DocumentationReference string `json:"documentation_reference,omitempty"`
+	// Suppression is the allowlist entry that matched the finding, if any.
+	Suppression *Suppression `json:"suppression,omitempty"`
+}
+
+// New returns a report of findings for command, with the allowlist entries
+// for rules applied.
+func New(command string, findings []Finding, allowlist []Suppression, rules []string) Report {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Message < findings[j].Message
+	})
+	unused := Apply(findings, allowlist, rules)
+	if findings == nil {
+		findings = []Finding{}
+	}
+	if unused == nil {
+		unused = []Suppression{}
+	}
+	return Report{Version: Version, Command: command, Findings: findings, UnusedSuppressions: unused}
+}
+
+// Unsuppressed returns the findings no allowlist entry matched.
+func (r Report) Unsuppressed() []Finding {
+	var findings []Finding
+	for _, f := range r.Findings {
+		if f.Suppression == nil {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// WriteJSON writes the report as indented JSON.
+func WriteJSON(w io.Writer, r Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNew(t *testing.T) {
+	findings := []Finding{
+		{Rule: "field-becoming-sensitive", Resource: "google_x", Field: "secret", Message: "b"},
+		{Rule: "field-becoming-sensitive", Resource: "google_x", Field: "token", Message: "a"},
+		{Rule: "resource-map-resource-removal-or-rename", Resource: "google_y", Message: "c"},
+	}
+	allowlist := []Suppression{
+		{Resource: "google_x", Field: "secret", Rule: "field-becoming-sensitive", Justification: "approved"},
+		{Resource: "google_y", Rule: "resource-map-resource-removal-or-rename", Justification: "approved"},
+		{Resource: "google_z", Rule: "resource-map-resource-removal-or-rename", Justification: "stale"},
+		{Resource: "google_x", Field: "token", Rule: "missing-test", Justification: "other command"},
+	}
+	rules := []string{"field-becoming-sensitive", "resource-map-resource-removal-or-rename"}
+
+	got := New("breaking-changes", findings, allowlist, rules)
+	want := Report{
+		Version: Version,
+		Command: "breaking-changes",
+		Findings: []Finding{
+			{Rule: "field-becoming-sensitive", Resource: "google_x", Field: "token", Message: "a"},
+			{Rule: "field-becoming-sensitive", Resource: "google_x", Field: "secret", Message: "b", Suppression: &allowlist[0]},
+			{Rule: "resource-map-resource-removal-or-rename", Resource: "google_y", Message: "c", Suppression: &allowlist[1]},
+		},
+		UnusedSuppressions: []Suppression{allowlist[2]},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("New() differs (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.Findings[:1], got.Unsuppressed()); diff != "" {
+		t.Errorf("Unsuppressed() differs (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, New("detect-missing-tests", nil, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if want := `"findings": [],`; !strings.Contains(buf.String(), want) {
+		t.Errorf("WriteJSON() of an empty report = %s, want it to contain %s", buf.String(), want)
+	}
+}
+
+func TestLoadAllowlist(t *testing.T) {
+	cases := map[string]struct {
+		content string
+		want    []Suppression
+		wantErr bool
+	}{
+		"valid": {
+			content: "suppressions:\n- resource: google_x\n  field: a.b\n  rule: field-becoming-sensitive\n  justification: approved\n",
+			want:    []Suppression{{Resource: "google_x", Field: "a.b", Rule: "field-becoming-sensitive", Justification: "approved"}},
+		},
+		"empty": {
+			content: "suppressions: []\n",
+			want:    []Suppression{},
+		},
+		"missing justification": {
+			content: "suppressions:\n- resource: google_x\n  rule: field-becoming-sensitive\n",
+			wantErr: true,
+		},
+		"invalid yaml": {
+			content: "suppressions: {",
+			wantErr: true,
+		},
+	}
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "allowlist.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadAllowlist(path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("LoadAllowlist() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("LoadAllowlist() differs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	r := New("breaking-changes", []Finding{
+		{Rule: "field-becoming-sensitive", Level: LevelError, Resource: "google_x", Field: "secret", Message: "a", DocumentationReference: "https://example.com/sensitive"},
+		{Rule: "resource-map-resource-removal-or-rename", Level: LevelError, Resource: "google_y", Message: "b"},
+	}, []Suppression{
+		{Resource: "google_y", Rule: "resource-map-resource-removal-or-rename", Justification: "approved"},
+	}, []string{"resource-map-resource-removal-or-rename"})
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "diff-processor",
+				InformationURI: "https://googlecloudplatform.github.io/magic-modules/",
+				Rules: []sarifRule{
+					{ID: "field-becoming-sensitive", HelpURI: "https://example.com/sensitive"},
+					{ID: "resource-map-resource-removal-or-rename"},
+				},
+			}},
+			Results: []sarifResult{
+				{
+					RuleID:    "field-becoming-sensitive",
+					Level:     LevelError,
+					Message:   sarifMessage{Text: "a"},
+					Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "google_x.secret", Kind: "member"}}}},
+				},
+				{
+					RuleID:       "resource-map-resource-removal-or-rename",
+					Level:        LevelError,
+					Message:      sarifMessage{Text: "b"},
+					Locations:    []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "google_y", Kind: "resource"}}}},
+					Suppressions: []sarifSuppression{{Kind: "external", Justification: "approved"}},
+				},
+			},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("WriteSARIF() differs (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// SARIF 2.1.0, limited to the properties code scanning tools read. Findings
+// are about Terraform resources rather than files, so they have logical
+// locations.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID      string `json:"id"`
+	HelpURI string `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log. Suppressed findings are
+// kept with an external suppression, and unused suppressions are left out.
+func WriteSARIF(w io.Writer, r Report) error {
+	helpURIs := map[string]string{}
+	for _, f := range r.Findings {
+		helpURIs[f.Rule] = f.DocumentationReference
+	}
+	var ruleIDs []string
+	for id := range helpURIs {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+	driver := sarifDriver{
+		Name:           "diff-processor",
+		InformationURI: "https://googlecloudplatform.github.io/magic-modules/",
+		Rules:          []sarifRule{},
+	}
+	for _, id := range ruleIDs {
+		driver.Rules = append(driver.Rules, sarifRule{ID: id, HelpURI: helpURIs[id]})
+	}
+
+	results := []sarifResult{}
+	for _, f := range r.Findings {
+		location := sarifLogicalLocation{FullyQualifiedName: f.Resource, Kind: "resource"}
+		if f.Field != "" {
+			location = sarifLogicalLocation{FullyQualifiedName: f.Resource + "." + f.Field, Kind: "member"}
+		}
+		result := sarifResult{
+			RuleID:    f.Rule,
+			Level:     f.Level,
+			Message:   sarifMessage{Text: f.Message},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{location}}},
+		}
+		if f.Suppression != nil {
+			result.Suppressions = []sarifSuppression{{Kind: "external", Justification: f.Suppression.Justification}}
+		}
+		results = append(results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
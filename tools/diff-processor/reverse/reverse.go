@@ -0,0 +1,370 @@
+// Package reverse turns handwritten SDKv2 resources into mmv1 resource YAML,
+// as a starting point for migrating them to the generator.
+//
+// Only what can be read from a *schema.Resource is written: the schema's
+// types, behaviors, field sets, limits and named functions. The API mapping
+// (URLs, async, identity) is left for a person to fill in, along with
+// everything else the tool can't map, which is listed as TODOs.
+package reverse
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Resource is the part of an mmv1 resource definition the tool writes.
+type Resource struct {
+	Name               string      `yaml:"name"`
+	Description        string      `yaml:"description"`
+	DeprecationMessage string      `yaml:"deprecation_message,omitempty"`
+	Timeouts           *Timeouts   `yaml:"timeouts,omitempty"`
+	Properties         []*Property `yaml:"properties"`
+}
+
+type Timeouts struct {
+	InsertMinutes int `yaml:"insert_minutes"`
+	UpdateMinutes int `yaml:"update_minutes"`
+	DeleteMinutes int `yaml:"delete_minutes"`
+}
+
+// Property is the part of an mmv1 field definition the tool writes. Item
+// types have no name.
+type Property struct {
+	Name                 string      `yaml:"name,omitempty"`
+	Type                 string      `yaml:"type"`
+	Description          string      `yaml:"description,omitempty"`
+	Required             bool        `yaml:"required,omitempty"`
+	Output               bool        `yaml:"output,omitempty"`
+	DefaultFromApi       bool        `yaml:"default_from_api,omitempty"`
+	Immutable            bool        `yaml:"immutable,omitempty"`
+	Sensitive            bool        `yaml:"sensitive,omitempty"`
+	DeprecationMessage   string      `yaml:"deprecation_message,omitempty"`
+	DefaultValue         interface{} `yaml:"default_value,omitempty"`
+	SchemaConfigModeAttr bool        `yaml:"schema_config_mode_attr,omitempty"`
+	IsSet                bool        `yaml:"is_set,omitempty"`
+	MinSize              int         `yaml:"min_size,omitempty"`
+	MaxSize              int         `yaml:"max_size,omitempty"`
+	Conflicts            []string    `yaml:"conflicts,omitempty"`
+	ExactlyOneOf         []string    `yaml:"exactly_one_of,omitempty"`
+	AtLeastOneOf         []string    `yaml:"at_least_one_of,omitempty"`
+	RequiredWith         []string    `yaml:"required_with,omitempty"`
+	DiffSuppressFunc     string      `yaml:"diff_suppress_func,omitempty"`
+	StateFunc            string      `yaml:"state_func,omitempty"`
+	SetHashFunc          string      `yaml:"set_hash_func,omitempty"`
+	Validation           *Validation `yaml:"validation,omitempty"`
+	ItemType             *Property   `yaml:"item_type,omitempty"`
+	Properties           []*Property `yaml:"properties,omitempty"`
+}
+
+type Validation struct {
+	Function string `yaml:"function"`
+}
+
+// Result is a converted resource and what couldn't be converted.
+type Result struct {
+	Resource *Resource
+	// TODOs describe what a person needs to add or check, one per line.
+	TODOs []string
+}
+
+// Fields the generator adds on its own, from URL parameters or labels.
+var generatedFields = map[string]string{
+	"project":          "project is added by the generator when the URLs contain {{project}}",
+	"region":           "region is added by the generator when the URLs contain {{region}}; declare it under parameters if it is set by users",
+	"zone":             "zone is added by the generator when the URLs contain {{zone}}; declare it under parameters if it is set by users",
+	"terraform_labels": "terraform_labels is generated from labels",
+	"effective_labels": "effective_labels is generated from labels",
+}
+
+// Convert converts the SDKv2 resource named tfName, for example
+// google_compute_instance.
+func Convert(tfName string, r *schema.Resource) *Result {
+	c := &converter{}
+	resource := &Resource{
+		Name:               resourceName(tfName),
+		Description:        r.Description,
+		DeprecationMessage: r.DeprecationMessage,
+	}
+	c.todo("", fmt.Sprintf("name %s is %s without google_ and the first word, assumed to be the product; check it", resource.Name, tfName))
+	c.todo("", "fill in base_url, self_link, create_url and the rest of the API mapping, which can't be read from the schema")
+	if resource.Description == "" {
+		c.todo("", "add a description")
+	}
+
+	if t := r.Timeouts; t != nil {
+		resource.Timeouts = &Timeouts{InsertMinutes: minutes(t.Create), UpdateMinutes: minutes(t.Update), DeleteMinutes: minutes(t.Delete)}
+	}
+	if r.CustomizeDiff != nil {
+		c.todo("", "port CustomizeDiff, for example to custom_diff")
+	}
+	if r.Importer != nil {
+		c.todo("", "check that import_format matches the formats the handwritten importer accepts")
+	}
+	if r.SchemaVersion > 0 || len(r.StateUpgraders) > 0 {
+		c.todo("", fmt.Sprintf("port the state upgraders to schema version %d, with schema_version and state_upgraders", r.SchemaVersion))
+	}
+	if r.Identity != nil {
+		c.todo("", "check that the generated resource identity matches the handwritten one")
+	}
+
+	resource.Properties = c.properties("", r.Schema)
+	if len(c.undocumented) > 0 {
+		c.todo("", "add descriptions to "+strings.Join(c.undocumented, ", "))
+	}
+	return &Result{Resource: resource, TODOs: c.todos}
+}
+
+type converter struct {
+	todos []string
+	// undocumented lists the fields without a description.
+	undocumented []string
+}
+
+func (c *converter) todo(field, message string) {
+	if field != "" {
+		message = field + ": " + message
+	}
+	c.todos = append(c.todos, message)
+}
+
+func (c *converter) properties(parent string, fields map[string]*schema.Schema) []*Property {
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var properties []*Property
+	for _, name := range names {
+		path := name
+		if parent != "" {
+			path = parent + ".0." + name
+		}
+		if reason, ok := generatedFields[name]; ok && parent == "" {
+			c.todo(path, "skipped; "+reason)
+			continue
+		}
+		if p := c.property(path, name, fields[name]); p != nil {
+			properties = append(properties, p)
+		}
+	}
+	return properties
+}
+
+func (c *converter) property(path, name string, s *schema.Schema) *Property {
+	p := &Property{
+		Name:               apiName(name),
+		Description:        s.Description,
+		Required:           s.Required,
+		Output:             s.Computed && !s.Optional && !s.Required,
+		DefaultFromApi:     s.Computed && s.Optional,
+		Immutable:          s.ForceNew && !(s.Computed && !s.Optional && !s.Required),
+		Sensitive:          s.Sensitive,
+		DeprecationMessage: s.Deprecated,
+		DefaultValue:       s.Default,
+		Conflicts:          s.ConflictsWith,
+		ExactlyOneOf:       s.ExactlyOneOf,
+		AtLeastOneOf:       s.AtLeastOneOf,
+		RequiredWith:       s.RequiredWith,
+	}
+	if s.Description == "" {
+		c.undocumented = append(c.undocumented, path)
+	}
+	if path == "labels" && s.Type == schema.TypeMap {
+		p.Type = "KeyValueLabels"
+		c.todo(path, "converted to KeyValueLabels, which also generates terraform_labels and effective_labels")
+		return p
+	}
+
+	c.funcs(path, p, s)
+	switch s.ConfigMode {
+	case schema.SchemaConfigModeAttr:
+		p.SchemaConfigModeAttr = true
+	case schema.SchemaConfigModeBlock:
+		c.todo(path, "ConfigMode is SchemaConfigModeBlock, which can't be set in YAML")
+	}
+	if s.DiffSuppressOnRefresh {
+		c.todo(path, "DiffSuppressOnRefresh can't be set in YAML")
+	}
+	if s.WriteOnly {
+		c.todo(path, "convert to write_only; the generator adds its own _wo field")
+	}
+	if s.InputDefault != "" {
+		c.todo(path, "InputDefault can't be set in YAML")
+	}
+
+	switch s.Type {
+	case schema.TypeString, schema.TypeInt, schema.TypeBool, schema.TypeFloat:
+		p.Type = primitiveTypes[s.Type]
+	case schema.TypeMap:
+		p.Type = "KeyValuePairs"
+		if elem, ok := s.Elem.(*schema.Schema); !ok || elem.Type != schema.TypeString {
+			c.todo(path, "a map of anything other than strings needs a Map type with key_name and value_type")
+		}
+	case schema.TypeList, schema.TypeSet:
+		p.IsSet = s.Type == schema.TypeSet
+		switch elem := s.Elem.(type) {
+		case *schema.Resource:
+			if s.MaxItems == 1 && !p.IsSet {
+				p.Type = "NestedObject"
+				p.Properties = c.properties(path, elem.Schema)
+				if s.MinItems > 0 {
+					c.todo(path, "MinItems on a NestedObject can't be set in YAML")
+				}
+				return p
+			}
+			p.Type = "Array"
+			p.ItemType = &Property{Type: "NestedObject", Properties: c.properties(path, elem.Schema)}
+		case *schema.Schema:
+			p.Type = "Array"
+			p.ItemType = &Property{Type: primitiveTypes[elem.Type]}
+			if p.ItemType.Type == "" {
+				c.todo(path, "list items of type "+elem.Type.String()+" can't be converted")
+			}
+			if elem.ValidateFunc != nil || elem.ValidateDiagFunc != nil || elem.DiffSuppressFunc != nil || elem.StateFunc != nil {
+				c.todo(path, "functions on list items need item_validation or custom code")
+			}
+		default:
+			p.Type = "Array"
+			c.todo(path, "list without an Elem can't be converted")
+		}
+		p.MinSize, p.MaxSize = s.MinItems, s.MaxItems
+	default:
+		c.todo(path, "type "+s.Type.String()+" can't be converted")
+	}
+	return p
+}
+
+var primitiveTypes = map[schema.ValueType]string{
+	schema.TypeString: "String",
+	schema.TypeInt:    "Integer",
+	schema.TypeBool:   "Boolean",
+	schema.TypeFloat:  "Double",
+}
+
+// funcs names the schema's functions, which the generator refers to by their
+// package-qualified name.
+func (c *converter) funcs(path string, p *Property, s *schema.Schema) {
+	named := func(kind string, f interface{}) string {
+		name, ok := funcName(f)
+		if !ok && name != "" {
+			c.todo(path, fmt.Sprintf("%s %s is a closure or method; name a function or port it to custom code", kind, name))
+		}
+		if !ok {
+			return ""
+		}
+		return name
+	}
+	p.DiffSuppressFunc = named("DiffSuppressFunc", s.DiffSuppressFunc)
+	p.StateFunc = named("StateFunc", s.StateFunc)
+	p.SetHashFunc = named("Set", s.Set)
+	if function := named("ValidateFunc", s.ValidateFunc); function != "" {
+		p.Validation = &Validation{Function: function}
+	}
+	if name, _ := funcName(s.ValidateDiagFunc); name != "" {
+		c.todo(path, fmt.Sprintf("ValidateDiagFunc %s can't be set in YAML", name))
+	}
+	if name, _ := funcName(s.DefaultFunc); name != "" {
+		c.todo(path, fmt.Sprintf("DefaultFunc %s can't be set in YAML; consider default_from_api or custom code", name))
+	}
+}
+
+// funcName returns the package-qualified name of a function, and whether it
+// is a plain function that YAML can refer to.
+func funcName(f interface{}) (string, bool) {
+	v := reflect.ValueOf(f)
+	if !v.IsValid() || v.IsNil() {
+		return "", false
+	}
+	name := runtime.FuncForPC(v.Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	return name, strings.Count(name, ".") == 1
+}
+
+var (
+	upperRegexp    = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+	camelRegexp    = regexp.MustCompile(`([a-z\d])([A-Z])`)
+	snakeCaseParts = regexp.MustCompile(`_([a-z\d])`)
+)
+
+// underscore converts a name the way the generator derives Terraform names.
+func underscore(s string) string {
+	s = upperRegexp.ReplaceAllString(s, "${1}_${2}")
+	s = camelRegexp.ReplaceAllString(s, "${1}_${2}")
+	return strings.ToLower(strings.NewReplacer("-", "_", ".", "_").Replace(s))
+}
+
+// apiName returns the camelCase name mmv1 conventionally uses for a field, or
+// the Terraform name if the generator wouldn't turn it back into name.
+func apiName(name string) string {
+	camel := snakeCaseParts.ReplaceAllStringFunc(name, func(m string) string { return strings.ToUpper(m[1:]) })
+	if underscore(camel) != name {
+		return name
+	}
+	return camel
+}
+
+func resourceName(tfName string) string {
+	parts := strings.Split(strings.TrimPrefix(tfName, "google_"), "_")
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+	var name string
+	for _, part := range parts {
+		if part != "" {
+			name += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return name
+}
+
+func minutes(d *time.Duration) int {
+	if d == nil {
+		return 20
+	}
+	return int(d.Minutes())
+}
+
+const header = `# Copyright %d Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+---
+`
+
+// WriteYAML writes the resource with the license header for year and the
+// TODOs as comments at the top.
+func (r *Result) WriteYAML(w io.Writer, year int) error {
+	if _, err := fmt.Fprintf(w, header, year); err != nil {
+		return err
+	}
+	for _, todo := range r.TODOs {
+		if _, err := fmt.Fprintf(w, "# TODO: %s\n", todo); err != nil {
+			return err
+		}
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(r.Resource); err != nil {
+		return err
+	}
+	return enc.Close()
+}
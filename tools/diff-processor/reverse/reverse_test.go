@@ -0,0 +1,255 @@
+package reverse
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func caseDiffSuppress(_, old, new string, _ *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+func testResource() *schema.Resource {
+	timeout := 30 * time.Minute
+	return &schema.Resource{
+		Description: "A widget.",
+		Timeouts:    &schema.ResourceTimeout{Create: &timeout, Update: &timeout, Delete: &timeout},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the widget.",
+			},
+			"display_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DiffSuppressFunc: caseDiffSuppress,
+				ConflictsWith:    []string{"description"},
+				Description:      "The display name.",
+			},
+			"description": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"display_name"},
+				Description:   "A description.",
+			},
+			"size_gb": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "The size.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the widget was created.",
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Tags.",
+			},
+			"shape": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The shape.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"sides": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ExactlyOneOf: []string{"shape.0.sides", "shape.0.round"},
+							Description:  "The number of sides.",
+						},
+						"round": {
+							Type:         schema.TypeBool,
+							Optional:     true,
+							ExactlyOneOf: []string{"shape.0.sides", "shape.0.round"},
+							Description:  "Whether the shape is round.",
+						},
+					},
+				},
+			},
+			"parts": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Required: true,
+							StateFunc: func(v interface{}) string {
+								return strings.ToLower(v.(string))
+							},
+						},
+					},
+				},
+			},
+			"project": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+const wantYAML = `# Copyright 2026 Google Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+---
+# TODO: name Widget is google_example_widget without google_ and the first word, assumed to be the product; check it
+# TODO: fill in base_url, self_link, create_url and the rest of the API mapping, which can't be read from the schema
+# TODO: parts.0.id: StateFunc reverse.testResource.func1 is a closure or method; name a function or port it to custom code
+# TODO: project: skipped; project is added by the generator when the URLs contain {{project}}
+# TODO: add descriptions to parts, parts.0.id
+name: Widget
+description: A widget.
+timeouts:
+  insert_minutes: 30
+  update_minutes: 30
+  delete_minutes: 30
+properties:
+  - name: createTime
+    type: String
+    description: When the widget was created.
+    output: true
+  - name: description
+    type: String
+    description: A description.
+    conflicts:
+      - display_name
+  - name: displayName
+    type: String
+    description: The display name.
+    conflicts:
+      - description
+    diff_suppress_func: reverse.caseDiffSuppress
+  - name: name
+    type: String
+    description: The name of the widget.
+    required: true
+    immutable: true
+  - name: parts
+    type: Array
+    item_type:
+      type: NestedObject
+      properties:
+        - name: id
+          type: String
+          required: true
+  - name: shape
+    type: NestedObject
+    description: The shape.
+    properties:
+      - name: round
+        type: Boolean
+        description: Whether the shape is round.
+        exactly_one_of:
+          - shape.0.sides
+          - shape.0.round
+      - name: sides
+        type: Integer
+        description: The number of sides.
+        exactly_one_of:
+          - shape.0.sides
+          - shape.0.round
+  - name: sizeGb
+    type: Integer
+    description: The size.
+    default_from_api: true
+  - name: tags
+    type: Array
+    description: Tags.
+    is_set: true
+    set_hash_func: schema.HashString
+    item_type:
+      type: String
+`
+
+func TestConvert(t *testing.T) {
+	result := Convert("google_example_widget", testResource())
+	var b strings.Builder
+	if err := result.WriteYAML(&b, 2026); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantYAML, b.String()); diff != "" {
+		t.Errorf("WriteYAML() differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	original := testResource()
+	result := Convert("google_example_widget", original)
+	drift, err := Drift(original, result.Resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The closure can't be named, so the generated field has no StateFunc.
+	if diff := cmp.Diff([]string{"parts.id"}, drift); diff != "" {
+		t.Errorf("Drift() differs (-want +got):\n%s", diff)
+	}
+
+	original.Schema["parts"].Elem.(*schema.Resource).Schema["id"].StateFunc = nil
+	drift, err = Drift(original, Convert("google_example_widget", original).Resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 0 {
+		t.Errorf("Drift() = %v, want none", drift)
+	}
+}
+
+func TestDriftUsesGeneratorRules(t *testing.T) {
+	original := &schema.Resource{Schema: map[string]*schema.Schema{
+		"size_gb": {Type: schema.TypeInt, Required: true, Description: "The size."},
+	}}
+	// The generator makes a field with a default from the API optional, even
+	// when it's required.
+	r := &Resource{Name: "Widget", Properties: []*Property{
+		{Name: "sizeGb", Type: "Integer", Description: "The size.", Required: true, DefaultFromApi: true},
+	}}
+	drift, err := Drift(original, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"size_gb"}, drift); diff != "" {
+		t.Errorf("Drift() differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestAPIName(t *testing.T) {
+	cases := map[string]string{
+		"name":          "name",
+		"size_gb":       "sizeGb",
+		"ipv4_address":  "ipv4Address",
+		"enable_ipv6":   "enableIpv6",
+		"self_link":     "selfLink",
+		"url_map_2":     "url_map_2",
+		"service_a_b_c": "service_a_b_c",
+	}
+	for name, want := range cases {
+		if got := apiName(name); got != want {
+			t.Errorf("apiName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package reverse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/magic-modules/mmv1/api"
+	"github.com/GoogleCloudPlatform/magic-modules/tools/diff-processor/diff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Schema returns the schema the generator would produce for r, as far as
+// ComputeSchemaDiff can tell. r is decoded as an mmv1 resource, so the
+// generator's own types and field rules apply; this mirrors
+// schema_property.go.tmpl. Functions are placeholders, since the diff only
+// compares whether they are set.
+func Schema(r *Resource) (*schema.Resource, error) {
+	b, err := yaml.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	resource := &api.Resource{}
+	if err := yaml.Unmarshal(b, resource); err != nil {
+		return nil, fmt.Errorf("decoding %s as an mmv1 resource: %w", r.Name, err)
+	}
+	resource.SetDefault(&api.Product{Name: "Reverse"})
+
+	fields, err := fields(resource.OrderProperties(resource.UserProperites()))
+	if err != nil {
+		return nil, err
+	}
+	return &schema.Resource{Schema: fields}, nil
+}
+
+func fields(properties []*api.Type) (map[string]*schema.Schema, error) {
+	fields := make(map[string]*schema.Schema, len(properties))
+	for _, p := range properties {
+		s, err := field(p)
+		if err != nil {
+			return nil, err
+		}
+		fields[underscore(p.Name)] = s
+	}
+	return fields, nil
+}
+
+func field(p *api.Type) (*schema.Schema, error) {
+	s := &schema.Schema{
+		Deprecated:    p.DeprecationMessage,
+		ForceNew:      p.IsForceNew(),
+		Description:   p.GetDescription(),
+		Sensitive:     p.Sensitive,
+		WriteOnly:     p.WriteOnlyLegacy || p.WriteOnly,
+		Default:       p.DefaultValue,
+		ConflictsWith: p.GetPropertySchemaPathList(p.Conflicting()),
+		AtLeastOneOf:  p.GetPropertySchemaPathList(p.AtLeastOneOfList()),
+		ExactlyOneOf:  p.GetPropertySchemaPathList(p.ExactlyOneOfList()),
+		RequiredWith:  p.GetPropertySchemaPathList(p.RequiredWithList()),
+	}
+	switch {
+	case p.DefaultFromApi:
+		s.Computed = true
+		s.Optional = true
+	case p.Required:
+		s.Required = true
+	case p.Output:
+		s.Computed = true
+	default:
+		s.Optional = true
+	}
+	if !p.Output && (p.Validation.Regex != "" || p.Validation.Function != "" || p.IsA("Enum")) {
+		s.ValidateFunc = validateFunc
+	}
+	if p.DiffSuppressFunc != "" || (p.IsA("ResourceRef") && !p.Output) {
+		s.DiffSuppressFunc = diffSuppressFunc
+	}
+	if p.StateFunc != "" {
+		s.StateFunc = stateFunc
+	}
+
+	var err error
+	if s.Type, err = valueType(p.TFType(p.Type)); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.IsA("NestedObject"):
+		if !p.Output {
+			s.MaxItems = 1
+		}
+		elem, err := fields(p.ResourceMetadata.OrderProperties(p.UserProperties()))
+		if err != nil {
+			return nil, err
+		}
+		s.Elem = &schema.Resource{Schema: elem}
+	case p.IsA("Array"):
+		if p.IsSet {
+			s.Type = schema.TypeSet
+		}
+		if p.MinSize != nil {
+			s.MinItems = *p.MinSize
+		}
+		if p.MaxSize != nil {
+			s.MaxItems = *p.MaxSize
+		}
+		if s.Elem, err = itemSchema(p); err != nil {
+			return nil, err
+		}
+		if p.IsSet && (p.SetHashFunc != "" || p.ItemType.IsA("String") || p.ItemType.IsA("Enum")) {
+			s.Set = setHashFunc
+		}
+	case strings.HasPrefix(p.Type, "KeyValue"):
+		s.Elem = &schema.Schema{Type: schema.TypeString}
+	case p.IsA("Map"):
+		return nil, fmt.Errorf("field %s of type Map isn't written by the reverse tool", p.Name)
+	}
+	return s, nil
+}
+
+// itemSchema returns the Elem of an Array field.
+func itemSchema(p *api.Type) (interface{}, error) {
+	item := p.ItemType
+	if item.IsA("NestedObject") {
+		elem, err := fields(p.ResourceMetadata.OrderProperties(item.UserProperties()))
+		if err != nil {
+			return nil, err
+		}
+		return &schema.Resource{Schema: elem}, nil
+	}
+
+	t, err := valueType(p.TFType(item.Type))
+	if err != nil {
+		return nil, err
+	}
+	elem := &schema.Schema{Type: t}
+	if item.IsA("String") && item.DiffSuppressFunc != "" {
+		elem.DiffSuppressFunc = diffSuppressFunc
+	}
+	if item.IsA("ResourceRef") && !p.Output {
+		elem.DiffSuppressFunc = diffSuppressFunc
+	}
+	if !p.Output && (item.IsA("Enum") || p.ItemValidation.Regex != "" || p.ItemValidation.Function != "") {
+		elem.ValidateFunc = validateFunc
+	}
+	return elem, nil
+}
+
+// valueType returns the value type named by api.Type.TFType.
+func valueType(tfType string) (schema.ValueType, error) {
+	switch tfType {
+	case "schema.TypeBool":
+		return schema.TypeBool, nil
+	case "schema.TypeInt":
+		return schema.TypeInt, nil
+	case "schema.TypeFloat":
+		return schema.TypeFloat, nil
+	case "schema.TypeString":
+		return schema.TypeString, nil
+	case "schema.TypeList":
+		return schema.TypeList, nil
+	case "schema.TypeMap":
+		return schema.TypeMap, nil
+	case "schema.TypeSet":
+		return schema.TypeSet, nil
+	}
+	return schema.TypeInvalid, fmt.Errorf("unknown schema type %s", tfType)
+}
+
+// Placeholders for the functions the generator sets by name. They are
+// closures, which the diff only checks are set, rather than compares by name.
+var (
+	validateFunc     = func(interface{}, string) ([]string, []error) { return nil, nil }
+	diffSuppressFunc = func(string, string, string, *schema.ResourceData) bool { return false }
+	stateFunc        = func(interface{}) string { return "" }
+	setHashFunc      = func(interface{}) int { return 0 }
+)
+
+// Drift returns the fields of original whose schema differs from the one
+// generated from the converted resource, skipping the fields the generator
+// adds on its own. An empty result means the YAML reproduces the schema.
+func Drift(original *schema.Resource, r *Resource) ([]string, error) {
+	kept := &schema.Resource{Schema: map[string]*schema.Schema{}}
+	for name, s := range original.Schema {
+		if _, ok := generatedFields[name]; !ok {
+			kept.Schema[name] = s
+		}
+	}
+	generated, err := Schema(r)
+	if err != nil {
+		return nil, err
+	}
+	const name = "resource"
+	d := diff.ComputeSchemaDiff(map[string]*schema.Resource{name: kept}, map[string]*schema.Resource{name: generated})
+	var drift []string
+	for field := range d[name].Fields {
+		drift = append(drift, field)
+	}
+	sort.Strings(drift)
+	return drift, nil
+}
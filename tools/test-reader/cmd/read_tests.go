@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/magic-modules/tools/test-reader/reader"
@@ -34,8 +35,13 @@ func newReadTestsCmd(rootOptions *rootOptions) *cobra.Command {
 
 func (o *readTestsOptions) run(args []string) error {
 	allTests, errs := reader.ReadAllTests(args[0])
-	for path, err := range errs {
-		fmt.Printf("error reading path: %s, err: %v\n", path, err)
+	paths := make([]string, 0, len(errs))
+	for path := range errs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Printf("error reading path: %s, err: %v\n", path, errs[path])
 	}
 
 	total := 0
@@ -59,5 +65,8 @@ func (o *readTestsOptions) run(args []string) error {
 		total += 1
 	}
 	fmt.Printf("Found %d tests\n", total)
+	if len(errs) > 0 {
+		fmt.Printf("%d tests or files could not be read completely\n", len(errs))
+	}
 	return nil
 }
@@ -0,0 +1,730 @@
+package reader
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The evaluator computes config strings by propagating constants through the
+// Go code that builds them: string literals and concatenation, fmt.Sprintf,
+// acctest.Nprintf with map literal contexts, calls to helper functions in the
+// same service and the loops and conditions in them.
+//
+// Values that are only known when the test runs, such as random suffixes or
+// environment variables, are unknown. An unknown value in a string is written
+// as a format substitution, which readConfigStr replaces before parsing.
+
+// unknown is a value the evaluator can't compute, with the reason why.
+type unknown struct {
+	reason string
+}
+
+// loopControl is returned by statements that leave a loop iteration.
+type loopControl token.Token
+
+// scope holds the variables of a function invocation.
+type scope map[string]any
+
+const (
+	// maxCallDepth bounds recursion through helper functions.
+	maxCallDepth = 32
+	// maxIterations bounds the iterations of a single loop.
+	maxIterations = 1000
+)
+
+type evaluator struct {
+	fset      *token.FileSet
+	funcDecls map[string]*ast.FuncDecl // map of function names to function declarations
+	varDecls  map[string]ast.Expr      // map of package-level constant and variable names to values
+	depth     int
+	// evaluating holds the package-level values being evaluated, to break
+	// cycles.
+	evaluating map[string]bool
+}
+
+func newEvaluator(fset *token.FileSet, funcDecls map[string]*ast.FuncDecl, varDecls map[string]ast.Expr) *evaluator {
+	return &evaluator{fset: fset, funcDecls: funcDecls, varDecls: varDecls, evaluating: make(map[string]bool)}
+}
+
+// errorf returns an error prefixed with the position of node.
+func (e *evaluator) errorf(node ast.Node, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", e.fset.Position(node.Pos()), fmt.Sprintf(format, args...))
+}
+
+func (e *evaluator) unknownf(node ast.Node, format string, args ...any) unknown {
+	return unknown{reason: e.errorf(node, format, args...).Error()}
+}
+
+// config evaluates a step's Config expression to a string.
+func (e *evaluator) config(expr ast.Expr, env scope) (string, error) {
+	v, err := e.eval(expr, env)
+	if err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case unknown:
+		return "", fmt.Errorf("config is not known before the test runs: %s", v.reason)
+	}
+	return "", e.errorf(expr, "config is a %T, not a string", v)
+}
+
+// str returns v as it appears in a string built from it.
+func str(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case unknown:
+		return "%v"
+	}
+	return fmt.Sprint(v)
+}
+
+func (e *evaluator) eval(expr ast.Expr, env scope) (any, error) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		switch expr.Kind {
+		case token.STRING, token.CHAR:
+			return strconv.Unquote(expr.Value)
+		case token.INT:
+			return strconv.Atoi(expr.Value)
+		}
+		return e.unknownf(expr, "unsupported literal %s", expr.Value), nil
+	case *ast.ParenExpr:
+		return e.eval(expr.X, env)
+	case *ast.Ident:
+		return e.ident(expr, env)
+	case *ast.BinaryExpr:
+		return e.binary(expr, env)
+	case *ast.UnaryExpr:
+		x, err := e.eval(expr.X, env)
+		if err != nil {
+			return nil, err
+		}
+		switch x := x.(type) {
+		case bool:
+			if expr.Op == token.NOT {
+				return !x, nil
+			}
+		case int:
+			if expr.Op == token.SUB {
+				return -x, nil
+			}
+		case unknown:
+			return x, nil
+		}
+		return e.unknownf(expr, "unsupported operator %s", expr.Op), nil
+	case *ast.CompositeLit:
+		return e.compositeLit(expr, env)
+	case *ast.IndexExpr:
+		return e.index(expr, env)
+	case *ast.CallExpr:
+		return e.call(expr, env)
+	case *ast.SelectorExpr:
+		return e.unknownf(expr, "%s is not known before the test runs", exprString(expr)), nil
+	}
+	return e.unknownf(expr, "unsupported expression %T", expr), nil
+}
+
+func (e *evaluator) ident(ident *ast.Ident, env scope) (any, error) {
+	if v, ok := env[ident.Name]; ok {
+		return v, nil
+	}
+	switch ident.Name {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "nil":
+		return nil, nil
+	}
+	if expr, ok := e.varDecls[ident.Name]; ok && !e.evaluating[ident.Name] {
+		e.evaluating[ident.Name] = true
+		defer delete(e.evaluating, ident.Name)
+		return e.eval(expr, scope{})
+	}
+	return e.unknownf(ident, "%s is not known before the test runs", ident.Name), nil
+}
+
+func (e *evaluator) binary(expr *ast.BinaryExpr, env scope) (any, error) {
+	x, err := e.eval(expr.X, env)
+	if err != nil {
+		return nil, err
+	}
+	y, err := e.eval(expr.Y, env)
+	if err != nil {
+		return nil, err
+	}
+	return e.op(expr, expr.Op, x, y), nil
+}
+
+// op applies a binary operator to computed values.
+func (e *evaluator) op(node ast.Node, op token.Token, x, y any) any {
+	if op == token.ADD {
+		_, xString := x.(string)
+		_, yString := y.(string)
+		if xString || yString {
+			return str(x) + str(y)
+		}
+	}
+	if u, ok := x.(unknown); ok {
+		return u
+	}
+	if u, ok := y.(unknown); ok {
+		return u
+	}
+	if op == token.EQL || op == token.NEQ {
+		if !isScalar(x) || !isScalar(y) {
+			return e.unknownf(node, "unsupported comparison of %T and %T", x, y)
+		}
+		return (x == y) == (op == token.EQL)
+	}
+	switch x := x.(type) {
+	case int:
+		if y, ok := y.(int); ok {
+			switch op {
+			case token.ADD:
+				return x + y
+			case token.SUB:
+				return x - y
+			case token.MUL:
+				return x * y
+			case token.QUO:
+				if y != 0 {
+					return x / y
+				}
+			case token.REM:
+				if y != 0 {
+					return x % y
+				}
+			case token.LSS:
+				return x < y
+			case token.LEQ:
+				return x <= y
+			case token.GTR:
+				return x > y
+			case token.GEQ:
+				return x >= y
+			}
+		}
+	case bool:
+		if y, ok := y.(bool); ok {
+			switch op {
+			case token.LAND:
+				return x && y
+			case token.LOR:
+				return x || y
+			}
+		}
+	}
+	return e.unknownf(node, "unsupported operation %T %s %T", x, op, y)
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case nil, string, int, bool:
+		return true
+	}
+	return false
+}
+
+func (e *evaluator) compositeLit(lit *ast.CompositeLit, env scope) (any, error) {
+	switch lit.Type.(type) {
+	case *ast.MapType:
+		m := make(map[string]any, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			k, err := e.eval(kv.Key, env)
+			if err != nil {
+				return nil, err
+			}
+			v, err := e.eval(kv.Value, env)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := k.(unknown); ok {
+				return e.unknownf(kv.Key, "map key is not known before the test runs"), nil
+			}
+			m[str(k)] = v
+		}
+		return m, nil
+	case *ast.ArrayType:
+		s := make([]any, 0, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			if _, ok := elt.(*ast.KeyValueExpr); ok {
+				return e.unknownf(elt, "indexed slice literals are not supported"), nil
+			}
+			v, err := e.eval(elt, env)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+	}
+	return e.unknownf(lit, "unsupported composite literal of type %s", exprString(lit.Type)), nil
+}
+
+func (e *evaluator) index(expr *ast.IndexExpr, env scope) (any, error) {
+	x, err := e.eval(expr.X, env)
+	if err != nil {
+		return nil, err
+	}
+	i, err := e.eval(expr.Index, env)
+	if err != nil {
+		return nil, err
+	}
+	switch x := x.(type) {
+	case map[string]any:
+		if v, ok := x[str(i)]; ok {
+			return v, nil
+		}
+		return e.unknownf(expr, "key %s is not set in %s", str(i), exprString(expr.X)), nil
+	case []any:
+		if i, ok := i.(int); ok && i >= 0 && i < len(x) {
+			return x[i], nil
+		}
+	case unknown:
+		return x, nil
+	}
+	return e.unknownf(expr, "unsupported index expression"), nil
+}
+
+func (e *evaluator) call(call *ast.CallExpr, env scope) (any, error) {
+	var args []any
+	for _, arg := range call.Args {
+		v, err := e.eval(arg, env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		if fun.Name == "Nprintf" {
+			return nprintf(args), nil
+		}
+		if fun.Name == "len" && len(args) == 1 {
+			switch x := args[0].(type) {
+			case string:
+				return len(x), nil
+			case []any:
+				return len(x), nil
+			case map[string]any:
+				return len(x), nil
+			}
+		}
+		if funcDecl, ok := e.funcDecls[fun.Name]; ok {
+			return e.callFunc(call, funcDecl, args)
+		}
+	case *ast.SelectorExpr:
+		switch exprString(fun) {
+		case "fmt.Sprintf":
+			if len(args) > 0 {
+				if format, ok := args[0].(string); ok {
+					return sprintf(format, args[1:]), nil
+				}
+			}
+		case "acctest.Nprintf":
+			return nprintf(args), nil
+		case "strings.Join":
+			if len(args) == 2 {
+				if elems, ok := args[0].([]any); ok {
+					var ss []string
+					for _, elem := range elems {
+						ss = append(ss, str(elem))
+					}
+					return strings.Join(ss, str(args[1])), nil
+				}
+			}
+		default:
+			// As before the evaluator existed, other calls whose first argument
+			// is a literal config, such as formatting helpers from other
+			// packages, return it unchanged.
+			if len(call.Args) > 0 {
+				if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					return args[0], nil
+				}
+			}
+		}
+	}
+	return e.unknownf(call, "call to %s is not evaluated", exprString(call.Fun)), nil
+}
+
+// sprintf formats known arguments like fmt.Sprintf, and leaves the verbs of
+// unknown arguments in the result.
+var sprintfVerb = regexp.MustCompile(`%(%|[-+# 0]*[0-9]*(\.[0-9]*)?[a-zA-Z])`)
+
+func sprintf(format string, args []any) string {
+	i := 0
+	return sprintfVerb.ReplaceAllStringFunc(format, func(verb string) string {
+		if verb == "%%" {
+			return "%"
+		}
+		if i >= len(args) {
+			return verb
+		}
+		arg := args[i]
+		i++
+		if _, ok := arg.(unknown); ok {
+			return verb
+		}
+		return fmt.Sprintf(verb, arg)
+	})
+}
+
+// nprintf substitutes known context values like acctest.Nprintf, and leaves
+// the %{key} substitutions of unknown ones in the result.
+func nprintf(args []any) any {
+	if len(args) == 0 {
+		return unknown{reason: "Nprintf called without a template"}
+	}
+	format, ok := args[0].(string)
+	if !ok {
+		return args[0]
+	}
+	if len(args) < 2 {
+		return format
+	}
+	context, ok := args[1].(map[string]any)
+	if !ok {
+		return format
+	}
+	for key, value := range context {
+		if _, ok := value.(unknown); ok {
+			continue
+		}
+		format = strings.ReplaceAll(format, "%{"+key+"}", fmt.Sprintf("%v", value))
+	}
+	return format
+}
+
+func (e *evaluator) callFunc(call *ast.CallExpr, funcDecl *ast.FuncDecl, args []any) (any, error) {
+	if e.depth >= maxCallDepth {
+		return e.unknownf(call, "calls to %s nest too deeply", funcDecl.Name.Name), nil
+	}
+	e.depth++
+	defer func() { e.depth-- }()
+
+	env := make(scope)
+	i := 0
+	for _, field := range funcDecl.Type.Params.List {
+		for _, name := range field.Names {
+			if i < len(args) {
+				env[name.Name] = args[i]
+			} else {
+				env[name.Name] = e.unknownf(call, "missing argument %s", name.Name)
+			}
+			i++
+		}
+	}
+	v, returned, err := e.block(funcDecl.Body.List, env)
+	if err != nil {
+		return nil, fmt.Errorf("in %s: %w", funcDecl.Name.Name, err)
+	}
+	if !returned {
+		return e.unknownf(funcDecl, "%s doesn't return a value", funcDecl.Name.Name), nil
+	}
+	return v, nil
+}
+
+// block runs statements and returns the value of the return statement that
+// ends it, if any.
+func (e *evaluator) block(stmts []ast.Stmt, env scope) (any, bool, error) {
+	for _, stmt := range stmts {
+		v, returned, err := e.stmt(stmt, env)
+		if err != nil || returned {
+			return v, returned, err
+		}
+		if _, ok := v.(loopControl); ok {
+			return v, false, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (e *evaluator) stmt(stmt ast.Stmt, env scope) (any, bool, error) {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		if len(stmt.Results) == 0 {
+			return nil, true, nil
+		}
+		v, err := e.eval(stmt.Results[0], env)
+		return v, true, err
+	case *ast.AssignStmt:
+		return nil, false, e.assign(stmt, env)
+	case *ast.IncDecStmt:
+		ident, ok := stmt.X.(*ast.Ident)
+		if !ok {
+			return nil, false, e.errorf(stmt, "unsupported %s statement", stmt.Tok)
+		}
+		if x, ok := env[ident.Name].(int); ok {
+			if stmt.Tok == token.INC {
+				env[ident.Name] = x + 1
+			} else {
+				env[ident.Name] = x - 1
+			}
+		} else {
+			env[ident.Name] = e.unknownf(stmt, "%s is not known before the test runs", ident.Name)
+		}
+		return nil, false, nil
+	case *ast.DeclStmt:
+		return nil, false, e.decl(stmt, env)
+	case *ast.ExprStmt:
+		// Calls for their side effects, such as t.Helper(), don't change the
+		// config.
+		return nil, false, nil
+	case *ast.BlockStmt:
+		return e.block(stmt.List, env)
+	case *ast.IfStmt:
+		if stmt.Init != nil {
+			if _, _, err := e.stmt(stmt.Init, env); err != nil {
+				return nil, false, err
+			}
+		}
+		cond, err := e.eval(stmt.Cond, env)
+		if err != nil {
+			return nil, false, err
+		}
+		b, ok := cond.(bool)
+		if !ok {
+			return nil, false, e.errorf(stmt.Cond, "condition %s is not known before the test runs", exprString(stmt.Cond))
+		}
+		if b {
+			return e.block(stmt.Body.List, env)
+		}
+		if stmt.Else != nil {
+			return e.stmt(stmt.Else, env)
+		}
+		return nil, false, nil
+	case *ast.RangeStmt:
+		return e.rangeStmt(stmt, env)
+	case *ast.ForStmt:
+		return e.forStmt(stmt, env)
+	case *ast.BranchStmt:
+		if stmt.Label == nil && (stmt.Tok == token.BREAK || stmt.Tok == token.CONTINUE) {
+			return loopControl(stmt.Tok), false, nil
+		}
+	}
+	return nil, false, e.errorf(stmt, "unsupported statement %T", stmt)
+}
+
+func (e *evaluator) assign(stmt *ast.AssignStmt, env scope) error {
+	var values []any
+	if len(stmt.Lhs) == len(stmt.Rhs) {
+		for _, rhs := range stmt.Rhs {
+			v, err := e.eval(rhs, env)
+			if err != nil {
+				return err
+			}
+			values = append(values, v)
+		}
+	} else {
+		// Assignments of multiple return values, or comma-ok expressions.
+		for range stmt.Lhs {
+			values = append(values, e.unknownf(stmt, "multiple assignment is not evaluated"))
+		}
+	}
+	for i, lhs := range stmt.Lhs {
+		v := values[i]
+		switch lhs := lhs.(type) {
+		case *ast.Ident:
+			if lhs.Name == "_" {
+				continue
+			}
+			switch stmt.Tok {
+			case token.ADD_ASSIGN:
+				current, err := e.eval(lhs, env)
+				if err != nil {
+					return err
+				}
+				v = e.op(stmt, token.ADD, current, v)
+			case token.DEFINE, token.ASSIGN:
+			default:
+				return e.errorf(stmt, "unsupported assignment %s", stmt.Tok)
+			}
+			env[lhs.Name] = v
+		case *ast.IndexExpr:
+			m, err := e.eval(lhs.X, env)
+			if err != nil {
+				return err
+			}
+			k, err := e.eval(lhs.Index, env)
+			if err != nil {
+				return err
+			}
+			if m, ok := m.(map[string]any); ok && stmt.Tok == token.ASSIGN {
+				if _, ok := k.(unknown); !ok {
+					m[str(k)] = v
+					continue
+				}
+			}
+			return e.errorf(stmt, "unsupported assignment to %s", exprString(lhs))
+		default:
+			return e.errorf(stmt, "unsupported assignment to %s", exprString(lhs))
+		}
+	}
+	return nil
+}
+
+func (e *evaluator) decl(stmt *ast.DeclStmt, env scope) error {
+	genDecl, ok := stmt.Decl.(*ast.GenDecl)
+	if !ok || (genDecl.Tok != token.VAR && genDecl.Tok != token.CONST) {
+		return nil
+	}
+	for _, spec := range genDecl.Specs {
+		valueSpec, ok := spec.(*ast.ValueSpec)
+		if !ok {
+			continue
+		}
+		for i, name := range valueSpec.Names {
+			var v any
+			switch {
+			case i < len(valueSpec.Values):
+				var err error
+				if v, err = e.eval(valueSpec.Values[i], env); err != nil {
+					return err
+				}
+			case exprString(valueSpec.Type) == "string":
+				v = ""
+			case exprString(valueSpec.Type) == "int":
+				v = 0
+			default:
+				v = e.unknownf(name, "%s has no value", name.Name)
+			}
+			env[name.Name] = v
+		}
+	}
+	return nil
+}
+
+func (e *evaluator) rangeStmt(stmt *ast.RangeStmt, env scope) (any, bool, error) {
+	x, err := e.eval(stmt.X, env)
+	if err != nil {
+		return nil, false, err
+	}
+	var keys, values []any
+	switch x := x.(type) {
+	case []any:
+		for i, v := range x {
+			keys = append(keys, i)
+			values = append(values, v)
+		}
+	case map[string]any:
+		var sorted []string
+		for k := range x {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			keys = append(keys, k)
+			values = append(values, x[k])
+		}
+	case int:
+		for i := 0; i < x; i++ {
+			keys = append(keys, i)
+			values = append(values, nil)
+		}
+	default:
+		return nil, false, e.errorf(stmt.X, "range over %s, which is not known before the test runs", exprString(stmt.X))
+	}
+	for i := range keys {
+		for _, iteration := range []struct {
+			expr  ast.Expr
+			value any
+		}{{stmt.Key, keys[i]}, {stmt.Value, values[i]}} {
+			if ident, ok := iteration.expr.(*ast.Ident); ok && ident.Name != "_" {
+				env[ident.Name] = iteration.value
+			}
+		}
+		v, returned, err := e.block(stmt.Body.List, env)
+		if err != nil || returned {
+			return v, returned, err
+		}
+		if v == loopControl(token.BREAK) {
+			break
+		}
+	}
+	return nil, false, nil
+}
+
+func (e *evaluator) forStmt(stmt *ast.ForStmt, env scope) (any, bool, error) {
+	if stmt.Init != nil {
+		if _, _, err := e.stmt(stmt.Init, env); err != nil {
+			return nil, false, err
+		}
+	}
+	for i := 0; ; i++ {
+		if i == maxIterations {
+			return nil, false, e.errorf(stmt, "loop runs more than %d times", maxIterations)
+		}
+		if stmt.Cond != nil {
+			cond, err := e.eval(stmt.Cond, env)
+			if err != nil {
+				return nil, false, err
+			}
+			b, ok := cond.(bool)
+			if !ok {
+				return nil, false, e.errorf(stmt.Cond, "loop condition %s is not known before the test runs", exprString(stmt.Cond))
+			}
+			if !b {
+				break
+			}
+		}
+		v, returned, err := e.block(stmt.Body.List, env)
+		if err != nil || returned {
+			return v, returned, err
+		}
+		if v == loopControl(token.BREAK) {
+			break
+		}
+		if stmt.Post != nil {
+			if _, _, err := e.stmt(stmt.Post, env); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// exprString returns the source of simple expressions, for error messages.
+func exprString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case nil:
+		return ""
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return exprString(expr.X) + "." + expr.Sel.Name
+	case *ast.CallExpr:
+		return exprString(expr.Fun) + "(...)"
+	case *ast.IndexExpr:
+		return exprString(expr.X) + "[" + exprString(expr.Index) + "]"
+	case *ast.BasicLit:
+		return expr.Value
+	case *ast.UnaryExpr:
+		return expr.Op.String() + exprString(expr.X)
+	case *ast.BinaryExpr:
+		return exprString(expr.X) + " " + expr.Op.String() + " " + exprString(expr.Y)
+	case *ast.ParenExpr:
+		return "(" + exprString(expr.X) + ")"
+	case *ast.StarExpr:
+		return "*" + exprString(expr.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(expr.Elt)
+	case *ast.MapType:
+		return "map[" + exprString(expr.Key) + "]" + exprString(expr.Value)
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+	return fmt.Sprintf("%T", expr)
+}
@@ -0,0 +1,108 @@
+package reader
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestEvaluatorConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		src     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "sprintf with known and unknown arguments",
+			src:  `func config(name string) string { return fmt.Sprintf("%s-%d-%s-%%", "a", 1, name) }`,
+			want: "a-1-%s-%",
+		},
+		{
+			name: "nprintf with a context built in the function",
+			src: `func config() string {
+	context := map[string]interface{}{"a": "x", "b": acctest.RandString(t, 10)}
+	context["c"] = 3
+	return acctest.Nprintf("%{a} %{b} %{c}", context)
+}`,
+			want: "x %{b} 3",
+		},
+		{
+			name: "concatenation with an unknown value",
+			src:  `func config(name string) string { return "name-" + name }`,
+			want: "name-%v",
+		},
+		{
+			name: "range over a slice literal",
+			src: `func config() string {
+	var config string
+	for i, zone := range []string{"a", "b"} {
+		if i > 0 {
+			config += ","
+		}
+		config += zone
+	}
+	return strings.Join([]string{config, "c"}, ";")
+}`,
+			want: "a,b;c",
+		},
+		{
+			name: "package constant",
+			src: `const suffix = "-x"
+func config() string { return "name" + suffix }`,
+			want: "name-x",
+		},
+		{
+			name:    "unknown condition",
+			src:     `func config(enabled bool) string { if enabled { return "a" }; return "b" }`,
+			wantErr: "config.go:2:39: condition enabled is not known before the test runs",
+		},
+		{
+			name:    "unknown call",
+			src:     `func config() string { return other.Config() }`,
+			wantErr: "config.go:2:31: call to other.Config is not evaluated",
+		},
+		{
+			name:    "switch statement",
+			src:     `func config() string { switch { }; return "" }`,
+			wantErr: "config.go:2:24: unsupported statement *ast.SwitchStmt",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "config.go", "package service\n"+tc.src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			funcDecls := make(map[string]*ast.FuncDecl)
+			varDecls := make(map[string]ast.Expr)
+			for _, decl := range f.Decls {
+				switch decl := decl.(type) {
+				case *ast.FuncDecl:
+					funcDecls[decl.Name.Name] = decl
+				case *ast.GenDecl:
+					for _, spec := range decl.Specs {
+						valueSpec := spec.(*ast.ValueSpec)
+						varDecls[valueSpec.Names[0].Name] = valueSpec.Values[0]
+					}
+				}
+			}
+			e := newEvaluator(fset, funcDecls, varDecls)
+			got, err := e.config(&ast.CallExpr{Fun: ast.NewIdent("config"), Args: []ast.Expr{ast.NewIdent("unknownArg")}}, scope{})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("config() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("config() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("config() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
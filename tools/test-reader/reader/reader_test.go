@@ -34,7 +34,7 @@ func TestReadCoveredResourceTestFile(t *testing.T) {
 	} else if coveredResource, ok := coveredResources["resource"]; !ok {
 		t.Errorf("did not find a covered resource in %v", coveredResources)
 	} else if expectedResource := (Resource{
-		"field_four.field_five.field_six": "true",
+		"field_four.field_five.field_six": "0",
 		"field_one":                       "\"value-one\"",
 		"field_seven":                     "true",
 	}); !reflect.DeepEqual(coveredResource, expectedResource) {
@@ -247,3 +247,36 @@ func TestFlattenResource(t *testing.T) {
 		}
 	}
 }
+
+func TestReadEvaluatedConfigs(t *testing.T) {
+	tests, err := ReadTestFiles([]string{"testdata/service/evaluated_config_test.go"})
+	if err != nil {
+		t.Fatalf("error reading evaluated config test file: %v", err)
+	}
+	if len(tests) != 1 {
+		t.Fatalf("unexpected number of tests: %d, expected 1", len(tests))
+	}
+	test := tests[0]
+	expectedSteps := []Step{
+		{
+			"evaluated_instance": Resources{
+				"primary": Resource{
+					"name":         "\"instance-true\"",
+					"zone":         "\"us-central1-a\"",
+					"disk_size_gb": "10",
+					"machine_type": "\"e2-medium\"",
+				},
+			},
+		},
+		{
+			"evaluated_disk": Resources{
+				"disk0": Resource{"name": "\"disk0\""},
+				"disk1": Resource{"name": "\"disk1\""},
+				"disk2": Resource{"name": "\"disk2\""},
+			},
+		},
+	}
+	if !reflect.DeepEqual(test.Steps, expectedSteps) {
+		t.Errorf("found unexpected steps: %#v, expected %#v", test.Steps, expectedSteps)
+	}
+}
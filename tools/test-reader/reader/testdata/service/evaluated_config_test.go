@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-provider-google-beta/google-beta/acctest"
+)
+
+const evaluatedMachineType = "e2-medium"
+
+func TestAccEvaluatedConfig(t *testing.T) {
+	context := map[string]interface{}{
+		"random_suffix": acctest.RandString(t, 10),
+		"disk_size":     10,
+	}
+	context["zone"] = "us-central1-a"
+
+	acctest.VcrTest(t, resource.TestCase{
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEvaluatedConfig_instance(context),
+			},
+			{
+				Config: testAccEvaluatedConfig_disks(3),
+			},
+		},
+	})
+}
+
+func testAccEvaluatedConfig_instance(context map[string]interface{}) string {
+	return acctest.Nprintf(`
+resource "evaluated_instance" "primary" {
+  name         = "instance-%{random_suffix}"
+  zone         = "%{zone}"
+  disk_size_gb = %{disk_size}
+`, context) + fmt.Sprintf(`  machine_type = %q
+}
+`, evaluatedMachineType)
+}
+
+func testAccEvaluatedConfig_disks(count int) string {
+	config := ""
+	for i := 0; i < count; i++ {
+		config += evaluatedDisk(fmt.Sprintf("disk%d", i))
+	}
+	return config
+}
+
+func evaluatedDisk(name string) string {
+	return fmt.Sprintf(`
+resource "evaluated_disk" "%s" {
+  name = "%s"
+}
+`, name, name)
+}